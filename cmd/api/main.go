@@ -4,11 +4,14 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/antonioparicio/numismaticapp/internal/api"
 	"github.com/antonioparicio/numismaticapp/internal/application"
 	"github.com/antonioparicio/numismaticapp/internal/infrastructure"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/ai"
 	"github.com/antonioparicio/numismaticapp/internal/infrastructure/gemini"
 	"github.com/antonioparicio/numismaticapp/internal/infrastructure/image"
 	infrastructure_migrations "github.com/antonioparicio/numismaticapp/internal/infrastructure/migrations"
@@ -43,12 +46,6 @@ func main() {
 			os.Exit(1)
 		}
 	}
-	geminiKey := os.Getenv("GEMINI_API_KEY")
-	if geminiKey == "" {
-		slog.Error("GEMINI_API_KEY is not set")
-		os.Exit(1)
-	}
-
 	// 2. Database with Retry Logic
 	var dbPool *pgxpool.Pool
 	var err error
@@ -88,18 +85,58 @@ func main() {
 
 	coinRepo := infrastructure.NewPostgresCoinRepository(dbPool)
 	groupRepo := infrastructure.NewPostgresGroupRepository(dbPool)
-
-	geminiModel := os.Getenv("GEMINI_MODEL")
-	geminiClient, err := gemini.NewGeminiService(ctx, os.Getenv("GEMINI_API_KEY"), geminiModel)
-	if err != nil {
-		slog.Error("Failed to create Gemini client", "error", err)
+	tagRepo := infrastructure.NewPostgresTagRepository(dbPool)
+	wishlistRepo := infrastructure.NewPostgresWishlistRepository(dbPool)
+	checklistRepo := infrastructure.NewPostgresChecklistRepository(dbPool)
+	jobRepo := infrastructure.NewPostgresJobRepository(dbPool)
+	userRepo := infrastructure.NewPostgresUserRepository(dbPool)
+	shareRepo := infrastructure.NewPostgresShareRepository(dbPool)
+
+	// AI providers: any of Gemini, an OpenAI-compatible API and Ollama.
+	// AI_PROVIDER picks the default one when more than one is configured.
+	aiRegistry := ai.NewRegistry()
+	if geminiKey := os.Getenv("GEMINI_API_KEY"); geminiKey != "" {
+		geminiClient, err := gemini.NewGeminiService(ctx, geminiKey, os.Getenv("GEMINI_MODEL"))
+		if err != nil {
+			slog.Error("Failed to create Gemini client", "error", err)
+			os.Exit(1)
+		}
+		defer func() {
+			if err := geminiClient.Close(); err != nil {
+				slog.Error("Failed to close Gemini client", "error", err)
+			}
+		}()
+		aiRegistry.Register("gemini", geminiClient)
+	}
+	if openAIKey, openAIURL := os.Getenv("OPENAI_API_KEY"), os.Getenv("OPENAI_BASE_URL"); openAIKey != "" || openAIURL != "" {
+		if openAIURL == "" {
+			openAIURL = "https://api.openai.com/v1"
+		}
+		openAIModel := os.Getenv("OPENAI_MODEL")
+		if openAIModel == "" {
+			openAIModel = "gpt-4o-mini"
+		}
+		aiRegistry.Register("openai", ai.NewOpenAIService(openAIURL, openAIKey, openAIModel))
+	}
+	if ollamaURL := os.Getenv("OLLAMA_URL"); ollamaURL != "" {
+		ollamaModel := os.Getenv("OLLAMA_MODEL")
+		if ollamaModel == "" {
+			ollamaModel = "llava"
+		}
+		// Ollama serves the OpenAI-compatible API under /v1
+		aiRegistry.Register("ollama", ai.NewOpenAIService(strings.TrimRight(ollamaURL, "/")+"/v1", "", ollamaModel))
+	}
+	if len(aiRegistry.Providers()) == 0 {
+		slog.Error("No AI provider configured: set GEMINI_API_KEY, OPENAI_API_KEY/OPENAI_BASE_URL or OLLAMA_URL")
 		os.Exit(1)
 	}
-	defer func() {
-		if err := geminiClient.Close(); err != nil {
-			slog.Error("Failed to close Gemini client", "error", err)
+	if p := os.Getenv("AI_PROVIDER"); p != "" {
+		if err := aiRegistry.SetDefault(p); err != nil {
+			slog.Error("Invalid AI_PROVIDER", "error", err)
+			os.Exit(1)
 		}
-	}()
+	}
+	slog.Info("AI providers configured", "providers", aiRegistry.Providers(), "default", aiRegistry.Default())
 
 	imageService := image.NewVipsImageService()
 	storageService := storage.NewLocalFileStorage("storage")
@@ -118,15 +155,62 @@ func main() {
 	priceClient := prices.NewCoinGeckoPriceClient()
 
 	// Initialize Application Services
-	coinService := application.NewCoinService(coinRepo, groupRepo, imageService, geminiClient, storageService, rembgClient, numistaClient, priceClient)
+	coinService := application.NewCoinService(coinRepo, groupRepo, tagRepo, wishlistRepo, checklistRepo, imageService, aiRegistry, storageService, rembgClient, numistaClient, priceClient, jobRepo)
+
+	// Background Jobs
+	jobWorkers := 2
+	if v, err := strconv.Atoi(os.Getenv("JOB_WORKERS")); err == nil && v > 0 {
+		jobWorkers = v
+	}
+	worker := application.NewJobWorker(jobRepo, 2*time.Second, jobWorkers)
+	worker.Register(application.JobKindProcessCoin, coinService.ProcessCoinJob)
+	worker.Register(application.JobKindEnrichNumista, coinService.EnrichNumistaJob)
+	if err := worker.Start(ctx); err != nil {
+		slog.Error("Failed to start job worker", "error", err)
+		os.Exit(1)
+	}
+
+	// Deleted coins stay in the trash for this long; 0 keeps them until purged by hand
+	trashRetentionDays := 30
+	if v, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && v >= 0 {
+		trashRetentionDays = v
+	}
+	if trashRetentionDays > 0 {
+		coinService.StartTrashPurger(ctx, time.Duration(trashRetentionDays)*24*time.Hour, time.Hour)
+	}
+
+	// Accounts: the admin from the environment is created on first start
+	sessionTTLHours := 720
+	if v, err := strconv.Atoi(os.Getenv("SESSION_TTL_HOURS")); err == nil && v > 0 {
+		sessionTTLHours = v
+	}
+	sessionTTL := time.Duration(sessionTTLHours) * time.Hour
+	authService := application.NewAuthService(userRepo, sessionTTL)
+	shareService := application.NewShareService(shareRepo, coinRepo, groupRepo)
+	if err := authService.Bootstrap(ctx, os.Getenv("ADMIN_USERNAME"), os.Getenv("ADMIN_PASSWORD")); err != nil {
+		slog.Error("Failed to bootstrap admin account", "error", err)
+		os.Exit(1)
+	}
 
 	// 5. API
+	// Bulk imports and backup restores upload a whole photo session in one
+	// request; every other route keeps the default limit
+	uploadLimitMB := 512
+	if v, err := strconv.Atoi(os.Getenv("MAX_UPLOAD_MB")); err == nil && v > 0 {
+		uploadLimitMB = v
+	}
 	app := fiber.New(fiber.Config{
-		BodyLimit: 20 * 1024 * 1024, // 20MB limit for images
+		BodyLimit: api.DefaultBodyLimit,
+		// Bodies over BodyLimit are streamed instead of refused, so that the
+		// upload routes can read them; api.BodyLimit enforces the limits
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 	coinHandler := api.NewCoinHandler(coinService)
 	healthHandler := api.NewHealthHandler(dbPool)
-	api.SetupRouter(app, coinHandler, healthHandler)
+	authHandler := api.NewAuthHandler(authService, sessionTTL)
+	shareHandler := api.NewShareHandler(shareService)
+	api.SetupRouter(app, coinHandler, healthHandler, authHandler, shareHandler, uploadLimitMB*1024*1024)
 
 	// 6. Start
 	port := os.Getenv("PORT")
@@ -0,0 +1,262 @@
+// Command import uploads a folder or ZIP of obverse/reverse photos to a
+// running server, which pairs them and adds one coin per pair. Given a .csv
+// in the export format it loads the coins in it instead.
+//
+//	go run ./cmd/import -group "Spain 1999" ./photos
+//	go run ./cmd/import -dry-run session.zip
+//	go run ./cmd/import -dry-run coins.csv
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+)
+
+func main() {
+	apiURL := flag.String("api", envOr("API_URL", "http://localhost:8080"), "base URL of the server")
+	token := flag.String("token", os.Getenv("API_TOKEN"), "API token (needs the write scope) sent as a Bearer token")
+	group := flag.String("group", "", "group to assign the imported coins to")
+	model := flag.String("model", "", "AI model to use for the analysis")
+	temperature := flag.Float64("temperature", 0.1, "temperature for the AI analysis")
+	dryRun := flag.Bool("dry-run", false, "only show how the photos would be paired, or validate the CSV")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <folder|file.zip|file.csv>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	src := flag.Arg(0)
+	baseURL := strings.TrimRight(*apiURL, "/")
+
+	if strings.EqualFold(filepath.Ext(src), ".csv") {
+		importCSV(baseURL, src, *token, *dryRun)
+		return
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	isZip := !info.IsDir()
+
+	if *dryRun {
+		files, closeFn, err := listFiles(src, isZip)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		defer closeFn()
+		pairs, unpaired := application.PairImportFiles(files)
+		for _, p := range pairs {
+			fmt.Printf("PAIR  %s + %s\n", p.Front.Name, p.Back.Name)
+		}
+		for _, u := range unpaired {
+			fmt.Printf("SKIP  %s (%s)\n", u.Front, u.Error)
+		}
+		fmt.Printf("\n%d pairs, %d unpaired\n", len(pairs), len(unpaired))
+		return
+	}
+
+	body, contentType := multipartBody(src, isZip, map[string]string{
+		"group_name":  *group,
+		"model_name":  *model,
+		"temperature": strconv.FormatFloat(*temperature, 'f', -1, 32),
+	})
+	resp, err := post(baseURL+"/api/v1/coins/import", contentType, *token, body)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		log.Fatalf("Import failed (%s): %s", resp.Status, msg)
+	}
+
+	var report application.BulkImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		log.Fatalf("Error: failed to decode report: %v", err)
+	}
+	for _, item := range report.Items {
+		switch {
+		case item.Status == application.ImportStatusFailed:
+			fmt.Printf("FAIL  %s %s: %s\n", item.Front, item.Back, item.Error)
+		case item.CoinID != nil:
+			fmt.Printf("%-5s %s + %s -> %s\n", strings.ToUpper(item.Status), item.Front, item.Back, item.CoinID)
+		}
+	}
+	fmt.Printf("\n%d imported, %d failed\n", report.Succeeded, report.Failed)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func importCSV(baseURL, src, token string, dryRun bool) {
+	f, err := os.Open(src)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	url := baseURL + "/api/v1/import/csv"
+	if dryRun {
+		url += "?dry_run=true"
+	}
+	resp, err := post(url, "text/csv", token, f)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		log.Fatalf("Import failed (%s): %s", resp.Status, msg)
+	}
+
+	var report application.CSVImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		log.Fatalf("Error: failed to decode report: %v", err)
+	}
+	for _, e := range report.Errors {
+		if e.Column != "" {
+			fmt.Printf("ROW %d  %s: %s\n", e.Row, e.Column, e.Error)
+		} else {
+			fmt.Printf("ROW %d  %s\n", e.Row, e.Error)
+		}
+	}
+	if len(report.IgnoredColumns) > 0 {
+		fmt.Printf("Ignored columns: %s\n", strings.Join(report.IgnoredColumns, ", "))
+	}
+	if len(report.GroupsCreated) > 0 {
+		fmt.Printf("New groups: %s\n", strings.Join(report.GroupsCreated, ", "))
+	}
+	verb := "imported"
+	if report.DryRun {
+		verb = "would be imported (dry run)"
+	}
+	fmt.Printf("\n%d rows: %d created, %d updated, %d failed; %s\n", report.Rows, report.Created, report.Updated, report.Failed, verb)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func listFiles(src string, isZip bool) ([]application.ImportFile, func(), error) {
+	if !isZip {
+		files, err := application.ImportFilesFromDir(src)
+		return files, func() {}, err
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	files, err := application.ImportFilesFromZip(f, info.Size())
+	if err != nil {
+		_ = f.Close()
+		return nil, nil, err
+	}
+	return files, func() { _ = f.Close() }, nil
+}
+
+// multipartBody streams the upload. A folder is zipped on the fly so the
+// server sees the same relative paths as with a ZIP.
+func multipartBody(src string, isZip bool, fields map[string]string) (io.Reader, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, src, isZip, fields))
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+func writeMultipart(mw *multipart.Writer, src string, isZip bool, fields map[string]string) error {
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := mw.WriteField(k, v); err != nil {
+			return err
+		}
+	}
+
+	part, err := mw.CreateFormFile("archive", filepath.Base(src)+".zip")
+	if err != nil {
+		return err
+	}
+	if isZip {
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		if _, err := io.Copy(part, f); err != nil {
+			return err
+		}
+	} else if err := zipDir(part, src); err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
+func zipDir(w io.Writer, dir string) error {
+	files, err := application.ImportFilesFromDir(dir)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	for _, file := range files {
+		// Photos are already compressed
+		dst, err := zw.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, rc)
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func post(url, contentType, token string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return http.DefaultClient.Do(req)
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
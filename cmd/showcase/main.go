@@ -0,0 +1,125 @@
+// Command showcase renders a collection as a static HTML site for plain
+// static hosting. It reads a backup archive, either a file saved from
+// /api/v1/export/backup or one downloaded from a running server, so it needs
+// no database access.
+//
+//	go run ./cmd/showcase -out site backup.zip
+//	go run ./cmd/showcase -out site -config showcase.json -api http://nas:8080 -token nma_...
+//
+// Prices, estimated values and personal notes are left out unless the config
+// file enables them:
+//
+//	{"title": "My coins", "privacy": {"show_prices": false, "show_values": true, "show_notes": false}}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/showcase"
+)
+
+func main() {
+	out := flag.String("out", "site", "directory to write the site to")
+	configFile := flag.String("config", "", "JSON file with the title and privacy settings")
+	title := flag.String("title", "", "site title (overrides the config file)")
+	apiURL := flag.String("api", os.Getenv("API_URL"), "download the backup from this server instead of reading a file")
+	token := flag.String("token", os.Getenv("API_TOKEN"), "API token (needs the export scope) sent as a Bearer token")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <backup.zip>\n       %s [flags] -api <url>\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var cfg showcase.Config
+	if *configFile != "" {
+		var err error
+		if cfg, err = showcase.LoadConfig(*configFile); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+	if *title != "" {
+		cfg.Title = *title
+	}
+
+	var f *os.File
+	switch {
+	case flag.NArg() == 1:
+		var err error
+		if f, err = os.Open(flag.Arg(0)); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	case flag.NArg() == 0 && *apiURL != "":
+		var err error
+		if f, err = downloadBackup(strings.TrimRight(*apiURL, "/"), *token); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		defer func() { _ = os.Remove(f.Name()) }()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	archive, err := application.OpenBackup(f, info.Size())
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	report, err := showcase.Build(showcase.Source{
+		Coins:       archive.Coins,
+		Groups:      archive.Groups,
+		GroupImages: archive.GroupImages,
+		Images:      archive.Images,
+		OpenFile:    archive.OpenFile,
+	}, cfg, *out)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	for _, w := range report.Warnings {
+		fmt.Printf("WARN  %s\n", w)
+	}
+	fmt.Printf("\n%d coins, %d groups: %d pages and %d images written to %s\n", report.Coins, report.Groups, report.Pages, report.Files, *out)
+}
+
+// downloadBackup saves the server's backup to a temporary file, as reading
+// the archive needs random access.
+func downloadBackup(baseURL, token string) (*os.File, error) {
+	req, err := http.NewRequest(http.MethodGet, baseURL+"/api/v1/export/backup", nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("backup download failed (%s): %s", resp.Status, msg)
+	}
+
+	f, err := os.CreateTemp("", "numismatic-backup-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return nil, fmt.Errorf("failed to download backup: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SessionCookie holds the session token for browsers. API clients can send
+// the same token as "Authorization: Bearer <token>" instead.
+const SessionCookie = "numismatic_session"
+
+const identityLocal = "identity"
+
+type AuthHandler struct {
+	auth       *application.AuthService
+	validate   *validator.Validate
+	sessionTTL time.Duration
+}
+
+func NewAuthHandler(auth *application.AuthService, sessionTTL time.Duration) *AuthHandler {
+	return &AuthHandler{
+		auth:       auth,
+		validate:   validator.New(),
+		sessionTTL: sessionTTL,
+	}
+}
+
+// RequireAuth rejects requests without a live session or API token. The
+// identity is stored in the request locals, its user recorded as the actor
+// of any change it makes, and the request limited to the user's collection.
+func (h *AuthHandler) RequireAuth(c *fiber.Ctx) error {
+	identity, err := h.auth.Authenticate(c.UserContext(), sessionToken(c))
+	if err != nil {
+		if errors.Is(err, application.ErrUnauthenticated) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Locals(identityLocal, identity)
+	ctx := domain.WithActor(c.UserContext(), identity.User.Username)
+	c.SetUserContext(domain.WithCollection(ctx, identity.User.CollectionID))
+	return c.Next()
+}
+
+// RequireScope returns a middleware that rejects identities without the
+// scope. It must run after RequireAuth.
+func (h *AuthHandler) RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if identity := currentIdentity(c); identity == nil || !identity.Can(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "missing scope: " + scope})
+		}
+		return c.Next()
+	}
+}
+
+// RequireMethodScope asks for the read scope on safe methods and the write
+// scope on everything else.
+func (h *AuthHandler) RequireMethodScope(c *fiber.Ctx) error {
+	scope := domain.ScopeWrite
+	if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+		scope = domain.ScopeRead
+	}
+	return h.RequireScope(scope)(c)
+}
+
+// RequireSession rejects API tokens, for account operations that need the
+// user to have logged in with a password.
+func (h *AuthHandler) RequireSession(c *fiber.Ctx) error {
+	if identity := currentIdentity(c); identity == nil || identity.Token != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "not allowed with an api token"})
+	}
+	return c.Next()
+}
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	token, user, err := h.auth.Login(c.UserContext(), req.Username, req.Password)
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidCredentials) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  time.Now().Add(h.sessionTTL),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.JSON(fiber.Map{"token": token, "user": user})
+}
+
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	if token := sessionToken(c); token != "" {
+		if err := h.auth.Logout(c.UserContext(), token); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+	c.ClearCookie(SessionCookie)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+type MeResponse struct {
+	*domain.User
+	Scopes []string `json:"scopes"`
+}
+
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	identity := currentIdentity(c)
+	return c.JSON(MeResponse{User: identity.User, Scopes: identity.Scopes})
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	var req ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.auth.ChangePassword(c.UserContext(), currentUser(c).ID, req.CurrentPassword, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidCredentials):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrWeakPassword):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// Every session was signed out, this one included
+	c.ClearCookie(SessionCookie)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *AuthHandler) ListUsers(c *fiber.Ctx) error {
+	users, err := h.auth.ListUsers(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(users)
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username" validate:"required,max=64"`
+	Password string `json:"password" validate:"required"`
+	IsAdmin  bool   `json:"is_admin"`
+	// CollectionID joins an existing collection; without it the user gets
+	// a new, empty one.
+	CollectionID *uuid.UUID `json:"collection_id"`
+}
+
+func (h *AuthHandler) CreateUser(c *fiber.Ctx) error {
+	var req CreateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	user, err := h.auth.CreateUser(c.UserContext(), req.Username, req.Password, req.IsAdmin, req.CollectionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrUserExists):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrWeakPassword), errors.Is(err, application.ErrCollectionNotFound):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+func (h *AuthHandler) ListCollections(c *fiber.Ctx) error {
+	collections, err := h.auth.ListCollections(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(collections)
+}
+
+func (h *AuthHandler) DeleteUser(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	if err := h.auth.DeleteUser(c.UserContext(), id); err != nil {
+		switch {
+		case errors.Is(err, application.ErrUserNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrLastAdmin):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"gte=0"`
+}
+
+func (h *AuthHandler) CreateAPIToken(c *fiber.Ctx) error {
+	var req CreateAPITokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	token, apiToken, err := h.auth.CreateAPIToken(c.UserContext(), currentUser(c), req.Name, req.Scopes, expiresAt)
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidScope) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"token": token, "api_token": apiToken})
+}
+
+func (h *AuthHandler) ListAPITokens(c *fiber.Ctx) error {
+	tokens, err := h.auth.ListAPITokens(c.UserContext(), currentUser(c).ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(tokens)
+}
+
+func (h *AuthHandler) RevokeAPIToken(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	if err := h.auth.RevokeAPIToken(c.UserContext(), currentUser(c).ID, id); err != nil {
+		if errors.Is(err, application.ErrTokenNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func currentIdentity(c *fiber.Ctx) *application.Identity {
+	identity, _ := c.Locals(identityLocal).(*application.Identity)
+	return identity
+}
+
+func currentUser(c *fiber.Ctx) *domain.User {
+	if identity := currentIdentity(c); identity != nil {
+		return identity.User
+	}
+	return nil
+}
+
+func sessionToken(c *fiber.Ctx) string {
+	if token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return c.Cookies(SessionCookie)
+}
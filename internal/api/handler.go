@@ -1,8 +1,18 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/antonioparicio/numismaticapp/internal/application"
 	"github.com/antonioparicio/numismaticapp/internal/domain"
@@ -23,6 +33,34 @@ func NewCoinHandler(service *application.CoinService) *CoinHandler {
 	}
 }
 
+// GetFile serves stored images only to the collection that owns them. Files
+// of other collections are reported as missing.
+func (h *CoinHandler) GetFile(c *fiber.Ctx) error {
+	file, ok := storagePath(c.Params("*"))
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	ok, err := h.service.CanReadFile(c.UserContext(), file)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	return c.SendFile("./storage" + file)
+}
+
+// storagePath decodes and cleans the file path of a storage request, so the
+// access check and SendFile look at the same file. SendFile decodes its
+// argument once more, so paths that would change again are refused.
+func storagePath(raw string) (string, bool) {
+	file, err := url.PathUnescape(raw)
+	if err != nil || strings.ContainsAny(file, "%?#") {
+		return "", false
+	}
+	return path.Clean("/" + file), true
+}
+
 func (h *CoinHandler) AddCoin(c *fiber.Ctx) error {
 	// Parse multipart form
 	frontFile, err := c.FormFile("front_image")
@@ -79,16 +117,93 @@ func (h *CoinHandler) AddCoin(c *fiber.Ctx) error {
 	}()
 
 	// Call service
-	coin, err := h.service.AddCoin(c.Context(), frontSrc, frontFile.Filename, backSrc, backFile.Filename, groupName, userNotes, name, mint, mintage, modelName, temperature)
+	coin, job, err := h.service.AddCoin(c.UserContext(), frontSrc, frontFile.Filename, backSrc, backFile.Filename, groupName, userNotes, name, mint, mintage, modelName, temperature)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(coin)
+	if job == nil {
+		return c.Status(fiber.StatusCreated).JSON(coin)
+	}
+	// Still processing in the background, the client polls /jobs/:id
+	return c.Status(fiber.StatusAccepted).JSON(AddCoinResponse{Coin: coin, JobID: job.ID})
+}
+
+// ImportCoins runs a batch of front/back photos through AddCoin. It takes a
+// ZIP in "archive" and/or loose files in "images".
+func (h *CoinHandler) ImportCoins(c *fiber.Ctx) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "multipart form is required"})
+	}
+
+	var files []application.ImportFile
+	for _, archive := range form.File["archive"] {
+		src, err := archive.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to open archive"})
+		}
+		defer func() {
+			if err := src.Close(); err != nil {
+				fmt.Printf("Failed to close archive: %v\n", err)
+			}
+		}()
+
+		zipFiles, err := application.ImportFilesFromZip(src, archive.Size)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		files = append(files, zipFiles...)
+	}
+	for _, fh := range form.File["images"] {
+		files = append(files, application.ImportFile{
+			Name: fh.Filename,
+			Open: func() (io.ReadCloser, error) { return fh.Open() },
+		})
+	}
+	if len(files) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "archive or images are required"})
+	}
+
+	opts := application.BulkImportOptions{
+		GroupName:   c.FormValue("group_name"),
+		ModelName:   c.FormValue("model_name"),
+		Temperature: 0.1,
+	}
+	if val, err := strconv.ParseFloat(c.FormValue("temperature"), 32); err == nil {
+		opts.Temperature = float32(val)
+	}
+
+	report, err := h.service.BulkImport(c.UserContext(), files, opts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(report)
+}
+
+// AddCoinResponse is the pending coin plus the job that is processing it.
+type AddCoinResponse struct {
+	*domain.Coin
+	JobID uuid.UUID `json:"job_id"`
+}
+
+func (h *CoinHandler) GetJob(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	job, err := h.service.GetJob(c.UserContext(), id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "job not found"})
+	}
+
+	return c.JSON(job)
 }
 
 func (h *CoinHandler) ListGeminiModels(c *fiber.Ctx) error {
-	models, err := h.service.GetGeminiModels(c.Context())
+	models, err := h.service.GetGeminiModels(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to list models"})
 	}
@@ -96,7 +211,7 @@ func (h *CoinHandler) ListGeminiModels(c *fiber.Ctx) error {
 }
 
 func (h *CoinHandler) ListGroups(c *fiber.Ctx) error {
-	groups, err := h.service.ListGroups(c.Context())
+	groups, err := h.service.ListGroups(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -124,7 +239,7 @@ func (h *CoinHandler) RotateCoin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	if err := h.service.RotateCoinImage(c.Context(), id, req.Side, req.Angle); err != nil {
+	if err := h.service.RotateCoinImage(c.UserContext(), id, req.Side, req.Angle); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -134,6 +249,11 @@ func (h *CoinHandler) RotateCoin(c *fiber.Ctx) error {
 type CreateGroupRequest struct {
 	Name        string `json:"name" validate:"required,min=3,max=50"`
 	Description string `json:"description" validate:"max=200"`
+	// Filter makes the group a smart group
+	Filter *domain.SmartFilter `json:"filter"`
+	// ParentID places a new group under another. Updates keep the parent:
+	// groups change place with MoveGroup.
+	ParentID *int `json:"parent_id"`
 }
 
 func (h *CoinHandler) CreateGroup(c *fiber.Ctx) error {
@@ -146,14 +266,23 @@ func (h *CoinHandler) CreateGroup(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	group, err := h.service.CreateGroup(c.Context(), req.Name, req.Description)
+	group, err := h.service.CreateGroup(c.UserContext(), req.Name, req.Description, req.Filter, req.ParentID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return groupError(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(group)
 }
 
+// UpdateGroupRequest renames a group. Filter is left raw to tell a missing
+// field, which keeps the smart filter, from null, which makes the group an
+// ordinary one.
+type UpdateGroupRequest struct {
+	Name        string          `json:"name" validate:"required,min=3,max=50"`
+	Description string          `json:"description" validate:"max=200"`
+	Filter      json.RawMessage `json:"filter"`
+}
+
 func (h *CoinHandler) UpdateGroup(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := strconv.Atoi(idStr)
@@ -161,7 +290,7 @@ func (h *CoinHandler) UpdateGroup(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
 	}
 
-	var req CreateGroupRequest
+	var req UpdateGroupRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
 	}
@@ -170,14 +299,57 @@ func (h *CoinHandler) UpdateGroup(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	group, err := h.service.UpdateGroup(c.Context(), id, req.Name, req.Description)
+	var filter *domain.SmartFilter
+	setFilter := len(req.Filter) > 0
+	if setFilter {
+		if err := json.Unmarshal(req.Filter, &filter); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid filter"})
+		}
+	}
+
+	group, err := h.service.UpdateGroup(c.UserContext(), id, req.Name, req.Description, filter, setFilter)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return groupError(c, err)
+	}
+
+	return c.JSON(group)
+}
+
+// MoveGroupRequest names the new parent of a group; null moves it to the
+// top level.
+type MoveGroupRequest struct {
+	ParentID *int `json:"parent_id"`
+}
+
+func (h *CoinHandler) MoveGroup(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req MoveGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
 	}
 
+	group, err := h.service.MoveGroup(c.UserContext(), id, req.ParentID)
+	if err != nil {
+		return groupError(c, err)
+	}
 	return c.JSON(group)
 }
 
+func groupError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, application.ErrGroupNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrGroupHasCoins), errors.Is(err, application.ErrSmartGroupParent),
+		errors.Is(err, domain.ErrGroupCycle):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
 func (h *CoinHandler) DeleteGroup(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := strconv.Atoi(idStr)
@@ -185,13 +357,310 @@ func (h *CoinHandler) DeleteGroup(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
 	}
 
-	if err := h.service.DeleteGroup(c.Context(), id); err != nil {
+	if err := h.service.DeleteGroup(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+func (h *CoinHandler) ListTags(c *fiber.Ctx) error {
+	tags, err := h.service.ListTags(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(tags)
+}
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (h *CoinHandler) CreateTag(c *fiber.Ctx) error {
+	var req TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tag, err := h.service.CreateTag(c.UserContext(), req.Name)
+	if err != nil {
+		return tagError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(tag)
+}
+
+func (h *CoinHandler) RenameTag(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req TagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tag, err := h.service.RenameTag(c.UserContext(), id, req.Name)
+	if err != nil {
+		return tagError(c, err)
+	}
+	return c.JSON(tag)
+}
+
+func (h *CoinHandler) DeleteTag(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.DeleteTag(c.UserContext(), id); err != nil {
+		return tagError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+type MergeTagRequest struct {
+	TargetID int `json:"target_id" validate:"required"`
+}
+
+// MergeTag moves the coins of the tag in the path to the tag in the body and
+// deletes the first one.
+func (h *CoinHandler) MergeTag(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req MergeTagRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tag, err := h.service.MergeTags(c.UserContext(), id, req.TargetID)
+	if err != nil {
+		return tagError(c, err)
+	}
+	return c.JSON(tag)
+}
+
+func tagError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidTag):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrTagNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrTagExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+// ListWishlist returns the open wishlist entries by default, with their
+// Numista thumbnails. status=fulfilled or status=all lists the others.
+func (h *CoinHandler) ListWishlist(c *fiber.Ctx) error {
+	status := domain.WishlistStatus(c.Query("status", string(domain.WishlistOpen)))
+	entries, err := h.service.ListWishlist(c.UserContext(), status)
+	if err != nil {
+		return wishlistError(c, err)
+	}
+	return c.JSON(entries)
+}
+
+func (h *CoinHandler) GetWant(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	entry, err := h.service.GetWant(c.UserContext(), id)
+	if err != nil {
+		return wishlistError(c, err)
+	}
+	return c.JSON(entry)
+}
+
+func (h *CoinHandler) CreateWant(c *fiber.Ctx) error {
+	var req application.WantParams
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+
+	entry, err := h.service.CreateWant(c.UserContext(), req)
+	if err != nil {
+		return wishlistError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+func (h *CoinHandler) UpdateWant(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req application.WantParams
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+
+	entry, err := h.service.UpdateWant(c.UserContext(), id, req)
+	if err != nil {
+		return wishlistError(c, err)
+	}
+	return c.JSON(entry)
+}
+
+func (h *CoinHandler) DeleteWant(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.DeleteWant(c.UserContext(), id); err != nil {
+		return wishlistError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func wishlistError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidWant):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrWantNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrWantExists):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+func (h *CoinHandler) ListChecklists(c *fiber.Ctx) error {
+	checklists, err := h.service.ListChecklists(c.UserContext())
+	if err != nil {
+		return checklistError(c, err)
+	}
+	return c.JSON(checklists)
+}
+
+// GetChecklist returns a checklist with its progress: completion, missing
+// slots and duplicates.
+func (h *CoinHandler) GetChecklist(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	checklist, err := h.service.GetChecklist(c.UserContext(), id)
+	if err != nil {
+		return checklistError(c, err)
+	}
+	return c.JSON(checklist)
+}
+
+func (h *CoinHandler) CreateChecklist(c *fiber.Ctx) error {
+	var req application.ChecklistParams
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+
+	checklist, err := h.service.CreateChecklist(c.UserContext(), req)
+	if err != nil {
+		return checklistError(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(checklist)
+}
+
+type UpdateChecklistRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+func (h *CoinHandler) UpdateChecklist(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req UpdateChecklistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	checklist, err := h.service.UpdateChecklist(c.UserContext(), id, req.Name, req.Description)
+	if err != nil {
+		return checklistError(c, err)
+	}
+	return c.JSON(checklist)
+}
+
+func (h *CoinHandler) DeleteChecklist(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	if err := h.service.DeleteChecklist(c.UserContext(), id); err != nil {
+		return checklistError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *CoinHandler) AddChecklistSlots(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	var req application.ChecklistSlots
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+	}
+
+	checklist, err := h.service.AddChecklistSlots(c.UserContext(), id, req)
+	if err != nil {
+		return checklistError(c, err)
+	}
+	return c.JSON(checklist)
+}
+
+func (h *CoinHandler) DeleteChecklistSlot(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+	slotID, err := strconv.Atoi(c.Params("slot_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid slot id"})
+	}
+
+	if err := h.service.DeleteChecklistSlot(c.UserContext(), id, slotID); err != nil {
+		return checklistError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func checklistError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidChecklist):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, application.ErrChecklistNotFound), errors.Is(err, application.ErrSlotNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
 func (h *CoinHandler) ListCoins(c *fiber.Ctx) error {
 	// Parse filters
 	limit := 50
@@ -208,15 +677,48 @@ func (h *CoinHandler) ListCoins(c *fiber.Ctx) error {
 		}
 	}
 
+	filter := coinFilterFromQuery(c)
+	filter.Limit = limit
+	filter.Offset = offset
+	filter.Cursor = c.Query("cursor")
+
+	// ?cursor (empty for the first page), ?total=true and ?facets=true wrap
+	// the coins in an object with the page cursors and the extras; without
+	// them the response stays a plain array
+	opts := application.CoinListOptions{
+		Total:  c.QueryBool("total"),
+		Facets: c.QueryBool("facets"),
+	}
+	if !opts.Total && !opts.Facets && !c.Context().QueryArgs().Has("cursor") {
+		coins, err := h.service.ListCoins(c.UserContext(), filter)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(coins)
+	}
+
+	list, err := h.service.ListCoinsPage(c.UserContext(), filter, opts)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(list)
+}
+
+// coinFilterFromQuery reads the coin list filters and sort order from the
+// query string. Limit and offset are left to the caller.
+func coinFilterFromQuery(c *fiber.Ctx) domain.CoinFilter {
 	filter := domain.CoinFilter{
-		Limit:     limit,
-		Offset:    offset,
 		Query:     strPtr(c.Query("q")),
 		Country:   strPtr(c.Query("country")),
 		Grade:     strPtr(c.Query("grade")),
 		Material:  strPtr(c.Query("material")),
 		SortBy:    strPtr(c.Query("sort_by")),
 		SortOrder: strPtr(c.Query("order")),
+		// With group_id, also the coins of its subgroups
+		IncludeSubgroups: c.QueryBool("include_subgroups"),
 	}
 
 	if g := c.Query("group_id"); g != "" {
@@ -259,12 +761,22 @@ func (h *CoinHandler) ListCoins(c *fiber.Ctx) error {
 		filter.SortOrder = &so
 	}
 
-	coins, err := h.service.ListCoins(c.Context(), filter)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
-	}
+	filter.TagsAny = queryList(c, "tags_any")
+	filter.TagsAll = queryList(c, "tags_all")
+	filter.TagsNone = queryList(c, "tags_none")
 
-	return c.JSON(coins)
+	return filter
+}
+
+// queryList splits a comma separated query parameter, dropping blanks.
+func queryList(c *fiber.Ctx, key string) []string {
+	var values []string
+	for _, v := range strings.Split(c.Query(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
 }
 
 func (h *CoinHandler) GetCoin(c *fiber.Ctx) error {
@@ -274,7 +786,7 @@ func (h *CoinHandler) GetCoin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
 	}
 
-	coin, err := h.service.GetCoin(c.Context(), id)
+	coin, err := h.service.GetCoin(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "coin not found"})
 	}
@@ -283,7 +795,7 @@ func (h *CoinHandler) GetCoin(c *fiber.Ctx) error {
 }
 
 func (h *CoinHandler) GetDashboardStats(c *fiber.Ctx) error {
-	stats, err := h.service.GetDashboardStats(c.Context())
+	stats, err := h.service.GetDashboardStats(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -302,7 +814,10 @@ func (h *CoinHandler) UpdateCoin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
 	}
 
-	coin, err := h.service.UpdateCoin(c.Context(), id, req)
+	coin, err := h.service.UpdateCoin(c.UserContext(), id, req)
+	if errors.Is(err, application.ErrSmartGroupCoins) || errors.Is(err, domain.ErrInvalidTag) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -317,13 +832,63 @@ func (h *CoinHandler) DeleteCoin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
 	}
 
-	if err := h.service.DeleteCoin(c.Context(), id); err != nil {
+	if err := h.service.DeleteCoin(c.UserContext(), id); err != nil {
+		if errors.Is(err, application.ErrCoinNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+func (h *CoinHandler) ListTrash(c *fiber.Ctx) error {
+	coins, err := h.service.ListTrash(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(coins)
+}
+
+func (h *CoinHandler) RestoreCoin(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	coin, err := h.service.RestoreCoin(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, application.ErrCoinNotInTrash) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(coin)
+}
+
+func (h *CoinHandler) PurgeCoin(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	if err := h.service.PurgeCoin(c.UserContext(), id); err != nil {
+		if errors.Is(err, application.ErrCoinNotInTrash) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *CoinHandler) EmptyTrash(c *fiber.Ctx) error {
+	purged, err := h.service.EmptyTrash(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"purged": purged})
+}
+
 type ReanalyzeRequest struct {
 	ModelName   string  `json:"model_name" validate:"required"`
 	Temperature float32 `json:"temperature" validate:"gte=0,lte=1"`
@@ -349,7 +914,7 @@ func (h *CoinHandler) ReanalyzeCoin(c *fiber.Ctx) error {
 		req.Temperature = 0.1
 	}
 
-	coin, err := h.service.ReanalyzeCoin(c.Context(), id, req.ModelName, req.Temperature)
+	coin, err := h.service.ReanalyzeCoin(c.UserContext(), id, req.ModelName, req.Temperature)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -364,7 +929,7 @@ func (h *CoinHandler) ReprocessNumista(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
 	}
 
-	if err := h.service.EnrichCoinWithNumista(c.Context(), id); err != nil {
+	if err := h.service.EnrichCoinWithNumista(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -384,7 +949,7 @@ func (h *CoinHandler) ApplyNumistaResult(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid numista id"})
 	}
 
-	coin, err := h.service.ApplyNumistaCandidate(c.Context(), id, numistaID)
+	coin, err := h.service.ApplyNumistaCandidate(c.UserContext(), id, numistaID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -392,6 +957,55 @@ func (h *CoinHandler) ApplyNumistaResult(c *fiber.Ctx) error {
 	return c.JSON(coin)
 }
 
+func (h *CoinHandler) GetNumistaEnrichment(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	enrichment, err := h.service.GetNumistaEnrichment(c.UserContext(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if enrichment == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "coin has no numista enrichment"})
+	}
+
+	return c.JSON(enrichment)
+}
+
+func (h *CoinHandler) ListFailedEnrichments(c *fiber.Ctx) error {
+	enrichments, err := h.service.ListFailedNumistaEnrichments(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(enrichments)
+}
+
+type RetryEnrichmentsRequest struct {
+	// Empty means every failed enrichment
+	CoinIDs []uuid.UUID `json:"coin_ids"`
+}
+
+func (h *CoinHandler) RetryEnrichments(c *fiber.Ctx) error {
+	var req RetryEnrichmentsRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot parse body"})
+		}
+	}
+
+	queued, err := h.service.RetryNumistaEnrichments(c.UserContext(), req.CoinIDs)
+	if err != nil {
+		if errors.Is(err, application.ErrCoinNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error(), "queued": queued})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error(), "queued": queued})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"queued": queued})
+}
+
 type SellCoinRequest struct {
 	SoldPrice   float64 `json:"sold_price" validate:"required,gt=0"`
 	SaleChannel string  `json:"sale_channel" validate:"required,min=1"`
@@ -413,7 +1027,7 @@ func (h *CoinHandler) SellCoin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	coin, err := h.service.MarkCoinAsSold(c.Context(), id, req.SoldPrice, req.SaleChannel)
+	coin, err := h.service.MarkCoinAsSold(c.UserContext(), id, req.SoldPrice, req.SaleChannel)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -421,8 +1035,63 @@ func (h *CoinHandler) SellCoin(c *fiber.Ctx) error {
 	return c.JSON(coin)
 }
 
+func (h *CoinHandler) GetCoinHistory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	history, err := h.service.GetCoinHistory(c.UserContext(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(history)
+}
+
+type RevertCoinRequest struct {
+	// Revision to go back to; 0 is the coin before its first recorded change
+	Revision *int `json:"revision" validate:"required,gte=0"`
+}
+
+func (h *CoinHandler) RevertCoin(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	var req RevertCoinRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	coin, err := h.service.RevertCoin(c.UserContext(), id, *req.Revision)
+	if err != nil {
+		if errors.Is(err, application.ErrRevisionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(coin)
+}
+
+func (h *CoinHandler) GetGroupHistory(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	history, err := h.service.GetGroupHistory(c.UserContext(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(history)
+}
+
 func (h *CoinHandler) GetSaleChannels(c *fiber.Ctx) error {
-	channels, err := h.service.GetSaleChannels(c.Context())
+	channels, err := h.service.GetSaleChannels(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -437,7 +1106,7 @@ func strPtr(s string) *string {
 }
 
 func (h *CoinHandler) ExportCSV(c *fiber.Ctx) error {
-	data, err := h.service.ExportCoinsCSV(c.Context())
+	data, err := h.service.ExportCoinsCSV(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -447,8 +1116,133 @@ func (h *CoinHandler) ExportCSV(c *fiber.Ctx) error {
 	return c.Send(data)
 }
 
+// ExportCatalogue renders a printable PDF of the coins matching the same
+// filters as ListCoins. ?values=true adds estimated values and ?title= sets
+// the cover title.
+func (h *CoinHandler) ExportCatalogue(c *fiber.Ctx) error {
+	opts := application.CatalogueOptions{
+		Title:         c.Query("title"),
+		IncludeValues: c.QueryBool("values"),
+	}
+
+	render, err := h.service.CataloguePDF(c.UserContext(), coinFilterFromQuery(c), opts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", `attachment; filename="catalogue.pdf"`)
+	// Rendered while sending; the status is already out if it fails
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := render(w); err != nil {
+			fmt.Printf("Failed to render catalogue: %v\n", err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			fmt.Printf("Failed to send catalogue: %v\n", err)
+		}
+	})
+	return nil
+}
+
+// ExportLabels renders a sheet of 2x2 holder labels, one per coin listed in
+// ?ids= (comma separated) or, without it, per coin matching the ListCoins
+// filters. The sheet is set with ?page=, ?label_width=, ?label_height=,
+// ?columns=, ?rows=, ?margin_top=, ?margin_left=, ?gap_x=, ?gap_y= (in
+// millimetres) and ?outline=; ?offset= skips positions of a partly used
+// sheet. The QR codes link under ?base_url=, by default the address the
+// request came to.
+func (h *CoinHandler) ExportLabels(c *fiber.Ctx) error {
+	var ids []uuid.UUID
+	for _, s := range strings.Split(c.Query("ids"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid: " + s})
+		}
+		ids = append(ids, id)
+	}
+
+	layout := application.DefaultLabelLayout
+	layout.PageSize = c.Query("page", layout.PageSize)
+	layout.Outline = c.QueryBool("outline", layout.Outline)
+	for name, dst := range map[string]*float64{
+		"label_width":  &layout.LabelWidth,
+		"label_height": &layout.LabelHeight,
+		"margin_top":   &layout.MarginTop,
+		"margin_left":  &layout.MarginLeft,
+		"gap_x":        &layout.GapX,
+		"gap_y":        &layout.GapY,
+	} {
+		*dst = c.QueryFloat(name, *dst)
+	}
+	layout.Columns = c.QueryInt("columns", layout.Columns)
+	layout.Rows = c.QueryInt("rows", layout.Rows)
+
+	opts := application.LabelOptions{
+		Layout:  layout,
+		Offset:  c.QueryInt("offset"),
+		BaseURL: c.Query("base_url", c.BaseURL()),
+	}
+
+	render, err := h.service.LabelsPDF(c.UserContext(), ids, coinFilterFromQuery(c), opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidLabelLayout):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, application.ErrCoinNotFound):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set("Content-Type", "application/pdf")
+	c.Set("Content-Disposition", `attachment; filename="labels.pdf"`)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := render(w); err != nil {
+			fmt.Printf("Failed to render labels: %v\n", err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			fmt.Printf("Failed to send labels: %v\n", err)
+		}
+	})
+	return nil
+}
+
+// ImportCSV loads a CSV in the ExportCSV format, sent as the "file" form
+// field or as the raw body. ?dry_run=true only validates.
+func (h *CoinHandler) ImportCSV(c *fiber.Ctx) error {
+	var src io.Reader
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		f, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to open file"})
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				fmt.Printf("Failed to close csv file: %v\n", err)
+			}
+		}()
+		src = f
+	} else if len(c.Body()) > 0 {
+		src = bytes.NewReader(c.Body())
+	} else {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "csv file is required"})
+	}
+
+	report, err := h.service.ImportCoinsCSV(c.UserContext(), src, c.QueryBool("dry_run"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(report)
+}
+
 func (h *CoinHandler) ExportSQL(c *fiber.Ctx) error {
-	data, err := h.service.ExportCoinsSQL(c.Context())
+	data, err := h.service.ExportCoinsSQL(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -458,6 +1252,64 @@ func (h *CoinHandler) ExportSQL(c *fiber.Ctx) error {
 	return c.Send(data)
 }
 
+// ExportBackup streams a ZIP with every row and image in the collection.
+// The archive is built in a temporary file first so a failure still gets a
+// proper error response.
+func (h *CoinHandler) ExportBackup(c *fiber.Ctx) error {
+	tmp, err := os.CreateTemp("", "numismatic-backup-*.zip")
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to create backup file"})
+	}
+	// Unlinked right away; the open handle keeps it readable until sent
+	if err := os.Remove(tmp.Name()); err != nil {
+		fmt.Printf("Failed to remove temp backup file: %v\n", err)
+	}
+
+	if err := h.service.ExportBackup(c.UserContext(), tmp); err != nil {
+		_ = tmp.Close()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	info, err := tmp.Stat()
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		_ = tmp.Close()
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to read backup file"})
+	}
+
+	filename := fmt.Sprintf("numismatic-backup-%s.zip", time.Now().Format("20060102-150405"))
+	c.Set("Content-Type", "application/zip")
+	c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	// The stream is closed by fasthttp once sent
+	return c.SendStream(tmp, int(info.Size()))
+}
+
+// RestoreBackup loads a ZIP produced by ExportBackup, sent as the "file"
+// form field.
+func (h *CoinHandler) RestoreBackup(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "backup file is required"})
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to open file"})
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			fmt.Printf("Failed to close backup file: %v\n", err)
+		}
+	}()
+
+	report, err := h.service.RestoreBackup(c.UserContext(), f, fileHeader.Size)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(report)
+}
+
 type AddLinkRequest struct {
 	URL string `json:"url" validate:"required,url"`
 }
@@ -469,7 +1321,7 @@ func (h *CoinHandler) ListCoinLinks(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
 	}
 
-	links, err := h.service.GetLinks(c.Context(), id)
+	links, err := h.service.GetLinks(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -492,7 +1344,7 @@ func (h *CoinHandler) AddCoinLink(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	link, err := h.service.AddLink(c.Context(), id, req.URL)
+	link, err := h.service.AddLink(c.UserContext(), id, req.URL)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -506,7 +1358,7 @@ func (h *CoinHandler) DeleteCoinLink(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid link uuid"})
 	}
 
-	if err := h.service.RemoveLink(c.Context(), linkID); err != nil {
+	if err := h.service.RemoveLink(c.UserContext(), linkID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -520,7 +1372,7 @@ func (h *CoinHandler) RefreshCoinLink(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid link uuid"})
 	}
 
-	link, err := h.service.RefreshLink(c.Context(), linkID)
+	link, err := h.service.RefreshLink(c.UserContext(), linkID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -546,7 +1398,7 @@ func (h *CoinHandler) AddGroupImage(c *fiber.Ctx) error {
 	}
 	defer func() { _ = src.Close() }()
 
-	if err := h.service.AddGroupImage(c.Context(), groupID, src, file.Filename); err != nil {
+	if err := h.service.AddGroupImage(c.UserContext(), groupID, src, file.Filename); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -560,7 +1412,7 @@ func (h *CoinHandler) RemoveGroupImage(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid image uuid"})
 	}
 
-	if err := h.service.RemoveGroupImage(c.Context(), imgID); err != nil {
+	if err := h.service.RemoveGroupImage(c.UserContext(), imgID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -585,7 +1437,7 @@ func (h *CoinHandler) AddCoinGalleryImage(c *fiber.Ctx) error {
 	}
 	defer func() { _ = src.Close() }()
 
-	if err := h.service.AddCoinGalleryImage(c.Context(), coinID, src, file.Filename); err != nil {
+	if err := h.service.AddCoinGalleryImage(c.UserContext(), coinID, src, file.Filename); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -599,7 +1451,7 @@ func (h *CoinHandler) RemoveCoinGalleryImage(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid image uuid"})
 	}
 
-	if err := h.service.RemoveCoinGalleryImage(c.Context(), imgID); err != nil {
+	if err := h.service.RemoveCoinGalleryImage(c.UserContext(), imgID); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -613,7 +1465,7 @@ func (h *CoinHandler) ListGroupImages(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid group id"})
 	}
 
-	images, err := h.service.ListGroupImages(c.Context(), groupID)
+	images, err := h.service.ListGroupImages(c.UserContext(), groupID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -627,7 +1479,7 @@ func (h *CoinHandler) ListCoinGalleryImages(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid coin uuid"})
 	}
 
-	images, err := h.service.ListCoinGalleryImages(c.Context(), coinID)
+	images, err := h.service.ListCoinGalleryImages(c.UserContext(), coinID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -641,7 +1493,7 @@ func (h *CoinHandler) GetCoinStats(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
 	}
 
-	stats, err := h.service.GetCoinStats(c.Context(), id)
+	stats, err := h.service.GetCoinStats(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
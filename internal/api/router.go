@@ -1,19 +1,27 @@
 package api
 
 import (
+	"io"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
-func SetupRouter(app *fiber.App, coinHandler *CoinHandler, healthHandler *HealthHandler) {
+// DefaultBodyLimit is the largest request body accepted outside the upload
+// routes: enough for the two photos of AddCoin.
+const DefaultBodyLimit = 20 * 1024 * 1024
+
+func SetupRouter(app *fiber.App, coinHandler *CoinHandler, healthHandler *HealthHandler, authHandler *AuthHandler, shareHandler *ShareHandler, uploadLimit int) {
 	// Middleware
 	app.Use(logger.New())
 	app.Use(cors.New())
+	app.Use(BodyLimit(DefaultBodyLimit, uploadLimit, "/api/v1/coins/import", "/api/v1/import/backup"))
 
-	// Static files (Images)
-	// Assuming storage is at ./storage relative to execution
-	app.Static("/storage", "./storage")
+	// Stored images, served from ./storage relative to execution
+	app.Get("/storage/*", authHandler.RequireAuth, authHandler.RequireScope(domain.ScopeRead), coinHandler.GetFile)
 
 	// Serve Frontend Static Files
 	app.Static("/", "./web/dist")
@@ -25,7 +33,45 @@ func SetupRouter(app *fiber.App, coinHandler *CoinHandler, healthHandler *Health
 	// Health Check
 	v1.Get("/health", healthHandler.HealthCheck)
 
+	// Auth: everything registered after RequireAuth needs a session or API
+	// token, with the read scope for GET and the write scope for changes
+	v1.Post("/auth/login", authHandler.Login)
+	v1.Post("/auth/logout", authHandler.Logout)
+
+	// Share links: public, the token in the path grants read-only access
+	share := v1.Group("/share/:token")
+	share.Get("/", shareHandler.OpenShare)
+	share.Get("/coins", shareHandler.ListSharedCoins)
+	share.Get("/coins/:id", shareHandler.GetSharedCoin)
+	share.Get("/storage/*", shareHandler.GetSharedFile)
+
+	v1.Use(authHandler.RequireAuth, authHandler.RequireMethodScope)
+	v1.Get("/auth/me", authHandler.Me)
+	v1.Post("/auth/password", authHandler.RequireSession, authHandler.ChangePassword)
+
+	// API Tokens
+	tokens := v1.Group("/auth/tokens", authHandler.RequireSession)
+	tokens.Get("/", authHandler.ListAPITokens)
+	tokens.Post("/", authHandler.CreateAPIToken)
+	tokens.Delete("/:id", authHandler.RevokeAPIToken)
+
+	// Users
+	users := v1.Group("/users", authHandler.RequireScope(domain.ScopeAdmin))
+	users.Get("/", authHandler.ListUsers)
+	users.Post("/", authHandler.CreateUser)
+	users.Delete("/:id", authHandler.DeleteUser)
+
+	// Share Link Management
+	v1.Get("/shares", shareHandler.ListShareLinks)
+	v1.Post("/shares", shareHandler.CreateShareLink)
+	v1.Delete("/shares/:id", shareHandler.RevokeShareLink)
+
+	// Collections
+	v1.Get("/collections", authHandler.RequireScope(domain.ScopeAdmin), authHandler.ListCollections)
+
 	v1.Post("/coins", coinHandler.AddCoin)
+	v1.Post("/coins/import", coinHandler.ImportCoins)
+	v1.Get("/jobs/:id", coinHandler.GetJob)
 
 	// Gemini Models
 	v1.Get("/gemini/models", coinHandler.ListGeminiModels)
@@ -35,6 +81,32 @@ func SetupRouter(app *fiber.App, coinHandler *CoinHandler, healthHandler *Health
 	v1.Post("/groups", coinHandler.CreateGroup)
 	v1.Put("/groups/:id", coinHandler.UpdateGroup)
 	v1.Delete("/groups/:id", coinHandler.DeleteGroup)
+	v1.Post("/groups/:id/move", coinHandler.MoveGroup)
+	v1.Get("/groups/:id/history", coinHandler.GetGroupHistory)
+
+	// Tags
+	v1.Get("/tags", coinHandler.ListTags)
+	v1.Post("/tags", coinHandler.CreateTag)
+	v1.Put("/tags/:id", coinHandler.RenameTag)
+	v1.Delete("/tags/:id", coinHandler.DeleteTag)
+	v1.Post("/tags/:id/merge", coinHandler.MergeTag)
+
+	// Wishlist
+	v1.Get("/wishlist", coinHandler.ListWishlist)
+	v1.Post("/wishlist", coinHandler.CreateWant)
+	v1.Get("/wishlist/:id", coinHandler.GetWant)
+	v1.Put("/wishlist/:id", coinHandler.UpdateWant)
+	v1.Delete("/wishlist/:id", coinHandler.DeleteWant)
+
+	// Checklists
+	v1.Get("/checklists", coinHandler.ListChecklists)
+	v1.Post("/checklists", coinHandler.CreateChecklist)
+	v1.Get("/checklists/:id", coinHandler.GetChecklist)
+	v1.Put("/checklists/:id", coinHandler.UpdateChecklist)
+	v1.Delete("/checklists/:id", coinHandler.DeleteChecklist)
+	v1.Post("/checklists/:id/slots", coinHandler.AddChecklistSlots)
+	v1.Delete("/checklists/:id/slots/:slot_id", coinHandler.DeleteChecklistSlot)
+
 	v1.Get("/coins", coinHandler.ListCoins)
 	v1.Get("/coins/:id", coinHandler.GetCoin)
 	v1.Put("/coins/:id", coinHandler.UpdateCoin)
@@ -42,13 +114,35 @@ func SetupRouter(app *fiber.App, coinHandler *CoinHandler, healthHandler *Health
 	v1.Post("/coins/:id/analyze", coinHandler.ReanalyzeCoin)
 	v1.Post("/coins/:id/reprocess-numista", coinHandler.ReprocessNumista)
 	v1.Post("/coins/:id/apply-numista/:numista_id", coinHandler.ApplyNumistaResult)
+	v1.Get("/coins/:id/numista-enrichment", coinHandler.GetNumistaEnrichment)
 	v1.Post("/coins/:id/rotate", coinHandler.RotateCoin)
 	v1.Post("/coins/:id/sell", coinHandler.SellCoin)
+	v1.Get("/coins/:id/history", coinHandler.GetCoinHistory)
+	v1.Post("/coins/:id/revert", coinHandler.RevertCoin)
 	v1.Delete("/coins/:id", coinHandler.DeleteCoin)
+
+	// Trash
+	v1.Get("/trash", coinHandler.ListTrash)
+	v1.Delete("/trash", coinHandler.EmptyTrash)
+	v1.Post("/trash/:id/restore", coinHandler.RestoreCoin)
+	v1.Delete("/trash/:id", coinHandler.PurgeCoin)
+
 	v1.Get("/dashboard", coinHandler.GetDashboardStats)
 	v1.Get("/sale-channels", coinHandler.GetSaleChannels)
-	v1.Get("/export/csv", coinHandler.ExportCSV)
-	v1.Get("/export/sql", coinHandler.ExportSQL)
+	v1.Post("/import/csv", coinHandler.ImportCSV)
+	v1.Post("/import/backup", authHandler.RequireScope(domain.ScopeAdmin), coinHandler.RestoreBackup)
+
+	// Export
+	export := v1.Group("/export", authHandler.RequireScope(domain.ScopeExport))
+	export.Get("/csv", coinHandler.ExportCSV)
+	export.Get("/sql", authHandler.RequireScope(domain.ScopeAdmin), coinHandler.ExportSQL)
+	export.Get("/backup", coinHandler.ExportBackup)
+	export.Get("/catalogue", coinHandler.ExportCatalogue)
+	export.Get("/labels", coinHandler.ExportLabels)
+
+	// Numista Enrichment
+	v1.Get("/numista/enrichments/failed", coinHandler.ListFailedEnrichments)
+	v1.Post("/numista/enrichments/retry", coinHandler.RetryEnrichments)
 
 	// Links
 	v1.Get("/coins/:id/links", coinHandler.ListCoinLinks)
@@ -72,3 +166,45 @@ func SetupRouter(app *fiber.App, coinHandler *CoinHandler, healthHandler *Health
 		return c.SendFile("./web/dist/index.html")
 	})
 }
+
+// BodyLimit refuses request bodies larger than limit with 413, except on the
+// upload routes given, which may send up to uploadLimit. The app streams
+// bodies that do not fit in its own BodyLimit, so this is what keeps them out
+// of memory: a streamed body is only read here when it is small enough.
+func BodyLimit(limit, uploadLimit int, uploadRoutes ...string) fiber.Handler {
+	uploads := make(map[string]bool, len(uploadRoutes))
+	for _, r := range uploadRoutes {
+		uploads[r] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		req := c.Request()
+		max := limit
+		if c.Method() == fiber.MethodPost && uploads[strings.TrimSuffix(c.Path(), "/")] {
+			// Multipart uploads are spooled to disk by MultipartForm
+			if req.IsBodyStream() && req.Header.ContentLength() < 0 {
+				c.Context().SetConnectionClose()
+				return c.Status(fiber.StatusLengthRequired).JSON(fiber.Map{"error": "Content-Length is required"})
+			}
+			max = uploadLimit
+		}
+		if req.Header.ContentLength() > max {
+			// The unread body would be taken for the next request
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "request body is too large"})
+		}
+		if max == limit && req.IsBodyStream() {
+			// Chunked body of unknown length: buffer it up to the limit
+			body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(limit)+1))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to read body"})
+			}
+			if len(body) > limit {
+				c.Context().SetConnectionClose()
+				return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": "request body is too large"})
+			}
+			req.SetBody(body)
+		}
+		return c.Next()
+	}
+}
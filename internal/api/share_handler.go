@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ShareHandler struct {
+	shares   *application.ShareService
+	validate *validator.Validate
+}
+
+func NewShareHandler(shares *application.ShareService) *ShareHandler {
+	return &ShareHandler{
+		shares:   shares,
+		validate: validator.New(),
+	}
+}
+
+type CreateShareLinkRequest struct {
+	Name          string             `json:"name" validate:"required,max=100"`
+	GroupID       *int               `json:"group_id"`
+	Filter        domain.ShareFilter `json:"filter"`
+	ExpiresInDays int                `json:"expires_in_days" validate:"gte=0"`
+}
+
+func (h *ShareHandler) CreateShareLink(c *fiber.Ctx) error {
+	var req CreateShareLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid body"})
+	}
+	if err := h.validate.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	token, link, err := h.shares.CreateShareLink(c.UserContext(), req.Name, req.GroupID, req.Filter, expiresAt)
+	if err != nil {
+		if errors.Is(err, application.ErrShareGroupNotFound) || errors.Is(err, application.ErrShareSmartGroup) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"token": token, "share_link": link})
+}
+
+func (h *ShareHandler) ListShareLinks(c *fiber.Ctx) error {
+	links, err := h.shares.ListShareLinks(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(links)
+}
+
+func (h *ShareHandler) RevokeShareLink(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	if err := h.shares.RevokeShareLink(c.UserContext(), id); err != nil {
+		if errors.Is(err, application.ErrShareNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// The handlers below are public: the token in the path is the only
+// credential, so unknown, revoked and expired links all look the same.
+
+func (h *ShareHandler) OpenShare(c *fiber.Ctx) error {
+	view, err := h.shares.OpenShare(c.UserContext(), c.Params("token"))
+	if err != nil {
+		return shareError(c, err)
+	}
+	return c.JSON(view)
+}
+
+func (h *ShareHandler) ListSharedCoins(c *fiber.Ctx) error {
+	limit := 50
+	if l := c.Query("limit"); l != "" {
+		if val, err := strconv.Atoi(l); err == nil && val > 0 {
+			limit = val
+		}
+	}
+
+	offset := 0
+	if o := c.Query("offset"); o != "" {
+		if val, err := strconv.Atoi(o); err == nil && val >= 0 {
+			offset = val
+		}
+	}
+
+	coins, err := h.shares.ListSharedCoins(c.UserContext(), c.Params("token"), limit, offset, strPtr(c.Query("sort_by")), strPtr(c.Query("order")))
+	if err != nil {
+		return shareError(c, err)
+	}
+	return c.JSON(coins)
+}
+
+func (h *ShareHandler) GetSharedCoin(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid uuid"})
+	}
+
+	coin, err := h.shares.GetSharedCoin(c.UserContext(), c.Params("token"), id)
+	if err != nil {
+		if errors.Is(err, application.ErrNotShared) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "coin not found"})
+		}
+		return shareError(c, err)
+	}
+	return c.JSON(coin)
+}
+
+// GetSharedFile serves the stored images of the coins and group a link shows.
+func (h *ShareHandler) GetSharedFile(c *fiber.Ctx) error {
+	file, ok := storagePath(c.Params("*"))
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	ok, err := h.shares.CanReadSharedFile(c.UserContext(), c.Params("token"), file)
+	if err != nil {
+		return shareError(c, err)
+	}
+	if !ok {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+	return c.SendFile("./storage" + file)
+}
+
+func shareError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, application.ErrShareNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
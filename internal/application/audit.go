@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strconv"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrRevisionNotFound is returned when reverting to a revision the entity
+// never had.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// recordAudit appends the changes to the history of an entity. The change
+// itself has already been stored by then, but a missing entry would leave a
+// gap that RevertCoin cannot undo, so the error is returned to the caller.
+func (s *CoinService) recordAudit(ctx context.Context, entityType, entityID, operation string, changes []domain.FieldChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	entry := &domain.AuditEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Actor:      domain.ActorFromContext(ctx),
+		Operation:  operation,
+		Changes:    changes,
+	}
+	if err := s.repo.AppendAuditEntry(ctx, entry); err != nil {
+		slog.Error("Failed to record audit entry", "entity_type", entityType, "entity_id", entityID, "operation", operation, "error", err)
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// recordCoinChange records the fields that differ between two versions of
+// a coin.
+func (s *CoinService) recordCoinChange(ctx context.Context, operation string, before, after *domain.Coin) error {
+	changes, err := domain.DiffCoins(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to diff coin: %w", err)
+	}
+	return s.recordAudit(ctx, domain.AuditEntityCoin, after.ID.String(), operation, changes)
+}
+
+// GetCoinHistory returns the recorded revisions of a coin, oldest first.
+func (s *CoinService) GetCoinHistory(ctx context.Context, id uuid.UUID) ([]*domain.AuditEntry, error) {
+	return s.repo.ListAuditEntries(ctx, domain.AuditEntityCoin, id.String())
+}
+
+// GetGroupHistory returns the recorded revisions of a group, oldest first.
+func (s *CoinService) GetGroupHistory(ctx context.Context, id int) ([]*domain.AuditEntry, error) {
+	return s.repo.ListAuditEntries(ctx, domain.AuditEntityGroup, strconv.Itoa(id))
+}
+
+// RevertCoin brings a coin back to its state right after the given revision
+// (0 for before the first recorded change) by undoing every later revision,
+// newest first. Image rotations are undone by rotating back. The revert is
+// itself recorded as a new revision, so it can be undone too.
+func (s *CoinService) RevertCoin(ctx context.Context, id uuid.UUID, revision int) (*domain.Coin, error) {
+	coin, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin: %w", err)
+	}
+
+	entries, err := s.GetCoinHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if revision < 0 || revision > len(entries) {
+		return nil, fmt.Errorf("%w: coin has %d revisions", ErrRevisionNotFound, len(entries))
+	}
+
+	values := make(map[string]json.RawMessage)
+	rotations := make(map[string]float64)
+	for i := len(entries) - 1; i >= 0 && entries[i].Revision > revision; i-- {
+		for _, c := range entries[i].Changes {
+			if side, ok := rotationSide(c.Field); ok {
+				var angle float64
+				if err := json.Unmarshal(c.After, &angle); err != nil {
+					return nil, fmt.Errorf("invalid rotation in revision %d: %w", entries[i].Revision, err)
+				}
+				rotations[side] -= angle
+				continue
+			}
+			values[c.Field] = c.Before
+		}
+	}
+
+	reverted, err := domain.RestoreCoinFields(coin, values)
+	if err != nil {
+		return nil, err
+	}
+	reverted.ID = coin.ID
+	reverted.Images = coin.Images
+	reverted.GalleryImages = coin.GalleryImages
+	reverted.Status = coin.Status
+	reverted.CreatedAt = coin.CreatedAt
+
+	if err := s.repo.Update(ctx, reverted); err != nil {
+		return nil, fmt.Errorf("failed to update coin: %w", err)
+	}
+	if reverted.Tags, err = s.setCoinTags(ctx, id, coin.Tags, reverted.Tags); err != nil {
+		return nil, err
+	}
+
+	changes, err := domain.DiffCoins(coin, reverted)
+	if err != nil {
+		return nil, err
+	}
+	for _, side := range []string{"front", "back"} {
+		angle := rotations[side]
+		if angle == 0 {
+			continue
+		}
+		if err := s.rotateImage(coin, side, angle); err != nil {
+			return nil, err
+		}
+		changes = append(changes, rotationChange(side, angle))
+	}
+	if err := s.recordAudit(ctx, domain.AuditEntityCoin, id.String(), domain.AuditOpRevert, changes); err != nil {
+		return nil, err
+	}
+
+	return reverted, nil
+}
+
+func rotationChange(side string, angle float64) domain.FieldChange {
+	after, _ := json.Marshal(angle)
+	return domain.FieldChange{Field: domain.RotationField(side), Before: json.RawMessage("null"), After: after}
+}
+
+func rotationSide(field string) (string, bool) {
+	for _, side := range []string{"front", "back"} {
+		if field == domain.RotationField(side) {
+			return side, true
+		}
+	}
+	return "", false
+}
+
+// snapshotCoin copies a coin before it is changed in place. Provenance is
+// updated in place, so it gets its own copy.
+func snapshotCoin(coin *domain.Coin) *domain.Coin {
+	snapshot := *coin
+	snapshot.Provenance = maps.Clone(coin.Provenance)
+	return &snapshot
+}
+
+// groupByID returns nil if the group is not in groups.
+func groupByID(groups []*domain.Group, id int) *domain.Group {
+	for _, g := range groups {
+		if g.ID == id {
+			return g
+		}
+	}
+	return nil
+}
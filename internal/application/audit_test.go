@@ -0,0 +1,161 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func change(field string, before, after any) domain.FieldChange {
+	b, _ := json.Marshal(before)
+	a, _ := json.Marshal(after)
+	return domain.FieldChange{Field: field, Before: b, After: a}
+}
+
+func TestRevertCoin(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	history := []*domain.AuditEntry{
+		{Revision: 1, Operation: domain.AuditOpReanalyze, Changes: []domain.FieldChange{
+			change("name", "", "25 Pesetas"),
+			change("year", 0, 1957),
+		}},
+		{Revision: 2, Operation: domain.AuditOpUpdate, Changes: []domain.FieldChange{
+			change("name", "25 Pesetas", "25 Ptas"),
+			change("grade", "", "MBC"),
+		}},
+		{Revision: 3, Operation: domain.AuditOpRotateImage, Changes: []domain.FieldChange{
+			change("front_image_rotation", nil, 90),
+		}},
+		{Revision: 4, Operation: domain.AuditOpMarkSold, Changes: []domain.FieldChange{
+			change("sold_price", 0, 12.5),
+		}},
+	}
+	current := func() *domain.Coin {
+		return &domain.Coin{
+			ID:        id,
+			Name:      "25 Ptas",
+			Year:      mustYear(1957),
+			Grade:     mustGrade("MBC"),
+			SoldPrice: 12.5,
+			Status:    domain.CoinStatusReady,
+			Images:    []domain.CoinImage{{Side: "front", ImageType: "crop", Path: "front.png"}},
+		}
+	}
+
+	t.Run("Undoes Later Revisions", func(t *testing.T) {
+		service, mockRepo, _, mockImageService, _, _, _, _, _ := setupTest(t)
+		mockRepo.EXPECT().GetByID(ctx, id).Return(current(), nil)
+		mockRepo.EXPECT().ListAuditEntries(ctx, domain.AuditEntityCoin, id.String()).Return(history, nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		mockImageService.EXPECT().Rotate("front.png", -90.0).Return(nil)
+		mockImageService.EXPECT().GenerateThumbnail("front.png", 300).Return("front_thumb.png", nil)
+
+		var recorded *domain.AuditEntry
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			recorded = e
+			return nil
+		})
+
+		coin, err := service.RevertCoin(ctx, id, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "25 Pesetas", coin.Name)
+		assert.Equal(t, 1957, coin.Year.Int())
+		assert.Equal(t, "", coin.Grade.String())
+		assert.Equal(t, 0.0, coin.SoldPrice)
+		// Kept as they are: not part of the history
+		assert.Equal(t, id, coin.ID)
+		assert.Equal(t, domain.CoinStatusReady, coin.Status)
+		assert.Len(t, coin.Images, 1)
+
+		if assert.NotNil(t, recorded) {
+			assert.Equal(t, domain.AuditOpRevert, recorded.Operation)
+			fields := make([]string, len(recorded.Changes))
+			for i, c := range recorded.Changes {
+				fields[i] = c.Field
+			}
+			assert.Equal(t, []string{"grade", "name", "sold_price", "front_image_rotation"}, fields)
+		}
+	})
+
+	t.Run("To Before First Change", func(t *testing.T) {
+		service, mockRepo, _, mockImageService, _, _, _, _, _ := setupTest(t)
+		mockRepo.EXPECT().GetByID(ctx, id).Return(current(), nil)
+		mockRepo.EXPECT().ListAuditEntries(ctx, domain.AuditEntityCoin, id.String()).Return(history, nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		mockImageService.EXPECT().Rotate("front.png", -90.0).Return(nil)
+		mockImageService.EXPECT().GenerateThumbnail("front.png", 300).Return("front_thumb.png", nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
+
+		coin, err := service.RevertCoin(ctx, id, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "", coin.Name)
+		assert.Equal(t, 0, coin.Year.Int())
+	})
+
+	t.Run("Unknown Revision", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		mockRepo.EXPECT().GetByID(ctx, id).Return(current(), nil)
+		mockRepo.EXPECT().ListAuditEntries(ctx, domain.AuditEntityCoin, id.String()).Return(history, nil)
+
+		_, err := service.RevertCoin(ctx, id, 5)
+		assert.ErrorIs(t, err, application.ErrRevisionNotFound)
+	})
+}
+
+func TestAuditRecording(t *testing.T) {
+	ctx := domain.WithActor(context.Background(), "ana")
+	id := uuid.New()
+
+	t.Run("Mark As Sold", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id, Name: "Duro"}, nil)
+		mockRepo.EXPECT().MarkAsSold(ctx, id, gomock.Any(), 40.0, "eBay").Return(&domain.Coin{ID: id, Name: "Duro", SoldPrice: 40, SaleChannel: "eBay"}, nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, "ana", e.Actor)
+			assert.Equal(t, domain.AuditOpMarkSold, e.Operation)
+			assert.Equal(t, []domain.FieldChange{change("sale_channel", "", "eBay"), change("sold_price", 0, 40)}, e.Changes)
+			return nil
+		})
+
+		_, err := service.MarkCoinAsSold(ctx, id, 40, "eBay")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Audit Failure Is Returned", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id}, nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(assert.AnError)
+
+		_, err := service.UpdateCoin(ctx, id, application.UpdateCoinParams{Name: "Duro"})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("No Changes No Entry", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id, Name: "Duro"}, nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		_, err := service.UpdateCoin(ctx, id, application.UpdateCoinParams{Name: "Duro"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestGetCoinHistory(t *testing.T) {
+	service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+	ctx := context.Background()
+	id := uuid.New()
+	entries := []*domain.AuditEntry{{Revision: 1, Changes: []domain.FieldChange{{Field: "name", Before: json.RawMessage(`""`), After: json.RawMessage(`"Duro"`)}}}}
+	mockRepo.EXPECT().ListAuditEntries(ctx, domain.AuditEntityCoin, id.String()).Return(entries, nil)
+
+	history, err := service.GetCoinHistory(ctx, id)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, history)
+}
@@ -0,0 +1,364 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is the shortest password accepted for an account.
+const MinPasswordLength = 8
+
+// APITokenPrefix marks API tokens, so a bearer token can be told apart from
+// a session token without a database lookup.
+const APITokenPrefix = "nma_"
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrUnauthenticated    = errors.New("not logged in")
+	ErrUserExists         = errors.New("username already taken")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrWeakPassword       = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	ErrLastAdmin          = errors.New("cannot delete the last admin")
+	ErrInvalidScope       = errors.New("invalid scope")
+	ErrTokenNotFound      = errors.New("api token not found")
+	ErrCollectionNotFound = errors.New("collection not found")
+)
+
+// Identity is who is behind a request and what they may do. A login session
+// grants every scope of its user; an API token only the scopes it was
+// created with.
+type Identity struct {
+	User   *domain.User
+	Scopes []string
+	// Token is set when the request used an API token.
+	Token *domain.APIToken
+}
+
+// Can reports whether the identity has the scope.
+func (i *Identity) Can(scope string) bool {
+	return domain.HasScope(i.Scopes, scope)
+}
+
+// AuthService manages local accounts, their login sessions and API tokens.
+// Both kinds of token are opaque random strings handed to the client; only
+// their SHA-256 is stored.
+type AuthService struct {
+	repo       domain.UserRepository
+	sessionTTL time.Duration
+}
+
+func NewAuthService(repo domain.UserRepository, sessionTTL time.Duration) *AuthService {
+	return &AuthService{
+		repo:       repo,
+		sessionTTL: sessionTTL,
+	}
+}
+
+// Bootstrap creates the configured admin account if it does not exist yet,
+// in the default collection that holds the data from before collections.
+// An existing account is left alone, so changing its password through the
+// API survives restarts.
+func (s *AuthService) Bootstrap(ctx context.Context, username, password string) error {
+	if username == "" {
+		n, err := s.repo.Count(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			slog.Warn("No user accounts exist and no admin is configured; nobody can log in")
+		}
+		return nil
+	}
+
+	existing, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	if _, err := s.CreateUser(ctx, username, password, true, &domain.DefaultCollectionID); err != nil {
+		return fmt.Errorf("failed to create admin %q: %w", username, err)
+	}
+	slog.Info("Created admin account", "username", username)
+	return nil
+}
+
+// Login checks the credentials and opens a session. It returns the session
+// token to hand to the client.
+func (s *AuthService) Login(ctx context.Context, username, password string) (string, *domain.User, error) {
+	user, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return "", nil, err
+	}
+	if user == nil || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return "", nil, ErrInvalidCredentials
+	}
+
+	// Good moment to drop stale sessions; failing to do so is harmless
+	if err := s.repo.DeleteExpiredSessions(ctx); err != nil {
+		slog.Error("Failed to delete expired sessions", "error", err)
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := s.repo.CreateSession(ctx, hashSessionToken(token), user.ID, time.Now().Add(s.sessionTTL)); err != nil {
+		return "", nil, err
+	}
+	return token, user, nil
+}
+
+// Authenticate resolves a session or API token.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	if token == "" {
+		return nil, ErrUnauthenticated
+	}
+	if strings.HasPrefix(token, APITokenPrefix) {
+		return s.authenticateAPIToken(ctx, token)
+	}
+
+	user, err := s.repo.GetSessionUser(ctx, hashSessionToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUnauthenticated
+	}
+	return &Identity{User: user, Scopes: userScopes(user)}, nil
+}
+
+func (s *AuthService) authenticateAPIToken(ctx context.Context, token string) (*Identity, error) {
+	apiToken, err := s.repo.GetAPIToken(ctx, hashSessionToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if apiToken == nil {
+		return nil, ErrUnauthenticated
+	}
+	user, err := s.repo.GetByID(ctx, apiToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	if err := s.repo.TouchAPIToken(ctx, apiToken.ID); err != nil {
+		slog.Error("Failed to update api token last use", "token_id", apiToken.ID, "error", err)
+	}
+
+	// A token never grants more than its user has now
+	allowed := userScopes(user)
+	var scopes []string
+	for _, scope := range apiToken.Scopes {
+		if slices.Contains(allowed, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &Identity{User: user, Scopes: scopes, Token: apiToken}, nil
+}
+
+// Logout ends a session.
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	return s.repo.DeleteSession(ctx, hashSessionToken(token))
+}
+
+// CreateUser adds an account to an existing collection, or to a new one
+// named after the user when collectionID is nil.
+func (s *AuthService) CreateUser(ctx context.Context, username, password string, isAdmin bool, collectionID *uuid.UUID) (*domain.User, error) {
+	username = strings.TrimSpace(username)
+	if len(password) < MinPasswordLength {
+		return nil, ErrWeakPassword
+	}
+
+	existing, err := s.repo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrUserExists
+	}
+
+	var collection *domain.Collection
+	if collectionID != nil {
+		collection, err = s.repo.GetCollection(ctx, *collectionID)
+		if err != nil {
+			return nil, err
+		}
+		if collection == nil {
+			return nil, ErrCollectionNotFound
+		}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	if collection == nil {
+		collection, err = s.repo.CreateCollection(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+	}
+	user := &domain.User{
+		Username:     username,
+		PasswordHash: string(hash),
+		IsAdmin:      isAdmin,
+		CollectionID: collection.ID,
+	}
+	if err := s.repo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *AuthService) ListUsers(ctx context.Context) ([]*domain.User, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *AuthService) ListCollections(ctx context.Context) ([]*domain.Collection, error) {
+	return s.repo.ListCollections(ctx)
+}
+
+// DeleteUser removes an account and, through the foreign keys, its sessions
+// and API tokens.
+// The last admin cannot be deleted.
+func (s *AuthService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var target *domain.User
+	admins := 0
+	for _, u := range users {
+		if u.ID == id {
+			target = u
+		}
+		if u.IsAdmin {
+			admins++
+		}
+	}
+	if target == nil {
+		return ErrUserNotFound
+	}
+	if target.IsAdmin && admins == 1 {
+		return ErrLastAdmin
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ChangePassword sets a new password after checking the current one, and
+// signs out every session of the user. API tokens keep working.
+func (s *AuthService) ChangePassword(ctx context.Context, id uuid.UUID, current, next string) error {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)) != nil {
+		return ErrInvalidCredentials
+	}
+	if len(next) < MinPasswordLength {
+		return ErrWeakPassword
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(next), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.repo.UpdatePassword(ctx, id, string(hash)); err != nil {
+		return err
+	}
+	return s.repo.DeleteUserSessions(ctx, id)
+}
+
+// CreateAPIToken issues a token for the user and returns it. The token is
+// not stored and cannot be shown again.
+func (s *AuthService) CreateAPIToken(ctx context.Context, user *domain.User, name string, scopes []string, expiresAt *time.Time) (string, *domain.APIToken, error) {
+	if len(scopes) == 0 {
+		return "", nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	allowed := userScopes(user)
+	for _, scope := range scopes {
+		if !slices.Contains(domain.Scopes, scope) {
+			return "", nil, fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+		if !slices.Contains(allowed, scope) {
+			return "", nil, fmt.Errorf("%w: %q requires an admin account", ErrInvalidScope, scope)
+		}
+	}
+
+	secret, err := newSessionToken()
+	if err != nil {
+		return "", nil, err
+	}
+	token := APITokenPrefix + secret
+
+	apiToken := &domain.APIToken{
+		UserID:    user.ID,
+		Name:      strings.TrimSpace(name),
+		Scopes:    slices.Compact(slices.Sorted(slices.Values(scopes))),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.CreateAPIToken(ctx, apiToken, hashSessionToken(token)); err != nil {
+		return "", nil, err
+	}
+	return token, apiToken, nil
+}
+
+func (s *AuthService) ListAPITokens(ctx context.Context, userID uuid.UUID) ([]*domain.APIToken, error) {
+	return s.repo.ListAPITokens(ctx, userID)
+}
+
+// RevokeAPIToken deletes one of the user's tokens.
+func (s *AuthService) RevokeAPIToken(ctx context.Context, userID, id uuid.UUID) error {
+	found, err := s.repo.DeleteAPIToken(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrTokenNotFound
+	}
+	return nil
+}
+
+// userScopes are the scopes a user holds when logged in.
+func userScopes(user *domain.User) []string {
+	if user.IsAdmin {
+		return domain.Scopes
+	}
+	return []string{domain.ScopeRead, domain.ScopeWrite, domain.ScopeExport}
+}
+
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
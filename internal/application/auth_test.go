@@ -0,0 +1,326 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func setupAuthTest(t *testing.T) (*application.AuthService, *mocks.MockUserRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockUserRepository(ctrl)
+	return application.NewAuthService(repo, time.Hour), repo
+}
+
+func userWithPassword(t *testing.T, username, password string, isAdmin bool) *domain.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.NoError(t, err)
+	return &domain.User{ID: uuid.New(), Username: username, PasswordHash: string(hash), IsAdmin: isAdmin}
+}
+
+func TestAuthBootstrap(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Creates Admin", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByUsername(ctx, "admin").Return(nil, nil).Times(2)
+		repo.EXPECT().GetCollection(ctx, domain.DefaultCollectionID).Return(&domain.Collection{ID: domain.DefaultCollectionID, Name: "Default"}, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, u *domain.User) error {
+			assert.Equal(t, "admin", u.Username)
+			assert.True(t, u.IsAdmin)
+			assert.Equal(t, domain.DefaultCollectionID, u.CollectionID)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("secret-password")))
+			return nil
+		})
+
+		assert.NoError(t, service.Bootstrap(ctx, "admin", "secret-password"))
+	})
+
+	t.Run("Keeps Existing Admin", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByUsername(ctx, "admin").Return(&domain.User{Username: "admin"}, nil)
+
+		assert.NoError(t, service.Bootstrap(ctx, "admin", "another-password"))
+	})
+
+	t.Run("Rejects Short Password", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByUsername(ctx, "admin").Return(nil, nil)
+
+		err := service.Bootstrap(ctx, "admin", "short")
+		assert.ErrorIs(t, err, application.ErrWeakPassword)
+	})
+
+	t.Run("Not Configured", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().Count(ctx).Return(int64(0), nil)
+
+		assert.NoError(t, service.Bootstrap(ctx, "", ""))
+	})
+}
+
+func TestAuthLogin(t *testing.T) {
+	ctx := context.Background()
+	user := userWithPassword(t, "ana", "correct-horse", false)
+
+	t.Run("Success", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByUsername(ctx, "ana").Return(user, nil)
+		repo.EXPECT().DeleteExpiredSessions(ctx).Return(nil)
+
+		var storedHash string
+		repo.EXPECT().CreateSession(ctx, gomock.Any(), user.ID, gomock.Any()).DoAndReturn(func(ctx context.Context, tokenHash string, userID uuid.UUID, expiresAt time.Time) error {
+			storedHash = tokenHash
+			assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+			return nil
+		})
+
+		token, got, err := service.Login(ctx, "ana", "correct-horse")
+		assert.NoError(t, err)
+		assert.Equal(t, user, got)
+		assert.NotEmpty(t, token)
+		// Only a hash of the token is stored
+		assert.NotEqual(t, token, storedHash)
+		assert.Len(t, storedHash, 64)
+
+		repo.EXPECT().GetSessionUser(ctx, storedHash).Return(user, nil)
+		identity, err := service.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, user, identity.User)
+		assert.Nil(t, identity.Token)
+		assert.True(t, identity.Can(domain.ScopeWrite))
+		assert.False(t, identity.Can(domain.ScopeAdmin))
+	})
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByUsername(ctx, "ana").Return(user, nil)
+
+		_, _, err := service.Login(ctx, "ana", "wrong")
+		assert.ErrorIs(t, err, application.ErrInvalidCredentials)
+	})
+
+	t.Run("Unknown User", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByUsername(ctx, "bob").Return(nil, nil)
+
+		_, _, err := service.Login(ctx, "bob", "correct-horse")
+		assert.ErrorIs(t, err, application.ErrInvalidCredentials)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("No Token", func(t *testing.T) {
+		service, _ := setupAuthTest(t)
+		_, err := service.Authenticate(ctx, "")
+		assert.ErrorIs(t, err, application.ErrUnauthenticated)
+	})
+
+	t.Run("Expired Or Unknown", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetSessionUser(ctx, gomock.Any()).Return(nil, nil)
+
+		_, err := service.Authenticate(ctx, "stale")
+		assert.ErrorIs(t, err, application.ErrUnauthenticated)
+	})
+}
+
+func TestCreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Username Taken", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByUsername(ctx, "ana").Return(&domain.User{Username: "ana"}, nil)
+
+		_, err := service.CreateUser(ctx, " ana ", "long-enough", false, nil)
+		assert.ErrorIs(t, err, application.ErrUserExists)
+	})
+
+	t.Run("New Collection", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		collection := &domain.Collection{ID: uuid.New(), Name: "ana"}
+		repo.EXPECT().GetByUsername(ctx, "ana").Return(nil, nil)
+		repo.EXPECT().CreateCollection(ctx, "ana").Return(collection, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		user, err := service.CreateUser(ctx, "ana", "long-enough", false, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, collection.ID, user.CollectionID)
+	})
+
+	t.Run("Joins Collection", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		collection := &domain.Collection{ID: uuid.New(), Name: "family"}
+		repo.EXPECT().GetByUsername(ctx, "ana").Return(nil, nil)
+		repo.EXPECT().GetCollection(ctx, collection.ID).Return(collection, nil)
+		repo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+		user, err := service.CreateUser(ctx, "ana", "long-enough", false, &collection.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, collection.ID, user.CollectionID)
+	})
+
+	t.Run("Unknown Collection", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		id := uuid.New()
+		repo.EXPECT().GetByUsername(ctx, "ana").Return(nil, nil)
+		repo.EXPECT().GetCollection(ctx, id).Return(nil, nil)
+
+		_, err := service.CreateUser(ctx, "ana", "long-enough", false, &id)
+		assert.ErrorIs(t, err, application.ErrCollectionNotFound)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	admin := &domain.User{ID: uuid.New(), Username: "admin", IsAdmin: true}
+	member := &domain.User{ID: uuid.New(), Username: "ana"}
+
+	t.Run("Success", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().List(ctx).Return([]*domain.User{admin, member}, nil)
+		repo.EXPECT().Delete(ctx, member.ID).Return(nil)
+
+		assert.NoError(t, service.DeleteUser(ctx, member.ID))
+	})
+
+	t.Run("Last Admin", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().List(ctx).Return([]*domain.User{admin, member}, nil)
+
+		err := service.DeleteUser(ctx, admin.ID)
+		assert.ErrorIs(t, err, application.ErrLastAdmin)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().List(ctx).Return([]*domain.User{admin}, nil)
+
+		err := service.DeleteUser(ctx, uuid.New())
+		assert.ErrorIs(t, err, application.ErrUserNotFound)
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	user := userWithPassword(t, "ana", "correct-horse", false)
+
+	t.Run("Success Signs Out Everywhere", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+		repo.EXPECT().UpdatePassword(ctx, user.ID, gomock.Any()).DoAndReturn(func(ctx context.Context, id uuid.UUID, hash string) error {
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("battery-staple")))
+			return nil
+		})
+		repo.EXPECT().DeleteUserSessions(ctx, user.ID).Return(nil)
+
+		assert.NoError(t, service.ChangePassword(ctx, user.ID, "correct-horse", "battery-staple"))
+	})
+
+	t.Run("Wrong Current Password", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+
+		err := service.ChangePassword(ctx, user.ID, "wrong", "battery-staple")
+		assert.ErrorIs(t, err, application.ErrInvalidCredentials)
+	})
+
+	t.Run("Too Short", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+
+		err := service.ChangePassword(ctx, user.ID, "correct-horse", "short")
+		assert.ErrorIs(t, err, application.ErrWeakPassword)
+	})
+}
+
+func TestAPITokens(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.User{ID: uuid.New(), Username: "ana"}
+	admin := &domain.User{ID: uuid.New(), Username: "admin", IsAdmin: true}
+
+	t.Run("Create And Authenticate", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+
+		var storedHash string
+		repo.EXPECT().CreateAPIToken(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, tok *domain.APIToken, tokenHash string) error {
+			assert.Equal(t, user.ID, tok.UserID)
+			assert.Equal(t, "backup script", tok.Name)
+			assert.Equal(t, []string{"export", "read"}, tok.Scopes)
+			storedHash = tokenHash
+			tok.ID = uuid.New()
+			return nil
+		})
+
+		token, apiToken, err := service.CreateAPIToken(ctx, user, " backup script ", []string{"read", "export", "read"}, nil)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(token, application.APITokenPrefix))
+		assert.NotContains(t, storedHash, token)
+
+		repo.EXPECT().GetAPIToken(ctx, storedHash).Return(apiToken, nil)
+		repo.EXPECT().GetByID(ctx, user.ID).Return(user, nil)
+		repo.EXPECT().TouchAPIToken(ctx, apiToken.ID).Return(nil)
+
+		identity, err := service.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, user, identity.User)
+		assert.Equal(t, apiToken, identity.Token)
+		assert.True(t, identity.Can(domain.ScopeRead))
+		assert.True(t, identity.Can(domain.ScopeExport))
+		assert.False(t, identity.Can(domain.ScopeWrite))
+	})
+
+	t.Run("Unknown Scope", func(t *testing.T) {
+		service, _ := setupAuthTest(t)
+		_, _, err := service.CreateAPIToken(ctx, user, "x", []string{"delete"}, nil)
+		assert.ErrorIs(t, err, application.ErrInvalidScope)
+	})
+
+	t.Run("Admin Scope Needs Admin", func(t *testing.T) {
+		service, _ := setupAuthTest(t)
+		_, _, err := service.CreateAPIToken(ctx, user, "x", []string{domain.ScopeAdmin}, nil)
+		assert.ErrorIs(t, err, application.ErrInvalidScope)
+	})
+
+	t.Run("Scopes Follow The User", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		// Created while the user was an admin
+		apiToken := &domain.APIToken{ID: uuid.New(), UserID: admin.ID, Scopes: []string{domain.ScopeAdmin, domain.ScopeRead}}
+		demoted := &domain.User{ID: admin.ID, Username: "admin"}
+		repo.EXPECT().GetAPIToken(ctx, gomock.Any()).Return(apiToken, nil)
+		repo.EXPECT().GetByID(ctx, admin.ID).Return(demoted, nil)
+		repo.EXPECT().TouchAPIToken(ctx, apiToken.ID).Return(nil)
+
+		identity, err := service.Authenticate(ctx, application.APITokenPrefix+"abc")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{domain.ScopeRead}, identity.Scopes)
+	})
+
+	t.Run("Revoked Or Expired", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		repo.EXPECT().GetAPIToken(ctx, gomock.Any()).Return(nil, nil)
+
+		_, err := service.Authenticate(ctx, application.APITokenPrefix+"abc")
+		assert.ErrorIs(t, err, application.ErrUnauthenticated)
+	})
+
+	t.Run("Revoke Someone Elses Token", func(t *testing.T) {
+		service, repo := setupAuthTest(t)
+		id := uuid.New()
+		repo.EXPECT().DeleteAPIToken(ctx, user.ID, id).Return(false, nil)
+
+		err := service.RevokeAPIToken(ctx, user.ID, id)
+		assert.ErrorIs(t, err, application.ErrTokenNotFound)
+	})
+}
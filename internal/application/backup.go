@@ -0,0 +1,569 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Backup archive layout:
+//
+//	manifest.json              format, version, counts and a checksum per entry
+//	data/<table>.json          the database rows, as domain JSON
+//	files/coins/<id>/<name>    every image referenced by a coin
+//	files/groups/<id>/<name>   every image referenced by a group
+const (
+	BackupFormat        = "numismaticapp-backup"
+	BackupFormatVersion = 1
+
+	backupManifestName = "manifest.json"
+)
+
+const (
+	backupDataGroups        = "data/groups.json"
+	backupDataGroupImages   = "data/group_images.json"
+	backupDataCoins         = "data/coins.json"
+	backupDataImages        = "data/coin_images.json"
+	backupDataGalleryImages = "data/coin_gallery_images.json"
+	backupDataLinks         = "data/coin_links.json"
+)
+
+type BackupManifest struct {
+	Format       string       `json:"format"`
+	Version      int          `json:"version"`
+	CreatedAt    time.Time    `json:"created_at"`
+	Counts       BackupCounts `json:"counts"`
+	Entries      []BackupFile `json:"entries"`
+	MissingFiles []string     `json:"missing_files,omitempty"` // referenced by a row but not found in storage
+}
+
+type BackupCounts struct {
+	Groups        int `json:"groups"`
+	GroupImages   int `json:"group_images"`
+	Coins         int `json:"coins"`
+	Images        int `json:"images"`
+	GalleryImages int `json:"gallery_images"`
+	Links         int `json:"links"`
+	Files         int `json:"files"`
+}
+
+// BackupFile is one archive entry. Path is the storage path the rows used
+// when the backup was taken; it is empty for data files.
+type BackupFile struct {
+	Name   string `json:"name"`
+	Path   string `json:"path,omitempty"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+type RestoreReport struct {
+	Version       int      `json:"version"`
+	GroupsCreated int      `json:"groups_created"`
+	GroupsMatched int      `json:"groups_matched"`
+	CoinsCreated  int      `json:"coins_created"`
+	CoinsSkipped  int      `json:"coins_skipped"` // already present in this instance
+	Images        int      `json:"images"`
+	GalleryImages int      `json:"gallery_images"`
+	GroupImages   int      `json:"group_images"`
+	Links         int      `json:"links"`
+	Files         int      `json:"files"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// ExportBackup writes the whole collection as a ZIP archive: every row needed
+// to rebuild it plus the image files those rows point to.
+func (s *CoinService) ExportBackup(ctx context.Context, w io.Writer) error {
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list groups: %w", err)
+	}
+	var groupImages []domain.GroupImage
+	for _, g := range groups {
+		imgs, err := s.groupRepo.ListImages(ctx, g.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list group images: %w", err)
+		}
+		groupImages = append(groupImages, imgs...)
+	}
+
+	coins, err := s.allCoins(ctx, domain.CoinFilter{})
+	if err != nil {
+		return err
+	}
+	images, err := s.repo.GetAllImages(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list images: %w", err)
+	}
+	links, err := s.repo.GetAllLinks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list links: %w", err)
+	}
+	var gallery []domain.CoinGalleryImage
+	for _, c := range coins {
+		imgs, err := s.repo.ListGalleryImages(ctx, c.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list gallery images: %w", err)
+		}
+		gallery = append(gallery, imgs...)
+		// Images travel in their own files
+		c.Images = nil
+		c.GalleryImages = nil
+	}
+
+	manifest := BackupManifest{
+		Format:    BackupFormat,
+		Version:   BackupFormatVersion,
+		CreatedAt: time.Now().UTC(),
+		Counts: BackupCounts{
+			Groups:        len(groups),
+			GroupImages:   len(groupImages),
+			Coins:         len(coins),
+			Images:        len(images),
+			GalleryImages: len(gallery),
+			Links:         len(links),
+		},
+	}
+
+	zw := zip.NewWriter(w)
+	written := make(map[string]bool)
+	addFile := func(storagePath, dir string) error {
+		if storagePath == "" || written[storagePath] {
+			return nil
+		}
+		written[storagePath] = true
+		data, err := s.storage.ReadFile(storagePath)
+		if err != nil {
+			slog.Warn("Backup: referenced file not found", "path", storagePath, "error", err)
+			manifest.MissingFiles = append(manifest.MissingFiles, storagePath)
+			return nil
+		}
+		entry, err := writeBackupEntry(zw, path.Join("files", dir, filepath.Base(storagePath)), data, zip.Store)
+		if err != nil {
+			return err
+		}
+		entry.Path = storagePath
+		manifest.Entries = append(manifest.Entries, entry)
+		manifest.Counts.Files++
+		return nil
+	}
+
+	for _, img := range groupImages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := addFile(img.Path, fmt.Sprintf("groups/%d", img.GroupID)); err != nil {
+			return err
+		}
+	}
+	for _, img := range images {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := addFile(img.Path, "coins/"+img.CoinID.String()); err != nil {
+			return err
+		}
+	}
+	for _, img := range gallery {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := addFile(img.Path, "coins/"+img.CoinID.String()); err != nil {
+			return err
+		}
+	}
+
+	tables := []struct {
+		name string
+		rows any
+	}{
+		{backupDataGroups, groups},
+		{backupDataGroupImages, groupImages},
+		{backupDataCoins, coins},
+		{backupDataImages, images},
+		{backupDataGalleryImages, gallery},
+		{backupDataLinks, links},
+	}
+	for _, t := range tables {
+		data, err := json.MarshalIndent(t.rows, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", t.name, err)
+		}
+		entry, err := writeBackupEntry(zw, t.name, data, zip.Deflate)
+		if err != nil {
+			return err
+		}
+		manifest.Entries = append(manifest.Entries, entry)
+	}
+
+	// The manifest goes last so it can list the checksums of everything else
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if _, err := writeBackupEntry(zw, backupManifestName, data, zip.Deflate); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish backup archive: %w", err)
+	}
+
+	slog.Info("Backup exported", "coins", manifest.Counts.Coins, "groups", manifest.Counts.Groups, "files", manifest.Counts.Files, "missing", len(manifest.MissingFiles))
+	return nil
+}
+
+func writeBackupEntry(zw *zip.Writer, name string, data []byte, method uint16) (BackupFile, error) {
+	dst, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: time.Now()})
+	if err != nil {
+		return BackupFile{}, fmt.Errorf("failed to add %s to backup: %w", name, err)
+	}
+	if _, err := dst.Write(data); err != nil {
+		return BackupFile{}, fmt.Errorf("failed to write %s to backup: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	return BackupFile{Name: name, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data))}, nil
+}
+
+// BackupArchive is a backup whose entries have all been checked against its
+// manifest, with the rows decoded.
+type BackupArchive struct {
+	Manifest    BackupManifest
+	Groups      []*domain.Group
+	GroupImages []domain.GroupImage
+	Coins       []*domain.Coin
+	Images      []domain.CoinImage
+	Gallery     []domain.CoinGalleryImage
+	Links       []*domain.CoinLink
+
+	entries map[string]*zip.File
+	byPath  map[string]BackupFile
+}
+
+// OpenBackup reads an archive written by ExportBackup. Every entry is
+// checked against the manifest, so a damaged archive is rejected as a whole.
+func OpenBackup(r io.ReaderAt, size int64) (*BackupArchive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup archive: %w", err)
+	}
+	a := &BackupArchive{
+		entries: make(map[string]*zip.File, len(zr.File)),
+		byPath:  make(map[string]BackupFile),
+	}
+	for _, f := range zr.File {
+		a.entries[f.Name] = f
+	}
+
+	manifestFile, ok := a.entries[backupManifestName]
+	if !ok {
+		return nil, fmt.Errorf("invalid backup: %s not found", backupManifestName)
+	}
+	raw, err := readZipEntry(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &a.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if a.Manifest.Format != BackupFormat {
+		return nil, fmt.Errorf("invalid backup: unknown format %q", a.Manifest.Format)
+	}
+	if a.Manifest.Version < 1 || a.Manifest.Version > BackupFormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d (this server reads up to %d)", a.Manifest.Version, BackupFormatVersion)
+	}
+
+	for _, e := range a.Manifest.Entries {
+		f, ok := a.entries[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("invalid backup: %s is listed in the manifest but missing", e.Name)
+		}
+		data, err := readZipEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		if int64(len(data)) != e.Size || hex.EncodeToString(sum[:]) != e.SHA256 {
+			return nil, fmt.Errorf("invalid backup: checksum mismatch for %s", e.Name)
+		}
+		if e.Path != "" {
+			a.byPath[e.Path] = e
+		}
+	}
+
+	tables := []struct {
+		name string
+		dst  any
+	}{
+		{backupDataGroups, &a.Groups},
+		{backupDataGroupImages, &a.GroupImages},
+		{backupDataCoins, &a.Coins},
+		{backupDataImages, &a.Images},
+		{backupDataGalleryImages, &a.Gallery},
+		{backupDataLinks, &a.Links},
+	}
+	for _, t := range tables {
+		f, ok := a.entries[t.name]
+		if !ok {
+			return nil, fmt.Errorf("invalid backup: %s not found", t.name)
+		}
+		data, err := readZipEntry(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, t.dst); err != nil {
+			return nil, fmt.Errorf("invalid backup: failed to decode %s: %w", t.name, err)
+		}
+	}
+	return a, nil
+}
+
+// OpenFile opens the archived copy of a file, by the storage path the rows
+// used when the backup was taken.
+func (a *BackupArchive) OpenFile(storagePath string) (io.ReadCloser, error) {
+	e, ok := a.byPath[storagePath]
+	if !ok {
+		return nil, fmt.Errorf("file %s is not in the backup", storagePath)
+	}
+	rc, err := a.entries[e.Name].Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", e.Name, err)
+	}
+	return rc, nil
+}
+
+// RestoreBackup loads an archive written by ExportBackup. Every entry is
+// checked against the manifest before anything is written. Groups are matched
+// by name, coins that already exist are left untouched, and images are saved
+// again through the storage so their paths fit this instance.
+func (s *CoinService) RestoreBackup(ctx context.Context, r io.ReaderAt, size int64) (*RestoreReport, error) {
+	archive, err := OpenBackup(r, size)
+	if err != nil {
+		return nil, err
+	}
+	groups, groupImages, coins := archive.Groups, archive.GroupImages, archive.Coins
+	images, gallery, links := archive.Images, archive.Gallery, archive.Links
+
+	report := &RestoreReport{Version: archive.Manifest.Version}
+	warn := func(format string, args ...any) {
+		msg := fmt.Sprintf(format, args...)
+		slog.Warn("Restore warning", "detail", msg)
+		report.Warnings = append(report.Warnings, msg)
+	}
+
+	// restoreFile copies an archived file into storage and returns its new path
+	restoreFile := func(oldPath string, save func(name string, content io.Reader) (string, error)) (string, bool) {
+		rc, err := archive.OpenFile(oldPath)
+		if err != nil {
+			warn("%v", err)
+			return "", false
+		}
+		defer func() { _ = rc.Close() }()
+		newPath, err := save(filepath.Base(oldPath), rc)
+		if err != nil {
+			warn("failed to restore %s: %v", oldPath, err)
+			return "", false
+		}
+		report.Files++
+		return newPath, true
+	}
+
+	// 1. Groups, matched by name
+	existingGroups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	groupByName := make(map[string]*domain.Group, len(existingGroups))
+	for _, g := range existingGroups {
+		groupByName[g.Name] = g
+	}
+	groupIDs := make(map[int]int, len(groups))
+	newGroups := make(map[int]bool)
+	for _, g := range groups {
+		if existing, ok := groupByName[g.Name]; ok {
+			report.GroupsMatched++
+			if existing.IsSmart() && !g.IsSmart() {
+				// Its coins are restored without a group
+				warn("group %q is a smart group here; its coins cannot be assigned to it", g.Name)
+				continue
+			}
+			groupIDs[g.ID] = existing.ID
+			continue
+		}
+		var created *domain.Group
+		if g.IsSmart() {
+			created, err = s.groupRepo.CreateSmart(ctx, g.Name, g.Description, *g.Filter)
+		} else {
+			created, err = s.groupRepo.Create(ctx, g.Name, g.Description)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create group %q: %w", g.Name, err)
+		}
+		groupIDs[g.ID] = created.ID
+		groupByName[g.Name] = created
+		newGroups[created.ID] = true
+		report.GroupsCreated++
+	}
+
+	// Created groups go back under their parents once all exist. Groups that
+	// were already here keep their place
+	backupGroups := make(map[int]*domain.Group, len(groups))
+	for _, g := range groups {
+		backupGroups[g.ID] = g
+	}
+	for _, g := range groups {
+		groupID, ok := groupIDs[g.ID]
+		if !ok || !newGroups[groupID] || g.ParentID == nil {
+			continue
+		}
+		parent, ok := backupGroups[*g.ParentID]
+		if !ok {
+			continue
+		}
+		target := groupByName[parent.Name]
+		if target == nil || target.IsSmart() {
+			warn("group %q cannot be put back under %q", g.Name, parent.Name)
+			continue
+		}
+		if err := s.groupRepo.Move(ctx, groupID, &target.ID); err != nil {
+			warn("failed to put group %q under %q: %v", g.Name, parent.Name, err)
+		}
+	}
+
+	// Group images only go into groups this restore created, so restoring
+	// twice does not duplicate them
+	for _, img := range groupImages {
+		groupID, ok := groupIDs[img.GroupID]
+		if !ok || !newGroups[groupID] {
+			continue
+		}
+		newPath, ok := restoreFile(img.Path, func(name string, content io.Reader) (string, error) {
+			return s.storage.SaveGroupFile(groupID, name, content)
+		})
+		if !ok {
+			continue
+		}
+		if err := s.groupRepo.AddImage(ctx, domain.GroupImage{GroupID: groupID, Path: newPath}); err != nil {
+			warn("failed to add image to group %d: %v", groupID, err)
+			continue
+		}
+		report.GroupImages++
+	}
+
+	// 2. Coins that are not here yet, with their images, gallery and links
+	existingCoins, err := s.allCoins(ctx, domain.CoinFilter{})
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[uuid.UUID]bool, len(existingCoins))
+	for _, c := range existingCoins {
+		known[c.ID] = true
+	}
+
+	imagesByCoin := make(map[uuid.UUID][]domain.CoinImage)
+	for _, img := range images {
+		imagesByCoin[img.CoinID] = append(imagesByCoin[img.CoinID], img)
+	}
+	galleryByCoin := make(map[uuid.UUID][]domain.CoinGalleryImage)
+	for _, img := range gallery {
+		galleryByCoin[img.CoinID] = append(galleryByCoin[img.CoinID], img)
+	}
+	linksByCoin := make(map[uuid.UUID][]*domain.CoinLink)
+	for _, l := range links {
+		linksByCoin[l.CoinID] = append(linksByCoin[l.CoinID], l)
+	}
+
+	for _, coin := range coins {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if known[coin.ID] {
+			report.CoinsSkipped++
+			continue
+		}
+		coinID := coin.ID
+		saveToCoin := func(name string, content io.Reader) (string, error) {
+			return s.storage.SaveFile(coinID, name, content)
+		}
+
+		if coin.GroupID != nil {
+			if id, ok := groupIDs[*coin.GroupID]; ok {
+				coin.GroupID = &id
+			} else {
+				warn("coin %s refers to unknown group %d", coin.ID, *coin.GroupID)
+				coin.GroupID = nil
+			}
+		}
+
+		coin.Images = nil
+		for _, img := range imagesByCoin[coin.ID] {
+			newPath, ok := restoreFile(img.Path, saveToCoin)
+			if !ok {
+				continue
+			}
+			img.Path = newPath
+			coin.Images = append(coin.Images, img)
+		}
+		coin.GalleryImages = nil
+
+		if err := s.repo.Save(ctx, coin); err != nil {
+			warn("failed to restore coin %s (%s): %v", coin.ID, coin.Name, err)
+			continue
+		}
+		known[coin.ID] = true
+		report.CoinsCreated++
+		report.Images += len(coin.Images)
+
+		if tags, err := domain.NormalizeTagNames(coin.Tags); err != nil {
+			warn("coin %s has an invalid tag: %v", coin.ID, err)
+		} else if len(tags) > 0 {
+			if _, err := s.tagRepo.SetCoinTags(ctx, coin.ID, tags); err != nil {
+				warn("failed to tag coin %s: %v", coin.ID, err)
+			}
+		}
+
+		for _, img := range galleryByCoin[coin.ID] {
+			newPath, ok := restoreFile(img.Path, saveToCoin)
+			if !ok {
+				continue
+			}
+			if err := s.repo.AddGalleryImage(ctx, domain.CoinGalleryImage{CoinID: coin.ID, Path: newPath}); err != nil {
+				warn("failed to add gallery image to coin %s: %v", coin.ID, err)
+				continue
+			}
+			report.GalleryImages++
+		}
+		for _, l := range linksByCoin[coin.ID] {
+			if err := s.repo.AddLink(ctx, l); err != nil {
+				warn("failed to add link %s to coin %s: %v", l.URL, coin.ID, err)
+				continue
+			}
+			report.Links++
+		}
+	}
+
+	slog.Info("Backup restored", "coins_created", report.CoinsCreated, "coins_skipped", report.CoinsSkipped, "groups_created", report.GroupsCreated, "files", report.Files, "warnings", len(report.Warnings))
+	return report, nil
+}
+
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	return data, nil
+}
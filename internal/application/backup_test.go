@@ -0,0 +1,225 @@
+package application_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// backupFixture exports a collection with one group (with a cover image) and
+// one coin (with an image, a gallery photo, a link and a missing file).
+func backupFixture(t *testing.T) (*domain.Coin, []byte) {
+	service, mockRepo, mockGroupRepo, _, _, mockStorage, _, _, _ := setupTest(t)
+	ctx := context.Background()
+	coin := csvTestCoin()
+	coin.Images = []domain.CoinImage{{Path: "ignored"}}
+
+	mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 3, Name: "Franco", Description: "1939-1975"}}, nil)
+	mockGroupRepo.EXPECT().ListImages(ctx, 3).Return([]domain.GroupImage{{GroupID: 3, Path: "storage/groups/3/cover.jpg"}}, nil)
+	mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{Coins: []*domain.Coin{coin}}, nil)
+	mockRepo.EXPECT().GetAllImages(ctx).Return([]domain.CoinImage{
+		{CoinID: coin.ID, ImageType: "crop", Side: "front", Path: "storage/coins/" + coin.ID.String() + "/crop_front.png"},
+		{CoinID: coin.ID, ImageType: "crop", Side: "back", Path: "storage/coins/" + coin.ID.String() + "/gone.png"},
+	}, nil)
+	mockRepo.EXPECT().GetAllLinks(ctx).Return([]*domain.CoinLink{{CoinID: coin.ID, URL: "https://en.numista.com/1234", Name: "Numista"}}, nil)
+	mockRepo.EXPECT().ListGalleryImages(ctx, coin.ID).Return([]domain.CoinGalleryImage{{CoinID: coin.ID, Path: "storage/coins/" + coin.ID.String() + "/box.jpg"}}, nil)
+
+	mockStorage.EXPECT().ReadFile("storage/groups/3/cover.jpg").Return([]byte("cover"), nil)
+	mockStorage.EXPECT().ReadFile("storage/coins/"+coin.ID.String()+"/crop_front.png").Return([]byte("front"), nil)
+	mockStorage.EXPECT().ReadFile("storage/coins/"+coin.ID.String()+"/gone.png").Return(nil, assert.AnError)
+	mockStorage.EXPECT().ReadFile("storage/coins/"+coin.ID.String()+"/box.jpg").Return([]byte("box"), nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, service.ExportBackup(ctx, &buf))
+	return coin, buf.Bytes()
+}
+
+func readBackupManifest(t *testing.T, data []byte) application.BackupManifest {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	var manifest application.BackupManifest
+	for _, f := range zr.File {
+		if f.Name == "manifest.json" {
+			rc, err := f.Open()
+			assert.NoError(t, err)
+			assert.NoError(t, json.NewDecoder(rc).Decode(&manifest))
+			_ = rc.Close()
+		}
+	}
+	return manifest
+}
+
+// rewriteBackup copies an archive, letting edit replace the content of entries.
+func rewriteBackup(t *testing.T, data []byte, edit func(name string, content []byte) []byte) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	zw := zip.NewWriter(&out)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		_ = rc.Close()
+		w, err := zw.Create(f.Name)
+		assert.NoError(t, err)
+		_, err = w.Write(edit(f.Name, content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return out.Bytes()
+}
+
+func TestExportBackup(t *testing.T) {
+	coin, data := backupFixture(t)
+
+	manifest := readBackupManifest(t, data)
+	assert.Equal(t, application.BackupFormat, manifest.Format)
+	assert.Equal(t, application.BackupFormatVersion, manifest.Version)
+	assert.Equal(t, 1, manifest.Counts.Coins)
+	assert.Equal(t, 2, manifest.Counts.Images)
+	assert.Equal(t, 3, manifest.Counts.Files)
+	assert.Equal(t, []string{"storage/coins/" + coin.ID.String() + "/gone.png"}, manifest.MissingFiles)
+
+	names := make(map[string]string)
+	for _, e := range manifest.Entries {
+		names[e.Name] = e.Path
+		assert.Len(t, e.SHA256, 64)
+	}
+	assert.Equal(t, "storage/groups/3/cover.jpg", names["files/groups/3/cover.jpg"])
+	assert.Equal(t, "storage/coins/"+coin.ID.String()+"/box.jpg", names["files/coins/"+coin.ID.String()+"/box.jpg"])
+	assert.Contains(t, names, "data/coins.json")
+	assert.Contains(t, names, "data/coin_links.json")
+}
+
+func TestRestoreBackup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Into Empty Instance", func(t *testing.T) {
+		coin, data := backupFixture(t)
+		service, m := setupTagTest(t)
+		mockRepo, mockGroupRepo, mockStorage := m.repo, m.groupRepo, m.storage
+
+		mockGroupRepo.EXPECT().List(ctx).Return(nil, nil)
+		mockGroupRepo.EXPECT().Create(ctx, "Franco", "1939-1975").Return(&domain.Group{ID: 7, Name: "Franco"}, nil)
+		mockStorage.EXPECT().SaveGroupFile(7, "cover.jpg", gomock.Any()).DoAndReturn(func(id int, name string, r io.Reader) (string, error) {
+			content, _ := io.ReadAll(r)
+			assert.Equal(t, "cover", string(content))
+			return "new/groups/7/cover.jpg", nil
+		})
+		mockGroupRepo.EXPECT().AddImage(ctx, domain.GroupImage{GroupID: 7, Path: "new/groups/7/cover.jpg"}).Return(nil)
+
+		mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{}, nil)
+		mockStorage.EXPECT().SaveFile(coin.ID, "crop_front.png", gomock.Any()).Return("new/coins/crop_front.png", nil)
+		mockRepo.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, got *domain.Coin) error {
+			assert.Equal(t, coin.ID, got.ID)
+			assert.Equal(t, coin.Name, got.Name)
+			assert.Equal(t, coin.Year, got.Year)
+			assert.Equal(t, coin.Grade, got.Grade)
+			assert.Equal(t, coin.PersonalNotes, got.PersonalNotes)
+			assert.Equal(t, 7, *got.GroupID)
+			if assert.Len(t, got.Images, 1) {
+				assert.Equal(t, "new/coins/crop_front.png", got.Images[0].Path)
+				assert.Equal(t, "front", got.Images[0].Side)
+			}
+			return nil
+		})
+		m.tagRepo.EXPECT().SetCoinTags(ctx, coin.ID, []string{"euro", "to sell"}).Return([]string{"euro", "to sell"}, nil)
+		mockStorage.EXPECT().SaveFile(coin.ID, "box.jpg", gomock.Any()).Return("new/coins/box.jpg", nil)
+		mockRepo.EXPECT().AddGalleryImage(ctx, domain.CoinGalleryImage{CoinID: coin.ID, Path: "new/coins/box.jpg"}).Return(nil)
+		mockRepo.EXPECT().AddLink(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, l *domain.CoinLink) error {
+			assert.Equal(t, coin.ID, l.CoinID)
+			assert.Equal(t, "https://en.numista.com/1234", l.URL)
+			return nil
+		})
+
+		report, err := service.RestoreBackup(ctx, bytes.NewReader(data), int64(len(data)))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.GroupsCreated)
+		assert.Equal(t, 1, report.CoinsCreated)
+		assert.Equal(t, 1, report.Images)
+		assert.Equal(t, 1, report.GalleryImages)
+		assert.Equal(t, 1, report.GroupImages)
+		assert.Equal(t, 1, report.Links)
+		assert.Equal(t, 3, report.Files)
+		// The file that was already missing at export time
+		assert.Len(t, report.Warnings, 1)
+	})
+
+	t.Run("Into Existing Instance", func(t *testing.T) {
+		coin, data := backupFixture(t)
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+
+		// Same group and coin are already here: nothing is written
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 1, Name: "Franco"}}, nil)
+		mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{Coins: []*domain.Coin{{ID: coin.ID}}}, nil)
+
+		report, err := service.RestoreBackup(ctx, bytes.NewReader(data), int64(len(data)))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.GroupsMatched)
+		assert.Equal(t, 1, report.CoinsSkipped)
+		assert.Equal(t, 0, report.CoinsCreated)
+		assert.Equal(t, 0, report.Files)
+	})
+
+	t.Run("Checksum Mismatch", func(t *testing.T) {
+		_, data := backupFixture(t)
+		service, _, _, _, _, _, _, _, _ := setupTest(t)
+
+		tampered := rewriteBackup(t, data, func(name string, content []byte) []byte {
+			if name == "data/coins.json" {
+				return bytes.Replace(content, []byte("100 Pesetas"), []byte("200 Pesetas"), 1)
+			}
+			return content
+		})
+		_, err := service.RestoreBackup(ctx, bytes.NewReader(tampered), int64(len(tampered)))
+		assert.ErrorContains(t, err, "checksum mismatch for data/coins.json")
+	})
+
+	t.Run("Unsupported Version", func(t *testing.T) {
+		_, data := backupFixture(t)
+		service, _, _, _, _, _, _, _, _ := setupTest(t)
+
+		future := rewriteBackup(t, data, func(name string, content []byte) []byte {
+			if name == "manifest.json" {
+				return bytes.Replace(content, []byte(`"version": 1`), []byte(`"version": 99`), 1)
+			}
+			return content
+		})
+		_, err := service.RestoreBackup(ctx, bytes.NewReader(future), int64(len(future)))
+		assert.ErrorContains(t, err, "unsupported backup version 99")
+	})
+
+	t.Run("Not A Backup", func(t *testing.T) {
+		service, _, _, _, _, _, _, _, _ := setupTest(t)
+		_, err := service.RestoreBackup(ctx, bytes.NewReader([]byte("nope")), 4)
+		assert.Error(t, err)
+	})
+}
+
+func TestOpenBackup(t *testing.T) {
+	coin, data := backupFixture(t)
+
+	archive, err := application.OpenBackup(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Len(t, archive.Coins, 1)
+	assert.Len(t, archive.Images, 2)
+	assert.Equal(t, "Franco", archive.Groups[0].Name)
+
+	rc, err := archive.OpenFile("storage/coins/" + coin.ID.String() + "/crop_front.png")
+	assert.NoError(t, err)
+	content, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	assert.Equal(t, "front", string(content))
+
+	_, err = archive.OpenFile("storage/coins/" + coin.ID.String() + "/gone.png")
+	assert.Error(t, err)
+}
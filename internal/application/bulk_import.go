@@ -0,0 +1,295 @@
+package application
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Bulk import item states.
+const (
+	ImportStatusCreated = "created" // processed inline
+	ImportStatusQueued  = "queued"  // handed to the job queue
+	ImportStatusFailed  = "failed"
+)
+
+var importImageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".webp": true,
+}
+
+// Side suffixes recognised in file names: "1999_peseta_front.jpg", "coin-3-rev.png"...
+var (
+	importSideSuffix = regexp.MustCompile(`(?i)^(.*?)(?:^|[ _./-]+)(front|back|obverse|reverse|obv|rev|anverso|reverso)$`)
+	importTrailingNo = regexp.MustCompile(`^(.*?)(\d+)$`)
+)
+
+// ImportFile is an image found in a folder or ZIP, opened lazily.
+type ImportFile struct {
+	Name string // path relative to the import root, always with forward slashes
+	Open func() (io.ReadCloser, error)
+}
+
+// ImportPair is a front/back couple ready to go through AddCoin.
+type ImportPair struct {
+	Front ImportFile
+	Back  ImportFile
+}
+
+type BulkImportOptions struct {
+	GroupName   string
+	ModelName   string
+	Temperature float32
+}
+
+type BulkImportItem struct {
+	Front  string     `json:"front"`
+	Back   string     `json:"back,omitempty"`
+	Status string     `json:"status"`
+	CoinID *uuid.UUID `json:"coin_id,omitempty"`
+	JobID  *uuid.UUID `json:"job_id,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+type BulkImportReport struct {
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BulkImportItem `json:"items"`
+}
+
+// ImportFilesFromDir lists the images under dir, recursively.
+func ImportFilesFromDir(dir string) ([]ImportFile, error) {
+	var files []ImportFile
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !isImportImage(name) {
+			return nil
+		}
+		files = append(files, ImportFile{
+			Name: name,
+			Open: func() (io.ReadCloser, error) { return os.Open(p) },
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import directory: %w", err)
+	}
+	return files, nil
+}
+
+// ImportFilesFromZip lists the images inside a ZIP archive.
+func ImportFilesFromZip(r io.ReaderAt, size int64) ([]ImportFile, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip: %w", err)
+	}
+
+	var files []ImportFile
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || !isImportImage(f.Name) {
+			continue
+		}
+		files = append(files, ImportFile{Name: f.Name, Open: f.Open})
+	}
+	return files, nil
+}
+
+func isImportImage(name string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, ".") {
+		return false
+	}
+	return importImageExtensions[strings.ToLower(path.Ext(base))]
+}
+
+// PairImportFiles matches fronts with backs. Files named with a side suffix
+// (_front/_back, _obverse/_reverse, _anverso/_reverso...) are paired by their
+// common prefix. The rest are sorted by their trailing number within each
+// folder and taken two by two: front first, back second. Files that could
+// not be paired are returned as failed report items.
+func PairImportFiles(files []ImportFile) ([]ImportPair, []BulkImportItem) {
+	type sides struct {
+		front, back *ImportFile
+	}
+	bySide := make(map[string]*sides)
+	var keys []string
+	var numbered []ImportFile
+
+	for i := range files {
+		f := files[i]
+		base := strings.TrimSuffix(f.Name, path.Ext(f.Name))
+		m := importSideSuffix.FindStringSubmatch(base)
+		if m == nil {
+			numbered = append(numbered, f)
+			continue
+		}
+
+		key := strings.ToLower(m[1])
+		s, ok := bySide[key]
+		if !ok {
+			s = &sides{}
+			bySide[key] = s
+			keys = append(keys, key)
+		}
+		switch strings.ToLower(m[2]) {
+		case "front", "obverse", "obv", "anverso":
+			s.front = &f
+		default:
+			s.back = &f
+		}
+	}
+
+	var pairs []ImportPair
+	var unpaired []BulkImportItem
+	sort.Strings(keys)
+	for _, key := range keys {
+		s := bySide[key]
+		switch {
+		case s.front != nil && s.back != nil:
+			pairs = append(pairs, ImportPair{Front: *s.front, Back: *s.back})
+		case s.front != nil:
+			unpaired = append(unpaired, BulkImportItem{Front: s.front.Name, Status: ImportStatusFailed, Error: "no matching back image"})
+		default:
+			unpaired = append(unpaired, BulkImportItem{Front: s.back.Name, Status: ImportStatusFailed, Error: "no matching front image"})
+		}
+	}
+
+	sort.SliceStable(numbered, func(i, j int) bool {
+		return importSortKey(numbered[i].Name).less(importSortKey(numbered[j].Name))
+	})
+	for i := 0; i < len(numbered); {
+		if i+1 < len(numbered) && path.Dir(numbered[i].Name) == path.Dir(numbered[i+1].Name) {
+			pairs = append(pairs, ImportPair{Front: numbered[i], Back: numbered[i+1]})
+			i += 2
+			continue
+		}
+		unpaired = append(unpaired, BulkImportItem{Front: numbered[i].Name, Status: ImportStatusFailed, Error: "no matching back image"})
+		i++
+	}
+
+	return pairs, unpaired
+}
+
+type importKey struct {
+	dir, prefix string
+	number      int
+	name        string
+}
+
+// importSortKey orders IMG_9.jpg before IMG_10.jpg.
+func importSortKey(name string) importKey {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	k := importKey{dir: path.Dir(name), prefix: base, number: -1, name: name}
+	if m := importTrailingNo.FindStringSubmatch(base); m != nil {
+		if n, err := strconv.Atoi(m[2]); err == nil {
+			k.prefix, k.number = m[1], n
+		}
+	}
+	return k
+}
+
+func (a importKey) less(b importKey) bool {
+	if a.dir != b.dir {
+		return a.dir < b.dir
+	}
+	if a.prefix != b.prefix {
+		return a.prefix < b.prefix
+	}
+	if a.number != b.number {
+		return a.number < b.number
+	}
+	return a.name < b.name
+}
+
+// BulkImport pairs the files and runs every pair through AddCoin. One bad
+// pair does not stop the rest; the report says what happened to each file.
+func (s *CoinService) BulkImport(ctx context.Context, files []ImportFile, opts BulkImportOptions) (*BulkImportReport, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no images to import")
+	}
+
+	pairs, unpaired := PairImportFiles(files)
+	slog.Info("Starting bulk import", "files", len(files), "pairs", len(pairs), "unpaired", len(unpaired), "group", opts.GroupName)
+
+	report := &BulkImportReport{Items: make([]BulkImportItem, 0, len(pairs)+len(unpaired))}
+	for _, pair := range pairs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		report.Items = append(report.Items, s.importPair(ctx, pair, opts))
+	}
+	report.Items = append(report.Items, unpaired...)
+
+	for _, item := range report.Items {
+		report.Total++
+		if item.Status == ImportStatusFailed {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+	slog.Info("Bulk import finished", "succeeded", report.Succeeded, "failed", report.Failed)
+	return report, nil
+}
+
+func (s *CoinService) importPair(ctx context.Context, pair ImportPair, opts BulkImportOptions) BulkImportItem {
+	item := BulkImportItem{Front: pair.Front.Name, Back: pair.Back.Name}
+	fail := func(err error) BulkImportItem {
+		slog.Warn("Bulk import item failed", "front", item.Front, "back", item.Back, "error", err)
+		item.Status = ImportStatusFailed
+		item.Error = err.Error()
+		return item
+	}
+
+	front, err := pair.Front.Open()
+	if err != nil {
+		return fail(fmt.Errorf("failed to open %s: %w", pair.Front.Name, err))
+	}
+	defer func() { _ = front.Close() }()
+	back, err := pair.Back.Open()
+	if err != nil {
+		return fail(fmt.Errorf("failed to open %s: %w", pair.Back.Name, err))
+	}
+	defer func() { _ = back.Close() }()
+
+	coin, job, err := s.AddCoin(ctx, front, path.Base(pair.Front.Name), back, path.Base(pair.Back.Name), opts.GroupName, "", "", "", 0, opts.ModelName, opts.Temperature)
+	if err != nil {
+		return fail(err)
+	}
+
+	item.CoinID = &coin.ID
+	item.Status = ImportStatusCreated
+	if job != nil {
+		item.JobID = &job.ID
+		item.Status = ImportStatusQueued
+	}
+	return item
+}
@@ -0,0 +1,196 @@
+package application_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func importFiles(names ...string) []application.ImportFile {
+	files := make([]application.ImportFile, len(names))
+	for i, name := range names {
+		content := name
+		files[i] = application.ImportFile{
+			Name: name,
+			Open: func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(content)), nil },
+		}
+	}
+	return files
+}
+
+func pairNames(pairs []application.ImportPair) [][2]string {
+	names := make([][2]string, len(pairs))
+	for i, p := range pairs {
+		names[i] = [2]string{p.Front.Name, p.Back.Name}
+	}
+	return names
+}
+
+func TestPairImportFiles(t *testing.T) {
+	t.Run("Side Suffixes", func(t *testing.T) {
+		pairs, unpaired := application.PairImportFiles(importFiles(
+			"peseta_back.jpg",
+			"peseta_front.jpg",
+			"duro-Reverse.PNG",
+			"duro-Obverse.png",
+			"real anverso.jpeg",
+			"real reverso.jpeg",
+		))
+		assert.Empty(t, unpaired)
+		assert.Equal(t, [][2]string{
+			{"duro-Obverse.png", "duro-Reverse.PNG"},
+			{"peseta_front.jpg", "peseta_back.jpg"},
+			{"real anverso.jpeg", "real reverso.jpeg"},
+		}, pairNames(pairs))
+	})
+
+	t.Run("Consecutive Numbering", func(t *testing.T) {
+		pairs, unpaired := application.PairImportFiles(importFiles(
+			"IMG_10.jpg",
+			"IMG_9.jpg",
+			"IMG_11.jpg",
+			"IMG_8.jpg",
+		))
+		assert.Empty(t, unpaired)
+		assert.Equal(t, [][2]string{
+			{"IMG_8.jpg", "IMG_9.jpg"},
+			{"IMG_10.jpg", "IMG_11.jpg"},
+		}, pairNames(pairs))
+	})
+
+	t.Run("Pairs Stay Within Folder", func(t *testing.T) {
+		pairs, unpaired := application.PairImportFiles(importFiles(
+			"a/1.jpg",
+			"a/2.jpg",
+			"a/3.jpg",
+			"b/1.jpg",
+			"b/2.jpg",
+			"a/front.jpg",
+			"b/front.jpg",
+			"b/back.jpg",
+		))
+		assert.Equal(t, [][2]string{
+			{"b/front.jpg", "b/back.jpg"},
+			{"a/1.jpg", "a/2.jpg"},
+			{"b/1.jpg", "b/2.jpg"},
+		}, pairNames(pairs))
+		if assert.Len(t, unpaired, 2) {
+			assert.Equal(t, "a/front.jpg", unpaired[0].Front)
+			assert.Equal(t, "no matching back image", unpaired[0].Error)
+			assert.Equal(t, "a/3.jpg", unpaired[1].Front)
+			assert.Equal(t, application.ImportStatusFailed, unpaired[1].Status)
+		}
+	})
+
+	t.Run("Suffix Needs Separator", func(t *testing.T) {
+		// "prev" must not be read as a reverse
+		pairs, unpaired := application.PairImportFiles(importFiles("prev.jpg", "next.jpg"))
+		assert.Empty(t, unpaired)
+		assert.Equal(t, [][2]string{{"next.jpg", "prev.jpg"}}, pairNames(pairs))
+	})
+}
+
+func TestImportFilesFromZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"session/x_front.jpg", "session/x_back.jpg", "session/notes.txt", "__MACOSX/session/._x_front.jpg", "session/.hidden.jpg"} {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(name))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+
+	files, err := application.ImportFilesFromZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	if assert.Len(t, files, 2) {
+		assert.Equal(t, "session/x_front.jpg", files[0].Name)
+		rc, err := files[0].Open()
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(rc)
+		assert.Equal(t, "session/x_front.jpg", string(data))
+		assert.NoError(t, rc.Close())
+	}
+
+	_, err = application.ImportFilesFromZip(bytes.NewReader([]byte("not a zip")), 9)
+	assert.Error(t, err)
+}
+
+func TestImportFilesFromDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "box1"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".thumbs"), 0755))
+	for _, name := range []string{"box1/1.jpg", "box1/2.jpg", "readme.md", ".thumbs/1.jpg"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+
+	files, err := application.ImportFilesFromDir(dir)
+	assert.NoError(t, err)
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name
+	}
+	assert.Equal(t, []string{"box1/1.jpg", "box1/2.jpg"}, names)
+}
+
+func TestBulkImport(t *testing.T) {
+	t.Run("Reports Each Item", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+
+		// First pair goes through, second fails saving its originals
+		m.storage.EXPECT().SaveFile(gomock.Any(), "original_front.jpg", gomock.Any()).Return("of", nil)
+		m.storage.EXPECT().SaveFile(gomock.Any(), "original_back.jpg", gomock.Any()).Return("ob", nil)
+		m.imageService.EXPECT().GetMetadata(gomock.Any()).Return(100, 100, int64(100), "image/jpeg", nil).Times(2)
+		m.repo.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		m.jobRepo.EXPECT().Enqueue(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, job *domain.Job) error {
+			assert.Contains(t, string(job.Payload), `"group_name":"Session"`)
+			assert.Contains(t, string(job.Payload), `"front_filename":"a_front.jpg"`)
+			job.ID = uuid.New()
+			return nil
+		})
+		m.storage.EXPECT().SaveFile(gomock.Any(), "original_front.jpg", gomock.Any()).Return("", assert.AnError)
+
+		report, err := service.BulkImport(ctx, importFiles("dir/a_front.jpg", "dir/a_back.jpg", "dir/b_front.jpg", "dir/b_back.jpg", "dir/c_front.jpg"), application.BulkImportOptions{GroupName: "Session", Temperature: 0.1})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.Total)
+		assert.Equal(t, 1, report.Succeeded)
+		assert.Equal(t, 2, report.Failed)
+
+		assert.Equal(t, application.ImportStatusQueued, report.Items[0].Status)
+		assert.NotNil(t, report.Items[0].CoinID)
+		assert.NotNil(t, report.Items[0].JobID)
+		assert.Equal(t, application.ImportStatusFailed, report.Items[1].Status)
+		assert.Equal(t, "dir/b_front.jpg", report.Items[1].Front)
+		assert.NotEmpty(t, report.Items[1].Error)
+		assert.Equal(t, "dir/c_front.jpg", report.Items[2].Front)
+	})
+
+	t.Run("Open Error", func(t *testing.T) {
+		service, _ := setupJobTest(t)
+		files := importFiles("x_front.jpg", "x_back.jpg")
+		files[1].Open = func() (io.ReadCloser, error) { return nil, assert.AnError }
+
+		report, err := service.BulkImport(context.Background(), files, application.BulkImportOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Failed)
+		assert.Contains(t, report.Items[0].Error, "x_back.jpg")
+	})
+
+	t.Run("No Files", func(t *testing.T) {
+		service, _ := setupJobTest(t)
+		_, err := service.BulkImport(context.Background(), nil, application.BulkImportOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,255 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/pdf"
+)
+
+// CatalogueOptions chooses what the PDF catalogue shows. Estimated values
+// are left out unless IncludeValues is set, as for a club exhibition.
+type CatalogueOptions struct {
+	Title         string
+	IncludeValues bool
+}
+
+// catalogueCountries is how many countries the summary lists before
+// grouping the rest as "Other".
+const catalogueCountries = 20
+
+// ExportCataloguePDF writes a printable catalogue of the coins matching
+// filter, ignoring its limit and offset. Each coin is shown with its
+// processed images, falling back to the originals for coins that were never
+// processed.
+func (s *CoinService) ExportCataloguePDF(ctx context.Context, filter domain.CoinFilter, opts CatalogueOptions, w io.Writer) error {
+	render, err := s.CataloguePDF(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	return render(w)
+}
+
+// CataloguePDF gathers the coins of a catalogue and returns the function
+// that renders it, so callers can report lookup errors before streaming the
+// document. Images are read from storage as each page is drawn.
+func (s *CoinService) CataloguePDF(ctx context.Context, filter domain.CoinFilter, opts CatalogueOptions) (func(w io.Writer) error, error) {
+	coins, err := s.allCoins(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	groupNames := make(map[int]string, len(groups))
+	for _, g := range groups {
+		groupNames[g.ID] = g.Name
+	}
+
+	title := opts.Title
+	if title == "" && filter.GroupID != nil {
+		title = groupNames[*filter.GroupID]
+	}
+	if title == "" {
+		title = "Coin Catalogue"
+	}
+
+	cat := pdf.Catalogue{
+		Title:          title,
+		Subtitle:       describeFilter(filter, groupNames),
+		Generated:      time.Now(),
+		BreakdownTitle: "Coins by country",
+	}
+
+	var (
+		minYear, maxYear   int
+		minValue, maxValue float64
+		weight             float64
+		countries          = make(map[string]int)
+	)
+	for _, c := range coins {
+		item := pdf.CatalogueItem{
+			Title: catalogueTitle(c),
+			Images: func() ([]byte, []byte) {
+				return s.catalogueImage(c, "front"), s.catalogueImage(c, "back")
+			},
+		}
+		add := func(label, value string) {
+			if value = strings.TrimSpace(value); value != "" {
+				item.Fields = append(item.Fields, pdf.Field{Label: label, Value: value})
+			}
+		}
+		add("Country", c.Country)
+		if y := c.Year.Int(); y != 0 {
+			add("Year", strconv.Itoa(y))
+		}
+		add("Face value", c.FaceValue+" "+c.Currency)
+		add("KM", c.KMCode.String())
+		add("Material", c.Material)
+		if c.WeightG > 0 {
+			add("Weight", strconv.FormatFloat(c.WeightG, 'f', -1, 64)+" g")
+		}
+		if c.DiameterMM > 0 {
+			add("Diameter", strconv.FormatFloat(c.DiameterMM, 'f', -1, 64)+" mm")
+		}
+		add("Mint", c.Mint)
+		add("Grade", c.Grade.String())
+		if c.GroupID != nil && filter.GroupID == nil {
+			add("Group", groupNames[*c.GroupID])
+		}
+		if opts.IncludeValues && (c.MinValue > 0 || c.MaxValue > 0) {
+			add("Value", valueRange(c.MinValue, c.MaxValue))
+		}
+		cat.Items = append(cat.Items, item)
+
+		if y := c.Year.Int(); y != 0 {
+			if minYear == 0 || y < minYear {
+				minYear = y
+			}
+			if maxYear == 0 || y > maxYear {
+				maxYear = y
+			}
+		}
+		minValue += c.MinValue
+		maxValue += c.MaxValue
+		weight += c.WeightG
+		country := c.Country
+		if country == "" {
+			country = "Unknown"
+		}
+		countries[country]++
+	}
+
+	cat.Summary = []pdf.Field{
+		{Label: "Coins", Value: strconv.Itoa(len(coins))},
+		{Label: "Countries", Value: strconv.Itoa(len(countries))},
+	}
+	if minYear != 0 {
+		cat.Summary = append(cat.Summary, pdf.Field{Label: "Years", Value: fmt.Sprintf("%d - %d", minYear, maxYear)})
+	}
+	if weight > 0 {
+		cat.Summary = append(cat.Summary, pdf.Field{Label: "Total weight", Value: strconv.FormatFloat(weight, 'f', 1, 64) + " g"})
+	}
+	if opts.IncludeValues {
+		cat.Summary = append(cat.Summary, pdf.Field{Label: "Estimated value", Value: valueRange(minValue, maxValue)})
+	}
+	cat.Breakdown = countryBreakdown(countries)
+
+	return func(w io.Writer) error {
+		if err := pdf.RenderCatalogue(w, cat); err != nil {
+			return err
+		}
+		slog.Info("Catalogue exported", "coins", len(coins), "values", opts.IncludeValues)
+		return nil
+	}, nil
+}
+
+// catalogueImage returns the processed image of a side, or the original if
+// there is none. A missing file leaves the side blank.
+func (s *CoinService) catalogueImage(c *domain.Coin, side string) []byte {
+	for _, imageType := range []string{"crop", "original"} {
+		for _, img := range c.Images {
+			if img.Side != side || img.ImageType != imageType {
+				continue
+			}
+			data, err := s.storage.ReadFile(img.Path)
+			if err != nil {
+				slog.Warn("Catalogue: image not found", "coin_id", c.ID, "path", img.Path, "error", err)
+				continue
+			}
+			return data
+		}
+	}
+	return nil
+}
+
+func catalogueTitle(c *domain.Coin) string {
+	name := c.Name
+	if name == "" {
+		name = "Untitled coin"
+	}
+	var details []string
+	if c.Country != "" {
+		details = append(details, c.Country)
+	}
+	if y := c.Year.Int(); y != 0 {
+		details = append(details, strconv.Itoa(y))
+	}
+	if len(details) == 0 {
+		return name
+	}
+	return name + " (" + strings.Join(details, ", ") + ")"
+}
+
+// describeFilter names the filters in effect, for the cover page.
+func describeFilter(f domain.CoinFilter, groupNames map[int]string) string {
+	var parts []string
+	if f.GroupID != nil {
+		parts = append(parts, "Group: "+groupNames[*f.GroupID])
+	}
+	if f.Country != nil {
+		parts = append(parts, "Country: "+*f.Country)
+	}
+	switch {
+	case f.Year != nil:
+		parts = append(parts, fmt.Sprintf("Year: %d", *f.Year))
+	case f.MinYear != nil && f.MaxYear != nil:
+		parts = append(parts, fmt.Sprintf("Years: %d - %d", *f.MinYear, *f.MaxYear))
+	case f.MinYear != nil:
+		parts = append(parts, fmt.Sprintf("From %d", *f.MinYear))
+	case f.MaxYear != nil:
+		parts = append(parts, fmt.Sprintf("Until %d", *f.MaxYear))
+	}
+	if f.Grade != nil {
+		parts = append(parts, "Grade: "+*f.Grade)
+	}
+	if f.Material != nil {
+		parts = append(parts, "Material: "+*f.Material)
+	}
+	if f.Query != nil {
+		parts = append(parts, fmt.Sprintf("Search: %q", *f.Query))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func countryBreakdown(counts map[string]int) []pdf.Field {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	var rows []pdf.Field
+	other := 0
+	for i, name := range names {
+		if i >= catalogueCountries {
+			other += counts[name]
+			continue
+		}
+		rows = append(rows, pdf.Field{Label: name, Value: strconv.Itoa(counts[name])})
+	}
+	if other > 0 {
+		rows = append(rows, pdf.Field{Label: "Other", Value: strconv.Itoa(other)})
+	}
+	return rows
+}
+
+func valueRange(lo, hi float64) string {
+	if lo == hi || lo == 0 {
+		return fmt.Sprintf("%.2f €", hi)
+	}
+	return fmt.Sprintf("%.2f - %.2f €", lo, hi)
+}
@@ -0,0 +1,68 @@
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportCataloguePDF(t *testing.T) {
+	ctx := context.Background()
+	service, mockRepo, mockGroupRepo, _, _, mockStorage, _, _, _ := setupTest(t)
+
+	var img bytes.Buffer
+	assert.NoError(t, png.Encode(&img, image.NewNRGBA(image.Rect(0, 0, 8, 8))))
+
+	groupID := 3
+	year, _ := domain.NewYear(1870)
+	var coins []*domain.Coin
+	for i := 0; i < 5; i++ {
+		id := uuid.New()
+		coins = append(coins, &domain.Coin{
+			ID:       id,
+			Name:     "5 Pesetas",
+			Country:  "España",
+			Year:     year,
+			GroupID:  &groupID,
+			MaxValue: 40,
+			Images: []domain.CoinImage{
+				{CoinID: id, ImageType: "crop", Side: "front", Path: "storage/coins/" + id.String() + "/processed_front.png"},
+				{CoinID: id, ImageType: "original", Side: "back", Path: "storage/coins/" + id.String() + "/original_back.jpg"},
+			},
+		})
+	}
+
+	mockGroupRepo.EXPECT().GetByID(ctx, groupID).Return(&domain.Group{ID: groupID, Name: "Amadeo I"}, nil)
+	// The coins come in two pages, the second after the first's cursor
+	mockRepo.EXPECT().ListPage(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, filter domain.CoinFilter) (*domain.CoinPage, error) {
+		assert.Equal(t, &groupID, filter.GroupID)
+		assert.Equal(t, 0, filter.Offset)
+		if filter.Cursor == "" {
+			return &domain.CoinPage{Coins: coins[:3], NextCursor: "page-2"}, nil
+		}
+		assert.Equal(t, "page-2", filter.Cursor)
+		return &domain.CoinPage{Coins: coins[3:], PrevCursor: "page-1"}, nil
+	}).Times(2)
+	mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: groupID, Name: "Amadeo I"}}, nil)
+	mockStorage.EXPECT().ReadFile(gomock.Any()).DoAndReturn(func(path string) ([]byte, error) {
+		if bytes.HasSuffix([]byte(path), []byte(".png")) {
+			return img.Bytes(), nil
+		}
+		return nil, assert.AnError
+	}).Times(10)
+
+	var out bytes.Buffer
+	err := service.ExportCataloguePDF(ctx, domain.CoinFilter{GroupID: &groupID, Limit: 10}, application.CatalogueOptions{IncludeValues: true}, &out)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out.Bytes(), []byte("%PDF-")))
+	// Cover, contents, summary and two pages of coins
+	assert.Equal(t, 5, bytes.Count(out.Bytes(), []byte("/Type /Page\n")))
+}
@@ -0,0 +1,209 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+var (
+	ErrChecklistNotFound = errors.New("checklist not found")
+	ErrSlotNotFound      = errors.New("checklist slot not found")
+)
+
+// ChecklistSlots are slots to add to a checklist. Slots are added as given;
+// each of NumistaTypes is expanded into one slot per issue listed by Numista,
+// or a single slot for any year and mint when Numista has no issues for it.
+// Slots the checklist already has are skipped.
+type ChecklistSlots struct {
+	Slots        []domain.ChecklistSlot `json:"slots"`
+	NumistaTypes []int                  `json:"numista_types"`
+}
+
+type ChecklistParams struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ChecklistSlots
+}
+
+// ListChecklists returns the checklists by name, each with its slots matched
+// against the coins of the collection.
+func (s *CoinService) ListChecklists(ctx context.Context) ([]*domain.Checklist, error) {
+	checklists, err := s.checklistRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.matchChecklists(ctx, checklists...); err != nil {
+		return nil, err
+	}
+	return checklists, nil
+}
+
+// GetChecklist returns a checklist with its progress: the coins filling each
+// slot, the completion, the missing slots and the duplicates.
+func (s *CoinService) GetChecklist(ctx context.Context, id int) (*domain.Checklist, error) {
+	c, err := s.findChecklist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.matchChecklists(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *CoinService) CreateChecklist(ctx context.Context, params ChecklistParams) (*domain.Checklist, error) {
+	c := &domain.Checklist{
+		Name:        params.Name,
+		Description: params.Description,
+		Slots:       params.Slots,
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	seeded, err := s.seedChecklistSlots(ctx, params.NumistaTypes)
+	if err != nil {
+		return nil, err
+	}
+	c.Slots = append(c.Slots, seeded...)
+
+	if err := s.checklistRepo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	if err := s.matchChecklists(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateChecklist renames a checklist and changes its description. Slots
+// are added and removed on their own.
+func (s *CoinService) UpdateChecklist(ctx context.Context, id int, name, description string) (*domain.Checklist, error) {
+	c, err := s.findChecklist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.Name, c.Description = name, description
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checklistRepo.Update(ctx, c); err != nil {
+		return nil, err
+	}
+	if err := s.matchChecklists(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *CoinService) DeleteChecklist(ctx context.Context, id int) error {
+	if _, err := s.findChecklist(ctx, id); err != nil {
+		return err
+	}
+	return s.checklistRepo.Delete(ctx, id)
+}
+
+// AddChecklistSlots adds slots to a checklist and returns it with its
+// progress.
+func (s *CoinService) AddChecklistSlots(ctx context.Context, id int, slots ChecklistSlots) (*domain.Checklist, error) {
+	if _, err := s.findChecklist(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateChecklistSlots(slots.Slots); err != nil {
+		return nil, err
+	}
+	seeded, err := s.seedChecklistSlots(ctx, slots.NumistaTypes)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.checklistRepo.AddSlots(ctx, id, append(slots.Slots, seeded...)); err != nil {
+		return nil, err
+	}
+	return s.GetChecklist(ctx, id)
+}
+
+func (s *CoinService) DeleteChecklistSlot(ctx context.Context, id, slotID int) error {
+	c, err := s.findChecklist(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, slot := range c.Slots {
+		if slot.ID == slotID {
+			return s.checklistRepo.DeleteSlot(ctx, id, slotID)
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrSlotNotFound, slotID)
+}
+
+func (s *CoinService) findChecklist(ctx context.Context, id int) (*domain.Checklist, error) {
+	c, err := s.checklistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, fmt.Errorf("%w: %d", ErrChecklistNotFound, id)
+	}
+	return c, nil
+}
+
+// seedChecklistSlots builds the slots of the Numista types from their
+// issues. A type whose issues cannot be read gets a single slot for any
+// year, and the error is only logged.
+func (s *CoinService) seedChecklistSlots(ctx context.Context, types []int) ([]domain.ChecklistSlot, error) {
+	var slots []domain.ChecklistSlot
+	for _, t := range types {
+		if t <= 0 {
+			return nil, fmt.Errorf("%w: invalid numista type %d", domain.ErrInvalidChecklist, t)
+		}
+		issues, err := s.numistaClient.GetIssues(ctx, t)
+		if err != nil {
+			slog.Warn("Failed to get numista issues, adding the type for any year", "numista_id", t, "error", err)
+		}
+		if len(issues) == 0 {
+			slots = append(slots, domain.ChecklistSlot{NumistaID: t})
+			continue
+		}
+		for _, issue := range issues {
+			slot := domain.ChecklistSlot{
+				NumistaID: t,
+				MintMark:  issue.MintLetter,
+				Label:     issue.Comment,
+			}
+			if issue.IsDated {
+				slot.Year = issue.GregorianYear
+				if slot.Year == 0 {
+					slot.Year = issue.Year
+				}
+			}
+			slots = append(slots, slot)
+		}
+	}
+	if err := domain.ValidateChecklistSlots(slots); err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// matchChecklists fills the slots of the checklists with the coins of their
+// Numista types, reading the coins once for all of them.
+func (s *CoinService) matchChecklists(ctx context.Context, checklists ...*domain.Checklist) error {
+	var types []int
+	for _, c := range checklists {
+		types = append(types, c.NumistaTypes()...)
+	}
+	coins := []*domain.Coin{}
+	if len(types) > 0 {
+		var err error
+		coins, err = s.allCoins(ctx, domain.CoinFilter{NumistaNumbers: types})
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range checklists {
+		domain.MatchChecklist(c, coins)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/numista"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type checklistTestMocks struct {
+	repo          *mocks.MockCoinRepository
+	checklistRepo *mocks.MockChecklistRepository
+	numista       *mocks.MockNumistaService
+}
+
+// setupChecklistTest builds a service whose checklist repository can be
+// mocked, which setupTest does not return.
+func setupChecklistTest(t *testing.T) (*application.CoinService, checklistTestMocks) {
+	ctrl := gomock.NewController(t)
+	m := checklistTestMocks{
+		repo:          mocks.NewMockCoinRepository(ctrl),
+		checklistRepo: mocks.NewMockChecklistRepository(ctrl),
+		numista:       mocks.NewMockNumistaService(ctrl),
+	}
+	service := application.NewCoinService(m.repo, nil, nil, nil, m.checklistRepo, nil, nil, nil, nil, m.numista, nil, nil)
+	return service, m
+}
+
+func TestCreateChecklist(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Seeded From Numista", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.numista.EXPECT().GetIssues(ctx, 100).Return([]numista.Issue{
+			{IsDated: true, Year: 1975, GregorianYear: 1975, MintLetter: "M"},
+			{IsDated: true, Year: 1976, GregorianYear: 1976, MintLetter: "M", Comment: "Proof"},
+		}, nil)
+		m.numista.EXPECT().GetIssues(ctx, 200).Return(nil, assert.AnError)
+		m.checklistRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *domain.Checklist) error {
+			assert.Equal(t, []domain.ChecklistSlot{
+				{NumistaID: 300, Year: 1980},
+				{NumistaID: 100, Year: 1975, MintMark: "M"},
+				{NumistaID: 100, Year: 1976, MintMark: "M", Label: "Proof"},
+				{NumistaID: 200},
+			}, c.Slots)
+			c.ID = 1
+			return nil
+		})
+		m.repo.EXPECT().ListPage(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, f domain.CoinFilter) (*domain.CoinPage, error) {
+			assert.ElementsMatch(t, []int{100, 200, 300}, f.NumistaNumbers)
+			return &domain.CoinPage{}, nil
+		})
+
+		c, err := service.CreateChecklist(ctx, application.ChecklistParams{
+			Name: "Pesetas",
+			ChecklistSlots: application.ChecklistSlots{
+				Slots:        []domain.ChecklistSlot{{NumistaID: 300, Year: 1980}},
+				NumistaTypes: []int{100, 200},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, c.ID)
+		assert.Equal(t, 4, c.Progress.Slots)
+	})
+
+	t.Run("Undated Issue", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.numista.EXPECT().GetIssues(ctx, 100).Return([]numista.Issue{{IsDated: false, Year: 0}}, nil)
+		m.checklistRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *domain.Checklist) error {
+			assert.Equal(t, []domain.ChecklistSlot{{NumistaID: 100}}, c.Slots)
+			return nil
+		})
+		m.repo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{}, nil)
+
+		_, err := service.CreateChecklist(ctx, application.ChecklistParams{
+			Name:           "Tokens",
+			ChecklistSlots: application.ChecklistSlots{NumistaTypes: []int{100}},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("No Name", func(t *testing.T) {
+		service, _ := setupChecklistTest(t)
+		_, err := service.CreateChecklist(ctx, application.ChecklistParams{Name: " "})
+		assert.ErrorIs(t, err, domain.ErrInvalidChecklist)
+	})
+}
+
+func TestGetChecklist(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Progress", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.checklistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Checklist{ID: 1, Name: "Pesetas", Slots: []domain.ChecklistSlot{
+			{ID: 1, NumistaID: 100, Year: 1975},
+			{ID: 2, NumistaID: 100, Year: 1976},
+		}}, nil)
+		y, _ := domain.NewYear(1975)
+		owned := []*domain.Coin{
+			{ID: uuid.New(), NumistaNumber: 100, Year: y},
+			{ID: uuid.New(), NumistaNumber: 100, Year: y},
+		}
+		m.repo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{Coins: owned}, nil)
+
+		c, err := service.GetChecklist(ctx, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 50.0, c.Progress.Completion)
+		assert.Equal(t, 2, c.Progress.Missing[0].ID)
+		assert.Equal(t, []uuid.UUID{owned[0].ID, owned[1].ID}, c.Progress.Duplicates[0].CoinIDs)
+	})
+
+	t.Run("No Slots Reads No Coins", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.checklistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Checklist{ID: 1, Name: "Empty"}, nil)
+
+		c, err := service.GetChecklist(ctx, 1)
+		assert.NoError(t, err)
+		assert.Zero(t, c.Progress.Slots)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.checklistRepo.EXPECT().GetByID(ctx, 9).Return(nil, nil)
+
+		_, err := service.GetChecklist(ctx, 9)
+		assert.ErrorIs(t, err, application.ErrChecklistNotFound)
+	})
+}
+
+func TestAddChecklistSlots(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.checklistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Checklist{ID: 1, Name: "Pesetas"}, nil)
+		m.numista.EXPECT().GetIssues(ctx, 100).Return([]numista.Issue{{IsDated: true, GregorianYear: 1975}}, nil)
+		m.checklistRepo.EXPECT().AddSlots(ctx, 1, []domain.ChecklistSlot{
+			{NumistaID: 200, MintMark: "M"},
+			{NumistaID: 100, Year: 1975},
+		}).Return(nil, nil)
+		m.checklistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Checklist{ID: 1, Name: "Pesetas", Slots: []domain.ChecklistSlot{
+			{ID: 1, NumistaID: 100, Year: 1975},
+			{ID: 2, NumistaID: 200, MintMark: "M"},
+		}}, nil)
+		m.repo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{}, nil)
+
+		c, err := service.AddChecklistSlots(ctx, 1, application.ChecklistSlots{
+			Slots:        []domain.ChecklistSlot{{NumistaID: 200, MintMark: " M"}},
+			NumistaTypes: []int{100},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, c.Progress.Missing, 2)
+	})
+
+	t.Run("Invalid Slot", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.checklistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Checklist{ID: 1, Name: "Pesetas"}, nil)
+
+		_, err := service.AddChecklistSlots(ctx, 1, application.ChecklistSlots{Slots: []domain.ChecklistSlot{{Year: 1975}}})
+		assert.ErrorIs(t, err, domain.ErrInvalidChecklist)
+	})
+}
+
+func TestDeleteChecklistSlot(t *testing.T) {
+	ctx := context.Background()
+	checklist := func() *domain.Checklist {
+		return &domain.Checklist{ID: 1, Name: "Pesetas", Slots: []domain.ChecklistSlot{{ID: 5, NumistaID: 100}}}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.checklistRepo.EXPECT().GetByID(ctx, 1).Return(checklist(), nil)
+		m.checklistRepo.EXPECT().DeleteSlot(ctx, 1, 5).Return(nil)
+		assert.NoError(t, service.DeleteChecklistSlot(ctx, 1, 5))
+	})
+
+	t.Run("Slot Of Another Checklist", func(t *testing.T) {
+		service, m := setupChecklistTest(t)
+		m.checklistRepo.EXPECT().GetByID(ctx, 1).Return(checklist(), nil)
+		assert.ErrorIs(t, service.DeleteChecklistSlot(ctx, 1, 6), application.ErrSlotNotFound)
+	})
+}
@@ -0,0 +1,487 @@
+package application
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+const csvDateLayout = "2006-01-02"
+
+// coinCSVRow is a coin being read from a CSV line. The group travels by name
+// because it may not exist yet.
+type coinCSVRow struct {
+	coin      *domain.Coin
+	groupName string
+	hasGroup  bool
+	tags      []string
+	hasTags   bool
+}
+
+// csvTagSeparator joins the tags of a coin in one CSV cell.
+const csvTagSeparator = ";"
+
+// csvColumn maps a CSV header to a coin field in both directions.
+type csvColumn struct {
+	header string
+	get    func(c *domain.Coin, groupName string) string
+	set    func(r *coinCSVRow, value string) error
+}
+
+// coinCSVColumns is shared by ExportCoinsCSV and ImportCoinsCSV, so an
+// exported file can be edited and loaded back. New columns go at the end to
+// keep older files readable.
+var coinCSVColumns = []csvColumn{
+	{"ID", func(c *domain.Coin, _ string) string { return c.ID.String() }, nil},
+	textColumn("Name", func(c *domain.Coin) *string { return &c.Name }),
+	textColumn("Country", func(c *domain.Coin) *string { return &c.Country }),
+	{"Year",
+		func(c *domain.Coin, _ string) string { return strconv.Itoa(c.Year.Int()) },
+		func(r *coinCSVRow, v string) error {
+			n, err := parseCSVInt(v)
+			if err != nil {
+				return err
+			}
+			year, err := domain.NewYear(n)
+			if err != nil {
+				return err
+			}
+			r.coin.Year = year
+			return nil
+		}},
+	textColumn("Face Value", func(c *domain.Coin) *string { return &c.FaceValue }),
+	textColumn("Currency", func(c *domain.Coin) *string { return &c.Currency }),
+	textColumn("Composition", func(c *domain.Coin) *string { return &c.Material }),
+	{"Grade",
+		func(c *domain.Coin, _ string) string { return c.Grade.String() },
+		func(r *coinCSVRow, v string) error {
+			grade, err := domain.NewGrade(v)
+			if err != nil {
+				return err
+			}
+			r.coin.Grade = grade
+			return nil
+		}},
+	{"Mintage",
+		func(c *domain.Coin, _ string) string { return strconv.FormatInt(c.Mintage.Int64(), 10) },
+		func(r *coinCSVRow, v string) error {
+			var n int64
+			if v != "" {
+				var err error
+				// Thousands separators are common in mintages: 1.000.000 or 1,000,000
+				digits := strings.NewReplacer(".", "", ",", "").Replace(v)
+				if n, err = strconv.ParseInt(digits, 10, 64); err != nil {
+					return fmt.Errorf("invalid number %q", v)
+				}
+			}
+			mintage, err := domain.NewMintage(n)
+			if err != nil {
+				return err
+			}
+			r.coin.Mintage = mintage
+			return nil
+		}},
+	textColumn("Mint", func(c *domain.Coin) *string { return &c.Mint }),
+	textColumn("Mint Mark", func(c *domain.Coin) *string { return &c.MintMark }),
+	floatColumn("Weight (g)", func(c *domain.Coin) *float64 { return &c.WeightG }),
+	floatColumn("Diameter (mm)", func(c *domain.Coin) *float64 { return &c.DiameterMM }),
+	dateColumn("Acquired Date", func(c *domain.Coin) **time.Time { return &c.AcquiredAt }),
+	floatColumn("Price Paid", func(c *domain.Coin) *float64 { return &c.PricePaid }),
+	dateColumn("Sold Date", func(c *domain.Coin) **time.Time { return &c.SoldAt }),
+	floatColumn("Sold Price", func(c *domain.Coin) *float64 { return &c.SoldPrice }),
+	textColumn("Notes", func(c *domain.Coin) *string { return &c.PersonalNotes }),
+	{"Group",
+		func(_ *domain.Coin, groupName string) string { return groupName },
+		func(r *coinCSVRow, v string) error {
+			r.groupName = v
+			r.hasGroup = true
+			return nil
+		}},
+	textColumn("Sale Channel", func(c *domain.Coin) *string { return &c.SaleChannel }),
+	textColumn("Technical Notes", func(c *domain.Coin) *string { return &c.TechnicalNotes }),
+	{"KM Code",
+		func(c *domain.Coin, _ string) string { return c.KMCode.String() },
+		func(r *coinCSVRow, v string) error {
+			km, err := domain.NewKMCode(v)
+			if err != nil {
+				return err
+			}
+			r.coin.KMCode = km
+			return nil
+		}},
+	floatColumn("Thickness (mm)", func(c *domain.Coin) *float64 { return &c.ThicknessMM }),
+	textColumn("Edge", func(c *domain.Coin) *string { return &c.Edge }),
+	textColumn("Shape", func(c *domain.Coin) *string { return &c.Shape }),
+	floatColumn("Min Value", func(c *domain.Coin) *float64 { return &c.MinValue }),
+	floatColumn("Max Value", func(c *domain.Coin) *float64 { return &c.MaxValue }),
+	{"Numista Number",
+		func(c *domain.Coin, _ string) string { return strconv.Itoa(c.NumistaNumber) },
+		func(r *coinCSVRow, v string) error {
+			n, err := parseCSVInt(v)
+			if err != nil {
+				return err
+			}
+			r.coin.NumistaNumber = n
+			return nil
+		}},
+	textColumn("Description", func(c *domain.Coin) *string { return &c.Description }),
+	{"Tags",
+		func(c *domain.Coin, _ string) string { return strings.Join(c.Tags, csvTagSeparator+" ") },
+		func(r *coinCSVRow, v string) error {
+			tags, err := domain.NormalizeTagNames(strings.Split(v, csvTagSeparator))
+			if err != nil {
+				return err
+			}
+			r.tags = tags
+			r.hasTags = true
+			return nil
+		}},
+}
+
+func textColumn(header string, field func(*domain.Coin) *string) csvColumn {
+	return csvColumn{
+		header: header,
+		get:    func(c *domain.Coin, _ string) string { return *field(c) },
+		set: func(r *coinCSVRow, v string) error {
+			*field(r.coin) = v
+			return nil
+		},
+	}
+}
+
+func floatColumn(header string, field func(*domain.Coin) *float64) csvColumn {
+	return csvColumn{
+		header: header,
+		get:    func(c *domain.Coin, _ string) string { return fmt.Sprintf("%.2f", *field(c)) },
+		set: func(r *coinCSVRow, v string) error {
+			f, err := parseCSVFloat(v)
+			if err != nil {
+				return err
+			}
+			if f < 0 {
+				return fmt.Errorf("cannot be negative")
+			}
+			*field(r.coin) = f
+			return nil
+		},
+	}
+}
+
+func dateColumn(header string, field func(*domain.Coin) **time.Time) csvColumn {
+	return csvColumn{
+		header: header,
+		get: func(c *domain.Coin, _ string) string {
+			if t := *field(c); t != nil {
+				return t.Format(csvDateLayout)
+			}
+			return ""
+		},
+		set: func(r *coinCSVRow, v string) error {
+			if v == "" {
+				*field(r.coin) = nil
+				return nil
+			}
+			t, err := time.Parse(csvDateLayout, v)
+			if err != nil {
+				return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", v)
+			}
+			*field(r.coin) = &t
+			return nil
+		},
+	}
+}
+
+func parseCSVInt(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", v)
+	}
+	return n, nil
+}
+
+// parseCSVFloat also takes a comma as decimal separator, as spreadsheets in
+// Spanish locale write it.
+func parseCSVFloat(v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	if !strings.Contains(v, ".") {
+		v = strings.Replace(v, ",", ".", 1)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", v)
+	}
+	return f, nil
+}
+
+type CSVImportError struct {
+	Row    int    `json:"row"` // line in the file, header is 1
+	Column string `json:"column,omitempty"`
+	Error  string `json:"error"`
+}
+
+type CSVImportReport struct {
+	DryRun         bool             `json:"dry_run"`
+	Rows           int              `json:"rows"`
+	Created        int              `json:"created"`
+	Updated        int              `json:"updated"`
+	Failed         int              `json:"failed"`
+	GroupsCreated  []string         `json:"groups_created"`
+	IgnoredColumns []string         `json:"ignored_columns"`
+	Errors         []CSVImportError `json:"errors"`
+}
+
+// ImportCoinsCSV loads coins from a CSV with the ExportCoinsCSV columns.
+// Rows whose ID matches a coin update it, the rest are created; only the
+// columns present in the file are touched. Rows are independent: a bad one
+// is reported and skipped. With dryRun nothing is written, but the report
+// says what would happen.
+func (s *CoinService) ImportCoinsCSV(ctx context.Context, r io.Reader, dryRun bool) (*CSVImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("csv file is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	report := &CSVImportReport{DryRun: dryRun, GroupsCreated: []string{}, IgnoredColumns: []string{}, Errors: []CSVImportError{}}
+	columns, idIndex, err := mapCSVHeader(header, report)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.allCoins(ctx, domain.CoinFilter{})
+	if err != nil {
+		return nil, err
+	}
+	coinsByID := make(map[uuid.UUID]*domain.Coin, len(existing))
+	for _, c := range existing {
+		coinsByID[c.ID] = c
+	}
+
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	groupsByName := make(map[string]*domain.Group, len(groups))
+	for _, g := range groups {
+		groupsByName[strings.ToLower(g.Name)] = g
+	}
+
+	seen := make(map[uuid.UUID]int)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			report.Rows++
+			report.Failed++
+			report.Errors = append(report.Errors, CSVImportError{Row: line, Error: err.Error()})
+			continue
+		}
+		if isBlankCSVRecord(record) {
+			continue
+		}
+		report.Rows++
+
+		rowErrs := s.importCSVRecord(ctx, line, record, columns, idIndex, coinsByID, groupsByName, seen, dryRun, report)
+		if len(rowErrs) > 0 {
+			report.Failed++
+			report.Errors = append(report.Errors, rowErrs...)
+		}
+	}
+
+	slog.Info("CSV import finished", "dry_run", dryRun, "rows", report.Rows, "created", report.Created, "updated", report.Updated, "failed", report.Failed)
+	return report, nil
+}
+
+// mapCSVHeader resolves each header to a column, case-insensitively.
+// Unknown headers are ignored and listed in the report.
+func mapCSVHeader(header []string, report *CSVImportReport) ([]*csvColumn, int, error) {
+	byName := make(map[string]*csvColumn, len(coinCSVColumns))
+	for i := range coinCSVColumns {
+		byName[strings.ToLower(coinCSVColumns[i].header)] = &coinCSVColumns[i]
+	}
+
+	columns := make([]*csvColumn, len(header))
+	idIndex := -1
+	used := make(map[string]bool)
+	known := 0
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		col, ok := byName[name]
+		if !ok {
+			report.IgnoredColumns = append(report.IgnoredColumns, h)
+			continue
+		}
+		if used[name] {
+			return nil, 0, fmt.Errorf("duplicate column %q", col.header)
+		}
+		used[name] = true
+		known++
+		if col.header == "ID" {
+			idIndex = i
+			continue
+		}
+		columns[i] = col
+	}
+	if known == 0 {
+		return nil, 0, fmt.Errorf("no known columns in csv header")
+	}
+	return columns, idIndex, nil
+}
+
+func (s *CoinService) importCSVRecord(
+	ctx context.Context,
+	line int,
+	record []string,
+	columns []*csvColumn,
+	idIndex int,
+	coinsByID map[uuid.UUID]*domain.Coin,
+	groupsByName map[string]*domain.Group,
+	seen map[uuid.UUID]int,
+	dryRun bool,
+	report *CSVImportReport,
+) []CSVImportError {
+	var errs []CSVImportError
+	rowErr := func(column string, err error) {
+		errs = append(errs, CSVImportError{Row: line, Column: column, Error: err.Error()})
+	}
+
+	// Resolve the target coin
+	var coin *domain.Coin
+	before := &domain.Coin{}
+	update := false
+	idStr := ""
+	if idIndex >= 0 && idIndex < len(record) {
+		idStr = strings.TrimSpace(record[idIndex])
+	}
+	if idStr == "" {
+		coin = &domain.Coin{ID: uuid.New(), Images: []domain.CoinImage{}, Status: domain.CoinStatusReady}
+	} else {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			rowErr("ID", fmt.Errorf("invalid uuid %q", idStr))
+			return errs
+		}
+		if prev, ok := seen[id]; ok {
+			rowErr("ID", fmt.Errorf("duplicate of row %d", prev))
+			return errs
+		}
+		seen[id] = line
+		if current, ok := coinsByID[id]; ok {
+			copied := *current
+			copied.Provenance = maps.Clone(current.Provenance)
+			coin = &copied
+			before = current
+			update = true
+		} else {
+			// Unknown ID: recreate the coin under it, e.g. moving data between instances
+			coin = &domain.Coin{ID: id, Images: []domain.CoinImage{}, Status: domain.CoinStatusReady}
+		}
+	}
+
+	row := &coinCSVRow{coin: coin}
+	for i, col := range columns {
+		if col == nil || i >= len(record) {
+			continue
+		}
+		if err := col.set(row, strings.TrimSpace(record[i])); err != nil {
+			rowErr(col.header, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	coin.SetProvenance(domain.ProvenanceUser, time.Now(), nil, coin.ChangedFields(before)...)
+
+	if row.hasGroup {
+		groupID, err := s.resolveCSVGroup(ctx, row.groupName, groupsByName, dryRun, report)
+		if err != nil {
+			rowErr("Group", err)
+			return errs
+		}
+		coin.GroupID = groupID
+	}
+
+	if !dryRun {
+		var err error
+		if update {
+			err = s.repo.Update(ctx, coin)
+		} else {
+			err = s.repo.Save(ctx, coin)
+		}
+		if err != nil {
+			rowErr("", err)
+			return errs
+		}
+		if row.hasTags {
+			if _, err := s.setCoinTags(ctx, coin.ID, before.Tags, row.tags); err != nil {
+				rowErr("Tags", err)
+				return errs
+			}
+		}
+	}
+
+	if update {
+		report.Updated++
+	} else {
+		report.Created++
+	}
+	return nil
+}
+
+// resolveCSVGroup finds a group by name, creating it unless this is a dry run.
+func (s *CoinService) resolveCSVGroup(ctx context.Context, name string, groupsByName map[string]*domain.Group, dryRun bool, report *CSVImportReport) (*int, error) {
+	if name == "" {
+		return nil, nil
+	}
+	key := strings.ToLower(name)
+	if g, ok := groupsByName[key]; ok {
+		if g.IsSmart() {
+			return nil, fmt.Errorf("%w: %s", ErrSmartGroupCoins, name)
+		}
+		return &g.ID, nil
+	}
+
+	group := &domain.Group{Name: name}
+	if !dryRun {
+		created, err := s.groupRepo.Create(ctx, name, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create group %q: %w", name, err)
+		}
+		group = created
+	}
+	groupsByName[key] = group
+	report.GroupsCreated = append(report.GroupsCreated, name)
+	return &group.ID, nil
+}
+
+func isBlankCSVRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
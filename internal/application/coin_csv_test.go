@@ -0,0 +1,233 @@
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func csvTestCoin() *domain.Coin {
+	groupID := 3
+	acquired := time.Date(2020, 5, 17, 0, 0, 0, 0, time.UTC)
+	sold := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	mintage, _ := domain.NewMintage(1500000)
+	km, _ := domain.NewKMCode("KM# 832")
+	return &domain.Coin{
+		ID:             uuid.New(),
+		Name:           "100 Pesetas",
+		Country:        "Spain",
+		Year:           mustYear(1966),
+		FaceValue:      "100",
+		Currency:       "Peseta",
+		Material:       "Silver",
+		Grade:          mustGrade("EBC"),
+		Mintage:        mintage,
+		Mint:           "Madrid",
+		MintMark:       "M",
+		WeightG:        19,
+		DiameterMM:     34,
+		AcquiredAt:     &acquired,
+		PricePaid:      12.5,
+		SoldAt:         &sold,
+		SoldPrice:      20,
+		PersonalNotes:  "From grandpa, \"the good one\"",
+		GroupID:        &groupID,
+		Tags:           []string{"euro", "to sell"},
+		SaleChannel:    "Wallapop",
+		TechnicalNotes: "Small scratch",
+		KMCode:         km,
+		NumistaNumber:  1234,
+		GeminiModel:    "kept-on-update",
+		Status:         domain.CoinStatusReady,
+	}
+}
+
+func TestExportCoinsCSV(t *testing.T) {
+	service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+	ctx := context.Background()
+	coin := csvTestCoin()
+
+	mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{Coins: []*domain.Coin{coin}}, nil)
+	mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 3, Name: "Franco"}}, nil)
+
+	data, err := service.ExportCoinsCSV(ctx)
+	assert.NoError(t, err)
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 2) {
+		row := make(map[string]string)
+		for i, h := range records[0] {
+			row[h] = records[1][i]
+		}
+		assert.Equal(t, coin.ID.String(), row["ID"])
+		assert.Equal(t, "1966", row["Year"])
+		assert.Equal(t, "1500000", row["Mintage"])
+		assert.Equal(t, "M", row["Mint Mark"])
+		assert.Equal(t, "2020-05-17", row["Acquired Date"])
+		assert.Equal(t, "Franco", row["Group"])
+		assert.Equal(t, "Wallapop", row["Sale Channel"])
+		assert.Equal(t, coin.PersonalNotes, row["Notes"])
+		assert.Equal(t, "Small scratch", row["Technical Notes"])
+		assert.Equal(t, "euro; to sell", row["Tags"])
+	}
+}
+
+func TestImportCoinsCSV(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Round Trip Updates Existing Coin", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		coin := csvTestCoin()
+
+		mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{Coins: []*domain.Coin{coin}}, nil).Times(2)
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 3, Name: "Franco"}}, nil).Times(2)
+
+		data, err := service.ExportCoinsCSV(ctx)
+		assert.NoError(t, err)
+
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, got *domain.Coin) error {
+			assert.Equal(t, coin.ID, got.ID)
+			assert.Equal(t, coin.Name, got.Name)
+			assert.Equal(t, coin.Year, got.Year)
+			assert.Equal(t, coin.Mintage, got.Mintage)
+			assert.Equal(t, coin.Grade, got.Grade)
+			assert.Equal(t, coin.KMCode, got.KMCode)
+			assert.Equal(t, coin.PricePaid, got.PricePaid)
+			assert.Equal(t, coin.SoldPrice, got.SoldPrice)
+			assert.True(t, coin.AcquiredAt.Equal(*got.AcquiredAt))
+			assert.True(t, coin.SoldAt.Equal(*got.SoldAt))
+			assert.Equal(t, coin.SaleChannel, got.SaleChannel)
+			assert.Equal(t, coin.PersonalNotes, got.PersonalNotes)
+			assert.Equal(t, coin.TechnicalNotes, got.TechnicalNotes)
+			assert.Equal(t, 3, *got.GroupID)
+			assert.Equal(t, coin.NumistaNumber, got.NumistaNumber)
+			// Fields outside the CSV are kept
+			assert.Equal(t, "kept-on-update", got.GeminiModel)
+			// Unchanged values keep their origin
+			assert.Empty(t, got.Provenance)
+			return nil
+		})
+
+		report, err := service.ImportCoinsCSV(ctx, bytes.NewReader(data), false)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Rows)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 0, report.Failed)
+		assert.Empty(t, report.Errors)
+	})
+
+	t.Run("Creates Coins And Groups", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		restoredID := uuid.New()
+
+		mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{}, nil)
+		mockGroupRepo.EXPECT().List(ctx).Return(nil, nil)
+		mockGroupRepo.EXPECT().Create(ctx, "Euros", "").Return(&domain.Group{ID: 9, Name: "Euros"}, nil)
+
+		var saved []*domain.Coin
+		mockRepo.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, c *domain.Coin) error {
+			saved = append(saved, c)
+			return nil
+		}).Times(2)
+
+		// Headers are matched case-insensitively; blank lines are skipped
+		input := "ID,name,year,group,price paid,mintage,extra\n" +
+			restoredID.String() + ",2 Euro,2002,Euros,\"3,50\",1.000.000,x\n" +
+			"\n" +
+			",1 Euro,2002,euros,1,,y\n"
+
+		report, err := service.ImportCoinsCSV(ctx, strings.NewReader(input), false)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Rows)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, []string{"Euros"}, report.GroupsCreated)
+		assert.Equal(t, []string{"extra"}, report.IgnoredColumns)
+
+		if assert.Len(t, saved, 2) {
+			assert.Equal(t, restoredID, saved[0].ID)
+			assert.Equal(t, 3.5, saved[0].PricePaid)
+			assert.Equal(t, int64(1000000), saved[0].Mintage.Int64())
+			assert.Equal(t, 9, *saved[0].GroupID)
+			assert.Equal(t, domain.CoinStatusReady, saved[0].Status)
+			assert.Equal(t, domain.ProvenanceUser, saved[0].Provenance["year"].Source)
+			assert.NotEqual(t, uuid.Nil, saved[1].ID)
+			assert.Equal(t, 9, *saved[1].GroupID)
+		}
+	})
+
+	t.Run("Dry Run Reports Row Errors", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		dup := uuid.New()
+
+		mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{}, nil)
+		mockGroupRepo.EXPECT().List(ctx).Return(nil, nil)
+		// Nothing is written: no Save, Update or group Create expected
+
+		input := "ID,Name,Year,Mintage,Acquired Date,Weight (g),Group\n" +
+			",Ok,1990,10,2020-01-01,5,New Group\n" +
+			",Bad year,99999,10,,,\n" +
+			",Bad mintage and date,1990,-5,01/02/2020,,\n" +
+			"not-a-uuid,Bad id,1990,,,,\n" +
+			dup.String() + ",First,1990,,,,\n" +
+			dup.String() + ",Second,1990,,,,\n" +
+			",Negative weight,1990,,,-1,\n"
+
+		report, err := service.ImportCoinsCSV(ctx, strings.NewReader(input), true)
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 7, report.Rows)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 5, report.Failed)
+		assert.Equal(t, []string{"New Group"}, report.GroupsCreated)
+
+		type key struct {
+			row    int
+			column string
+		}
+		got := make(map[key]bool)
+		for _, e := range report.Errors {
+			got[key{e.Row, e.Column}] = true
+		}
+		assert.True(t, got[key{3, "Year"}])
+		assert.True(t, got[key{4, "Mintage"}])
+		assert.True(t, got[key{4, "Acquired Date"}])
+		assert.True(t, got[key{5, "ID"}])
+		assert.True(t, got[key{7, "ID"}])
+		assert.True(t, got[key{8, "Weight (g)"}])
+		assert.Len(t, report.Errors, 6)
+	})
+
+	t.Run("Save Error Is A Row Error", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockRepo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{}, nil)
+		mockGroupRepo.EXPECT().List(ctx).Return(nil, nil)
+		mockRepo.EXPECT().Save(ctx, gomock.Any()).Return(assert.AnError)
+
+		report, err := service.ImportCoinsCSV(ctx, strings.NewReader("Name\nX\n"), false)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, 2, report.Errors[0].Row)
+	})
+
+	t.Run("Invalid Header", func(t *testing.T) {
+		service, _, _, _, _, _, _, _, _ := setupTest(t)
+
+		_, err := service.ImportCoinsCSV(ctx, strings.NewReader("foo,bar\n1,2\n"), true)
+		assert.Error(t, err)
+
+		_, err = service.ImportCoinsCSV(ctx, strings.NewReader("Name,name\nA,B\n"), true)
+		assert.Error(t, err)
+
+		_, err = service.ImportCoinsCSV(ctx, strings.NewReader(""), true)
+		assert.Error(t, err)
+	})
+}
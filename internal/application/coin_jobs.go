@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// JobKindProcessCoin runs the AddCoin pipeline (AI analysis, image
+	// processing and group management) in the background.
+	JobKindProcessCoin = "process_coin"
+
+	processCoinMaxAttempts = 5
+)
+
+// Steps of a process_coin job. Each one is tracked in job_steps.
+const (
+	stepAnalysis = "analysis"
+	stepImages   = "images"
+	stepGroup    = "group"
+	stepFinalize = "finalize"
+)
+
+// processCoinPayload is everything the worker needs to finish a coin that
+// AddCoin accepted.
+type processCoinPayload struct {
+	GroupName         string  `json:"group_name"`
+	UserNotes         string  `json:"user_notes"`
+	ModelName         string  `json:"model_name"`
+	Temperature       float32 `json:"temperature"`
+	FrontFilename     string  `json:"front_filename"`
+	BackFilename      string  `json:"back_filename"`
+	OriginalFrontPath string  `json:"original_front_path"`
+	OriginalBackPath  string  `json:"original_back_path"`
+}
+
+// processedImages is the output of the image processing step.
+type processedImages struct {
+	ProcessedFrontPath string `json:"processed_front_path"`
+	ProcessedBackPath  string `json:"processed_back_path"`
+	ThumbFrontPath     string `json:"thumb_front_path"`
+	ThumbBackPath      string `json:"thumb_back_path"`
+}
+
+type groupStepResult struct {
+	GroupID *int `json:"group_id"`
+}
+
+// GetJob returns a job together with the state of each of its steps.
+func (s *CoinService) GetJob(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	if s.jobRepo == nil {
+		return nil, fmt.Errorf("job queue is not configured")
+	}
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := s.jobRepo.ListSteps(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.Steps = steps
+	return job, nil
+}
+
+// ProcessCoinJob is the worker handler for JobKindProcessCoin. Steps that
+// succeeded in a previous attempt are not run again.
+func (s *CoinService) ProcessCoinJob(ctx context.Context, job *domain.Job) error {
+	err := s.processCoinJob(ctx, job)
+	if err != nil && job.CoinID != nil && job.Attempts >= job.MaxAttempts {
+		if statusErr := s.repo.UpdateStatus(ctx, *job.CoinID, domain.CoinStatusFailed); statusErr != nil {
+			slog.Error("Failed to mark coin as failed", "coin_id", *job.CoinID, "error", statusErr)
+		}
+	}
+	return err
+}
+
+func (s *CoinService) processCoinJob(ctx context.Context, job *domain.Job) error {
+	if job.CoinID == nil {
+		return fmt.Errorf("job %s has no coin", job.ID)
+	}
+	coinID := *job.CoinID
+
+	var payload processCoinPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid job payload: %w", err)
+	}
+
+	steps, err := s.jobRepo.ListSteps(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	done := make(map[string]json.RawMessage)
+	for _, step := range steps {
+		if step.Status == domain.JobStatusSucceeded {
+			done[step.Name] = step.Output
+		}
+	}
+	lastAttempt := job.Attempts >= job.MaxAttempts
+
+	var (
+		analysis domain.CoinAnalysisResult
+		imgs     processedImages
+		grp      groupStepResult
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		errs     []error
+	)
+	run := func(name string, out any, fn func() error) {
+		defer wg.Done()
+		if err := s.runJobStep(ctx, job.ID, name, done, out, fn); err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		}
+	}
+
+	// Task A, B and C run in parallel like the inline pipeline
+	wg.Add(3)
+	go run(stepAnalysis, &analysis, func() error {
+		res, err := s.analyzeCoin(ctx, coinID, payload)
+		if err != nil {
+			if !lastAttempt {
+				return err
+			}
+			// Out of retries: store the placeholder so the coin is still usable
+			res = analysisPlaceholder(err)
+		}
+		analysis = *res
+		return nil
+	})
+	go run(stepImages, &imgs, func() error {
+		frontBytes, err := s.storage.ReadFile(payload.OriginalFrontPath)
+		if err != nil {
+			return fmt.Errorf("failed to read original front: %w", err)
+		}
+		backBytes, err := s.storage.ReadFile(payload.OriginalBackPath)
+		if err != nil {
+			return fmt.Errorf("failed to read original back: %w", err)
+		}
+		res, err := s.processImages(ctx, coinID, frontBytes, backBytes)
+		if err != nil {
+			return err
+		}
+		imgs = *res
+		return nil
+	})
+	go run(stepGroup, &grp, func() error {
+		groupID, err := s.resolveGroup(ctx, coinID, payload.GroupName)
+		if err != nil {
+			return err
+		}
+		grp.GroupID = groupID
+		return nil
+	})
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	return s.runJobStep(ctx, job.ID, stepFinalize, done, nil, func() error {
+		coin, err := s.repo.GetByID(ctx, coinID)
+		if err != nil {
+			return fmt.Errorf("failed to get coin: %w", err)
+		}
+
+		applyAnalysis(coin, &analysis)
+		coin.GroupID = grp.GroupID
+
+		// A retried finalize may find some of the images already stored
+		processed := &domain.Coin{ID: coinID}
+		if err := s.addProcessedImageRecords(processed, &imgs, payload); err != nil {
+			return err
+		}
+		for _, img := range processed.Images {
+			if hasImagePath(coin.Images, img.Path) {
+				continue
+			}
+			if err := s.repo.AddImage(ctx, img); err != nil {
+				return err
+			}
+			coin.Images = append(coin.Images, img)
+		}
+
+		coin.Status = domain.CoinStatusReady
+		if err := s.repo.Update(ctx, coin); err != nil {
+			return fmt.Errorf("failed to update coin: %w", err)
+		}
+		slog.Info("Successfully processed coin", "coin_id", coinID, "job_id", job.ID)
+		s.fulfilWants(ctx, coin)
+
+		s.triggerNumistaEnrichment(ctx, coinID)
+		return nil
+	})
+}
+
+// runJobStep records the execution of fn as a job step. When the step already
+// succeeded in an earlier attempt, its stored output is decoded into out and
+// fn is skipped. On success out is persisted as the step output.
+func (s *CoinService) runJobStep(ctx context.Context, jobID uuid.UUID, name string, done map[string]json.RawMessage, out any, fn func() error) error {
+	if previous, ok := done[name]; ok {
+		if out == nil || json.Unmarshal(previous, out) == nil {
+			return nil
+		}
+		// Unreadable output, run the step again
+	}
+
+	if err := s.jobRepo.StartStep(ctx, jobID, name); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		if stepErr := s.jobRepo.FailStep(ctx, jobID, name, err.Error()); stepErr != nil {
+			slog.Error("Failed to record step failure", "job_id", jobID, "step", name, "error", stepErr)
+		}
+		return fmt.Errorf("step %s failed: %w", name, err)
+	}
+
+	var output json.RawMessage
+	if out != nil {
+		var err error
+		if output, err = json.Marshal(out); err != nil {
+			return fmt.Errorf("failed to marshal %s output: %w", name, err)
+		}
+	}
+	return s.jobRepo.CompleteStep(ctx, jobID, name, output)
+}
+
+func hasImagePath(images []domain.CoinImage, path string) bool {
+	for _, img := range images {
+		if img.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type jobTestMocks struct {
+	repo         *mocks.MockCoinRepository
+	groupRepo    *mocks.MockGroupRepository
+	imageService *mocks.MockImageService
+	aiService    *mocks.MockAIService
+	storage      *mocks.MockStorageService
+	bgRemover    *mocks.MockBackgroundRemover
+	jobRepo      *mocks.MockJobRepository
+	wishlistRepo *mocks.MockWishlistRepository
+}
+
+// setupJobTest builds a service backed by the job queue. Numista is left out
+// so no enrichment goroutines are started.
+func setupJobTest(t *testing.T) (*application.CoinService, jobTestMocks) {
+	ctrl := gomock.NewController(t)
+	m := jobTestMocks{
+		repo:         mocks.NewMockCoinRepository(ctrl),
+		groupRepo:    mocks.NewMockGroupRepository(ctrl),
+		imageService: mocks.NewMockImageService(ctrl),
+		aiService:    mocks.NewMockAIService(ctrl),
+		storage:      mocks.NewMockStorageService(ctrl),
+		bgRemover:    mocks.NewMockBackgroundRemover(ctrl),
+		jobRepo:      mocks.NewMockJobRepository(ctrl),
+		wishlistRepo: mocks.NewMockWishlistRepository(ctrl),
+	}
+	service := application.NewCoinService(m.repo, m.groupRepo, nil, m.wishlistRepo, nil, m.imageService, m.aiService, m.storage, m.bgRemover, nil, nil, m.jobRepo)
+	return service, m
+}
+
+func newProcessCoinJob(t *testing.T, coinID uuid.UUID, attempts int) *domain.Job {
+	payload, err := json.Marshal(map[string]any{
+		"group_name":          "G",
+		"model_name":          "m",
+		"front_filename":      "f.jpg",
+		"back_filename":       "b.jpg",
+		"original_front_path": "storage/coins/x/original_front.jpg",
+		"original_back_path":  "storage/coins/x/original_back.jpg",
+	})
+	assert.NoError(t, err)
+	return &domain.Job{
+		ID:          uuid.New(),
+		Kind:        application.JobKindProcessCoin,
+		CoinID:      &coinID,
+		Payload:     payload,
+		Attempts:    attempts,
+		MaxAttempts: 5,
+	}
+}
+
+func TestAddCoin_Queued(t *testing.T) {
+	t.Run("Saves Pending Coin And Enqueues", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+
+		m.storage.EXPECT().SaveFile(gomock.Any(), "original_front.jpg", gomock.Any()).Return("of", nil)
+		m.storage.EXPECT().SaveFile(gomock.Any(), "original_back.jpg", gomock.Any()).Return("ob", nil)
+		m.imageService.EXPECT().GetMetadata(gomock.Any()).Return(100, 100, int64(100), "image/jpeg", nil).Times(2)
+		m.repo.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, coin *domain.Coin) error {
+			assert.Equal(t, domain.CoinStatusPending, coin.Status)
+			assert.Equal(t, "notes", coin.PersonalNotes)
+			assert.Len(t, coin.Images, 2)
+			return nil
+		})
+		m.jobRepo.EXPECT().Enqueue(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, job *domain.Job) error {
+			assert.Equal(t, application.JobKindProcessCoin, job.Kind)
+			assert.Contains(t, string(job.Payload), `"group_name":"G"`)
+			job.ID = uuid.New()
+			return nil
+		})
+
+		coin, job, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "notes", "", "", 0, "m", 0.1)
+		assert.NoError(t, err)
+		assert.NotNil(t, job)
+		assert.Equal(t, coin.ID, *job.CoinID)
+	})
+
+	t.Run("Enqueue Error Marks Coin Failed", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+
+		m.storage.EXPECT().SaveFile(gomock.Any(), gomock.Any(), gomock.Any()).Return("p", nil).Times(2)
+		m.imageService.EXPECT().GetMetadata(gomock.Any()).Return(100, 100, int64(100), "image/jpeg", nil).Times(2)
+		m.repo.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		m.jobRepo.EXPECT().Enqueue(ctx, gomock.Any()).Return(assert.AnError)
+		m.repo.EXPECT().UpdateStatus(ctx, gomock.Any(), domain.CoinStatusFailed).Return(nil)
+
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestProcessCoinJob(t *testing.T) {
+	coinID := uuid.New()
+	pendingCoin := func() *domain.Coin {
+		return &domain.Coin{
+			ID:     coinID,
+			Status: domain.CoinStatusPending,
+			Images: []domain.CoinImage{
+				{Path: "storage/coins/x/original_front.jpg", ImageType: "original", Side: "front"},
+				{Path: "storage/coins/x/original_back.jpg", ImageType: "original", Side: "back"},
+			},
+		}
+	}
+	expectImagePipeline := func(m jobTestMocks) {
+		m.storage.EXPECT().ReadFile(gomock.Any()).Return([]byte("raw"), nil).Times(2)
+		m.bgRemover.EXPECT().RemoveBackground(gomock.Any(), gomock.Any()).Return([]byte("b"), nil).Times(2)
+		m.imageService.EXPECT().CropToContent(gomock.Any()).Return([]byte("c"), nil).Times(2)
+		m.storage.EXPECT().SaveFile(coinID, gomock.Any(), gomock.Any()).DoAndReturn(func(id uuid.UUID, name string, _ any) (string, error) {
+			return name, nil
+		}).Times(2)
+		m.imageService.EXPECT().GenerateThumbnail(gomock.Any(), 300).DoAndReturn(func(path string, _ int) (string, error) {
+			return "thumb_" + path, nil
+		}).Times(2)
+	}
+	allowSteps := func(m jobTestMocks) {
+		m.jobRepo.EXPECT().StartStep(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		m.jobRepo.EXPECT().CompleteStep(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+		job := newProcessCoinJob(t, coinID, 1)
+
+		m.jobRepo.EXPECT().ListSteps(ctx, job.ID).Return(nil, nil)
+		allowSteps(m)
+		m.aiService.EXPECT().AnalyzeCoin(gomock.Any(), gomock.Any(), gomock.Any(), "m", gomock.Any(), "es").Return(&domain.CoinAnalysisResult{Name: "Peseta", Year: 1975}, nil)
+		expectImagePipeline(m)
+		m.groupRepo.EXPECT().GetByName(gomock.Any(), "G").Return(&domain.Group{ID: 7}, nil)
+		m.repo.EXPECT().GetByID(gomock.Any(), coinID).Return(pendingCoin(), nil)
+		m.imageService.EXPECT().GetMetadata(gomock.Any()).Return(100, 100, int64(100), "image/png", nil).Times(4)
+		m.repo.EXPECT().AddImage(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+		m.repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, coin *domain.Coin) error {
+			assert.Equal(t, domain.CoinStatusReady, coin.Status)
+			assert.Equal(t, "Peseta", coin.Name)
+			assert.Equal(t, 1975, coin.Year.Int())
+			assert.Equal(t, 7, *coin.GroupID)
+			assert.Len(t, coin.Images, 6)
+			return nil
+		})
+
+		assert.NoError(t, service.ProcessCoinJob(ctx, job))
+	})
+
+	t.Run("Skips Completed Steps", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+		job := newProcessCoinJob(t, coinID, 2)
+		groupID := 3
+
+		m.jobRepo.EXPECT().ListSteps(ctx, job.ID).Return([]domain.JobStep{
+			{Name: "analysis", Status: domain.JobStatusSucceeded, Output: mustJSON(t, domain.CoinAnalysisResult{Name: "Duro"})},
+			{Name: "images", Status: domain.JobStatusSucceeded, Output: mustJSON(t, map[string]string{
+				"processed_front_path": "pf", "processed_back_path": "pb", "thumb_front_path": "tf", "thumb_back_path": "tb",
+			})},
+			{Name: "group", Status: domain.JobStatusSucceeded, Output: mustJSON(t, map[string]any{"group_id": groupID})},
+		}, nil)
+		// Only finalize runs
+		m.jobRepo.EXPECT().StartStep(gomock.Any(), job.ID, "finalize").Return(nil)
+		m.jobRepo.EXPECT().CompleteStep(gomock.Any(), job.ID, "finalize", gomock.Any()).Return(nil)
+
+		coin := pendingCoin()
+		// A previous finalize already stored the processed front
+		coin.Images = append(coin.Images, domain.CoinImage{Path: "pf", ImageType: "crop", Side: "front"})
+		m.repo.EXPECT().GetByID(gomock.Any(), coinID).Return(coin, nil)
+		m.imageService.EXPECT().GetMetadata(gomock.Any()).Return(100, 100, int64(100), "image/png", nil).Times(4)
+		m.repo.EXPECT().AddImage(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+		m.repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, coin *domain.Coin) error {
+			assert.Equal(t, "Duro", coin.Name)
+			assert.Equal(t, groupID, *coin.GroupID)
+			return nil
+		})
+
+		assert.NoError(t, service.ProcessCoinJob(ctx, job))
+	})
+
+	t.Run("Fulfils Wishlist", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+		job := newProcessCoinJob(t, coinID, 2)
+
+		m.jobRepo.EXPECT().ListSteps(ctx, job.ID).Return([]domain.JobStep{
+			{Name: "analysis", Status: domain.JobStatusSucceeded, Output: mustJSON(t, domain.CoinAnalysisResult{Name: "2 Euro", NumistaNumber: 95420})},
+			{Name: "images", Status: domain.JobStatusSucceeded, Output: mustJSON(t, map[string]string{
+				"processed_front_path": "pf", "processed_back_path": "pb", "thumb_front_path": "tf", "thumb_back_path": "tb",
+			})},
+			{Name: "group", Status: domain.JobStatusSucceeded, Output: mustJSON(t, map[string]any{"group_id": nil})},
+		}, nil)
+		allowSteps(m)
+		m.repo.EXPECT().GetByID(gomock.Any(), coinID).Return(pendingCoin(), nil)
+		m.imageService.EXPECT().GetMetadata(gomock.Any()).Return(100, 100, int64(100), "image/png", nil).Times(4)
+		m.repo.EXPECT().AddImage(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+		m.repo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+		m.wishlistRepo.EXPECT().Fulfil(gomock.Any(), 95420, coinID).Return([]*domain.WishlistEntry{{ID: 1, NumistaID: 95420}}, nil)
+
+		assert.NoError(t, service.ProcessCoinJob(ctx, job))
+	})
+
+	t.Run("AI Error Is Retried", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+		job := newProcessCoinJob(t, coinID, 1)
+
+		m.jobRepo.EXPECT().ListSteps(ctx, job.ID).Return(nil, nil)
+		allowSteps(m)
+		m.aiService.EXPECT().AnalyzeCoin(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+		m.jobRepo.EXPECT().FailStep(gomock.Any(), job.ID, "analysis", gomock.Any()).Return(nil)
+		expectImagePipeline(m)
+		m.groupRepo.EXPECT().GetByName(gomock.Any(), "G").Return(&domain.Group{ID: 7}, nil)
+
+		err := service.ProcessCoinJob(ctx, job)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("AI Error On Last Attempt Uses Placeholder", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+		job := newProcessCoinJob(t, coinID, 5)
+
+		m.jobRepo.EXPECT().ListSteps(ctx, job.ID).Return(nil, nil)
+		allowSteps(m)
+		failed := &domain.AnalysisFailedError{Err: assert.AnError, Details: map[string]any{"attempts": []string{"not json"}}}
+		m.aiService.EXPECT().AnalyzeCoin(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, failed)
+		expectImagePipeline(m)
+		m.groupRepo.EXPECT().GetByName(gomock.Any(), "G").Return(&domain.Group{ID: 7}, nil)
+		m.repo.EXPECT().GetByID(gomock.Any(), coinID).Return(pendingCoin(), nil)
+		m.imageService.EXPECT().GetMetadata(gomock.Any()).Return(100, 100, int64(100), "image/png", nil).Times(4)
+		m.repo.EXPECT().AddImage(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+		m.repo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, coin *domain.Coin) error {
+			assert.Equal(t, "Analysis failed", coin.Description)
+			assert.Equal(t, domain.CoinStatusReady, coin.Status)
+			assert.Equal(t, []string{"not json"}, coin.GeminiDetails["attempts"])
+			assert.Equal(t, assert.AnError.Error(), coin.GeminiDetails["error"])
+			return nil
+		})
+
+		assert.NoError(t, service.ProcessCoinJob(ctx, job))
+	})
+
+	t.Run("Image Error On Last Attempt Marks Coin Failed", func(t *testing.T) {
+		service, m := setupJobTest(t)
+		ctx := context.Background()
+		job := newProcessCoinJob(t, coinID, 5)
+
+		m.jobRepo.EXPECT().ListSteps(ctx, job.ID).Return(nil, nil)
+		allowSteps(m)
+		m.aiService.EXPECT().AnalyzeCoin(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.CoinAnalysisResult{}, nil)
+		m.storage.EXPECT().ReadFile(gomock.Any()).Return(nil, assert.AnError)
+		m.jobRepo.EXPECT().FailStep(gomock.Any(), job.ID, "images", gomock.Any()).Return(nil)
+		m.groupRepo.EXPECT().GetByName(gomock.Any(), "G").Return(&domain.Group{ID: 7}, nil)
+		m.repo.EXPECT().UpdateStatus(ctx, coinID, domain.CoinStatusFailed).Return(nil)
+
+		assert.Error(t, service.ProcessCoinJob(ctx, job))
+	})
+}
+
+func TestGetJob(t *testing.T) {
+	service, m := setupJobTest(t)
+	ctx := context.Background()
+	jobID := uuid.New()
+
+	m.jobRepo.EXPECT().GetByID(ctx, jobID).Return(&domain.Job{ID: jobID, Status: domain.JobStatusRunning}, nil)
+	m.jobRepo.EXPECT().ListSteps(ctx, jobID).Return([]domain.JobStep{{Name: "analysis", Status: domain.JobStatusSucceeded}}, nil)
+
+	job, err := service.GetJob(ctx, jobID)
+	assert.NoError(t, err)
+	assert.Len(t, job.Steps, 1)
+}
+
+func mustJSON(t *testing.T, v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return data
+}
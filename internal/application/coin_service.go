@@ -5,9 +5,12 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,9 +21,27 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrCoinNotFound is returned when a coin does not exist in the collection of
+// the caller.
+var ErrCoinNotFound = errors.New("coin not found")
+
+var (
+	// ErrSmartGroupCoins is returned when assigning a coin to a smart group,
+	// whose coins are the ones matching its filter.
+	ErrSmartGroupCoins = errors.New("coins cannot be assigned to a smart group")
+	// ErrGroupHasCoins is returned when turning a group that has coins
+	// assigned into a smart group.
+	ErrGroupHasCoins = errors.New("group has coins assigned")
+	// ErrSmartGroupParent is returned when putting a group under a smart
+	// group, or turning a group with subgroups into one.
+	ErrSmartGroupParent = errors.New("a smart group cannot contain other groups")
+	ErrGroupNotFound    = errors.New("group not found")
+)
+
 type NumistaService interface {
 	SearchTypes(ctx context.Context, query, category, year, issuer string, count int) (*numista.TypeSearchResponse, error)
 	GetType(ctx context.Context, id int) (map[string]any, error)
+	GetIssues(ctx context.Context, typeID int) ([]numista.Issue, error)
 }
 
 // StorageService interface defined below
@@ -37,42 +58,55 @@ type StorageService interface {
 	SaveGroupFile(groupID int, filename string, content io.Reader) (string, error)
 	EnsureDir(coinID uuid.UUID) (string, error)
 	DeleteCoinDirectory(coinID uuid.UUID) error
+	ReadFile(path string) ([]byte, error)
 }
 
 type CoinService struct {
 	repo          domain.CoinRepository
 	groupRepo     domain.GroupRepository
+	tagRepo       domain.TagRepository
+	wishlistRepo  domain.WishlistRepository
+	checklistRepo domain.ChecklistRepository
 	imageService  domain.ImageService
 	aiService     domain.AIService
 	storage       StorageService
 	bgRemover     domain.BackgroundRemover
 	numistaClient NumistaService
 	priceClient   domain.PriceClient
+	jobRepo       domain.JobRepository
 }
 
 func NewCoinService(
 	repo domain.CoinRepository,
 	groupRepo domain.GroupRepository,
+	tagRepo domain.TagRepository,
+	wishlistRepo domain.WishlistRepository,
+	checklistRepo domain.ChecklistRepository,
 	imageService domain.ImageService,
 	aiService domain.AIService,
 	storage StorageService,
 	bgRemover domain.BackgroundRemover,
 	numistaClient NumistaService,
 	priceClient domain.PriceClient,
+	jobRepo domain.JobRepository,
 ) *CoinService {
 	return &CoinService{
 		repo:          repo,
 		groupRepo:     groupRepo,
+		tagRepo:       tagRepo,
+		wishlistRepo:  wishlistRepo,
+		checklistRepo: checklistRepo,
 		imageService:  imageService,
 		aiService:     aiService,
 		storage:       storage,
 		bgRemover:     bgRemover,
 		numistaClient: numistaClient,
 		priceClient:   priceClient,
+		jobRepo:       jobRepo,
 	}
 }
 
-func (s *CoinService) AddCoin(ctx context.Context, frontData io.Reader, frontFilename string, backData io.Reader, backFilename string, groupName, userNotes, name, mint string, mintage int, modelName string, temperature float32) (*domain.Coin, error) {
+func (s *CoinService) AddCoin(ctx context.Context, frontData io.Reader, frontFilename string, backData io.Reader, backFilename string, groupName, userNotes, name, mint string, mintage int, modelName string, temperature float32) (*domain.Coin, *domain.Job, error) {
 	coinID := uuid.New()
 	// Start Log
 	slog.Info("Starting AddCoin process", "coin_id", coinID)
@@ -80,48 +114,92 @@ func (s *CoinService) AddCoin(ctx context.Context, frontData io.Reader, frontFil
 	// 1. Sync: Read and Save Original Images
 	frontBytes, err := io.ReadAll(frontData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read front file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read front file: %w", err)
 	}
 	backBytes, err := io.ReadAll(backData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read back file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read back file: %w", err)
 	}
 
 	originalFrontPath, err := s.storage.SaveFile(coinID, "original_front.jpg", bytes.NewReader(frontBytes))
 	if err != nil {
-		return nil, fmt.Errorf("failed to save original front: %w", err)
+		return nil, nil, fmt.Errorf("failed to save original front: %w", err)
 	}
 	originalBackPath, err := s.storage.SaveFile(coinID, "original_back.jpg", bytes.NewReader(backBytes))
 	if err != nil {
-		return nil, fmt.Errorf("failed to save original back: %w", err)
+		return nil, nil, fmt.Errorf("failed to save original back: %w", err)
 	}
 
-	// 2. Async: Launch Parallel Tasks
-	var wg sync.WaitGroup
-
-	// Channels for results and errors
-	errChan := make(chan error, 3)
+	payload := processCoinPayload{
+		GroupName:         groupName,
+		UserNotes:         userNotes,
+		ModelName:         modelName,
+		Temperature:       temperature,
+		FrontFilename:     frontFilename,
+		BackFilename:      backFilename,
+		OriginalFrontPath: originalFrontPath,
+		OriginalBackPath:  originalBackPath,
+	}
 
-	// AI Result
-	type aiResult struct {
-		res *domain.CoinAnalysisResult
+	// Without a job queue (tools, tests) everything runs inline like before
+	if s.jobRepo == nil {
+		coin, err := s.processCoinInline(ctx, coinID, frontBytes, backBytes, payload)
+		return coin, nil, err
 	}
-	aiChan := make(chan aiResult, 1)
 
-	// Image Proc Result
-	type imgResult struct {
-		processedFrontPath string
-		processedBackPath  string
-		thumbFrontPath     string
-		thumbBackPath      string
+	// 2. Persist the coin as pending with its originals and hand the rest to the worker
+	coin := &domain.Coin{
+		ID:                coinID,
+		Images:            []domain.CoinImage{},
+		PersonalNotes:     userNotes,
+		GeminiModel:       modelName,
+		GeminiTemperature: float64(temperature),
+		Status:            domain.CoinStatusPending,
+	}
+	if err := s.addImageRecord(coin, originalFrontPath, "original", "front", frontFilename); err != nil {
+		return nil, nil, err
+	}
+	if err := s.addImageRecord(coin, originalBackPath, "original", "back", backFilename); err != nil {
+		return nil, nil, err
+	}
+	if err := s.repo.Save(ctx, coin); err != nil {
+		slog.Error("Failed to save pending coin to DB", "coin_id", coinID, "error", err)
+		return nil, nil, fmt.Errorf("failed to save coin to db: %w", err)
 	}
-	imgChan := make(chan imgResult, 1)
 
-	// Group Result
-	type grpResult struct {
-		id *int
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+	job := &domain.Job{
+		Kind:        JobKindProcessCoin,
+		CoinID:      &coinID,
+		Payload:     payloadJSON,
+		MaxAttempts: processCoinMaxAttempts,
+	}
+	if err := s.jobRepo.Enqueue(ctx, job); err != nil {
+		// Nobody will pick this coin up, don't leave it pending forever
+		if statusErr := s.repo.UpdateStatus(ctx, coinID, domain.CoinStatusFailed); statusErr != nil {
+			slog.Error("Failed to mark coin as failed", "coin_id", coinID, "error", statusErr)
+		}
+		return nil, nil, fmt.Errorf("failed to enqueue coin processing: %w", err)
 	}
-	grpChan := make(chan grpResult, 1)
+	slog.Info("Coin queued for processing", "coin_id", coinID, "job_id", job.ID)
+
+	return coin, job, nil
+}
+
+// processCoinInline runs the whole pipeline synchronously and persists the
+// finished coin in one go.
+func (s *CoinService) processCoinInline(ctx context.Context, coinID uuid.UUID, frontBytes, backBytes []byte, payload processCoinPayload) (*domain.Coin, error) {
+	// 2. Async: Launch Parallel Tasks
+	var wg sync.WaitGroup
+
+	// Channels for results and errors
+	errChan := make(chan error, 3)
+	aiChan := make(chan *domain.CoinAnalysisResult, 1)
+	imgChan := make(chan *processedImages, 1)
+	grpChan := make(chan *int, 1)
 
 	// Context for cancellation if one fails
 	ctx, cancel := context.WithCancel(ctx)
@@ -131,106 +209,36 @@ func (s *CoinService) AddCoin(ctx context.Context, frontData io.Reader, frontFil
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		slog.Info("Starting Task A: AI Analysis", "coin_id", coinID, "model", modelName)
-		// Assuming "es" as default language per previous refactor
-		analysis, err := s.aiService.AnalyzeCoin(ctx, originalFrontPath, originalBackPath, modelName, temperature, "es")
+		analysis, err := s.analyzeCoin(ctx, coinID, payload)
 		if err != nil {
-			slog.Warn("Gemini analysis failed", "coin_id", coinID, "error", err)
 			// Don't fail the whole process, just return empty/error result
-			analysis = &domain.CoinAnalysisResult{
-				Description: "Analysis failed",
-				RawDetails:  map[string]any{"error": err.Error()},
-			}
+			analysis = analysisPlaceholder(err)
 		}
-		slog.Info("Completed Task A: AI Analysis", "coin_id", coinID)
-		aiChan <- aiResult{res: analysis}
+		aiChan <- analysis
 	}()
 
 	// Task B: Image Processing
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		slog.Info("Starting Task B: Image Processing", "coin_id", coinID)
-
-		// Process Front
-		pFrontBytes, err := s.bgRemover.RemoveBackground(ctx, frontBytes)
-		if err != nil {
-			slog.Error("Failed to remove background from front", "coin_id", coinID, "error", err)
-			errChan <- fmt.Errorf("failed to bg remove front: %w", err)
-			return
-		}
-		cFrontBytes, err := s.imageService.CropToContent(pFrontBytes)
-		if err != nil {
-			errChan <- fmt.Errorf("failed to crop front: %w", err)
-			return
-		}
-		pFrontPath, err := s.storage.SaveFile(coinID, "processed_front.png", bytes.NewReader(cFrontBytes))
-		if err != nil {
-			errChan <- fmt.Errorf("failed to save processed front: %w", err)
-			return
-		}
-		tFrontPath, err := s.imageService.GenerateThumbnail(pFrontPath, 300)
-		if err != nil {
-			errChan <- fmt.Errorf("failed to thumb front: %w", err)
-			return
-		}
-
-		// Process Back
-		pBackBytes, err := s.bgRemover.RemoveBackground(ctx, backBytes)
+		imgs, err := s.processImages(ctx, coinID, frontBytes, backBytes)
 		if err != nil {
-			slog.Error("Failed to remove background from back", "coin_id", coinID, "error", err)
-			errChan <- fmt.Errorf("failed to bg remove back: %w", err)
+			errChan <- err
 			return
 		}
-		cBackBytes, err := s.imageService.CropToContent(pBackBytes)
-		if err != nil {
-			errChan <- fmt.Errorf("failed to crop back: %w", err)
-			return
-		}
-		pBackPath, err := s.storage.SaveFile(coinID, "processed_back.png", bytes.NewReader(cBackBytes))
-		if err != nil {
-			errChan <- fmt.Errorf("failed to save processed back: %w", err)
-			return
-		}
-		tBackPath, err := s.imageService.GenerateThumbnail(pBackPath, 300)
-		if err != nil {
-			errChan <- fmt.Errorf("failed to thumb back: %w", err)
-			return
-		}
-
-		imgChan <- imgResult{
-			processedFrontPath: pFrontPath,
-			processedBackPath:  pBackPath,
-			thumbFrontPath:     tFrontPath,
-			thumbBackPath:      tBackPath,
-		}
-		slog.Info("Completed Task B: Image Processing", "coin_id", coinID)
+		imgChan <- imgs
 	}()
 
 	// Task C: Group Management
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		slog.Info("Starting Task C: Group Management", "coin_id", coinID)
-		groupName = strings.TrimSpace(groupName)
-		if groupName == "" {
-			slog.Info("No group name provided, skipping group creation", "coin_id", coinID)
-			grpChan <- grpResult{id: nil}
-			return
-		}
-
-		group, err := s.groupRepo.GetByName(ctx, groupName)
+		groupID, err := s.resolveGroup(ctx, coinID, payload.GroupName)
 		if err != nil {
-			// Try create
-			group, err = s.groupRepo.Create(ctx, groupName, "")
-			if err != nil {
-				slog.Error("Failed to create group", "coin_id", coinID, "group_name", groupName, "error", err)
-				errChan <- fmt.Errorf("failed to create group: %w", err)
-				return
-			}
+			errChan <- err
+			return
 		}
-		slog.Info("Completed Task C: Group Management", "coin_id", coinID, "group_id", group.ID)
-		grpChan <- grpResult{id: &group.ID}
+		grpChan <- groupID
 	}()
 
 	// Wait for all
@@ -248,116 +256,33 @@ func (s *CoinService) AddCoin(ctx context.Context, frontData io.Reader, frontFil
 		}
 	}
 
-	analysisRes := (<-aiChan).res
+	analysisRes := <-aiChan
 	imgRes := <-imgChan
-	grpRes := <-grpChan
+	groupID := <-grpChan
 
 	// 5. Assemble Coin Entity
-	yearVO, err := domain.NewYear(analysisRes.Year)
-	if err != nil {
-		slog.Warn("AI returned invalid year", "coin_id", coinID, "year", analysisRes.Year, "error", err)
-		// We can default to 0 (Unknown) or keep invalid if our validation was strict.
-		// Since validation returned error, let's use 0.
-		yearVO, _ = domain.NewYear(0)
-	}
-
-	kmVO, _ := domain.NewKMCode(analysisRes.KMCode)
-	gradeVO, _ := domain.NewGrade(normalizeGrade(analysisRes.Grade))
-	mintageVO, _ := domain.NewMintage(analysisRes.Mintage)
-
 	coin := &domain.Coin{
 		ID:                coinID,
-		Country:           analysisRes.Country,
-		Year:              yearVO,
-		FaceValue:         analysisRes.FaceValue,
-		Currency:          analysisRes.Currency,
-		Material:          analysisRes.Material,
-		Description:       analysisRes.Description,
-		KMCode:            kmVO,
-		NumistaNumber:     analysisRes.NumistaNumber,
-		MinValue:          analysisRes.MinValue,
-		MaxValue:          analysisRes.MaxValue,
-		Grade:             gradeVO,
-		TechnicalNotes:    analysisRes.Notes,
-		GeminiDetails:     analysisRes.RawDetails,
 		Images:            []domain.CoinImage{},
-		GroupID:           grpRes.id,
-		PersonalNotes:     userNotes,
-		Name:              analysisRes.Name,
-		Mint:              analysisRes.Mint,
-		Mintage:           mintageVO,
-		WeightG:           analysisRes.WeightG,
-		DiameterMM:        analysisRes.DiameterMM,
-		ThicknessMM:       analysisRes.ThicknessMM,
-		Edge:              analysisRes.Edge,
-		Shape:             analysisRes.Shape,
+		GroupID:           groupID,
+		PersonalNotes:     payload.UserNotes,
 		AcquiredAt:        nil, // TODO
 		SoldAt:            nil,
 		PricePaid:         0,
 		SoldPrice:         0,
-		GeminiModel:       modelName,
-		GeminiTemperature: float64(temperature),
+		GeminiModel:       payload.ModelName,
+		GeminiTemperature: float64(payload.Temperature),
+		Status:            domain.CoinStatusReady,
 	}
+	applyAnalysis(coin, analysisRes)
 
-	// Helper to add image RECORD
-	addImage := func(path, imgType, side, originalFilename string) error {
-		w, h, size, mime, err := s.imageService.GetMetadata(path)
-		if err != nil {
-			return fmt.Errorf("failed to get metadata for %s: %w", imgType, err)
-		}
-		coin.Images = append(coin.Images, domain.CoinImage{
-			ID:               uuid.New(),
-			CoinID:           coinID,
-			ImageType:        imgType,
-			Side:             side,
-			Path:             path,
-			Extension:        "png", // processed are png
-			Size:             size,
-			Width:            w,
-			Height:           h,
-			MimeType:         mime,
-			OriginalFilename: originalFilename,
-		})
-		return nil
-	}
-	// For original jpgs
-	addOriginal := func(path, side, originalFilename string) error {
-		w, h, size, mime, err := s.imageService.GetMetadata(path)
-		if err != nil {
-			return fmt.Errorf("failed to get metadata for original: %w", err)
-		}
-		coin.Images = append(coin.Images, domain.CoinImage{
-			ID:               uuid.New(),
-			CoinID:           coinID,
-			ImageType:        "original",
-			Side:             side,
-			Path:             path,
-			Extension:        "jpg",
-			Size:             size,
-			Width:            w,
-			Height:           h,
-			MimeType:         mime,
-			OriginalFilename: originalFilename,
-		})
-		return nil
-	}
-
-	if err := addOriginal(originalFrontPath, "front", frontFilename); err != nil {
+	if err := s.addImageRecord(coin, payload.OriginalFrontPath, "original", "front", payload.FrontFilename); err != nil {
 		return nil, err
 	}
-	if err := addOriginal(originalBackPath, "back", backFilename); err != nil {
+	if err := s.addImageRecord(coin, payload.OriginalBackPath, "original", "back", payload.BackFilename); err != nil {
 		return nil, err
 	}
-	if err := addImage(imgRes.processedFrontPath, "crop", "front", frontFilename); err != nil {
-		return nil, err
-	}
-	if err := addImage(imgRes.processedBackPath, "crop", "back", backFilename); err != nil {
-		return nil, err
-	}
-	if err := addImage(imgRes.thumbFrontPath, "thumbnail", "front", frontFilename); err != nil {
-		return nil, err
-	}
-	if err := addImage(imgRes.thumbBackPath, "thumbnail", "back", backFilename); err != nil {
+	if err := s.addProcessedImageRecords(coin, imgRes, payload); err != nil {
 		return nil, err
 	}
 
@@ -367,23 +292,220 @@ func (s *CoinService) AddCoin(ctx context.Context, frontData io.Reader, frontFil
 		return nil, fmt.Errorf("failed to save coin to db: %w", err)
 	}
 	slog.Info("Successfully saved coin", "coin_id", coinID)
+	s.fulfilWants(ctx, coin)
 
 	// 7. Trigger Numista Enrichment (Async)
-	if s.numistaClient != nil {
-		go func(id uuid.UUID) {
-			bgCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer cancel()
-			if err := s.EnrichCoinWithNumista(bgCtx, id); err != nil {
-				slog.Error("Failed to enrich coin with Numista", "coin_id", id, "error", err)
-			} else {
-				slog.Info("Successfully enriched coin with Numista", "coin_id", id)
-			}
-		}(coin.ID)
-	}
+	s.triggerNumistaEnrichment(ctx, coin.ID)
 
 	return coin, nil
 }
 
+// analyzeCoin is Task A: ask the AI backend to identify the coin.
+func (s *CoinService) analyzeCoin(ctx context.Context, coinID uuid.UUID, payload processCoinPayload) (*domain.CoinAnalysisResult, error) {
+	slog.Info("Starting Task A: AI Analysis", "coin_id", coinID, "model", payload.ModelName)
+	// Assuming "es" as default language per previous refactor
+	analysis, err := s.aiService.AnalyzeCoin(ctx, payload.OriginalFrontPath, payload.OriginalBackPath, payload.ModelName, payload.Temperature, "es")
+	if err != nil {
+		slog.Warn("Gemini analysis failed", "coin_id", coinID, "error", err)
+		return nil, err
+	}
+	slog.Info("Completed Task A: AI Analysis", "coin_id", coinID)
+	return analysis, nil
+}
+
+// analysisPlaceholder is stored when the AI gave up, so the coin can still be
+// saved and edited by hand. The details of a failed analysis, such as the
+// rejected attempts, are kept next to the error.
+func analysisPlaceholder(err error) *domain.CoinAnalysisResult {
+	details := map[string]any{}
+	var failed *domain.AnalysisFailedError
+	if errors.As(err, &failed) {
+		maps.Copy(details, failed.Details)
+	}
+	details["error"] = err.Error()
+	return &domain.CoinAnalysisResult{
+		Description: "Analysis failed",
+		RawDetails:  details,
+	}
+}
+
+// processImages is Task B: remove the background, crop and thumbnail both sides.
+func (s *CoinService) processImages(ctx context.Context, coinID uuid.UUID, frontBytes, backBytes []byte) (*processedImages, error) {
+	slog.Info("Starting Task B: Image Processing", "coin_id", coinID)
+
+	// Process Front
+	pFrontBytes, err := s.bgRemover.RemoveBackground(ctx, frontBytes)
+	if err != nil {
+		slog.Error("Failed to remove background from front", "coin_id", coinID, "error", err)
+		return nil, fmt.Errorf("failed to bg remove front: %w", err)
+	}
+	cFrontBytes, err := s.imageService.CropToContent(pFrontBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to crop front: %w", err)
+	}
+	pFrontPath, err := s.storage.SaveFile(coinID, "processed_front.png", bytes.NewReader(cFrontBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save processed front: %w", err)
+	}
+	tFrontPath, err := s.imageService.GenerateThumbnail(pFrontPath, 300)
+	if err != nil {
+		return nil, fmt.Errorf("failed to thumb front: %w", err)
+	}
+
+	// Process Back
+	pBackBytes, err := s.bgRemover.RemoveBackground(ctx, backBytes)
+	if err != nil {
+		slog.Error("Failed to remove background from back", "coin_id", coinID, "error", err)
+		return nil, fmt.Errorf("failed to bg remove back: %w", err)
+	}
+	cBackBytes, err := s.imageService.CropToContent(pBackBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to crop back: %w", err)
+	}
+	pBackPath, err := s.storage.SaveFile(coinID, "processed_back.png", bytes.NewReader(cBackBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save processed back: %w", err)
+	}
+	tBackPath, err := s.imageService.GenerateThumbnail(pBackPath, 300)
+	if err != nil {
+		return nil, fmt.Errorf("failed to thumb back: %w", err)
+	}
+
+	slog.Info("Completed Task B: Image Processing", "coin_id", coinID)
+	return &processedImages{
+		ProcessedFrontPath: pFrontPath,
+		ProcessedBackPath:  pBackPath,
+		ThumbFrontPath:     tFrontPath,
+		ThumbBackPath:      tBackPath,
+	}, nil
+}
+
+// resolveGroup is Task C: find or create the group by name.
+func (s *CoinService) resolveGroup(ctx context.Context, coinID uuid.UUID, groupName string) (*int, error) {
+	slog.Info("Starting Task C: Group Management", "coin_id", coinID)
+	groupName = strings.TrimSpace(groupName)
+	if groupName == "" {
+		slog.Info("No group name provided, skipping group creation", "coin_id", coinID)
+		return nil, nil
+	}
+
+	group, err := s.groupRepo.GetByName(ctx, groupName)
+	if err != nil {
+		// Try create
+		group, err = s.groupRepo.Create(ctx, groupName, "")
+		if err != nil {
+			slog.Error("Failed to create group", "coin_id", coinID, "group_name", groupName, "error", err)
+			return nil, fmt.Errorf("failed to create group: %w", err)
+		}
+	}
+	if group.IsSmart() {
+		return nil, fmt.Errorf("%w: %s", ErrSmartGroupCoins, groupName)
+	}
+	slog.Info("Completed Task C: Group Management", "coin_id", coinID, "group_id", group.ID)
+	return &group.ID, nil
+}
+
+// applyAnalysis copies the AI result onto the coin.
+func applyAnalysis(coin *domain.Coin, analysisRes *domain.CoinAnalysisResult) {
+	yearVO, err := domain.NewYear(analysisRes.Year)
+	if err != nil {
+		slog.Warn("AI returned invalid year", "coin_id", coin.ID, "year", analysisRes.Year, "error", err)
+		// We can default to 0 (Unknown) or keep invalid if our validation was strict.
+		// Since validation returned error, let's use 0.
+		yearVO, _ = domain.NewYear(0)
+	}
+
+	kmVO, _ := domain.NewKMCode(analysisRes.KMCode)
+	gradeVO, _ := domain.NewGrade(normalizeGrade(analysisRes.Grade))
+	mintageVO, _ := domain.NewMintage(analysisRes.Mintage)
+
+	coin.Country = analysisRes.Country
+	coin.Year = yearVO
+	coin.FaceValue = analysisRes.FaceValue
+	coin.Currency = analysisRes.Currency
+	coin.Material = analysisRes.Material
+	coin.Description = analysisRes.Description
+	coin.KMCode = kmVO
+	coin.NumistaNumber = analysisRes.NumistaNumber
+	coin.MinValue = analysisRes.MinValue
+	coin.MaxValue = analysisRes.MaxValue
+	coin.Grade = gradeVO
+	coin.TechnicalNotes = analysisRes.Notes
+	coin.GeminiDetails = analysisRes.RawDetails
+	coin.Name = analysisRes.Name
+	coin.Mint = analysisRes.Mint
+	coin.MintMark = analysisRes.MintMark
+	coin.Mintage = mintageVO
+	coin.WeightG = analysisRes.WeightG
+	coin.DiameterMM = analysisRes.DiameterMM
+	coin.ThicknessMM = analysisRes.ThicknessMM
+	coin.Edge = analysisRes.Edge
+	coin.Shape = analysisRes.Shape
+
+	coin.SetProvenance(domain.ProvenanceAI, time.Now(), analysisConfidence(analysisRes), analysisFields...)
+}
+
+// analysisFields are the coin fields applyAnalysis fills in.
+var analysisFields = []string{
+	"country", "year", "face_value", "currency", "material", "description",
+	"km_code", "numista_number", "min_value", "max_value", "grade",
+	"technical_notes", "name", "mint", "mint_mark", "mintage", "weight_g", "diameter_mm",
+	"thickness_mm", "edge", "shape",
+}
+
+// analysisConfidence keys the model's confidence by coin field. The model
+// knows technical_notes as "notes".
+func analysisConfidence(analysisRes *domain.CoinAnalysisResult) map[string]float64 {
+	if len(analysisRes.Confidence) == 0 {
+		return nil
+	}
+	conf := maps.Clone(analysisRes.Confidence)
+	if v, ok := conf["notes"]; ok {
+		conf["technical_notes"] = v
+		delete(conf, "notes")
+	}
+	return conf
+}
+
+// addImageRecord reads the file metadata and appends an image RECORD to the coin.
+func (s *CoinService) addImageRecord(coin *domain.Coin, path, imgType, side, originalFilename string) error {
+	w, h, size, mime, err := s.imageService.GetMetadata(path)
+	if err != nil {
+		return fmt.Errorf("failed to get metadata for %s: %w", imgType, err)
+	}
+	ext := "png" // processed are png
+	if imgType == "original" {
+		ext = "jpg"
+	}
+	coin.Images = append(coin.Images, domain.CoinImage{
+		ID:               uuid.New(),
+		CoinID:           coin.ID,
+		ImageType:        imgType,
+		Side:             side,
+		Path:             path,
+		Extension:        ext,
+		Size:             size,
+		Width:            w,
+		Height:           h,
+		MimeType:         mime,
+		OriginalFilename: originalFilename,
+	})
+	return nil
+}
+
+func (s *CoinService) addProcessedImageRecords(coin *domain.Coin, imgs *processedImages, payload processCoinPayload) error {
+	if err := s.addImageRecord(coin, imgs.ProcessedFrontPath, "crop", "front", payload.FrontFilename); err != nil {
+		return err
+	}
+	if err := s.addImageRecord(coin, imgs.ProcessedBackPath, "crop", "back", payload.BackFilename); err != nil {
+		return err
+	}
+	if err := s.addImageRecord(coin, imgs.ThumbFrontPath, "thumbnail", "front", payload.FrontFilename); err != nil {
+		return err
+	}
+	return s.addImageRecord(coin, imgs.ThumbBackPath, "thumbnail", "back", payload.BackFilename)
+}
+
 func (s *CoinService) EnrichCoinWithNumista(ctx context.Context, coinID uuid.UUID) error {
 	slog.Info("Starting Numista enrichment", "coin_id", coinID)
 	// 1. Get Coin
@@ -573,6 +695,7 @@ func (s *CoinService) EnrichCoinWithNumista(ctx context.Context, coinID uuid.UUI
 			slog.Error("Failed to persist coin updates", "error", err)
 			return fmt.Errorf("failed to update coin with numista details: %w", err)
 		}
+		s.fulfilWants(ctx, coin)
 	} else {
 		// Even if no details applied, we must save the NumistaSearch field we set earlier
 		if err := s.repo.Update(ctx, coin); err != nil {
@@ -597,6 +720,7 @@ func (s *CoinService) ApplyNumistaCandidate(ctx context.Context, coinID uuid.UUI
 	if err != nil {
 		return nil, fmt.Errorf("failed to get numista details: %w", err)
 	}
+	before := snapshotCoin(coin)
 
 	coin.NumistaDetails = details
 	coin.NumistaNumber = numistaID
@@ -606,31 +730,43 @@ func (s *CoinService) ApplyNumistaCandidate(ctx context.Context, coinID uuid.UUI
 	if err := s.repo.Update(ctx, coin); err != nil {
 		return nil, fmt.Errorf("failed to update coin: %w", err)
 	}
+	if err := s.recordCoinChange(ctx, domain.AuditOpApplyNumista, before, coin); err != nil {
+		return nil, err
+	}
+	s.fulfilWants(ctx, coin)
 
 	return coin, nil
 }
 
 func (s *CoinService) mapNumistaDetails(coin *domain.Coin, details map[string]any) {
+	// Fields set below, for provenance
+	set := []string{"numista_number"}
+
 	// 1. Dimensions & Weight
 	if v, ok := details["size"].(float64); ok {
 		coin.DiameterMM = v
+		set = append(set, "diameter_mm")
 	}
 	if v, ok := details["thickness"].(float64); ok {
 		coin.ThicknessMM = v
+		set = append(set, "thickness_mm")
 	}
 	if v, ok := details["weight"].(float64); ok {
 		coin.WeightG = v
+		set = append(set, "weight_g")
 	}
 
 	// 2. Shape
 	if v, ok := details["shape"].(string); ok {
 		coin.Shape = v
+		set = append(set, "shape")
 	}
 
 	// 3. Material (Composition)
 	if comp, ok := details["composition"].(map[string]any); ok {
 		if text, ok := comp["text"].(string); ok {
 			coin.Material = text
+			set = append(set, "material")
 		}
 	}
 
@@ -639,6 +775,7 @@ func (s *CoinService) mapNumistaDetails(coin *domain.Coin, details map[string]an
 		if firstMint, ok := mints[0].(map[string]any); ok {
 			if name, ok := firstMint["name"].(string); ok {
 				coin.Mint = name
+				set = append(set, "mint")
 			}
 		}
 	}
@@ -651,6 +788,7 @@ func (s *CoinService) mapNumistaDetails(coin *domain.Coin, details map[string]an
 					if code, ok := cat["code"].(string); ok && code == "KM" {
 						if number, ok := refMap["number"].(string); ok {
 							coin.KMCode, _ = domain.NewKMCode(fmt.Sprintf("KM# %s", number))
+							set = append(set, "km_code")
 							break // Found KM
 						}
 					}
@@ -664,6 +802,7 @@ func (s *CoinService) mapNumistaDetails(coin *domain.Coin, details map[string]an
 		if firstRuler, ok := rulers[0].(map[string]any); ok {
 			if name, ok := firstRuler["name"].(string); ok {
 				coin.Ruler = name
+				set = append(set, "ruler")
 			}
 		}
 	}
@@ -671,18 +810,23 @@ func (s *CoinService) mapNumistaDetails(coin *domain.Coin, details map[string]an
 	// 7. Orientation
 	if v, ok := details["orientation"].(string); ok {
 		coin.Orientation = v
+		set = append(set, "orientation")
 	}
 
 	// 8. Series
 	if v, ok := details["series"].(string); ok {
 		coin.Series = v
+		set = append(set, "series")
 	}
 
 	// 9. Commemorated Topic
 	if v, ok := details["commemorated_topic"].(string); ok {
 		coin.CommemoratedTopic = v
+		set = append(set, "commemorated_topic")
 	}
 
+	coin.SetProvenance(domain.ProvenanceNumista, time.Now(), nil, set...)
+
 	slog.Info("Mapped Numista details to coin fields",
 		"diameter", coin.DiameterMM,
 		"weight", coin.WeightG,
@@ -701,9 +845,110 @@ func (s *CoinService) mapNumistaDetails(coin *domain.Coin, details map[string]an
 // Let's rewrite saveNumistaImage slightly to take filename and dbSide.
 
 func (s *CoinService) ListCoins(ctx context.Context, filter domain.CoinFilter) ([]*domain.Coin, error) {
+	filter, err := s.withSmartGroup(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
 	return s.repo.List(ctx, filter)
 }
 
+// withSmartGroup replaces a GroupID naming a smart group with the group's
+// filter, which is how its coins are found.
+func (s *CoinService) withSmartGroup(ctx context.Context, filter domain.CoinFilter) (domain.CoinFilter, error) {
+	if filter.GroupID == nil {
+		return filter, nil
+	}
+	group, err := s.groupRepo.GetByID(ctx, *filter.GroupID)
+	if err != nil {
+		return filter, err
+	}
+	if group != nil && group.IsSmart() {
+		filter.GroupID, filter.Smart = nil, group.Filter
+	}
+	return filter, nil
+}
+
+// CoinListOptions asks ListCoinsPage for more than the coins.
+type CoinListOptions struct {
+	Total  bool
+	Facets bool
+}
+
+// CoinList is a page of coins with the cursors of the pages around it and,
+// when asked for, the number of coins matching the filter and the facets of
+// the filter controls.
+type CoinList struct {
+	Coins      []*domain.Coin     `json:"coins"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	PrevCursor string             `json:"prev_cursor,omitempty"`
+	Total      *int64             `json:"total,omitempty"`
+	Facets     *domain.CoinFacets `json:"facets,omitempty"`
+}
+
+// ListCoinsPage lists a page of coins like ListCoins, adding the page
+// cursors and the total and facets asked for in opts.
+func (s *CoinService) ListCoinsPage(ctx context.Context, filter domain.CoinFilter, opts CoinListOptions) (*CoinList, error) {
+	filter, err := s.withSmartGroup(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.repo.ListPage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	list := &CoinList{Coins: page.Coins, NextCursor: page.NextCursor, PrevCursor: page.PrevCursor}
+	if opts.Total {
+		total, err := s.repo.CountMatching(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		list.Total = &total
+	}
+	if opts.Facets {
+		if list.Facets, err = s.repo.Facets(ctx, filter); err != nil {
+			return nil, fmt.Errorf("failed to count facets: %w", err)
+		}
+	}
+	return list, nil
+}
+
+// exportPageSize is how many coins the exports read at a time.
+const exportPageSize = 500
+
+// eachCoinPage calls fn with every page of the coins matching filter,
+// ignoring its limit and offset. Pages are followed by cursor, so coins
+// added or removed meanwhile are not read twice or skipped.
+func (s *CoinService) eachCoinPage(ctx context.Context, filter domain.CoinFilter, fn func([]*domain.Coin) error) error {
+	filter, err := s.withSmartGroup(ctx, filter)
+	if err != nil {
+		return err
+	}
+	filter.Limit, filter.Offset, filter.Cursor = exportPageSize, 0, ""
+	for {
+		page, err := s.repo.ListPage(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list coins: %w", err)
+		}
+		if err := fn(page.Coins); err != nil {
+			return err
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// allCoins returns every coin matching filter, read with eachCoinPage.
+func (s *CoinService) allCoins(ctx context.Context, filter domain.CoinFilter) ([]*domain.Coin, error) {
+	coins := []*domain.Coin{}
+	err := s.eachCoinPage(ctx, filter, func(page []*domain.Coin) error {
+		coins = append(coins, page...)
+		return nil
+	})
+	return coins, err
+}
+
 func (s *CoinService) GetCoin(ctx context.Context, id uuid.UUID) (*domain.Coin, error) {
 	return s.repo.GetByID(ctx, id)
 }
@@ -731,6 +976,14 @@ func (s *CoinService) ListGroups(ctx context.Context) ([]*domain.Group, error) {
 	}
 
 	for _, group := range groups {
+		if group.IsSmart() {
+			stat, err := s.repo.GetFilterStats(ctx, group.Filter.CoinFilter())
+			if err != nil {
+				slog.Warn("Failed to fetch smart group stats", "group_id", group.ID, "error", err)
+				continue
+			}
+			statMap[group.ID] = *stat
+		}
 		if stat, ok := statMap[group.ID]; ok {
 			group.CoinCount = int(stat.Count)
 			group.AvgValue = stat.AvgVal
@@ -744,6 +997,30 @@ func (s *CoinService) ListGroups(ctx context.Context) ([]*domain.Group, error) {
 	return groups, nil
 }
 
+// smartGroupStats evaluates every smart group for the dashboard. Groups that
+// fail are left out, like the other optional widgets.
+func (s *CoinService) smartGroupStats(ctx context.Context) []domain.GroupStat {
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		slog.Warn("Failed to list groups", "error", err)
+		return nil
+	}
+	var stats []domain.GroupStat
+	for _, g := range groups {
+		if !g.IsSmart() {
+			continue
+		}
+		stat, err := s.repo.GetFilterStats(ctx, g.Filter.CoinFilter())
+		if err != nil {
+			slog.Warn("Failed to fetch smart group stats", "group_id", g.ID, "error", err)
+			continue
+		}
+		stat.GroupID, stat.GroupName, stat.Smart = g.ID, g.Name, true
+		stats = append(stats, *stat)
+	}
+	return stats
+}
+
 func (s *CoinService) GetDashboardStats(ctx context.Context) (*domain.DashboardStats, error) {
 	stats := &domain.DashboardStats{}
 
@@ -921,9 +1198,11 @@ func (s *CoinService) GetDashboardStats(ctx context.Context) (*domain.DashboardS
 	}
 
 	stats.GroupDistribution, _ = s.repo.GetGroupDistribution(ctx)
+	stats.TagDistribution, _ = s.repo.GetTagDistribution(ctx)
 
-	// Group Stats for Widget
+	// Group Stats for Widget, smart groups after the ordinary ones
 	stats.GroupStats, _ = s.repo.GetGroupStats(ctx)
+	stats.GroupStats = append(stats.GroupStats, s.smartGroupStats(ctx)...)
 
 	// Previously fetched weights here, now calculated above
 
@@ -952,12 +1231,38 @@ func (s *CoinService) GetDashboardStats(ctx context.Context) (*domain.DashboardS
 	return stats, nil
 }
 
-func (s *CoinService) CreateGroup(ctx context.Context, name, description string) (*domain.Group, error) {
+// CreateGroup creates a group, or a smart group when filter is set, under
+// parentID or at the top level when nil.
+func (s *CoinService) CreateGroup(ctx context.Context, name, description string, filter *domain.SmartFilter, parentID *int) (*domain.Group, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, fmt.Errorf("group name cannot be empty")
 	}
-	return s.groupRepo.Create(ctx, name, description)
+	if parentID != nil {
+		parent, err := s.groupRepo.GetByID(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkParent(parent, *parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	var group *domain.Group
+	var err error
+	if filter != nil {
+		group, err = s.groupRepo.CreateSmart(ctx, name, description, *filter)
+	} else {
+		group, err = s.groupRepo.Create(ctx, name, description)
+	}
+	if err != nil || parentID == nil {
+		return group, err
+	}
+	if err := s.groupRepo.Move(ctx, group.ID, parentID); err != nil {
+		return nil, err
+	}
+	group.ParentID = parentID
+	return group, nil
 }
 
 func (s *CoinService) RotateCoinImage(ctx context.Context, coinID uuid.UUID, side string, angle float64) error {
@@ -967,6 +1272,15 @@ func (s *CoinService) RotateCoinImage(ctx context.Context, coinID uuid.UUID, sid
 		return fmt.Errorf("failed to get coin: %w", err)
 	}
 
+	if err := s.rotateImage(coin, side, angle); err != nil {
+		return err
+	}
+	return s.recordAudit(ctx, domain.AuditEntityCoin, coinID.String(), domain.AuditOpRotateImage, []domain.FieldChange{rotationChange(side, angle)})
+}
+
+// rotateImage rotates the processed image of one side of the coin and
+// regenerates its thumbnail.
+func (s *CoinService) rotateImage(coin *domain.Coin, side string, angle float64) error {
 	// Find the processed image for the side
 	var targetImg *domain.CoinImage
 	for i := range coin.Images {
@@ -1003,7 +1317,11 @@ func (s *CoinService) RotateCoinImage(ctx context.Context, coinID uuid.UUID, sid
 	return nil
 }
 
-func (s *CoinService) UpdateGroup(ctx context.Context, id int, name, description string) (*domain.Group, error) {
+// UpdateGroup replaces the name and description of a group. When setFilter
+// is true it replaces the filter too, a nil filter making it an ordinary
+// group; otherwise the group keeps its filter. A group with coins assigned,
+// in the trash or not, cannot become a smart group.
+func (s *CoinService) UpdateGroup(ctx context.Context, id int, name, description string, filter *domain.SmartFilter, setFilter bool) (*domain.Group, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, fmt.Errorf("group name cannot be empty")
@@ -1013,18 +1331,114 @@ func (s *CoinService) UpdateGroup(ctx context.Context, id int, name, description
 		ID:          id,
 		Name:        name,
 		Description: description,
+		Filter:      filter,
+	}
+	// Without the groups the update still goes ahead, unaudited, unless the
+	// filter has to be kept
+	groups, listErr := s.groupRepo.List(ctx)
+	if listErr != nil {
+		if !setFilter {
+			return nil, fmt.Errorf("failed to list groups: %w", listErr)
+		}
+		slog.Error("Failed to list groups", "error", listErr)
+	}
+	before := groupByID(groups, id)
+	if before != nil {
+		group.ParentID = before.ParentID
+		if !setFilter {
+			group.Filter = before.Filter
+		}
+	}
+
+	if group.Filter != nil && (before == nil || !before.IsSmart()) {
+		// Trashed coins count too: restored, they would point at a smart group
+		assigned, err := s.repo.CountMatching(ctx, domain.CoinFilter{GroupID: &id, IncludeTrashed: true})
+		if err != nil {
+			return nil, err
+		}
+		if assigned > 0 {
+			return nil, fmt.Errorf("%w: move its %d coins out first, counting those in the trash", ErrGroupHasCoins, assigned)
+		}
+		if listErr != nil {
+			return nil, fmt.Errorf("failed to list groups: %w", listErr)
+		}
+		for _, g := range groups {
+			if g.ParentID != nil && *g.ParentID == id {
+				return nil, fmt.Errorf("%w: move its subgroups out first", ErrSmartGroupParent)
+			}
+		}
 	}
 
 	if err := s.groupRepo.Update(ctx, group); err != nil {
 		return nil, err
 	}
+
+	if before != nil {
+		changes, err := domain.DiffGroups(before, group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff group: %w", err)
+		}
+		if err := s.recordAudit(ctx, domain.AuditEntityGroup, strconv.Itoa(id), domain.AuditOpUpdate, changes); err != nil {
+			return nil, err
+		}
+	}
 	return group, nil
 }
 
+// DeleteGroup deletes a group. Its subgroups move up to its parent.
 func (s *CoinService) DeleteGroup(ctx context.Context, id int) error {
 	return s.groupRepo.Delete(ctx, id)
 }
 
+// MoveGroup puts a group under parentID, or at the top level when nil, with
+// its subgroups. A group cannot go under itself, one of its subgroups or a
+// smart group.
+func (s *CoinService) MoveGroup(ctx context.Context, id int, parentID *int) (*domain.Group, error) {
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	group := groupByID(groups, id)
+	if group == nil {
+		return nil, fmt.Errorf("%w: %d", ErrGroupNotFound, id)
+	}
+	if parentID != nil {
+		parent := groupByID(groups, *parentID)
+		if err := checkParent(parent, *parentID); err != nil {
+			return nil, err
+		}
+		if domain.InSubtree(groups, *parentID, id) {
+			return nil, fmt.Errorf("%w: %s under %s", domain.ErrGroupCycle, group.Name, parent.Name)
+		}
+	}
+
+	before := *group
+	if err := s.groupRepo.Move(ctx, id, parentID); err != nil {
+		return nil, err
+	}
+	group.ParentID = parentID
+
+	changes, err := domain.DiffGroups(&before, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff group: %w", err)
+	}
+	if err := s.recordAudit(ctx, domain.AuditEntityGroup, strconv.Itoa(id), domain.AuditOpMove, changes); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// checkParent checks that a group, looked up by id, can hold subgroups.
+func checkParent(parent *domain.Group, id int) error {
+	if parent == nil {
+		return fmt.Errorf("%w: %d", ErrGroupNotFound, id)
+	}
+	if parent.IsSmart() {
+		return fmt.Errorf("%w: %s", ErrSmartGroupParent, parent.Name)
+	}
+	return nil
+}
+
 func normalizeGrade(input string) string {
 	// Map of common variations to standard enum values
 	// 'MC', 'RC', 'BC', 'MBC', 'EBC', 'SC', 'FDC', 'PROOF'
@@ -1072,6 +1486,7 @@ func normalizeGrade(input string) string {
 type UpdateCoinParams struct {
 	Name           string     `json:"name"`
 	Mint           string     `json:"mint"`
+	MintMark       string     `json:"mint_mark"`
 	Mintage        int64      `json:"mintage"`
 	Country        string     `json:"country"`
 	Year           int        `json:"year"`
@@ -1095,6 +1510,8 @@ type UpdateCoinParams struct {
 	PricePaid      float64    `json:"price_paid"`
 	SoldPrice      float64    `json:"sold_price"`
 	GroupName      string     `json:"group_name"`
+	// Tags replaces the tags of the coin; left out, they are kept
+	Tags []string `json:"tags"`
 }
 
 func (s *CoinService) UpdateCoin(ctx context.Context, id uuid.UUID, params UpdateCoinParams) (*domain.Coin, error) {
@@ -1102,10 +1519,19 @@ func (s *CoinService) UpdateCoin(ctx context.Context, id uuid.UUID, params Updat
 	if err != nil {
 		return nil, fmt.Errorf("failed to get coin: %w", err)
 	}
+	before := snapshotCoin(coin)
+
+	var tags []string
+	if params.Tags != nil {
+		if tags, err = domain.NormalizeTagNames(params.Tags); err != nil {
+			return nil, err
+		}
+	}
 
 	// Update fields
 	coin.Name = params.Name
 	coin.Mint = params.Mint
+	coin.MintMark = strings.TrimSpace(params.MintMark)
 	coin.Mintage, _ = domain.NewMintage(params.Mintage)
 	coin.Country = params.Country
 	coin.Year, _ = domain.NewYear(params.Year)
@@ -1128,6 +1554,7 @@ func (s *CoinService) UpdateCoin(ctx context.Context, id uuid.UUID, params Updat
 	coin.SoldAt = params.SoldAt
 	coin.PricePaid = params.PricePaid
 	coin.SoldPrice = params.SoldPrice
+	coin.SetProvenance(domain.ProvenanceUser, time.Now(), nil, coin.ChangedFields(before)...)
 
 	// Handle Group
 	if params.GroupName != "" {
@@ -1139,6 +1566,9 @@ func (s *CoinService) UpdateCoin(ctx context.Context, id uuid.UUID, params Updat
 				return nil, fmt.Errorf("failed to create group: %w", err)
 			}
 		}
+		if group.IsSmart() {
+			return nil, fmt.Errorf("%w: %s", ErrSmartGroupCoins, params.GroupName)
+		}
 		coin.GroupID = &group.ID
 	} else {
 		coin.GroupID = nil
@@ -1147,24 +1577,16 @@ func (s *CoinService) UpdateCoin(ctx context.Context, id uuid.UUID, params Updat
 	if err := s.repo.Update(ctx, coin); err != nil {
 		return nil, fmt.Errorf("failed to update coin: %w", err)
 	}
-
-	return coin, nil
-}
-
-func (s *CoinService) DeleteCoin(ctx context.Context, id uuid.UUID) error {
-	// Delete from database first
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return err
+	if params.Tags != nil {
+		if coin.Tags, err = s.setCoinTags(ctx, id, before.Tags, tags); err != nil {
+			return nil, err
+		}
 	}
-
-	// Then delete files from storage
-	if err := s.storage.DeleteCoinDirectory(id); err != nil {
-		// Log error but don't fail the deletion
-		// The coin is already deleted from DB
-		slog.Error("failed to delete coin files", "coin_id", id, "error", err)
+	if err := s.recordCoinChange(ctx, domain.AuditOpUpdate, before, coin); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return coin, nil
 }
 
 func (s *CoinService) GetGeminiModels(ctx context.Context) ([]domain.GeminiModelInfo, error) {
@@ -1203,27 +1625,8 @@ func (s *CoinService) ReanalyzeCoin(ctx context.Context, id uuid.UUID, modelName
 	}
 
 	// 4. Update Coin Fields from Analysis
-	coin.Country = analysis.Country
-	coin.Year, _ = domain.NewYear(analysis.Year)
-	coin.FaceValue = analysis.FaceValue
-	coin.Currency = analysis.Currency
-	coin.Material = analysis.Material
-	coin.Description = analysis.Description
-	coin.KMCode, _ = domain.NewKMCode(analysis.KMCode)
-	coin.NumistaNumber = analysis.NumistaNumber
-	coin.MinValue = analysis.MinValue
-	coin.MaxValue = analysis.MaxValue
-	coin.Grade, _ = domain.NewGrade(normalizeGrade(analysis.Grade))
-	coin.TechnicalNotes = analysis.Notes
-	coin.GeminiDetails = analysis.RawDetails
-	coin.Name = analysis.Name
-	coin.Mint = analysis.Mint
-	coin.Mintage, _ = domain.NewMintage(analysis.Mintage)
-	coin.WeightG = analysis.WeightG
-	coin.DiameterMM = analysis.DiameterMM
-	coin.ThicknessMM = analysis.ThicknessMM
-	coin.Edge = analysis.Edge
-	coin.Shape = analysis.Shape
+	before := snapshotCoin(coin)
+	applyAnalysis(coin, analysis)
 	coin.GeminiModel = modelName
 	coin.GeminiTemperature = float64(temperature)
 	// We don't overwrite UserNotes, AddedAt, etc.
@@ -1235,14 +1638,29 @@ func (s *CoinService) ReanalyzeCoin(ctx context.Context, id uuid.UUID, modelName
 	if err := s.repo.Update(ctx, coin); err != nil {
 		return nil, fmt.Errorf("failed to update coin: %w", err)
 	}
+	if err := s.recordCoinChange(ctx, domain.AuditOpReanalyze, before, coin); err != nil {
+		return nil, err
+	}
 
 	return coin, nil
 }
 
 // MarkCoinAsSold marks a coin as sold
 func (s *CoinService) MarkCoinAsSold(ctx context.Context, id uuid.UUID, soldPrice float64, saleChannel string) (*domain.Coin, error) {
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin: %w", err)
+	}
+
 	soldAt := time.Now()
-	return s.repo.MarkAsSold(ctx, id, soldAt, soldPrice, saleChannel)
+	coin, err := s.repo.MarkAsSold(ctx, id, soldAt, soldPrice, saleChannel)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordCoinChange(ctx, domain.AuditOpMarkSold, before, coin); err != nil {
+		return nil, err
+	}
+	return coin, nil
 }
 
 // GetSaleChannels returns list of distinct sale channels
@@ -1251,61 +1669,57 @@ func (s *CoinService) GetSaleChannels(ctx context.Context) ([]string, error) {
 }
 
 func (s *CoinService) ExportCoinsCSV(ctx context.Context) ([]byte, error) {
-	coins, err := s.repo.List(ctx, domain.CoinFilter{Limit: 1000000}) // Get all
+	groups, err := s.groupRepo.List(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list coins: %w", err)
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	groupNames := make(map[int]string, len(groups))
+	for _, g := range groups {
+		groupNames[g.ID] = g.Name
 	}
 
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
 
 	// Headers
-	header := []string{
-		"ID", "Name", "Country", "Year", "Face Value", "Currency", "Composition",
-		"Grade", "Mintage", "Mint", "Weight (g)", "Diameter (mm)", "Acquired Date",
-		"Price Paid", "Sold Date", "Sold Price", "Notes",
+	header := make([]string, len(coinCSVColumns))
+	for i, col := range coinCSVColumns {
+		header[i] = col.header
 	}
 	if err := writer.Write(header); err != nil {
 		return nil, err
 	}
 
-	for _, c := range coins {
-		acquired := ""
-		if c.AcquiredAt != nil {
-			acquired = c.AcquiredAt.Format("2006-01-02")
-		}
-		sold := ""
-		if c.SoldAt != nil {
-			sold = c.SoldAt.Format("2006-01-02")
-		}
-		record := []string{
-			c.ID.String(),
-			c.Name,
-			c.Country,
-			fmt.Sprintf("%d", c.Year.Int()),
-			c.FaceValue,
-			c.Currency,
-			c.Material,
-			c.Grade.String(),
-			fmt.Sprintf("%d", c.Mintage.Int64()),
-			c.Mint,
-			fmt.Sprintf("%.2f", c.WeightG),
-			fmt.Sprintf("%.2f", c.DiameterMM),
-			acquired,
-			fmt.Sprintf("%.2f", c.PricePaid),
-			sold,
-			fmt.Sprintf("%.2f", c.SoldPrice),
-			c.PersonalNotes + " " + c.TechnicalNotes,
-		}
-		if err := writer.Write(record); err != nil {
-			return nil, err
+	err = s.eachCoinPage(ctx, domain.CoinFilter{}, func(coins []*domain.Coin) error {
+		for _, c := range coins {
+			groupName := ""
+			if c.GroupID != nil {
+				groupName = groupNames[*c.GroupID]
+			}
+			record := make([]string, len(coinCSVColumns))
+			for i, col := range coinCSVColumns {
+				record[i] = col.get(c, groupName)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	writer.Flush()
-	return buf.Bytes(), nil
+	return buf.Bytes(), writer.Error()
 }
 
 func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
+	// Every row is written back into the collection it came from
+	collectionID, ok := domain.CollectionFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrNoCollection
+	}
+
 	// 1. Fetch all data
 	// Groups
 	groups, err := s.groupRepo.List(ctx)
@@ -1314,9 +1728,9 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 	}
 
 	// Coins (get all)
-	coins, err := s.repo.List(ctx, domain.CoinFilter{Limit: 1000000})
+	coins, err := s.allCoins(ctx, domain.CoinFilter{})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list coins: %w", err)
+		return nil, err
 	}
 
 	// Images (get all raw)
@@ -1331,6 +1745,12 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 		return nil, fmt.Errorf("failed to list links: %w", err)
 	}
 
+	// Tags
+	tags, err := s.tagRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
 	var sb strings.Builder
 	sb.WriteString("-- NumismaticApp Full Database Dump\n")
 	sb.WriteString(fmt.Sprintf("-- Generated: %s\n\n", time.Now().Format(time.RFC3339)))
@@ -1367,22 +1787,36 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 		}
 		return escape(t.Format(time.RFC3339))
 	}
+	smartOrNull := func(f *domain.SmartFilter) string {
+		if f == nil {
+			return "NULL"
+		}
+		data, _ := json.Marshal(f)
+		return escape(string(data))
+	}
 	// For nullable raw strings (pgtype logic in repo, but here we have string)
 	// Empty string in domain often means NULL or empty. Let's assume empty string for attributes is NULL?
 	// Or preserve empty string. For text/varchar, NULL is better than "".
 	// existing logic used sOrNull.
 
-	// 2. Groups
+	// 2. Groups, parents first so that parent_id always names a group
+	// already inserted
 	sb.WriteString("-- Groups\n")
-	for _, g := range groups {
-		// id, name, description, created_at
+	for _, g := range domain.ParentsFirst(groups) {
+		parentID := "NULL"
+		if g.ParentID != nil {
+			parentID = fmt.Sprintf("%d", *g.ParentID)
+		}
 		vals := []string{
 			fmt.Sprintf("%d", g.ID),
 			escape(g.Name),
 			sOrNull(g.Description),
 			escape(g.CreatedAt.Format(time.RFC3339)),
+			escape(collectionID.String()),
+			smartOrNull(g.Filter),
+			parentID,
 		}
-		sb.WriteString(fmt.Sprintf("INSERT INTO groups (id, name, description, created_at) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n", strings.Join(vals, ", ")))
+		sb.WriteString(fmt.Sprintf("INSERT INTO groups (id, name, description, created_at, collection_id, smart_filter, parent_id) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n", strings.Join(vals, ", ")))
 	}
 	sb.WriteString("\n")
 
@@ -1400,6 +1834,7 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 			sOrNull(c.Grade.String()),
 			iOrNull(c.Mintage.Int64()),
 			sOrNull(c.Mint),
+			escape(c.MintMark),
 			fOrNull(c.WeightG),
 			fOrNull(c.DiameterMM),
 			fOrNull(c.PricePaid),
@@ -1411,6 +1846,7 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 			timeOrNull(c.SoldAt),
 			escape(c.CreatedAt.Format(time.RFC3339)),
 			escape(c.UpdatedAt.Format(time.RFC3339)),
+			escape(collectionID.String()),
 			// We skip numista_details/gemini_details JSON for now or dump as string?
 			// The user wants "all DB with all tables and columns".
 			// We should try to export JSON too.
@@ -1419,9 +1855,9 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 			// Let's try to include JSON if possible, but standard marshaling.
 		}
 		// Full list of columns:
-		// id, name, country, year, face_value, currency, material, grade, mintage, mint,
+		// id, name, country, year, face_value, currency, material, grade, mintage, mint, mint_mark,
 		// weight_g, diameter_mm, price_paid, sold_price, personal_notes, technical_notes,
-		// group_id, acquired_at, sold_at, created_at, updated_at
+		// group_id, acquired_at, sold_at, created_at, updated_at, collection_id
 		// (Missing: numista_number, numista_details, gemini_details, gemini_model, gemini_temperature,
 		//  thickness_mm, edge, shape, sale_channel, etc.)
 
@@ -1429,7 +1865,7 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 		// The previous implementation was already partial. I am improving it by adding other tables.
 		// I will add as many columns as reasonable.
 
-		line := fmt.Sprintf("INSERT INTO coins (id, name, country, year, face_value, currency, material, grade, mintage, mint, weight_g, diameter_mm, price_paid, sold_price, personal_notes, technical_notes, group_id, acquired_at, sold_at, created_at, updated_at) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n",
+		line := fmt.Sprintf("INSERT INTO coins (id, name, country, year, face_value, currency, material, grade, mintage, mint, mint_mark, weight_g, diameter_mm, price_paid, sold_price, personal_notes, technical_notes, group_id, acquired_at, sold_at, created_at, updated_at, collection_id) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n",
 			strings.Join(vals, ", "))
 		sb.WriteString(line)
 	}
@@ -1452,8 +1888,9 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 			sOrNull(img.OriginalFilename),
 			escape(img.CreatedAt.Format(time.RFC3339)),
 			escape(img.UpdatedAt.Format(time.RFC3339)),
+			escape(collectionID.String()),
 		}
-		sb.WriteString(fmt.Sprintf("INSERT INTO coin_images (id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n", strings.Join(vals, ", ")))
+		sb.WriteString(fmt.Sprintf("INSERT INTO coin_images (id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at, collection_id) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n", strings.Join(vals, ", ")))
 	}
 	sb.WriteString("\n")
 
@@ -1469,8 +1906,34 @@ func (s *CoinService) ExportCoinsSQL(ctx context.Context) ([]byte, error) {
 			sOrNull(l.OGDescription),
 			sOrNull(l.OGImage),
 			escape(l.CreatedAt.Format(time.RFC3339)),
+			escape(collectionID.String()),
+		}
+		sb.WriteString(fmt.Sprintf("INSERT INTO coin_links (id, coin_id, url, name, og_title, og_description, og_image, created_at, collection_id) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n", strings.Join(vals, ", ")))
+	}
+	sb.WriteString("\n")
+
+	// 6. Tags
+	sb.WriteString("-- Tags\n")
+	tagIDs := make(map[string]int, len(tags))
+	for _, t := range tags {
+		tagIDs[strings.ToLower(t.Name)] = t.ID
+		vals := []string{
+			fmt.Sprintf("%d", t.ID),
+			escape(t.Name),
+			escape(t.CreatedAt.Format(time.RFC3339)),
+			escape(collectionID.String()),
+		}
+		sb.WriteString(fmt.Sprintf("INSERT INTO tags (id, name, created_at, collection_id) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n", strings.Join(vals, ", ")))
+	}
+	for _, c := range coins {
+		for _, name := range c.Tags {
+			id, ok := tagIDs[strings.ToLower(name)]
+			if !ok {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("INSERT INTO coin_tags (coin_id, tag_id, collection_id) VALUES (%s, %d, %s) ON CONFLICT DO NOTHING;\n",
+				escape(c.ID.String()), id, escape(collectionID.String())))
 		}
-		sb.WriteString(fmt.Sprintf("INSERT INTO coin_links (id, coin_id, url, name, og_title, og_description, og_image, created_at) VALUES (%s) ON CONFLICT (id) DO NOTHING;\n", strings.Join(vals, ", ")))
 	}
 
 	sb.WriteString("\nCOMMIT;\n")
@@ -45,7 +45,7 @@ func TestAddCoin_AICoverage(t *testing.T) {
 		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(&domain.Coin{}, nil).AnyTimes()
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
 		assert.NoError(t, err)
 		time.Sleep(50 * time.Millisecond) // Wait for async
 	})
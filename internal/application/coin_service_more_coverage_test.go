@@ -112,7 +112,7 @@ func TestAddCoin_GroupCreateError(t *testing.T) {
 		mockGroupRepo.EXPECT().GetByName(gomock.Any(), "FailGroup").Return(nil, errors.New("not found"))
 		mockGroupRepo.EXPECT().Create(gomock.Any(), "FailGroup", "").Return(nil, errors.New("create error"))
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "FailGroup", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "FailGroup", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create group")
 	})
@@ -129,7 +129,7 @@ func TestAddCoin_ImageProcErrors(t *testing.T) {
 		// Crop fails
 		mockImageService.EXPECT().CropToContent(gomock.Any()).Return(nil, errors.New("crop fail")).Times(1)
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to crop front")
 	})
@@ -149,7 +149,7 @@ func TestAddCoin_ImageProcErrors(t *testing.T) {
 
 		mockAIService.EXPECT().AnalyzeCoin(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.CoinAnalysisResult{}, nil).AnyTimes()
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to save processed front")
 	})
@@ -165,7 +165,7 @@ func TestAddCoin_ImageProcErrors(t *testing.T) {
 		// Thumb Fails
 		mockImageService.EXPECT().GenerateThumbnail(gomock.Any(), 300).Return("", errors.New("thumb fail")).Times(1)
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to thumb front")
 	})
@@ -188,7 +188,7 @@ func TestAddCoin_ImageProcErrors(t *testing.T) {
 		// 2. BgRemove Back Fail
 		mockBgRemover.EXPECT().RemoveBackground(gomock.Any(), gomock.Any()).Return(nil, errors.New("bg back fail")).Times(1) // Back
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to bg remove back")
 	})
@@ -221,7 +221,7 @@ func TestAddCoin_AIAnalysisError(t *testing.T) {
 		mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(&domain.Coin{}, nil).AnyTimes()
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
 		assert.NoError(t, err)
 		time.Sleep(50 * time.Millisecond) // Wait for async
 	})
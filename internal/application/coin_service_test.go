@@ -47,6 +47,7 @@ func setupTest(t *testing.T) (
 	ctrl := gomock.NewController(t)
 	mockRepo := mocks.NewMockCoinRepository(ctrl)
 	mockGroupRepo := mocks.NewMockGroupRepository(ctrl)
+	mockTagRepo := mocks.NewMockTagRepository(ctrl) // see setupTagTest
 	mockImageService := mocks.NewMockImageService(ctrl)
 	mockAIService := mocks.NewMockAIService(ctrl)
 	mockStorage := mocks.NewMockStorageService(ctrl)
@@ -57,12 +58,16 @@ func setupTest(t *testing.T) (
 	service := application.NewCoinService(
 		mockRepo,
 		mockGroupRepo,
+		mockTagRepo,
+		nil, // see setupWishlistTest
+		nil, // see setupChecklistTest
 		mockImageService,
 		mockAIService,
 		mockStorage,
 		mockBgRemover,
 		mockNumistaClient,
 		mockPriceClient,
+		nil, // no job queue: AddCoin runs inline
 	)
 
 	return service, mockRepo, mockGroupRepo, mockImageService, mockAIService, mockStorage, mockBgRemover, mockNumistaClient, mockPriceClient
@@ -105,6 +110,53 @@ func TestListCoins(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Empty(t, coins)
 	})
+	t.Run("Smart Group", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		groupID := 7
+		material := "Silver"
+		smart := &domain.SmartFilter{Material: &material}
+		mockGroupRepo.EXPECT().GetByID(ctx, groupID).Return(&domain.Group{ID: groupID, Filter: smart}, nil)
+		// The group's filter replaces the group, on top of the other filters
+		year := 1900
+		mockRepo.EXPECT().List(ctx, domain.CoinFilter{Year: &year, Smart: smart}).Return([]*domain.Coin{}, nil)
+
+		_, err := service.ListCoins(ctx, domain.CoinFilter{GroupID: &groupID, Year: &year})
+		assert.NoError(t, err)
+	})
+}
+
+func TestListCoinsPage(t *testing.T) {
+	country := "Spain"
+	filter := domain.CoinFilter{Country: &country, Limit: 2}
+
+	t.Run("Coins Only", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockRepo.EXPECT().ListPage(ctx, filter).Return(&domain.CoinPage{Coins: []*domain.Coin{}, NextCursor: "next"}, nil)
+
+		list, err := service.ListCoinsPage(ctx, filter, application.CoinListOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "next", list.NextCursor)
+		assert.Empty(t, list.PrevCursor)
+		assert.Nil(t, list.Total)
+		assert.Nil(t, list.Facets)
+	})
+
+	t.Run("Total And Facets", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		facets := &domain.CoinFacets{Countries: []domain.FacetBucket{{Value: "Spain", Count: 12}, {Value: "France", Count: 3}}}
+		mockRepo.EXPECT().ListPage(ctx, filter).Return(&domain.CoinPage{Coins: []*domain.Coin{{ID: uuid.New()}, {ID: uuid.New()}}}, nil)
+		mockRepo.EXPECT().CountMatching(ctx, filter).Return(int64(12), nil)
+		mockRepo.EXPECT().Facets(ctx, filter).Return(facets, nil)
+
+		list, err := service.ListCoinsPage(ctx, filter, application.CoinListOptions{Total: true, Facets: true})
+		assert.NoError(t, err)
+		assert.Len(t, list.Coins, 2)
+		assert.Equal(t, int64(12), *list.Total)
+		assert.Equal(t, facets, list.Facets)
+	})
 }
 
 func TestGetCoin(t *testing.T) {
@@ -130,7 +182,7 @@ func TestGetCoin(t *testing.T) {
 }
 
 func TestGetDashboardStats(t *testing.T) {
-	service, mockRepo, _, _, _, _, _, _, mockPriceClient := setupTest(t)
+	service, mockRepo, mockGroupRepo, _, _, _, _, _, mockPriceClient := setupTest(t)
 	ctx := context.Background()
 
 	mockRepo.EXPECT().Count(ctx).Return(int64(10), nil)
@@ -153,7 +205,14 @@ func TestGetDashboardStats(t *testing.T) {
 	mockRepo.EXPECT().GetOldestCoin(ctx).Return(&domain.Coin{Year: mustYear(1800)}, nil)
 	mockRepo.EXPECT().GetRarestCoins(ctx, 5).Return([]*domain.Coin{}, nil)
 	mockRepo.EXPECT().GetGroupDistribution(ctx).Return(map[string]int{"Group 1": 1}, nil)
-	mockRepo.EXPECT().GetGroupStats(ctx).Return([]domain.GroupStat{}, nil)
+	mockRepo.EXPECT().GetTagDistribution(ctx).Return(map[string]int{"euro": 4, "to sell": 1}, nil)
+	mockRepo.EXPECT().GetGroupStats(ctx).Return([]domain.GroupStat{{GroupID: 1, GroupName: "Group 1", Count: 1}}, nil)
+	silver := "Silver"
+	mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{
+		{ID: 1, Name: "Group 1"},
+		{ID: 2, Name: "Silver", Filter: &domain.SmartFilter{Material: &silver}},
+	}, nil)
+	mockRepo.EXPECT().GetFilterStats(ctx, domain.CoinFilter{Material: &silver}).Return(&domain.GroupStat{Count: 3, MaxVal: 40}, nil)
 	mockRepo.EXPECT().GetHeaviestCoin(ctx).Return(&domain.Coin{}, nil)
 	mockRepo.EXPECT().GetSmallestCoin(ctx).Return(&domain.Coin{}, nil)
 	mockRepo.EXPECT().GetRandomCoin(ctx).Return(&domain.Coin{}, nil)
@@ -163,6 +222,11 @@ func TestGetDashboardStats(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, stats)
 	assert.Equal(t, int64(10), stats.TotalCoins)
+	assert.Equal(t, []domain.GroupStat{
+		{GroupID: 1, GroupName: "Group 1", Count: 1},
+		{GroupID: 2, GroupName: "Silver", Count: 3, MaxVal: 40, Smart: true},
+	}, stats.GroupStats)
+	assert.Equal(t, map[string]int{"euro": 4, "to sell": 1}, stats.TagDistribution)
 }
 
 func TestAddCoin_Flows(t *testing.T) {
@@ -186,7 +250,7 @@ func TestAddCoin_Flows(t *testing.T) {
 		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(&domain.Coin{Year: mustYear(2024), FaceValue: "1"}, nil).AnyTimes()
 		mockNumistaClient.EXPECT().SearchTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&numista.TypeSearchResponse{}, nil).AnyTimes()
 
-		_, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G", "", "", "", 0, "m", 0)
 		assert.NoError(t, err)
 		// Wait slightly for async to potentially run? Not strict.
 	})
@@ -199,7 +263,7 @@ func TestAddCoin_Flows(t *testing.T) {
 		// Other calls might happen or not depending on race, allow them
 		mockStorage.EXPECT().SaveFile(gomock.Any(), gomock.Any(), gomock.Any()).Return("p", nil).AnyTimes()
 
-		_, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 	})
 
@@ -227,7 +291,7 @@ func TestAddCoin_Flows(t *testing.T) {
 		mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(&domain.Coin{}, nil).AnyTimes()
 		mockNumistaClient.EXPECT().SearchTypes(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&numista.TypeSearchResponse{}, nil).AnyTimes()
 
-		coin, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G", "", "", "", 0, "m", 0)
+		coin, _, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G", "", "", "", 0, "m", 0)
 		assert.NoError(t, err)
 		assert.NotNil(t, coin)
 	})
@@ -258,6 +322,9 @@ func TestEnrichCoinWithNumista(t *testing.T) {
 
 		err := service.EnrichCoinWithNumista(ctx, coinID)
 		assert.NoError(t, err)
+		assert.Equal(t, domain.ProvenanceNumista, coin.Provenance["shape"].Source)
+		assert.Equal(t, domain.ProvenanceNumista, coin.Provenance["numista_number"].Source)
+		assert.NotContains(t, coin.Provenance, "weight_g")
 	})
 
 	t.Run("No Match Found", func(t *testing.T) {
@@ -310,6 +377,7 @@ func TestApplyNumistaCandidate(t *testing.T) {
 		mockRepo.EXPECT().GetByID(ctx, coinID).Return(&domain.Coin{ID: coinID}, nil)
 		mockNumistaClient.EXPECT().GetType(ctx, 999).Return(map[string]any{"title": "Manual Selection"}, nil)
 		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
 		_, err := service.ApplyNumistaCandidate(ctx, coinID, 999)
 		assert.NoError(t, err)
 	})
@@ -368,6 +436,7 @@ func TestApplyNumistaCandidate(t *testing.T) {
 			assert.Equal(t, "Anniversary", c.CommemoratedTopic)
 			return nil
 		})
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
 
 		_, err := service.ApplyNumistaCandidate(ctx, coinID, 999)
 		assert.NoError(t, err)
@@ -406,6 +475,27 @@ func TestListGroups(t *testing.T) {
 		assert.Equal(t, 0, groups[1].CoinCount)
 	})
 
+	t.Run("Smart Group", func(t *testing.T) {
+		service, mockCoinRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		country := "Spain"
+		minYear := 1868
+
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{
+			{ID: 3, Name: "Peseta era", Filter: &domain.SmartFilter{Country: &country, MinYear: &minYear}},
+		}, nil)
+		mockCoinRepo.EXPECT().GetGroupStats(ctx).Return([]domain.GroupStat{}, nil)
+		mockCoinRepo.EXPECT().GetFilterStats(ctx, domain.CoinFilter{Country: &country, MinYear: &minYear}).Return(&domain.GroupStat{
+			Count: 12, MinYear: 1868, MaxYear: 2001, MinVal: 1, MaxVal: 300, AvgVal: 20,
+		}, nil)
+
+		groups, err := service.ListGroups(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, groups[0].CoinCount)
+		assert.Equal(t, 1868, groups[0].MinYear)
+		assert.Equal(t, 300.0, groups[0].MaxValue)
+	})
+
 	t.Run("List Error", func(t *testing.T) {
 		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
@@ -438,7 +528,7 @@ func TestCreateGroup(t *testing.T) {
 		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
 		mockGroupRepo.EXPECT().Create(ctx, "G1", "Desc").Return(&domain.Group{ID: 1}, nil)
-		g, err := service.CreateGroup(ctx, "G1", "Desc")
+		g, err := service.CreateGroup(ctx, "G1", "Desc", nil, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, g.ID)
 	})
@@ -447,20 +537,275 @@ func TestCreateGroup(t *testing.T) {
 		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
 		mockGroupRepo.EXPECT().Create(ctx, "G1", "Desc").Return(nil, errors.New("create error"))
-		g, err := service.CreateGroup(ctx, "G1", "Desc")
+		g, err := service.CreateGroup(ctx, "G1", "Desc", nil, nil)
 		assert.Error(t, err)
 		assert.Nil(t, g)
 		assert.Contains(t, err.Error(), "create error")
 	})
+
+	t.Run("Under A Parent", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		parentID := 1
+		mockGroupRepo.EXPECT().GetByID(ctx, parentID).Return(&domain.Group{ID: parentID, Name: "Europe"}, nil)
+		mockGroupRepo.EXPECT().Create(ctx, "Spain", "").Return(&domain.Group{ID: 2, Name: "Spain"}, nil)
+		mockGroupRepo.EXPECT().Move(ctx, 2, &parentID).Return(nil)
+
+		g, err := service.CreateGroup(ctx, "Spain", "", nil, &parentID)
+		assert.NoError(t, err)
+		assert.Equal(t, &parentID, g.ParentID)
+	})
+
+	t.Run("Under A Smart Group", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		parentID := 1
+		mockGroupRepo.EXPECT().GetByID(ctx, parentID).Return(&domain.Group{ID: parentID, Name: "Silver", Filter: &domain.SmartFilter{}}, nil)
+
+		_, err := service.CreateGroup(ctx, "Spain", "", nil, &parentID)
+		assert.ErrorIs(t, err, application.ErrSmartGroupParent)
+	})
+
+	t.Run("Missing Parent", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		parentID := 9
+		mockGroupRepo.EXPECT().GetByID(ctx, parentID).Return(nil, nil)
+
+		_, err := service.CreateGroup(ctx, "Spain", "", nil, &parentID)
+		assert.ErrorIs(t, err, application.ErrGroupNotFound)
+	})
+}
+
+func TestMoveGroup(t *testing.T) {
+	ctx := context.Background()
+	intPtr := func(i int) *int { return &i }
+	// Europe > Spain > Franco, and a smart group
+	groups := func() []*domain.Group {
+		return []*domain.Group{
+			{ID: 1, Name: "Europe"},
+			{ID: 2, Name: "Spain", ParentID: intPtr(1)},
+			{ID: 3, Name: "Franco", ParentID: intPtr(2)},
+			{ID: 4, Name: "Silver", Filter: &domain.SmartFilter{}},
+			{ID: 5, Name: "Portugal"},
+		}
+	}
+
+	t.Run("Under Another Group", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().List(ctx).Return(groups(), nil)
+		mockGroupRepo.EXPECT().Move(ctx, 5, intPtr(1)).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.AuditOpMove, e.Operation)
+			assert.Equal(t, "5", e.EntityID)
+			if assert.Len(t, e.Changes, 1) {
+				assert.Equal(t, "parent_id", e.Changes[0].Field)
+				assert.JSONEq(t, "null", string(e.Changes[0].Before))
+				assert.JSONEq(t, "1", string(e.Changes[0].After))
+			}
+			return nil
+		})
+
+		g, err := service.MoveGroup(ctx, 5, intPtr(1))
+		assert.NoError(t, err)
+		assert.Equal(t, intPtr(1), g.ParentID)
+	})
+
+	t.Run("To The Top Level", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().List(ctx).Return(groups(), nil)
+		mockGroupRepo.EXPECT().Move(ctx, 3, nil).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
+
+		g, err := service.MoveGroup(ctx, 3, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, g.ParentID)
+	})
+
+	t.Run("Under Its Own Subgroup", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().List(ctx).Return(groups(), nil)
+
+		_, err := service.MoveGroup(ctx, 1, intPtr(3))
+		assert.ErrorIs(t, err, domain.ErrGroupCycle)
+	})
+
+	t.Run("Under Itself", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().List(ctx).Return(groups(), nil)
+
+		_, err := service.MoveGroup(ctx, 2, intPtr(2))
+		assert.ErrorIs(t, err, domain.ErrGroupCycle)
+	})
+
+	t.Run("Cycle Made By A Concurrent Move", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().List(ctx).Return(groups(), nil)
+		mockGroupRepo.EXPECT().Move(ctx, 5, intPtr(1)).Return(domain.ErrGroupCycle)
+
+		_, err := service.MoveGroup(ctx, 5, intPtr(1))
+		assert.ErrorIs(t, err, domain.ErrGroupCycle)
+	})
+
+	t.Run("Under A Smart Group", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().List(ctx).Return(groups(), nil)
+
+		_, err := service.MoveGroup(ctx, 5, intPtr(4))
+		assert.ErrorIs(t, err, application.ErrSmartGroupParent)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().List(ctx).Return(groups(), nil)
+
+		_, err := service.MoveGroup(ctx, 9, nil)
+		assert.ErrorIs(t, err, application.ErrGroupNotFound)
+	})
+}
+
+func TestExportCoinsSQL_GroupParents(t *testing.T) {
+	service, m := setupTagTest(t)
+	ctx := domain.WithCollection(context.Background(), uuid.New())
+	parentID := 1
+	m.groupRepo.EXPECT().List(ctx).Return([]*domain.Group{
+		{ID: 2, Name: "Spain", ParentID: &parentID},
+		{ID: 1, Name: "Europe"},
+	}, nil)
+	m.repo.EXPECT().ListPage(ctx, gomock.Any()).Return(&domain.CoinPage{}, nil)
+	m.repo.EXPECT().GetAllImages(ctx).Return(nil, nil)
+	m.repo.EXPECT().GetAllLinks(ctx).Return(nil, nil)
+	m.tagRepo.EXPECT().List(ctx).Return(nil, nil)
+
+	data, err := service.ExportCoinsSQL(ctx)
+	assert.NoError(t, err)
+	parent := bytes.Index(data, []byte("VALUES (1, 'Europe', NULL, "))
+	child := bytes.Index(data, []byte("VALUES (2, 'Spain', NULL, "))
+	assert.Contains(t, string(data), "smart_filter, parent_id) VALUES (1, ")
+	assert.True(t, parent >= 0 && child > parent, "the parent is inserted first")
+	assert.Contains(t, string(data[child:]), "NULL, 1) ON CONFLICT (id) DO NOTHING;")
 }
 
 func TestUpdateGroup(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 1, Name: "G1"}}, nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.AuditEntityGroup, e.EntityType)
+			assert.Equal(t, "1", e.EntityID)
+			assert.Len(t, e.Changes, 2) // name and description
+			return nil
+		})
+		mockGroupRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		_, err := service.UpdateGroup(ctx, 1, "G2", "Desc2", nil, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Into A Smart Group", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		groupID := 1
+		country := "France"
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: groupID, Name: "G1"}}, nil)
+		mockRepo.EXPECT().CountMatching(ctx, domain.CoinFilter{GroupID: &groupID, IncludeTrashed: true}).Return(int64(0), nil)
+		mockGroupRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, g *domain.Group) error {
+			assert.Equal(t, &country, g.Filter.Country)
+			return nil
+		})
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, "filter", e.Changes[0].Field)
+			return nil
+		})
+
+		g, err := service.UpdateGroup(ctx, groupID, "G1", "", &domain.SmartFilter{Country: &country}, true)
+		assert.NoError(t, err)
+		assert.True(t, g.IsSmart())
+	})
+
+	t.Run("Smart Group With Coins", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		groupID := 1
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: groupID, Name: "G1"}}, nil)
+		mockRepo.EXPECT().CountMatching(ctx, domain.CoinFilter{GroupID: &groupID, IncludeTrashed: true}).Return(int64(4), nil)
+
+		_, err := service.UpdateGroup(ctx, groupID, "G1", "", &domain.SmartFilter{}, true)
+		assert.ErrorIs(t, err, application.ErrGroupHasCoins)
+	})
+
+	t.Run("Smart Group With Subgroups", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		groupID := 1
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: groupID, Name: "G1"}, {ID: 2, Name: "G2", ParentID: &groupID}}, nil)
+		mockRepo.EXPECT().CountMatching(ctx, domain.CoinFilter{GroupID: &groupID, IncludeTrashed: true}).Return(int64(0), nil)
+
+		_, err := service.UpdateGroup(ctx, groupID, "G1", "", &domain.SmartFilter{}, true)
+		assert.ErrorIs(t, err, application.ErrSmartGroupParent)
+	})
+
+	t.Run("Keeps The Parent", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		parentID := 1
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 2, Name: "G2", ParentID: &parentID}}, nil)
+		mockGroupRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Len(t, e.Changes, 1) // name only
+			return nil
+		})
+
+		g, err := service.UpdateGroup(ctx, 2, "G3", "", nil, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &parentID, g.ParentID)
+	})
+
+	t.Run("Rename Keeps The Filter", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
+		country := "France"
+		filter := &domain.SmartFilter{Country: &country}
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 1, Name: "French", Filter: filter}}, nil)
+		mockGroupRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, g *domain.Group) error {
+			assert.Equal(t, filter, g.Filter)
+			return nil
+		})
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Len(t, e.Changes, 1) // name only
+			return nil
+		})
+
+		g, err := service.UpdateGroup(ctx, 1, "France", "", nil, false)
+		assert.NoError(t, err)
+		assert.True(t, g.IsSmart())
+	})
+
+	t.Run("Clears The Filter", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		country := "France"
+		mockGroupRepo.EXPECT().List(ctx).Return([]*domain.Group{{ID: 1, Name: "French", Filter: &domain.SmartFilter{Country: &country}}}, nil)
 		mockGroupRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
-		_, err := service.UpdateGroup(ctx, 1, "G2", "Desc2")
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, "filter", e.Changes[0].Field)
+			assert.JSONEq(t, "null", string(e.Changes[0].After))
+			return nil
+		})
+
+		g, err := service.UpdateGroup(ctx, 1, "French", "", nil, true)
 		assert.NoError(t, err)
+		assert.False(t, g.IsSmart())
+	})
+
+	t.Run("Rename Without The Groups", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockGroupRepo.EXPECT().List(ctx).Return(nil, errors.New("db error"))
+
+		// The filter to keep is unknown, so nothing is written
+		_, err := service.UpdateGroup(ctx, 1, "French", "", nil, false)
+		assert.Error(t, err)
 	})
 	// TestUpdateGroup_RepoError is already defined below at line ~1123, so we don't add it here.
 }
@@ -491,11 +836,25 @@ func TestUpdateCoin(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
-		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id}, nil)
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id, Country: "Spain", Provenance: domain.Provenance{
+			"country": {Source: domain.ProvenanceAI},
+		}}, nil)
 		mockGroupRepo.EXPECT().GetByName(ctx, "New Group").Return(&domain.Group{ID: 2}, nil)
 		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
-		_, err := service.UpdateCoin(ctx, id, params)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.AuditEntityCoin, e.EntityType)
+			assert.Equal(t, id.String(), e.EntityID)
+			assert.Equal(t, domain.AuditOpUpdate, e.Operation)
+			assert.Equal(t, domain.DefaultActor, e.Actor)
+			assert.Equal(t, "name", e.Changes[2].Field)
+			assert.JSONEq(t, `"Updated Name"`, string(e.Changes[2].After))
+			return nil
+		})
+		coin, err := service.UpdateCoin(ctx, id, params)
 		assert.NoError(t, err)
+		assert.Equal(t, domain.ProvenanceUser, coin.Provenance["name"].Source)
+		// Country was cleared by the edit, so its AI origin no longer applies
+		assert.NotContains(t, coin.Provenance, "country")
 	})
 
 	t.Run("Get Error", func(t *testing.T) {
@@ -507,26 +866,6 @@ func TestUpdateCoin(t *testing.T) {
 	})
 }
 
-func TestDeleteCoin(t *testing.T) {
-	id := uuid.New()
-	t.Run("Success", func(t *testing.T) {
-		service, mockRepo, _, _, _, mockStorage, _, _, _ := setupTest(t)
-		ctx := context.Background()
-		mockStorage.EXPECT().DeleteCoinDirectory(id).Return(nil)
-		mockRepo.EXPECT().Delete(ctx, id).Return(nil)
-		err := service.DeleteCoin(ctx, id)
-		assert.NoError(t, err)
-	})
-
-	t.Run("Error", func(t *testing.T) {
-		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
-		ctx := context.Background()
-		mockRepo.EXPECT().Delete(ctx, id).Return(assert.AnError)
-		err := service.DeleteCoin(ctx, id)
-		assert.Error(t, err)
-	})
-}
-
 func TestRotateCoinImage(t *testing.T) {
 	coinID := uuid.New()
 
@@ -540,6 +879,12 @@ func TestRotateCoinImage(t *testing.T) {
 
 		mockImageService.EXPECT().Rotate("path/front.png", 90.0).Return(nil)
 		mockImageService.EXPECT().GenerateThumbnail("path/front.png", 300).Return("path/thumb.png", nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.AuditOpRotateImage, e.Operation)
+			assert.Equal(t, "front_image_rotation", e.Changes[0].Field)
+			assert.JSONEq(t, "90", string(e.Changes[0].After))
+			return nil
+		})
 		// No strict update check as it might be implicit
 
 		err := service.RotateCoinImage(ctx, coinID, "front", 90.0)
@@ -570,14 +915,22 @@ func TestReanalyzeCoin(t *testing.T) {
 		}, nil)
 
 		mockAIService.EXPECT().AnalyzeCoin(ctx, "front.jpg", "back.jpg", "gemini-pro", float32(0.1), "es").Return(&domain.CoinAnalysisResult{
-			Name: "Reanalyzed",
+			Name:       "Reanalyzed",
+			Country:    "Spain",
+			Notes:      "Weak strike",
+			Confidence: map[string]float64{"name": 0.95, "notes": 0.4},
 		}, nil)
 
 		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
 
 		coin, err := service.ReanalyzeCoin(ctx, coinID, "gemini-pro", 0.1)
 		assert.NoError(t, err)
 		assert.Equal(t, "Reanalyzed", coin.Name)
+		assert.Equal(t, "gemini-pro", coin.GeminiModel)
+		assert.Equal(t, domain.ProvenanceAI, coin.Provenance["country"].Source)
+		assert.InDelta(t, 0.95, *coin.Provenance["name"].Confidence, 0.001)
+		assert.InDelta(t, 0.4, *coin.Provenance["technical_notes"].Confidence, 0.001)
 	})
 
 	t.Run("AI Error", func(t *testing.T) {
@@ -632,6 +985,7 @@ func TestUpdateCoin_Grades(t *testing.T) {
 				assert.Equal(t, tc.expected, c.Grade.String()) // Update to use String()
 				return nil
 			})
+			mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil).MaxTimes(1)
 
 			params := application.UpdateCoinParams{
 				Grade: tc.input,
@@ -644,7 +998,7 @@ func TestUpdateCoin_Grades(t *testing.T) {
 
 func TestGetDashboardStats_Century(t *testing.T) {
 	// Tests the fallback in toRoman for centuries > 21
-	service, mockRepo, _, _, _, _, _, _, mockPriceClient := setupTest(t)
+	service, mockRepo, mockGroupRepo, _, _, _, _, _, mockPriceClient := setupTest(t)
 	ctx := context.Background()
 
 	mockRepo.EXPECT().Count(ctx).Return(int64(1), nil)
@@ -659,7 +1013,9 @@ func TestGetDashboardStats_Century(t *testing.T) {
 	mockRepo.EXPECT().GetOldestCoin(ctx).Return(&domain.Coin{Year: mustYear(1800)}, nil)
 	mockRepo.EXPECT().GetRarestCoins(ctx, 5).Return([]*domain.Coin{}, nil)
 	mockRepo.EXPECT().GetGroupDistribution(ctx).Return(map[string]int{}, nil)
+	mockRepo.EXPECT().GetTagDistribution(ctx).Return(map[string]int{}, nil)
 	mockRepo.EXPECT().GetGroupStats(ctx).Return([]domain.GroupStat{}, nil)
+	mockGroupRepo.EXPECT().List(ctx).Return(nil, nil)
 	mockRepo.EXPECT().GetHeaviestCoin(ctx).Return(&domain.Coin{}, nil)
 	mockRepo.EXPECT().GetSmallestCoin(ctx).Return(&domain.Coin{}, nil)
 	mockRepo.EXPECT().GetRandomCoin(ctx).Return(&domain.Coin{}, nil)
@@ -715,13 +1071,13 @@ func TestCreateGroup_Errors(t *testing.T) {
 		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
 		mockGroupRepo.EXPECT().Create(ctx, "G1", "Desc").Return(nil, errors.New("db error"))
-		_, err := service.CreateGroup(ctx, "G1", "Desc")
+		_, err := service.CreateGroup(ctx, "G1", "Desc", nil, nil)
 		assert.Error(t, err)
 	})
 	t.Run("Validation Error", func(t *testing.T) {
 		service, _, _, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
-		_, err := service.CreateGroup(ctx, "", "Desc")
+		_, err := service.CreateGroup(ctx, "", "Desc", nil, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "group name cannot be empty")
 	})
@@ -757,7 +1113,7 @@ func TestAddCoin_ErrorPaths(t *testing.T) {
 		// Note: Clean up (DeleteCoin) might be called if implemented, or it just errors out.
 		// The current implementation returns error on first error from channels.
 
-		_, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G_Fail", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "G_Fail", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create group")
 	})
@@ -773,7 +1129,7 @@ func TestAddCoin_ErrorPaths(t *testing.T) {
 		mockBgRemover.EXPECT().RemoveBackground(gomock.Any(), gomock.Any()).Return(nil, errors.New("bg error")).Times(1)
 		// Note: RemoveBackground is called twice (front/back). If first fails, it returns error.
 
-		_, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to bg remove")
 	})
@@ -782,7 +1138,7 @@ func TestAddCoin_ErrorPaths(t *testing.T) {
 func TestUpdateGroup_Validation(t *testing.T) {
 	service, _, _, _, _, _, _, _, _ := setupTest(t)
 	ctx := context.Background()
-	_, err := service.UpdateGroup(ctx, 1, "", "Desc")
+	_, err := service.UpdateGroup(ctx, 1, "", "Desc", nil, false)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "group name cannot be empty")
 }
@@ -864,7 +1220,7 @@ func TestAddCoin_EdgeCases(t *testing.T) {
 	t.Run("Reader Error Front", func(t *testing.T) {
 		service, _, _, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
-		_, err := service.AddCoin(ctx, &errReader{}, "f.jpg", bytes.NewReader([]byte{}), "b.jpg", "", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, &errReader{}, "f.jpg", bytes.NewReader([]byte{}), "b.jpg", "", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to read front file")
 	})
@@ -872,7 +1228,7 @@ func TestAddCoin_EdgeCases(t *testing.T) {
 	t.Run("Reader Error Back", func(t *testing.T) {
 		service, _, _, _, _, _, _, _, _ := setupTest(t)
 		ctx := context.Background()
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", &errReader{}, "b.jpg", "", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", &errReader{}, "b.jpg", "", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to read back file")
 	})
@@ -897,7 +1253,7 @@ func TestAddCoin_EdgeCases(t *testing.T) {
 		// 3. Fail metadata on first call (original front)
 		mockImageService.EXPECT().GetMetadata("path").Return(0, 0, int64(0), "", errors.New("meta error")).Times(1)
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to get metadata for original")
 	})
@@ -924,14 +1280,14 @@ func TestAddCoin_EdgeCases(t *testing.T) {
 		// 3.2 Processed Front -> Error
 		mockImageService.EXPECT().GetMetadata("proc_path").Return(0, 0, int64(0), "", errors.New("meta error")).Times(1)
 
-		_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to get metadata for crop")
 	})
 }
 
 func TestGetDashboardStats_Errors(t *testing.T) {
-	service, mockRepo, _, _, _, _, _, _, mockPriceClient := setupTest(t)
+	service, mockRepo, mockGroupRepo, _, _, _, _, _, mockPriceClient := setupTest(t)
 	ctx := context.Background()
 
 	t.Run("Count Error", func(t *testing.T) {
@@ -1037,7 +1393,9 @@ func TestGetDashboardStats_Errors(t *testing.T) {
 		// mockRepo.EXPECT().GetOldestCoin(ctx).Return(&domain.Coin{}, nil) // Skipped on error
 		mockRepo.EXPECT().GetRarestCoins(ctx, 5).Return([]*domain.Coin{}, nil)
 		mockRepo.EXPECT().GetGroupDistribution(ctx).Return(map[string]int{}, nil)
+		mockRepo.EXPECT().GetTagDistribution(ctx).Return(map[string]int{}, nil)
 		mockRepo.EXPECT().GetGroupStats(ctx).Return([]domain.GroupStat{}, nil)
+		mockGroupRepo.EXPECT().List(ctx).Return(nil, nil)
 		mockRepo.EXPECT().GetHeaviestCoin(ctx).Return(&domain.Coin{}, nil)
 		mockRepo.EXPECT().GetSmallestCoin(ctx).Return(&domain.Coin{}, nil)
 		mockRepo.EXPECT().GetRandomCoin(ctx).Return(&domain.Coin{}, nil)
@@ -1071,7 +1429,7 @@ func TestAddCoin_SaveError(t *testing.T) {
 	// Save fails - Use gomock.Any() for context as it's modified (WithCancel)
 	mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
 
-	_, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
+	_, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "G", "", "", "", 0, "m", 0)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to save coin")
 }
@@ -1107,7 +1465,7 @@ func TestAddCoin_GroupCreateSuccess(t *testing.T) {
 	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mockRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(&domain.Coin{}, nil).AnyTimes()
 
-	coin, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "NewGroup", "", "", "", 0, "m", 0)
+	coin, _, err := service.AddCoin(ctx, bytes.NewReader([]byte("f")), "f.jpg", bytes.NewReader([]byte("b")), "b.jpg", "NewGroup", "", "", "", 0, "m", 0)
 	assert.NoError(t, err)
 	assert.NotNil(t, coin)
 	assert.Equal(t, 2, *coin.GroupID)
@@ -1158,9 +1516,10 @@ func TestUpdateGroup_RepoError(t *testing.T) {
 	ctx := context.Background()
 
 	// UpdateGroup does not call GetByID, it constructs group object and calls Update directly
+	mockGroupRepo.EXPECT().List(ctx).Return(nil, nil)
 	mockGroupRepo.EXPECT().Update(ctx, gomock.Any()).Return(errors.New("db error"))
 
-	_, err := service.UpdateGroup(ctx, 1, "Name", "Desc")
+	_, err := service.UpdateGroup(ctx, 1, "Name", "Desc", nil, true)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "db error")
 }
@@ -1197,6 +1556,7 @@ func TestApplyNumistaCandidate_Flows(t *testing.T) {
 		mockRepo.EXPECT().GetByID(ctx, coinID).Return(coin, nil)
 		mockNumistaClient.EXPECT().GetType(ctx, numistaID).Return(map[string]any{"title": "Coin"}, nil)
 		mockRepo.EXPECT().Update(ctx, coin).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
 
 		updatedCoin, err := service.ApplyNumistaCandidate(ctx, coinID, numistaID)
 		assert.NoError(t, err)
@@ -1309,7 +1669,7 @@ func TestAddCoin_ImageDetailedErrors(t *testing.T) {
 
 			tc.setupMocks(mockStorage, mockImageService, mockBgRemover)
 
-			_, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "", "", "", "", 0, "m", 0)
+			_, _, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "", "", "", "", 0, "m", 0)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tc.expectedError)
 		})
@@ -1352,7 +1712,7 @@ func TestAddCoin_GroupError(t *testing.T) {
 
 		mockRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Times(0) // Should not save coin if group failed
 
-		_, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "NewGroup", "", "", "", 0, "m", 0)
+		_, _, err := service.AddCoin(ctx, bytes.NewReader(frontData), "f.jpg", bytes.NewReader(backData), "b.jpg", "NewGroup", "", "", "", 0, "m", 0)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create group")
 	})
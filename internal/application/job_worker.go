@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+const (
+	jobRetryBaseDelay = 10 * time.Second
+	jobRetryMaxDelay  = time.Hour
+	jobTimeout        = 10 * time.Minute
+)
+
+// JobHandler executes a claimed job. Returning an error schedules a retry
+// with exponential backoff until the job runs out of attempts.
+type JobHandler func(ctx context.Context, job *domain.Job) error
+
+// JobWorker polls the persistent job queue and dispatches jobs by kind.
+type JobWorker struct {
+	repo         domain.JobRepository
+	handlers     map[string]JobHandler
+	pollInterval time.Duration
+	concurrency  int
+}
+
+func NewJobWorker(repo domain.JobRepository, pollInterval time.Duration, concurrency int) *JobWorker {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &JobWorker{
+		repo:         repo,
+		handlers:     make(map[string]JobHandler),
+		pollInterval: pollInterval,
+		concurrency:  concurrency,
+	}
+}
+
+// Register sets the handler for a job kind. Must be called before Start.
+func (w *JobWorker) Register(kind string, handler JobHandler) {
+	w.handlers[kind] = handler
+}
+
+// Start puts back in the queue any job left running by a previous process
+// and launches the polling loops. They stop when ctx is cancelled.
+func (w *JobWorker) Start(ctx context.Context) error {
+	n, err := w.repo.RequeueRunning(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		slog.Info("Resuming interrupted jobs", "count", n)
+	}
+
+	for i := 0; i < w.concurrency; i++ {
+		go w.loop(ctx)
+	}
+	return nil
+}
+
+func (w *JobWorker) loop(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before going back to sleep
+		for {
+			found, err := w.RunNext(ctx)
+			if err != nil {
+				slog.Error("Job worker error", "error", err)
+			}
+			if !found || err != nil || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunNext claims and executes a single job. It reports whether a job was found.
+func (w *JobWorker) RunNext(ctx context.Context) (bool, error) {
+	job, err := w.repo.ClaimNext(ctx)
+	if err != nil {
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	handler, ok := w.handlers[job.Kind]
+	if !ok {
+		return true, w.repo.Fail(ctx, job.ID, fmt.Sprintf("no handler for job kind %q", job.Kind))
+	}
+
+	slog.Info("Running job", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempts)
+	// The handler works on the collection the job was enqueued from
+	jobCtx, cancel := context.WithTimeout(domain.WithCollection(ctx, job.CollectionID), jobTimeout)
+	err = handler(jobCtx, job)
+	cancel()
+
+	if err == nil {
+		slog.Info("Job succeeded", "job_id", job.ID, "kind", job.Kind)
+		return true, w.repo.Complete(ctx, job.ID)
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		slog.Error("Job failed permanently", "job_id", job.ID, "kind", job.Kind, "attempts", job.Attempts, "error", err)
+		return true, w.repo.Fail(ctx, job.ID, err.Error())
+	}
+
+	delay := retryBackoff(job.Attempts)
+	slog.Warn("Job failed, will retry", "job_id", job.ID, "kind", job.Kind, "attempt", job.Attempts, "retry_in", delay, "error", err)
+	return true, w.repo.Retry(ctx, job.ID, err.Error(), time.Now().Add(delay))
+}
+
+// retryBackoff doubles the delay on every attempt: 10s, 20s, 40s... capped at an hour.
+func retryBackoff(attempt int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= jobRetryMaxDelay {
+			return jobRetryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,143 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJobWorker_RunNext(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Empty Queue", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockJobRepo := mocks.NewMockJobRepository(ctrl)
+		worker := application.NewJobWorker(mockJobRepo, time.Second, 1)
+
+		mockJobRepo.EXPECT().ClaimNext(ctx).Return(nil, nil)
+
+		found, err := worker.RunNext(ctx)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockJobRepo := mocks.NewMockJobRepository(ctrl)
+		worker := application.NewJobWorker(mockJobRepo, time.Second, 1)
+		job := &domain.Job{ID: uuid.New(), Kind: "test", CollectionID: uuid.New(), Attempts: 1, MaxAttempts: 3}
+
+		called := false
+		worker.Register("test", func(ctx context.Context, j *domain.Job) error {
+			called = true
+			assert.Equal(t, job.ID, j.ID)
+			// The handler runs in the collection of the job
+			collectionID, ok := domain.CollectionFromContext(ctx)
+			assert.True(t, ok)
+			assert.Equal(t, job.CollectionID, collectionID)
+			return nil
+		})
+		mockJobRepo.EXPECT().ClaimNext(ctx).Return(job, nil)
+		mockJobRepo.EXPECT().Complete(ctx, job.ID).Return(nil)
+
+		found, err := worker.RunNext(ctx)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.True(t, called)
+	})
+
+	t.Run("Failure Schedules Retry With Backoff", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockJobRepo := mocks.NewMockJobRepository(ctrl)
+		worker := application.NewJobWorker(mockJobRepo, time.Second, 1)
+		job := &domain.Job{ID: uuid.New(), Kind: "test", Attempts: 2, MaxAttempts: 3}
+
+		worker.Register("test", func(ctx context.Context, j *domain.Job) error {
+			return errors.New("boom")
+		})
+		mockJobRepo.EXPECT().ClaimNext(ctx).Return(job, nil)
+		before := time.Now()
+		mockJobRepo.EXPECT().Retry(ctx, job.ID, "boom", gomock.Any()).DoAndReturn(
+			func(ctx context.Context, id uuid.UUID, lastError string, runAt time.Time) error {
+				// Second attempt waits twice the base delay
+				assert.WithinDuration(t, before.Add(20*time.Second), runAt, 5*time.Second)
+				return nil
+			})
+
+		found, err := worker.RunNext(ctx)
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("Failure On Last Attempt", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockJobRepo := mocks.NewMockJobRepository(ctrl)
+		worker := application.NewJobWorker(mockJobRepo, time.Second, 1)
+		job := &domain.Job{ID: uuid.New(), Kind: "test", Attempts: 3, MaxAttempts: 3}
+
+		worker.Register("test", func(ctx context.Context, j *domain.Job) error {
+			return errors.New("boom")
+		})
+		mockJobRepo.EXPECT().ClaimNext(ctx).Return(job, nil)
+		mockJobRepo.EXPECT().Fail(ctx, job.ID, "boom").Return(nil)
+
+		found, err := worker.RunNext(ctx)
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("Unknown Kind", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockJobRepo := mocks.NewMockJobRepository(ctrl)
+		worker := application.NewJobWorker(mockJobRepo, time.Second, 1)
+		job := &domain.Job{ID: uuid.New(), Kind: "mystery", Attempts: 1, MaxAttempts: 3}
+
+		mockJobRepo.EXPECT().ClaimNext(ctx).Return(job, nil)
+		mockJobRepo.EXPECT().Fail(ctx, job.ID, gomock.Any()).Return(nil)
+
+		found, err := worker.RunNext(ctx)
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("Claim Error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockJobRepo := mocks.NewMockJobRepository(ctrl)
+		worker := application.NewJobWorker(mockJobRepo, time.Second, 1)
+
+		mockJobRepo.EXPECT().ClaimNext(ctx).Return(nil, assert.AnError)
+
+		found, err := worker.RunNext(ctx)
+		assert.Error(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestJobWorker_StartRequeuesRunningJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockJobRepo := mocks.NewMockJobRepository(ctrl)
+	worker := application.NewJobWorker(mockJobRepo, time.Hour, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	polled := make(chan struct{})
+	mockJobRepo.EXPECT().RequeueRunning(ctx).Return(int64(2), nil)
+	mockJobRepo.EXPECT().ClaimNext(gomock.Any()).DoAndReturn(func(ctx context.Context) (*domain.Job, error) {
+		// Stop the loop after the first poll
+		cancel()
+		close(polled)
+		return nil, nil
+	})
+
+	assert.NoError(t, worker.Start(ctx))
+	<-polled
+}
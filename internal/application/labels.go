@@ -0,0 +1,100 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/pdf"
+	"github.com/google/uuid"
+)
+
+// LabelLayout is the sheet the labels are printed on, in millimetres.
+type LabelLayout = pdf.LabelLayout
+
+var (
+	// DefaultLabelLayout fits 2x2 holders on A4.
+	DefaultLabelLayout = pdf.DefaultLabelLayout
+	// ErrInvalidLabelLayout is returned for sheets that do not fit the page
+	// and offsets beyond the sheet.
+	ErrInvalidLabelLayout = pdf.ErrInvalidLayout
+)
+
+// LabelOptions chooses the sheet the labels are printed on. Offset skips
+// that many positions of the first sheet, for one that is partly used.
+// BaseURL is where the web app is served; each QR code links to the detail
+// page of its coin under it.
+type LabelOptions struct {
+	Layout  LabelLayout
+	Offset  int
+	BaseURL string
+}
+
+// LabelsPDF checks the coins and the layout and returns the function that
+// renders the labels, one per coin in the order given. Without coinIDs the
+// labels are for the coins matching filter, ignoring its limit and offset.
+func (s *CoinService) LabelsPDF(ctx context.Context, coinIDs []uuid.UUID, filter domain.CoinFilter, opts LabelOptions) (func(w io.Writer) error, error) {
+	perSheet, err := opts.Layout.LabelsPerSheet()
+	if err != nil {
+		return nil, err
+	}
+	if opts.Offset < 0 || opts.Offset >= perSheet {
+		return nil, fmt.Errorf("%w: offset must be between 0 and %d", ErrInvalidLabelLayout, perSheet-1)
+	}
+
+	var coins []*domain.Coin
+	if len(coinIDs) == 0 {
+		if coins, err = s.allCoins(ctx, filter); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range coinIDs {
+		ok, err := s.repo.Exists(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrCoinNotFound, id)
+		}
+		c, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		coins = append(coins, c)
+	}
+
+	base := strings.TrimSuffix(opts.BaseURL, "/")
+	labels := make([]pdf.Label, 0, len(coins))
+	for _, c := range coins {
+		label := pdf.Label{Link: base + "/coin/" + c.ID.String()}
+		add := func(line string) {
+			if line = strings.TrimSpace(line); line != "" {
+				label.Lines = append(label.Lines, line)
+			}
+		}
+		add(c.Country)
+		if y := c.Year.Int(); y != 0 {
+			add(strconv.Itoa(y))
+		}
+		add(c.FaceValue + " " + c.Currency)
+		if km := c.KMCode.String(); km != "" && !strings.HasPrefix(strings.ToUpper(km), "KM") {
+			add("KM " + km)
+		} else {
+			add(km)
+		}
+		add(c.Grade.String())
+		labels = append(labels, label)
+	}
+
+	return func(w io.Writer) error {
+		if err := pdf.RenderLabels(w, opts.Layout, labels, opts.Offset); err != nil {
+			return err
+		}
+		slog.Info("Labels exported", "coins", len(labels), "offset", opts.Offset)
+		return nil
+	}, nil
+}
@@ -0,0 +1,81 @@
+package application_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLabelsPDF(t *testing.T) {
+	ctx := context.Background()
+	year, _ := domain.NewYear(1975)
+	km, _ := domain.NewKMCode("808")
+
+	t.Run("Selected Coins From An Offset", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		var ids []uuid.UUID
+		for i := 0; i < 3; i++ {
+			id := uuid.New()
+			ids = append(ids, id)
+			mockRepo.EXPECT().Exists(ctx, id).Return(true, nil)
+			mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{
+				ID: id, Country: "España", Year: year, FaceValue: "25", Currency: "Pesetas", KMCode: km,
+			}, nil)
+		}
+
+		render, err := service.LabelsPDF(ctx, ids, domain.CoinFilter{}, application.LabelOptions{
+			Layout:  application.DefaultLabelLayout,
+			Offset:  18, // the last two positions of the first sheet
+			BaseURL: "https://coins.example.com/",
+		})
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		require.NoError(t, render(&out))
+		assert.True(t, bytes.HasPrefix(out.Bytes(), []byte("%PDF-")))
+		assert.Equal(t, 2, bytes.Count(out.Bytes(), []byte("/Type /Page\n")))
+	})
+
+	t.Run("Coins Of A Filter", func(t *testing.T) {
+		service, mockRepo, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		groupID := 2
+		mockGroupRepo.EXPECT().GetByID(ctx, groupID).Return(&domain.Group{ID: groupID}, nil)
+		mockRepo.EXPECT().ListPage(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, filter domain.CoinFilter) (*domain.CoinPage, error) {
+			assert.Equal(t, &groupID, filter.GroupID)
+			return &domain.CoinPage{Coins: []*domain.Coin{{ID: uuid.New(), Country: "Portugal"}}}, nil
+		})
+
+		render, err := service.LabelsPDF(ctx, nil, domain.CoinFilter{GroupID: &groupID}, application.LabelOptions{Layout: application.DefaultLabelLayout})
+		require.NoError(t, err)
+		assert.NoError(t, render(&bytes.Buffer{}))
+	})
+
+	t.Run("Unknown Coin", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		id := uuid.New()
+		mockRepo.EXPECT().Exists(ctx, id).Return(false, nil)
+
+		_, err := service.LabelsPDF(ctx, []uuid.UUID{id}, domain.CoinFilter{}, application.LabelOptions{Layout: application.DefaultLabelLayout})
+		assert.True(t, errors.Is(err, application.ErrCoinNotFound))
+	})
+
+	t.Run("Invalid Layout", func(t *testing.T) {
+		service, _, _, _, _, _, _, _, _ := setupTest(t)
+		wide := application.DefaultLabelLayout
+		wide.Columns = 5
+
+		_, err := service.LabelsPDF(ctx, []uuid.UUID{uuid.New()}, domain.CoinFilter{}, application.LabelOptions{Layout: wide})
+		assert.True(t, errors.Is(err, application.ErrInvalidLabelLayout))
+
+		_, err = service.LabelsPDF(ctx, []uuid.UUID{uuid.New()}, domain.CoinFilter{}, application.LabelOptions{Layout: application.DefaultLabelLayout, Offset: 20})
+		assert.True(t, errors.Is(err, application.ErrInvalidLabelLayout))
+	})
+}
@@ -43,6 +43,21 @@ func (m *MockNumistaService) EXPECT() *MockNumistaServiceMockRecorder {
 	return m.recorder
 }
 
+// GetIssues mocks base method.
+func (m *MockNumistaService) GetIssues(ctx context.Context, typeID int) ([]numista.Issue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIssues", ctx, typeID)
+	ret0, _ := ret[0].([]numista.Issue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssues indicates an expected call of GetIssues.
+func (mr *MockNumistaServiceMockRecorder) GetIssues(ctx, typeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssues", reflect.TypeOf((*MockNumistaService)(nil).GetIssues), ctx, typeID)
+}
+
 // GetType mocks base method.
 func (m *MockNumistaService) GetType(ctx context.Context, id int) (map[string]any, error) {
 	m.ctrl.T.Helper()
@@ -155,3 +170,18 @@ func (mr *MockStorageServiceMockRecorder) DeleteCoinDirectory(coinID any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCoinDirectory", reflect.TypeOf((*MockStorageService)(nil).DeleteCoinDirectory), coinID)
 }
+
+// ReadFile mocks base method.
+func (m *MockStorageService) ReadFile(path string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadFile", path)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadFile indicates an expected call of ReadFile.
+func (mr *MockStorageServiceMockRecorder) ReadFile(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadFile", reflect.TypeOf((*MockStorageService)(nil).ReadFile), path)
+}
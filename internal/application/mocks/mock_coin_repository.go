@@ -57,6 +57,20 @@ func (mr *MockCoinRepositoryMockRecorder) AddImage(ctx, image any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddImage", reflect.TypeOf((*MockCoinRepository)(nil).AddImage), ctx, image)
 }
 
+// AppendAuditEntry mocks base method.
+func (m *MockCoinRepository) AppendAuditEntry(ctx context.Context, e *domain.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendAuditEntry", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAuditEntry indicates an expected call of AppendAuditEntry.
+func (mr *MockCoinRepositoryMockRecorder) AppendAuditEntry(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAuditEntry", reflect.TypeOf((*MockCoinRepository)(nil).AppendAuditEntry), ctx, e)
+}
+
 // Count mocks base method.
 func (m *MockCoinRepository) Count(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +86,36 @@ func (mr *MockCoinRepositoryMockRecorder) Count(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockCoinRepository)(nil).Count), ctx)
 }
 
+// CountMatching mocks base method.
+func (m *MockCoinRepository) CountMatching(ctx context.Context, filter domain.CoinFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMatching", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountMatching indicates an expected call of CountMatching.
+func (mr *MockCoinRepositoryMockRecorder) CountMatching(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMatching", reflect.TypeOf((*MockCoinRepository)(nil).CountMatching), ctx, filter)
+}
+
+// Matches mocks base method.
+func (m *MockCoinRepository) Matches(ctx context.Context, filter domain.CoinFilter, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Matches", ctx, filter, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Matches indicates an expected call of Matches.
+func (mr *MockCoinRepositoryMockRecorder) Matches(ctx, filter, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Matches", reflect.TypeOf((*MockCoinRepository)(nil).Matches), ctx, filter, id)
+}
+
 // Delete mocks base method.
 func (m *MockCoinRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -86,6 +130,36 @@ func (mr *MockCoinRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCoinRepository)(nil).Delete), ctx, id)
 }
 
+// Exists mocks base method.
+func (m *MockCoinRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockCoinRepositoryMockRecorder) Exists(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockCoinRepository)(nil).Exists), ctx, id)
+}
+
+// Facets mocks base method.
+func (m *MockCoinRepository) Facets(ctx context.Context, filter domain.CoinFilter) (*domain.CoinFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Facets", ctx, filter)
+	ret0, _ := ret[0].(*domain.CoinFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Facets indicates an expected call of Facets.
+func (mr *MockCoinRepositoryMockRecorder) Facets(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Facets", reflect.TypeOf((*MockCoinRepository)(nil).Facets), ctx, filter)
+}
+
 // GetAllCoins mocks base method.
 func (m *MockCoinRepository) GetAllCoins(ctx context.Context) ([]*domain.Coin, error) {
 	m.ctrl.T.Helper()
@@ -161,6 +235,21 @@ func (mr *MockCoinRepositoryMockRecorder) GetCountryDistribution(ctx any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCountryDistribution", reflect.TypeOf((*MockCoinRepository)(nil).GetCountryDistribution), ctx)
 }
 
+// GetFilterStats mocks base method.
+func (m *MockCoinRepository) GetFilterStats(ctx context.Context, filter domain.CoinFilter) (*domain.GroupStat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilterStats", ctx, filter)
+	ret0, _ := ret[0].(*domain.GroupStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilterStats indicates an expected call of GetFilterStats.
+func (mr *MockCoinRepositoryMockRecorder) GetFilterStats(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilterStats", reflect.TypeOf((*MockCoinRepository)(nil).GetFilterStats), ctx, filter)
+}
+
 // GetGradeDistribution mocks base method.
 func (m *MockCoinRepository) GetGradeDistribution(ctx context.Context) (map[string]int, error) {
 	m.ctrl.T.Helper()
@@ -296,6 +385,21 @@ func (mr *MockCoinRepositoryMockRecorder) GetSmallestCoin(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSmallestCoin", reflect.TypeOf((*MockCoinRepository)(nil).GetSmallestCoin), ctx)
 }
 
+// GetTagDistribution mocks base method.
+func (m *MockCoinRepository) GetTagDistribution(ctx context.Context) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagDistribution", ctx)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagDistribution indicates an expected call of GetTagDistribution.
+func (mr *MockCoinRepositoryMockRecorder) GetTagDistribution(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagDistribution", reflect.TypeOf((*MockCoinRepository)(nil).GetTagDistribution), ctx)
+}
+
 // GetTotalValue mocks base method.
 func (m *MockCoinRepository) GetTotalValue(ctx context.Context) (float64, error) {
 	m.ctrl.T.Helper()
@@ -341,6 +445,21 @@ func (mr *MockCoinRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCoinRepository)(nil).List), ctx, filter)
 }
 
+// ListPage mocks base method.
+func (m *MockCoinRepository) ListPage(ctx context.Context, filter domain.CoinFilter) (*domain.CoinPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPage", ctx, filter)
+	ret0, _ := ret[0].(*domain.CoinPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPage indicates an expected call of ListPage.
+func (mr *MockCoinRepositoryMockRecorder) ListPage(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*MockCoinRepository)(nil).ListPage), ctx, filter)
+}
+
 // ListRecent mocks base method.
 func (m *MockCoinRepository) ListRecent(ctx context.Context) ([]*domain.Coin, error) {
 	m.ctrl.T.Helper()
@@ -371,6 +490,20 @@ func (mr *MockCoinRepositoryMockRecorder) ListTopValuable(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTopValuable", reflect.TypeOf((*MockCoinRepository)(nil).ListTopValuable), ctx)
 }
 
+// RestoreFromTrash mocks base method.
+func (m *MockCoinRepository) RestoreFromTrash(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreFromTrash", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreFromTrash indicates an expected call of RestoreFromTrash.
+func (mr *MockCoinRepositoryMockRecorder) RestoreFromTrash(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreFromTrash", reflect.TypeOf((*MockCoinRepository)(nil).RestoreFromTrash), ctx, id)
+}
+
 // Save mocks base method.
 func (m *MockCoinRepository) Save(ctx context.Context, coin *domain.Coin) error {
 	m.ctrl.T.Helper()
@@ -385,6 +518,21 @@ func (mr *MockCoinRepositoryMockRecorder) Save(ctx, coin any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCoinRepository)(nil).Save), ctx, coin)
 }
 
+// Trash mocks base method.
+func (m *MockCoinRepository) Trash(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trash", ctx, id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trash indicates an expected call of Trash.
+func (mr *MockCoinRepositoryMockRecorder) Trash(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trash", reflect.TypeOf((*MockCoinRepository)(nil).Trash), ctx, id, at)
+}
+
 // Update mocks base method.
 func (m *MockCoinRepository) Update(ctx context.Context, coin *domain.Coin) error {
 	m.ctrl.T.Helper()
@@ -414,6 +562,36 @@ func (mr *MockCoinRepositoryMockRecorder) GetSaleChannels(ctx any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSaleChannels", reflect.TypeOf((*MockCoinRepository)(nil).GetSaleChannels), ctx)
 }
 
+// ListTrash mocks base method.
+func (m *MockCoinRepository) ListTrash(ctx context.Context) ([]*domain.Coin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrash", ctx)
+	ret0, _ := ret[0].([]*domain.Coin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrash indicates an expected call of ListTrash.
+func (mr *MockCoinRepositoryMockRecorder) ListTrash(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrash", reflect.TypeOf((*MockCoinRepository)(nil).ListTrash), ctx)
+}
+
+// ListTrashedBefore mocks base method.
+func (m *MockCoinRepository) ListTrashedBefore(ctx context.Context, cutoff time.Time) (map[uuid.UUID][]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrashedBefore", ctx, cutoff)
+	ret0, _ := ret[0].(map[uuid.UUID][]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrashedBefore indicates an expected call of ListTrashedBefore.
+func (mr *MockCoinRepositoryMockRecorder) ListTrashedBefore(ctx, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrashedBefore", reflect.TypeOf((*MockCoinRepository)(nil).ListTrashedBefore), ctx, cutoff)
+}
+
 // MarkAsSold mocks base method.
 func (m *MockCoinRepository) MarkAsSold(ctx context.Context, id uuid.UUID, soldAt time.Time, soldPrice float64, saleChannel string) (*domain.Coin, error) {
 	m.ctrl.T.Helper()
@@ -559,6 +737,21 @@ func (mr *MockCoinRepositoryMockRecorder) RemoveGalleryImage(ctx, id any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveGalleryImage", reflect.TypeOf((*MockCoinRepository)(nil).RemoveGalleryImage), ctx, id)
 }
 
+// ListAuditEntries mocks base method.
+func (m *MockCoinRepository) ListAuditEntries(ctx context.Context, entityType, entityID string) ([]*domain.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEntries", ctx, entityType, entityID)
+	ret0, _ := ret[0].([]*domain.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEntries indicates an expected call of ListAuditEntries.
+func (mr *MockCoinRepositoryMockRecorder) ListAuditEntries(ctx, entityType, entityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEntries", reflect.TypeOf((*MockCoinRepository)(nil).ListAuditEntries), ctx, entityType, entityID)
+}
+
 // ListGalleryImages mocks base method.
 func (m *MockCoinRepository) ListGalleryImages(ctx context.Context, coinID uuid.UUID) ([]domain.CoinGalleryImage, error) {
 	m.ctrl.T.Helper()
@@ -588,3 +781,61 @@ func (mr *MockCoinRepositoryMockRecorder) GetCoinStats(ctx, id any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoinStats", reflect.TypeOf((*MockCoinRepository)(nil).GetCoinStats), ctx, id)
 }
+
+// UpdateStatus mocks base method.
+func (m *MockCoinRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockCoinRepositoryMockRecorder) UpdateStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockCoinRepository)(nil).UpdateStatus), ctx, id, status)
+}
+
+// SaveNumistaEnrichment mocks base method.
+func (m *MockCoinRepository) SaveNumistaEnrichment(ctx context.Context, e *domain.NumistaEnrichment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveNumistaEnrichment", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveNumistaEnrichment indicates an expected call of SaveNumistaEnrichment.
+func (mr *MockCoinRepositoryMockRecorder) SaveNumistaEnrichment(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNumistaEnrichment", reflect.TypeOf((*MockCoinRepository)(nil).SaveNumistaEnrichment), ctx, e)
+}
+
+// GetNumistaEnrichment mocks base method.
+func (m *MockCoinRepository) GetNumistaEnrichment(ctx context.Context, coinID uuid.UUID) (*domain.NumistaEnrichment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNumistaEnrichment", ctx, coinID)
+	ret0, _ := ret[0].(*domain.NumistaEnrichment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNumistaEnrichment indicates an expected call of GetNumistaEnrichment.
+func (mr *MockCoinRepositoryMockRecorder) GetNumistaEnrichment(ctx, coinID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNumistaEnrichment", reflect.TypeOf((*MockCoinRepository)(nil).GetNumistaEnrichment), ctx, coinID)
+}
+
+// ListNumistaEnrichments mocks base method.
+func (m *MockCoinRepository) ListNumistaEnrichments(ctx context.Context, status string) ([]*domain.NumistaEnrichment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNumistaEnrichments", ctx, status)
+	ret0, _ := ret[0].([]*domain.NumistaEnrichment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNumistaEnrichments indicates an expected call of ListNumistaEnrichments.
+func (mr *MockCoinRepositoryMockRecorder) ListNumistaEnrichments(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNumistaEnrichments", reflect.TypeOf((*MockCoinRepository)(nil).ListNumistaEnrichments), ctx, status)
+}
@@ -57,6 +57,21 @@ func (mr *MockGroupRepositoryMockRecorder) Create(ctx, name, description any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockGroupRepository)(nil).Create), ctx, name, description)
 }
 
+// CreateSmart mocks base method.
+func (m *MockGroupRepository) CreateSmart(ctx context.Context, name, description string, filter domain.SmartFilter) (*domain.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSmart", ctx, name, description, filter)
+	ret0, _ := ret[0].(*domain.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSmart indicates an expected call of CreateSmart.
+func (mr *MockGroupRepositoryMockRecorder) CreateSmart(ctx, name, description, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSmart", reflect.TypeOf((*MockGroupRepository)(nil).CreateSmart), ctx, name, description, filter)
+}
+
 // Delete mocks base method.
 func (m *MockGroupRepository) Delete(ctx context.Context, id int) error {
 	m.ctrl.T.Helper()
@@ -71,6 +86,36 @@ func (mr *MockGroupRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockGroupRepository)(nil).Delete), ctx, id)
 }
 
+// Exists mocks base method.
+func (m *MockGroupRepository) Exists(ctx context.Context, id int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockGroupRepositoryMockRecorder) Exists(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockGroupRepository)(nil).Exists), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockGroupRepository) GetByID(ctx context.Context, id int) (*domain.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockGroupRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockGroupRepository)(nil).GetByID), ctx, id)
+}
+
 // GetByName mocks base method.
 func (m *MockGroupRepository) GetByName(ctx context.Context, name string) (*domain.Group, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +146,20 @@ func (mr *MockGroupRepositoryMockRecorder) List(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockGroupRepository)(nil).List), ctx)
 }
 
+// Move mocks base method.
+func (m *MockGroupRepository) Move(ctx context.Context, id int, parentID *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Move", ctx, id, parentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Move indicates an expected call of Move.
+func (mr *MockGroupRepositoryMockRecorder) Move(ctx, id, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Move", reflect.TypeOf((*MockGroupRepository)(nil).Move), ctx, id, parentID)
+}
+
 // Update mocks base method.
 func (m *MockGroupRepository) Update(ctx context.Context, group *domain.Group) error {
 	m.ctrl.T.Helper()
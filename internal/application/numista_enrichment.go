@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// JobKindEnrichNumista looks up a coin in Numista and stores the matching type.
+	JobKindEnrichNumista = "enrich_numista"
+
+	enrichNumistaMaxAttempts = 6
+)
+
+// triggerNumistaEnrichment queues the Numista lookup of a coin. Without a job
+// queue it falls back to a detached goroutine.
+func (s *CoinService) triggerNumistaEnrichment(ctx context.Context, coinID uuid.UUID) {
+	if s.numistaClient == nil {
+		return
+	}
+
+	if s.jobRepo == nil {
+		go func(id uuid.UUID) {
+			// Outlive the request but keep its collection
+			bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Minute)
+			defer cancel()
+			if err := s.EnrichCoinWithNumista(bgCtx, id); err != nil {
+				slog.Error("Failed to enrich coin with Numista", "coin_id", id, "error", err)
+			} else {
+				slog.Info("Successfully enriched coin with Numista", "coin_id", id)
+			}
+		}(coinID)
+		return
+	}
+
+	if err := s.queueNumistaEnrichment(ctx, coinID); err != nil {
+		slog.Error("Failed to queue Numista enrichment", "coin_id", coinID, "error", err)
+	}
+}
+
+func (s *CoinService) queueNumistaEnrichment(ctx context.Context, coinID uuid.UUID) error {
+	job := &domain.Job{
+		Kind:        JobKindEnrichNumista,
+		CoinID:      &coinID,
+		MaxAttempts: enrichNumistaMaxAttempts,
+	}
+	if err := s.jobRepo.Enqueue(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue numista enrichment: %w", err)
+	}
+
+	return s.repo.SaveNumistaEnrichment(ctx, &domain.NumistaEnrichment{
+		CoinID: coinID,
+		Status: domain.EnrichmentStatusQueued,
+		JobID:  &job.ID,
+	})
+}
+
+// EnrichNumistaJob is the worker handler for JobKindEnrichNumista. It keeps
+// the coin's enrichment state in sync with the job: the worker retries it
+// with the same backoff recorded here as next_retry_at.
+func (s *CoinService) EnrichNumistaJob(ctx context.Context, job *domain.Job) error {
+	if job.CoinID == nil {
+		return fmt.Errorf("job %s has no coin", job.ID)
+	}
+	coinID := *job.CoinID
+
+	state := &domain.NumistaEnrichment{
+		CoinID:   coinID,
+		Status:   domain.EnrichmentStatusRunning,
+		Attempts: job.Attempts,
+		JobID:    &job.ID,
+	}
+	s.saveEnrichmentState(ctx, state)
+
+	err := s.EnrichCoinWithNumista(ctx, coinID)
+	switch {
+	case err == nil:
+		state.Status = domain.EnrichmentStatusSucceeded
+	case job.Attempts >= job.MaxAttempts:
+		state.Status = domain.EnrichmentStatusFailed
+		state.LastError = err.Error()
+	default:
+		next := time.Now().Add(retryBackoff(job.Attempts))
+		state.Status = domain.EnrichmentStatusRetrying
+		state.LastError = err.Error()
+		state.NextRetryAt = &next
+	}
+	s.saveEnrichmentState(ctx, state)
+
+	return err
+}
+
+// saveEnrichmentState only logs errors: losing a state update must not make
+// the enrichment itself fail.
+func (s *CoinService) saveEnrichmentState(ctx context.Context, state *domain.NumistaEnrichment) {
+	if err := s.repo.SaveNumistaEnrichment(ctx, state); err != nil {
+		slog.Error("Failed to save Numista enrichment state", "coin_id", state.CoinID, "status", state.Status, "error", err)
+	}
+}
+
+// GetNumistaEnrichment returns the enrichment state of a coin, or nil if it
+// was never queued.
+func (s *CoinService) GetNumistaEnrichment(ctx context.Context, coinID uuid.UUID) (*domain.NumistaEnrichment, error) {
+	return s.repo.GetNumistaEnrichment(ctx, coinID)
+}
+
+// ListFailedNumistaEnrichments returns the coins whose enrichment ran out of attempts.
+func (s *CoinService) ListFailedNumistaEnrichments(ctx context.Context) ([]*domain.NumistaEnrichment, error) {
+	return s.repo.ListNumistaEnrichments(ctx, domain.EnrichmentStatusFailed)
+}
+
+// RetryNumistaEnrichments queues the enrichment of the given coins again, or
+// of every failed one when coinIDs is empty. It returns how many were queued.
+// Given coins must all belong to the collection of the caller, otherwise
+// nothing is queued and ErrCoinNotFound is returned.
+func (s *CoinService) RetryNumistaEnrichments(ctx context.Context, coinIDs []uuid.UUID) (int, error) {
+	if s.numistaClient == nil {
+		return 0, fmt.Errorf("numista client is not configured")
+	}
+	if s.jobRepo == nil {
+		return 0, fmt.Errorf("job queue is not configured")
+	}
+
+	if len(coinIDs) == 0 {
+		failed, err := s.ListFailedNumistaEnrichments(ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, e := range failed {
+			coinIDs = append(coinIDs, e.CoinID)
+		}
+	} else {
+		for _, id := range coinIDs {
+			ok, err := s.repo.Exists(ctx, id)
+			if err != nil {
+				return 0, err
+			}
+			if !ok {
+				return 0, fmt.Errorf("%w: %s", ErrCoinNotFound, id)
+			}
+		}
+	}
+
+	for i, id := range coinIDs {
+		if err := s.queueNumistaEnrichment(ctx, id); err != nil {
+			return i, err
+		}
+	}
+	slog.Info("Queued Numista enrichment retries", "count", len(coinIDs))
+	return len(coinIDs), nil
+}
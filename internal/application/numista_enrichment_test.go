@@ -0,0 +1,191 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/numista"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupEnrichmentTest(t *testing.T) (*application.CoinService, *mocks.MockCoinRepository, *mocks.MockNumistaService, *mocks.MockJobRepository) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mocks.NewMockCoinRepository(ctrl)
+	mockNumista := mocks.NewMockNumistaService(ctrl)
+	mockJobRepo := mocks.NewMockJobRepository(ctrl)
+	service := application.NewCoinService(mockRepo, nil, nil, nil, nil, nil, nil, nil, nil, mockNumista, nil, mockJobRepo)
+	return service, mockRepo, mockNumista, mockJobRepo
+}
+
+func newEnrichJob(coinID uuid.UUID, attempts int) *domain.Job {
+	return &domain.Job{
+		ID:          uuid.New(),
+		Kind:        application.JobKindEnrichNumista,
+		CoinID:      &coinID,
+		Attempts:    attempts,
+		MaxAttempts: 6,
+	}
+}
+
+func TestEnrichNumistaJob(t *testing.T) {
+	ctx := context.Background()
+	coinID := uuid.New()
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockRepo, mockNumista, _ := setupEnrichmentTest(t)
+		job := newEnrichJob(coinID, 1)
+
+		var states []string
+		mockRepo.EXPECT().SaveNumistaEnrichment(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.NumistaEnrichment) error {
+			states = append(states, e.Status)
+			assert.Equal(t, job.ID, *e.JobID)
+			return nil
+		}).Times(2)
+		mockRepo.EXPECT().GetByID(ctx, coinID).Return(&domain.Coin{ID: coinID, FaceValue: "1 Euro", Year: mustYear(2002)}, nil)
+		mockNumista.EXPECT().SearchTypes(ctx, gomock.Any(), gomock.Any(), "2002", gomock.Any(), gomock.Any()).Return(&numista.TypeSearchResponse{Count: 0}, nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		err := service.EnrichNumistaJob(ctx, job)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{domain.EnrichmentStatusRunning, domain.EnrichmentStatusSucceeded}, states)
+	})
+
+	t.Run("Failure Schedules Retry", func(t *testing.T) {
+		service, mockRepo, _, _ := setupEnrichmentTest(t)
+		job := newEnrichJob(coinID, 2)
+
+		var last *domain.NumistaEnrichment
+		mockRepo.EXPECT().SaveNumistaEnrichment(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.NumistaEnrichment) error {
+			copied := *e
+			last = &copied
+			return nil
+		}).Times(2)
+		mockRepo.EXPECT().GetByID(ctx, coinID).Return(nil, assert.AnError)
+
+		before := time.Now()
+		err := service.EnrichNumistaJob(ctx, job)
+		assert.Error(t, err)
+		assert.Equal(t, domain.EnrichmentStatusRetrying, last.Status)
+		assert.Equal(t, 2, last.Attempts)
+		assert.NotEmpty(t, last.LastError)
+		if assert.NotNil(t, last.NextRetryAt) {
+			assert.WithinDuration(t, before.Add(20*time.Second), *last.NextRetryAt, 5*time.Second)
+		}
+	})
+
+	t.Run("Failure On Last Attempt", func(t *testing.T) {
+		service, mockRepo, _, _ := setupEnrichmentTest(t)
+		job := newEnrichJob(coinID, 6)
+
+		var last *domain.NumistaEnrichment
+		mockRepo.EXPECT().SaveNumistaEnrichment(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.NumistaEnrichment) error {
+			copied := *e
+			last = &copied
+			return nil
+		}).Times(2)
+		mockRepo.EXPECT().GetByID(ctx, coinID).Return(nil, assert.AnError)
+
+		err := service.EnrichNumistaJob(ctx, job)
+		assert.Error(t, err)
+		assert.Equal(t, domain.EnrichmentStatusFailed, last.Status)
+		assert.Nil(t, last.NextRetryAt)
+	})
+
+	t.Run("State Save Error Does Not Fail Job", func(t *testing.T) {
+		service, mockRepo, mockNumista, _ := setupEnrichmentTest(t)
+		job := newEnrichJob(coinID, 1)
+
+		mockRepo.EXPECT().SaveNumistaEnrichment(ctx, gomock.Any()).Return(assert.AnError).Times(2)
+		mockRepo.EXPECT().GetByID(ctx, coinID).Return(&domain.Coin{ID: coinID, FaceValue: "1 Euro", Year: mustYear(2002)}, nil)
+		mockNumista.EXPECT().SearchTypes(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&numista.TypeSearchResponse{Count: 0}, nil)
+		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		assert.NoError(t, service.EnrichNumistaJob(ctx, job))
+	})
+
+	t.Run("Job Without Coin", func(t *testing.T) {
+		service, _, _, _ := setupEnrichmentTest(t)
+		err := service.EnrichNumistaJob(ctx, &domain.Job{ID: uuid.New(), Kind: application.JobKindEnrichNumista})
+		assert.Error(t, err)
+	})
+}
+
+func TestRetryNumistaEnrichments(t *testing.T) {
+	ctx := context.Background()
+
+	expectQueued := func(mockRepo *mocks.MockCoinRepository, mockJobRepo *mocks.MockJobRepository, coinID uuid.UUID) {
+		jobID := uuid.New()
+		mockJobRepo.EXPECT().Enqueue(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, job *domain.Job) error {
+			assert.Equal(t, application.JobKindEnrichNumista, job.Kind)
+			assert.Equal(t, coinID, *job.CoinID)
+			job.ID = jobID
+			return nil
+		})
+		mockRepo.EXPECT().SaveNumistaEnrichment(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.NumistaEnrichment) error {
+			assert.Equal(t, coinID, e.CoinID)
+			assert.Equal(t, domain.EnrichmentStatusQueued, e.Status)
+			assert.Equal(t, jobID, *e.JobID)
+			return nil
+		})
+	}
+
+	t.Run("All Failed", func(t *testing.T) {
+		service, mockRepo, _, mockJobRepo := setupEnrichmentTest(t)
+		id1, id2 := uuid.New(), uuid.New()
+
+		mockRepo.EXPECT().ListNumistaEnrichments(ctx, domain.EnrichmentStatusFailed).Return([]*domain.NumistaEnrichment{
+			{CoinID: id1, Status: domain.EnrichmentStatusFailed},
+			{CoinID: id2, Status: domain.EnrichmentStatusFailed},
+		}, nil)
+		expectQueued(mockRepo, mockJobRepo, id1)
+		expectQueued(mockRepo, mockJobRepo, id2)
+
+		n, err := service.RetryNumistaEnrichments(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("Selected Coins", func(t *testing.T) {
+		service, mockRepo, _, mockJobRepo := setupEnrichmentTest(t)
+		id := uuid.New()
+		mockRepo.EXPECT().Exists(ctx, id).Return(true, nil)
+		expectQueued(mockRepo, mockJobRepo, id)
+
+		n, err := service.RetryNumistaEnrichments(ctx, []uuid.UUID{id})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("Coin Of Another Collection", func(t *testing.T) {
+		service, mockRepo, _, _ := setupEnrichmentTest(t)
+		own, foreign := uuid.New(), uuid.New()
+		mockRepo.EXPECT().Exists(ctx, own).Return(true, nil)
+		mockRepo.EXPECT().Exists(ctx, foreign).Return(false, nil)
+
+		n, err := service.RetryNumistaEnrichments(ctx, []uuid.UUID{own, foreign})
+		assert.ErrorIs(t, err, application.ErrCoinNotFound)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("Enqueue Error", func(t *testing.T) {
+		service, mockRepo, _, mockJobRepo := setupEnrichmentTest(t)
+		mockRepo.EXPECT().Exists(ctx, gomock.Any()).Return(true, nil).Times(2)
+		mockJobRepo.EXPECT().Enqueue(ctx, gomock.Any()).Return(assert.AnError)
+
+		n, err := service.RetryNumistaEnrichments(ctx, []uuid.UUID{uuid.New(), uuid.New()})
+		assert.Error(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("No Job Queue", func(t *testing.T) {
+		service, _, _, _, _, _, _, _, _ := setupTest(t)
+		_, err := service.RetryNumistaEnrichments(ctx, nil)
+		assert.Error(t, err)
+	})
+}
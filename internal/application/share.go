@@ -0,0 +1,247 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrShareNotFound      = errors.New("share link not found")
+	ErrNotShared          = errors.New("not part of this share link")
+	ErrShareGroupNotFound = errors.New("group not found")
+	ErrShareSmartGroup    = errors.New("smart groups cannot be shared")
+)
+
+// maxSharedCoins caps a page of the public coin list, so an anonymous
+// visitor cannot read the whole collection in one request.
+const maxSharedCoins = 100
+
+// SharedGroup is the group a share link shows, if any.
+type SharedGroup struct {
+	ID          int                 `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Images      []domain.GroupImage `json:"images"`
+}
+
+// SharedView is the landing page of a share link.
+type SharedView struct {
+	Name      string             `json:"name"`
+	Group     *SharedGroup       `json:"group"`
+	Filter    domain.ShareFilter `json:"filter"`
+	ExpiresAt *time.Time         `json:"expires_at"`
+}
+
+// ShareService manages share links and serves what they show to anonymous
+// visitors. Every public method resolves the token first and then works in
+// the collection of the link, so nothing outside it can be reached.
+type ShareService struct {
+	repo      domain.ShareRepository
+	coinRepo  domain.CoinRepository
+	groupRepo domain.GroupRepository
+}
+
+func NewShareService(repo domain.ShareRepository, coinRepo domain.CoinRepository, groupRepo domain.GroupRepository) *ShareService {
+	return &ShareService{
+		repo:      repo,
+		coinRepo:  coinRepo,
+		groupRepo: groupRepo,
+	}
+}
+
+// CreateShareLink creates a link to a group (when groupID is set) or to the
+// coins matching filter, and returns its token. As with API tokens, the
+// token is not stored and cannot be shown again.
+func (s *ShareService) CreateShareLink(ctx context.Context, name string, groupID *int, filter domain.ShareFilter, expiresAt *time.Time) (string, *domain.ShareLink, error) {
+	if groupID != nil {
+		group, err := s.groupRepo.GetByID(ctx, *groupID)
+		if err != nil {
+			return "", nil, err
+		}
+		if group == nil {
+			return "", nil, ErrShareGroupNotFound
+		}
+		// Links check their coins in Go, which cannot evaluate a search
+		if group.IsSmart() {
+			return "", nil, ErrShareSmartGroup
+		}
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return "", nil, err
+	}
+	link := &domain.ShareLink{
+		Name:      strings.TrimSpace(name),
+		GroupID:   groupID,
+		Filter:    filter,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Create(ctx, link, hashSessionToken(token)); err != nil {
+		return "", nil, err
+	}
+	return token, link, nil
+}
+
+func (s *ShareService) ListShareLinks(ctx context.Context) ([]*domain.ShareLink, error) {
+	return s.repo.List(ctx)
+}
+
+// RevokeShareLink deletes a link; its token stops working at once.
+func (s *ShareService) RevokeShareLink(ctx context.Context, id uuid.UUID) error {
+	found, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrShareNotFound
+	}
+	return nil
+}
+
+// OpenShare returns the landing page of a link and counts the visit.
+func (s *ShareService) OpenShare(ctx context.Context, token string) (*SharedView, error) {
+	link, ctx, err := s.resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// A lost count is not worth failing the visit for
+	if err := s.repo.RecordView(ctx, link.ID); err != nil {
+		slog.Error("Failed to record share link view", "share_id", link.ID, "error", err)
+	}
+
+	view := &SharedView{Name: link.Name, Filter: link.Filter, ExpiresAt: link.ExpiresAt}
+	if link.GroupID != nil {
+		group, err := s.sharedGroup(ctx, *link.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		view.Group = group
+	}
+	return view, nil
+}
+
+// ListSharedCoins returns a page of the coins the link shows.
+func (s *ShareService) ListSharedCoins(ctx context.Context, token string, limit, offset int, sortBy, sortOrder *string) ([]*domain.PublicCoin, error) {
+	link, ctx, err := s.resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = min(limit, maxSharedCoins)
+	coins, err := s.coinRepo.List(ctx, link.CoinFilter(limit, offset, sortBy, sortOrder))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*domain.PublicCoin, len(coins))
+	for i, c := range coins {
+		result[i] = domain.NewPublicCoin(c)
+	}
+	return result, nil
+}
+
+// GetSharedCoin returns one coin of the link, gallery included.
+func (s *ShareService) GetSharedCoin(ctx context.Context, token string, id uuid.UUID) (*domain.PublicCoin, error) {
+	link, ctx, err := s.resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	coin, err := s.sharedCoin(ctx, link, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewPublicCoin(coin), nil
+}
+
+// CanReadSharedFile reports whether a stored file, given by its path below
+// the storage directory, belongs to a coin the link shows or to its group.
+func (s *ShareService) CanReadSharedFile(ctx context.Context, token, file string) (bool, error) {
+	link, ctx, err := s.resolve(ctx, token)
+	if err != nil {
+		return false, err
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path.Clean("/"+file), "/"), "/")
+	if len(parts) < 3 {
+		return false, nil
+	}
+	switch parts[0] {
+	case "coins":
+		id, err := uuid.Parse(parts[1])
+		if err != nil {
+			return false, nil
+		}
+		if _, err := s.sharedCoin(ctx, link, id); err != nil {
+			if errors.Is(err, ErrNotShared) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	case "groups":
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return false, nil
+		}
+		return link.GroupID != nil && *link.GroupID == id, nil
+	}
+	return false, nil
+}
+
+// resolve finds the link of a token and returns a context limited to its
+// collection.
+func (s *ShareService) resolve(ctx context.Context, token string) (*domain.ShareLink, context.Context, error) {
+	if token == "" {
+		return nil, nil, ErrShareNotFound
+	}
+	link, err := s.repo.GetByTokenHash(ctx, hashSessionToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if link == nil {
+		return nil, nil, ErrShareNotFound
+	}
+	return link, domain.WithCollection(ctx, link.CollectionID), nil
+}
+
+// sharedCoin returns a coin the link shows. Membership is checked with the
+// same query that lists the link's coins, so both always agree.
+func (s *ShareService) sharedCoin(ctx context.Context, link *domain.ShareLink, id uuid.UUID) (*domain.Coin, error) {
+	ok, err := s.coinRepo.Matches(ctx, link.CoinFilter(0, 0, nil, nil), id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotShared
+	}
+	return s.coinRepo.GetByID(ctx, id)
+}
+
+func (s *ShareService) sharedGroup(ctx context.Context, id int) (*SharedGroup, error) {
+	groups, err := s.groupRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if g.ID != id {
+			continue
+		}
+		images, err := s.groupRepo.ListImages(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &SharedGroup{ID: g.ID, Name: g.Name, Description: g.Description, Images: images}, nil
+	}
+	// The link goes away with its group; this only happens mid-deletion
+	return nil, ErrShareNotFound
+}
@@ -0,0 +1,226 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupShareTest(t *testing.T) (*application.ShareService, *mocks.MockShareRepository, *mocks.MockCoinRepository, *mocks.MockGroupRepository) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockShareRepository(ctrl)
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	groupRepo := mocks.NewMockGroupRepository(ctrl)
+	return application.NewShareService(repo, coinRepo, groupRepo), repo, coinRepo, groupRepo
+}
+
+// inCollection matches contexts limited to the given collection.
+func inCollection(id uuid.UUID) gomock.Matcher {
+	return gomock.Cond(func(ctx context.Context) bool {
+		got, ok := domain.CollectionFromContext(ctx)
+		return ok && got == id
+	})
+}
+
+func TestCreateShareLink(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Stores Only The Hash", func(t *testing.T) {
+		service, repo, _, groupRepo := setupShareTest(t)
+		groupID := 4
+		groupRepo.EXPECT().GetByID(ctx, groupID).Return(&domain.Group{ID: groupID}, nil)
+		var storedHash string
+		repo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, link *domain.ShareLink, tokenHash string) error {
+			assert.Equal(t, "Roman coins", link.Name)
+			assert.Equal(t, &groupID, link.GroupID)
+			storedHash = tokenHash
+			return nil
+		})
+
+		token, link, err := service.CreateShareLink(ctx, " Roman coins ", &groupID, domain.ShareFilter{}, nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, link)
+		assert.NotEmpty(t, token)
+		assert.NotEqual(t, token, storedHash)
+		assert.Len(t, storedHash, 64)
+	})
+
+	t.Run("Unknown Group", func(t *testing.T) {
+		service, _, _, groupRepo := setupShareTest(t)
+		groupID := 9
+		groupRepo.EXPECT().GetByID(ctx, groupID).Return(nil, nil)
+
+		_, _, err := service.CreateShareLink(ctx, "Missing", &groupID, domain.ShareFilter{}, nil)
+		assert.ErrorIs(t, err, application.ErrShareGroupNotFound)
+	})
+
+	t.Run("Smart Group", func(t *testing.T) {
+		service, _, _, groupRepo := setupShareTest(t)
+		groupID := 5
+		groupRepo.EXPECT().GetByID(ctx, groupID).Return(&domain.Group{ID: groupID, Filter: &domain.SmartFilter{}}, nil)
+
+		_, _, err := service.CreateShareLink(ctx, "Smart", &groupID, domain.ShareFilter{}, nil)
+		assert.ErrorIs(t, err, application.ErrShareSmartGroup)
+	})
+}
+
+func TestRevokeShareLink(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, _ := setupShareTest(t)
+	id := uuid.New()
+	repo.EXPECT().Delete(ctx, id).Return(false, nil)
+
+	assert.ErrorIs(t, service.RevokeShareLink(ctx, id), application.ErrShareNotFound)
+}
+
+func TestOpenShare(t *testing.T) {
+	ctx := context.Background()
+	collectionID := uuid.New()
+	groupID := 2
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		service, repo, _, _ := setupShareTest(t)
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(nil, nil)
+
+		_, err := service.OpenShare(ctx, "revoked")
+		assert.ErrorIs(t, err, application.ErrShareNotFound)
+	})
+
+	t.Run("Group Link", func(t *testing.T) {
+		service, repo, _, groupRepo := setupShareTest(t)
+		link := &domain.ShareLink{ID: uuid.New(), Name: "Spain", GroupID: &groupID, CollectionID: collectionID}
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(link, nil)
+		repo.EXPECT().RecordView(inCollection(collectionID), link.ID).Return(nil)
+		groupRepo.EXPECT().List(inCollection(collectionID)).Return([]*domain.Group{{ID: 1, Name: "Other"}, {ID: groupID, Name: "Spain"}}, nil)
+		groupRepo.EXPECT().ListImages(inCollection(collectionID), groupID).Return(nil, nil)
+
+		view, err := service.OpenShare(ctx, "token")
+		assert.NoError(t, err)
+		assert.Equal(t, "Spain", view.Name)
+		assert.Equal(t, groupID, view.Group.ID)
+	})
+}
+
+func TestListSharedCoins(t *testing.T) {
+	ctx := context.Background()
+	collectionID := uuid.New()
+	service, repo, coinRepo, _ := setupShareTest(t)
+	country := "Spain"
+	link := &domain.ShareLink{Filter: domain.ShareFilter{Country: &country}, CollectionID: collectionID}
+	repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(link, nil)
+	coinRepo.EXPECT().List(inCollection(collectionID), gomock.Any()).DoAndReturn(func(ctx context.Context, filter domain.CoinFilter) ([]*domain.Coin, error) {
+		assert.Equal(t, &country, filter.Country)
+		assert.Equal(t, 10, filter.Limit)
+		return []*domain.Coin{{Name: "Duro", Country: "Spain", PricePaid: 30}}, nil
+	})
+
+	coins, err := service.ListSharedCoins(ctx, "token", 10, 0, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, coins, 1)
+	assert.Equal(t, "Duro", coins[0].Name)
+}
+
+func TestListSharedCoins_CapsLimit(t *testing.T) {
+	ctx := context.Background()
+	service, repo, coinRepo, _ := setupShareTest(t)
+	repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(&domain.ShareLink{CollectionID: uuid.New()}, nil)
+	coinRepo.EXPECT().List(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, filter domain.CoinFilter) ([]*domain.Coin, error) {
+		assert.Equal(t, 100, filter.Limit)
+		return nil, nil
+	})
+
+	_, err := service.ListSharedCoins(ctx, "token", 1000000, 0, nil, nil)
+	assert.NoError(t, err)
+}
+
+func TestGetSharedCoin(t *testing.T) {
+	ctx := context.Background()
+	collectionID := uuid.New()
+	groupID := 2
+	link := &domain.ShareLink{GroupID: &groupID, CollectionID: collectionID}
+
+	t.Run("Coin In Group", func(t *testing.T) {
+		service, repo, coinRepo, _ := setupShareTest(t)
+		id := uuid.New()
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(link, nil)
+		coinRepo.EXPECT().Matches(inCollection(collectionID), link.CoinFilter(0, 0, nil, nil), id).Return(true, nil)
+		coinRepo.EXPECT().GetByID(inCollection(collectionID), id).Return(&domain.Coin{ID: id, GroupID: &groupID}, nil)
+
+		coin, err := service.GetSharedCoin(ctx, "token", id)
+		assert.NoError(t, err)
+		assert.Equal(t, id, coin.ID)
+	})
+
+	t.Run("Coin Not Shared", func(t *testing.T) {
+		service, repo, coinRepo, _ := setupShareTest(t)
+		id := uuid.New()
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(link, nil)
+		coinRepo.EXPECT().Matches(gomock.Any(), gomock.Any(), id).Return(false, nil)
+
+		_, err := service.GetSharedCoin(ctx, "token", id)
+		assert.ErrorIs(t, err, application.ErrNotShared)
+	})
+
+	t.Run("Searches What Visitors See", func(t *testing.T) {
+		service, repo, coinRepo, _ := setupShareTest(t)
+		id := uuid.New()
+		query := "peseta"
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(&domain.ShareLink{Filter: domain.ShareFilter{Query: &query}, CollectionID: collectionID}, nil)
+		coinRepo.EXPECT().Matches(gomock.Any(), gomock.Any(), id).DoAndReturn(func(_ context.Context, f domain.CoinFilter, _ uuid.UUID) (bool, error) {
+			assert.Equal(t, &query, f.Query)
+			assert.True(t, f.PublicSearch)
+			return true, nil
+		})
+		coinRepo.EXPECT().GetByID(gomock.Any(), id).Return(&domain.Coin{ID: id, Name: "Peseta"}, nil)
+
+		coin, err := service.GetSharedCoin(ctx, "token", id)
+		assert.NoError(t, err)
+		assert.Equal(t, "Peseta", coin.Name)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		service, repo, coinRepo, _ := setupShareTest(t)
+		id := uuid.New()
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(link, nil)
+		coinRepo.EXPECT().Matches(gomock.Any(), gomock.Any(), id).Return(false, assert.AnError)
+
+		_, err := service.GetSharedCoin(ctx, "token", id)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestCanReadSharedFile(t *testing.T) {
+	ctx := context.Background()
+	groupID := 2
+	link := &domain.ShareLink{GroupID: &groupID, CollectionID: uuid.New()}
+
+	t.Run("Group File", func(t *testing.T) {
+		service, repo, _, _ := setupShareTest(t)
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(link, nil).Times(2)
+
+		ok, err := service.CanReadSharedFile(ctx, "token", "/groups/2/cover.png")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = service.CanReadSharedFile(ctx, "token", "/groups/2/../3/cover.png")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Hidden Coin", func(t *testing.T) {
+		service, repo, coinRepo, _ := setupShareTest(t)
+		id := uuid.New()
+		repo.EXPECT().GetByTokenHash(ctx, gomock.Any()).Return(link, nil)
+		coinRepo.EXPECT().Matches(gomock.Any(), gomock.Any(), id).Return(false, nil)
+
+		ok, err := service.CanReadSharedFile(ctx, "token", "/coins/"+id.String()+"/front.jpg")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
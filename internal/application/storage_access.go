@@ -0,0 +1,37 @@
+package application
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CanReadFile reports whether a stored file, given by its path below the
+// storage directory, belongs to the collection of the context. Coin files
+// live in coins/<coin id>/ and group files in groups/<group id>/; anything
+// else is refused.
+func (s *CoinService) CanReadFile(ctx context.Context, file string) (bool, error) {
+	parts := strings.Split(strings.TrimPrefix(path.Clean("/"+file), "/"), "/")
+	if len(parts) < 3 {
+		return false, nil
+	}
+
+	switch parts[0] {
+	case "coins":
+		id, err := uuid.Parse(parts[1])
+		if err != nil {
+			return false, nil
+		}
+		return s.repo.Exists(ctx, id)
+	case "groups":
+		id, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return false, nil
+		}
+		return s.groupRepo.Exists(ctx, id)
+	}
+	return false, nil
+}
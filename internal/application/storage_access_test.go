@@ -0,0 +1,61 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanReadFile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Coin File", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		id := uuid.New()
+		mockRepo.EXPECT().Exists(ctx, id).Return(true, nil)
+
+		ok, err := service.CanReadFile(ctx, "/coins/"+id.String()+"/front.jpg")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Coin Of Another Collection", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		id := uuid.New()
+		mockRepo.EXPECT().Exists(ctx, id).Return(false, nil)
+
+		ok, err := service.CanReadFile(ctx, "/coins/"+id.String()+"/front.jpg")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Group File", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().Exists(ctx, 7).Return(true, nil)
+
+		ok, err := service.CanReadFile(ctx, "/groups/7/cover.png")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Path Is Cleaned First", func(t *testing.T) {
+		service, _, mockGroupRepo, _, _, _, _, _, _ := setupTest(t)
+		mockGroupRepo.EXPECT().Exists(ctx, 3).Return(false, nil)
+
+		ok, err := service.CanReadFile(ctx, "/coins/"+uuid.NewString()+"/../../groups/3/cover.png")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Unknown Layout", func(t *testing.T) {
+		service, _, _, _, _, _, _, _, _ := setupTest(t)
+
+		for _, file := range []string{"/backup.sql", "/coins/not-a-uuid/front.jpg", "/groups/x/a.png", "/coins/" + uuid.NewString()} {
+			ok, err := service.CanReadFile(ctx, file)
+			assert.NoError(t, err)
+			assert.False(t, ok, file)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	// ErrTagExists is returned when creating or renaming a tag to the name
+	// of another, ignoring case. Merging them is the way to join the two.
+	ErrTagExists = errors.New("tag already exists")
+)
+
+// ListTags returns the tags of the collection by name, with their coin
+// counts.
+func (s *CoinService) ListTags(ctx context.Context) ([]*domain.Tag, error) {
+	return s.tagRepo.List(ctx)
+}
+
+func (s *CoinService) CreateTag(ctx context.Context, name string) (*domain.Tag, error) {
+	name, err := domain.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.tagRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTagExists, existing.Name)
+	}
+	return s.tagRepo.Create(ctx, name)
+}
+
+// RenameTag renames a tag on every coin that has it. Changing only the case
+// of the name is allowed.
+func (s *CoinService) RenameTag(ctx context.Context, id int, name string) (*domain.Tag, error) {
+	name, err := domain.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findTag(ctx, id); err != nil {
+		return nil, err
+	}
+	existing, err := s.tagRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != id {
+		return nil, fmt.Errorf("%w: %s", ErrTagExists, existing.Name)
+	}
+	return s.tagRepo.Rename(ctx, id, name)
+}
+
+// DeleteTag removes a tag from every coin and deletes it.
+func (s *CoinService) DeleteTag(ctx context.Context, id int) error {
+	if _, err := s.findTag(ctx, id); err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(ctx, id)
+}
+
+// MergeTags gives the coins tagged with source the target tag instead, and
+// deletes source. It returns the target.
+func (s *CoinService) MergeTags(ctx context.Context, sourceID, targetID int) (*domain.Tag, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("%w: cannot merge a tag into itself", domain.ErrInvalidTag)
+	}
+	if _, err := s.findTag(ctx, sourceID); err != nil {
+		return nil, err
+	}
+	target, err := s.findTag(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.Merge(ctx, sourceID, targetID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (s *CoinService) findTag(ctx context.Context, id int) (*domain.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, fmt.Errorf("%w: %d", ErrTagNotFound, id)
+	}
+	return tag, nil
+}
+
+// setCoinTags stores the tags of a coin unless it already has exactly those,
+// and returns them as stored. Names must already be normalized.
+func (s *CoinService) setCoinTags(ctx context.Context, coinID uuid.UUID, current, names []string) ([]string, error) {
+	if sameTags(current, names) {
+		return current, nil
+	}
+	tags, err := s.tagRepo.SetCoinTags(ctx, coinID, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set coin tags: %w", err)
+	}
+	return tags, nil
+}
+
+func sameTags(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -0,0 +1,207 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type tagTestMocks struct {
+	repo      *mocks.MockCoinRepository
+	groupRepo *mocks.MockGroupRepository
+	tagRepo   *mocks.MockTagRepository
+	storage   *mocks.MockStorageService
+}
+
+// setupTagTest builds a service whose tag repository can be mocked, which
+// setupTest does not return.
+func setupTagTest(t *testing.T) (*application.CoinService, tagTestMocks) {
+	ctrl := gomock.NewController(t)
+	m := tagTestMocks{
+		repo:      mocks.NewMockCoinRepository(ctrl),
+		groupRepo: mocks.NewMockGroupRepository(ctrl),
+		tagRepo:   mocks.NewMockTagRepository(ctrl),
+		storage:   mocks.NewMockStorageService(ctrl),
+	}
+	service := application.NewCoinService(m.repo, m.groupRepo, m.tagRepo, nil, nil, nil, nil, m.storage, nil, nil, nil, nil)
+	return service, m
+}
+
+func TestCreateTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByName(ctx, "Euro").Return(nil, nil)
+		m.tagRepo.EXPECT().Create(ctx, "Euro").Return(&domain.Tag{ID: 1, Name: "Euro"}, nil)
+
+		tag, err := service.CreateTag(ctx, "  Euro ")
+		assert.NoError(t, err)
+		assert.Equal(t, "Euro", tag.Name)
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByName(ctx, "EURO").Return(&domain.Tag{ID: 1, Name: "euro"}, nil)
+
+		_, err := service.CreateTag(ctx, "EURO")
+		assert.ErrorIs(t, err, application.ErrTagExists)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		service, _ := setupTagTest(t)
+		_, err := service.CreateTag(ctx, "   ")
+		assert.ErrorIs(t, err, domain.ErrInvalidTag)
+	})
+}
+
+func TestRenameTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Tag{ID: 1, Name: "to sel"}, nil)
+		m.tagRepo.EXPECT().GetByName(ctx, "to sell").Return(nil, nil)
+		m.tagRepo.EXPECT().Rename(ctx, 1, "to sell").Return(&domain.Tag{ID: 1, Name: "to sell"}, nil)
+
+		tag, err := service.RenameTag(ctx, 1, "to sell")
+		assert.NoError(t, err)
+		assert.Equal(t, "to sell", tag.Name)
+	})
+
+	t.Run("Case Change", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Tag{ID: 1, Name: "euro"}, nil)
+		m.tagRepo.EXPECT().GetByName(ctx, "Euro").Return(&domain.Tag{ID: 1, Name: "euro"}, nil)
+		m.tagRepo.EXPECT().Rename(ctx, 1, "Euro").Return(&domain.Tag{ID: 1, Name: "Euro"}, nil)
+
+		_, err := service.RenameTag(ctx, 1, "Euro")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Name Taken", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Tag{ID: 1, Name: "eur"}, nil)
+		m.tagRepo.EXPECT().GetByName(ctx, "euro").Return(&domain.Tag{ID: 2, Name: "euro"}, nil)
+
+		_, err := service.RenameTag(ctx, 1, "euro")
+		assert.ErrorIs(t, err, application.ErrTagExists)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 9).Return(nil, nil)
+
+		_, err := service.RenameTag(ctx, 9, "euro")
+		assert.ErrorIs(t, err, application.ErrTagNotFound)
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Tag{ID: 1, Name: "euro"}, nil)
+		m.tagRepo.EXPECT().Delete(ctx, 1).Return(nil)
+		assert.NoError(t, service.DeleteTag(ctx, 1))
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 1).Return(nil, nil)
+		assert.ErrorIs(t, service.DeleteTag(ctx, 1), application.ErrTagNotFound)
+	})
+}
+
+func TestMergeTags(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Tag{ID: 1, Name: "euros"}, nil)
+		m.tagRepo.EXPECT().GetByID(ctx, 2).Return(&domain.Tag{ID: 2, Name: "euro"}, nil)
+		m.tagRepo.EXPECT().Merge(ctx, 1, 2).Return(nil)
+
+		tag, err := service.MergeTags(ctx, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "euro", tag.Name)
+	})
+
+	t.Run("Into Itself", func(t *testing.T) {
+		service, _ := setupTagTest(t)
+		_, err := service.MergeTags(ctx, 1, 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidTag)
+	})
+
+	t.Run("Target Not Found", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.tagRepo.EXPECT().GetByID(ctx, 1).Return(&domain.Tag{ID: 1, Name: "euros"}, nil)
+		m.tagRepo.EXPECT().GetByID(ctx, 2).Return(nil, nil)
+
+		_, err := service.MergeTags(ctx, 1, 2)
+		assert.ErrorIs(t, err, application.ErrTagNotFound)
+	})
+}
+
+func TestUpdateCoin_Tags(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	current := func() *domain.Coin {
+		return &domain.Coin{ID: id, Name: "2 Euro", Tags: []string{"euro"}}
+	}
+
+	t.Run("Sets Tags", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.repo.EXPECT().GetByID(ctx, id).Return(current(), nil)
+		m.repo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		m.tagRepo.EXPECT().SetCoinTags(ctx, id, []string{"euro", "To sell"}).Return([]string{"euro", "To sell"}, nil)
+		m.repo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
+
+		coin, err := service.UpdateCoin(ctx, id, application.UpdateCoinParams{
+			Name: "2 Euro",
+			Tags: []string{"euro", " To sell", "to sell", ""},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"euro", "To sell"}, coin.Tags)
+	})
+
+	t.Run("Unchanged Tags Are Not Written", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.repo.EXPECT().GetByID(ctx, id).Return(current(), nil)
+		m.repo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		m.repo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
+
+		coin, err := service.UpdateCoin(ctx, id, application.UpdateCoinParams{Name: "2 Euros", Tags: []string{"euro"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"euro"}, coin.Tags)
+	})
+
+	t.Run("Left Out Tags Are Kept", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.repo.EXPECT().GetByID(ctx, id).Return(current(), nil)
+		m.repo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		m.repo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
+
+		coin, err := service.UpdateCoin(ctx, id, application.UpdateCoinParams{Name: "2 Euros"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"euro"}, coin.Tags)
+	})
+
+	t.Run("Invalid Tag", func(t *testing.T) {
+		service, m := setupTagTest(t)
+		m.repo.EXPECT().GetByID(ctx, id).Return(current(), nil)
+
+		_, err := service.UpdateCoin(ctx, id, application.UpdateCoinParams{
+			Name: "2 Euro",
+			Tags: []string{"a tag name that goes well beyond the fifty characters allowed"},
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidTag)
+	})
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ErrCoinNotInTrash is returned when restoring or purging a coin that was
+// never deleted.
+var ErrCoinNotInTrash = errors.New("coin is not in the trash")
+
+// DeleteCoin moves a coin to the trash. It disappears from listings and
+// stats, but its row and files are kept until it is purged. Unknown coins,
+// coins of other collections and coins already in the trash give
+// ErrCoinNotFound.
+func (s *CoinService) DeleteCoin(ctx context.Context, id uuid.UUID) error {
+	at := time.Now()
+	ok, err := s.repo.Trash(ctx, id, at)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCoinNotFound
+	}
+	return s.recordAudit(ctx, domain.AuditEntityCoin, id.String(), domain.AuditOpTrash, []domain.FieldChange{deletedAtChange(nil, &at)})
+}
+
+// ListTrash returns the coins in the trash, most recently deleted first.
+func (s *CoinService) ListTrash(ctx context.Context) ([]*domain.Coin, error) {
+	return s.repo.ListTrash(ctx)
+}
+
+// RestoreCoin takes a coin out of the trash.
+func (s *CoinService) RestoreCoin(ctx context.Context, id uuid.UUID) (*domain.Coin, error) {
+	coin, err := s.trashedCoin(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RestoreFromTrash(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.recordAudit(ctx, domain.AuditEntityCoin, id.String(), domain.AuditOpRestore, []domain.FieldChange{deletedAtChange(coin.DeletedAt, nil)}); err != nil {
+		return nil, err
+	}
+
+	coin.DeletedAt = nil
+	return coin, nil
+}
+
+// PurgeCoin deletes a trashed coin for good, files included.
+func (s *CoinService) PurgeCoin(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.trashedCoin(ctx, id); err != nil {
+		return err
+	}
+	return s.purgeCoin(ctx, id)
+}
+
+// EmptyTrash purges every coin in the trash and returns how many were
+// deleted.
+func (s *CoinService) EmptyTrash(ctx context.Context) (int, error) {
+	coins, err := s.repo.ListTrash(ctx)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]uuid.UUID, len(coins))
+	for i, c := range coins {
+		ids[i] = c.ID
+	}
+	return s.purgeCoins(ctx, ids)
+}
+
+// PurgeExpiredTrash purges the coins that have been in the trash for longer
+// than retention, in every collection.
+func (s *CoinService) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int, error) {
+	expired, err := s.repo.ListTrashedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for collectionID, ids := range expired {
+		n, err := s.purgeCoins(domain.WithCollection(ctx, collectionID), ids)
+		purged += n
+		if err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// StartTrashPurger purges expired trash every interval until ctx is done.
+func (s *CoinService) StartTrashPurger(ctx context.Context, retention, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			n, err := s.PurgeExpiredTrash(ctx, retention)
+			if err != nil {
+				slog.Error("Failed to purge expired trash", "error", err)
+			} else if n > 0 {
+				slog.Info("Purged expired trash", "coins", n)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *CoinService) trashedCoin(ctx context.Context, id uuid.UUID) (*domain.Coin, error) {
+	coin, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get coin: %w", err)
+	}
+	if coin.DeletedAt == nil {
+		return nil, ErrCoinNotInTrash
+	}
+	return coin, nil
+}
+
+func (s *CoinService) purgeCoins(ctx context.Context, ids []uuid.UUID) (int, error) {
+	purged := 0
+	for _, id := range ids {
+		if err := s.purgeCoin(ctx, id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *CoinService) purgeCoin(ctx context.Context, id uuid.UUID) error {
+	// Delete from database first
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// Then delete files from storage
+	if err := s.storage.DeleteCoinDirectory(id); err != nil {
+		// Log error but don't fail the deletion
+		// The coin is already deleted from DB
+		slog.Error("failed to delete coin files", "coin_id", id, "error", err)
+	}
+
+	return nil
+}
+
+func deletedAtChange(before, after *time.Time) domain.FieldChange {
+	b, _ := json.Marshal(before)
+	a, _ := json.Marshal(after)
+	return domain.FieldChange{Field: "deleted_at", Before: b, After: a}
+}
@@ -0,0 +1,154 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteCoin(t *testing.T) {
+	id := uuid.New()
+	t.Run("Moves To Trash", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		// Files stay on disk until the coin is purged
+		mockRepo.EXPECT().Trash(ctx, id, gomock.Any()).Return(true, nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.AuditOpTrash, e.Operation)
+			assert.Equal(t, "deleted_at", e.Changes[0].Field)
+			assert.JSONEq(t, "null", string(e.Changes[0].Before))
+			return nil
+		})
+		err := service.DeleteCoin(ctx, id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockRepo.EXPECT().Trash(ctx, id, gomock.Any()).Return(false, assert.AnError)
+		err := service.DeleteCoin(ctx, id)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown Coin", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		// No audit entry is expected
+		mockRepo.EXPECT().Trash(ctx, id, gomock.Any()).Return(false, nil)
+		err := service.DeleteCoin(ctx, id)
+		assert.ErrorIs(t, err, application.ErrCoinNotFound)
+	})
+}
+
+func TestRestoreCoin(t *testing.T) {
+	id := uuid.New()
+	deletedAt := time.Now().Add(-time.Hour)
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id, Name: "Duro", DeletedAt: &deletedAt}, nil)
+		mockRepo.EXPECT().RestoreFromTrash(ctx, id).Return(nil)
+		mockRepo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, e *domain.AuditEntry) error {
+			assert.Equal(t, domain.AuditOpRestore, e.Operation)
+			assert.JSONEq(t, "null", string(e.Changes[0].After))
+			return nil
+		})
+
+		coin, err := service.RestoreCoin(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, "Duro", coin.Name)
+		assert.Nil(t, coin.DeletedAt)
+	})
+
+	t.Run("Not In Trash", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id}, nil)
+
+		_, err := service.RestoreCoin(ctx, id)
+		assert.ErrorIs(t, err, application.ErrCoinNotInTrash)
+	})
+}
+
+func TestPurgeCoin(t *testing.T) {
+	id := uuid.New()
+	deletedAt := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockRepo, _, _, _, mockStorage, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id, DeletedAt: &deletedAt}, nil)
+		mockRepo.EXPECT().Delete(ctx, id).Return(nil)
+		mockStorage.EXPECT().DeleteCoinDirectory(id).Return(nil)
+
+		err := service.PurgeCoin(ctx, id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("File Error Is Ignored", func(t *testing.T) {
+		service, mockRepo, _, _, _, mockStorage, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id, DeletedAt: &deletedAt}, nil)
+		mockRepo.EXPECT().Delete(ctx, id).Return(nil)
+		mockStorage.EXPECT().DeleteCoinDirectory(id).Return(assert.AnError)
+
+		err := service.PurgeCoin(ctx, id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Not In Trash", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _, _ := setupTest(t)
+		ctx := context.Background()
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&domain.Coin{ID: id}, nil)
+
+		err := service.PurgeCoin(ctx, id)
+		assert.ErrorIs(t, err, application.ErrCoinNotInTrash)
+	})
+}
+
+func TestEmptyTrash(t *testing.T) {
+	service, mockRepo, _, _, _, mockStorage, _, _, _ := setupTest(t)
+	ctx := context.Background()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	mockRepo.EXPECT().ListTrash(ctx).Return([]*domain.Coin{{ID: ids[0]}, {ID: ids[1]}}, nil)
+	for _, id := range ids {
+		mockRepo.EXPECT().Delete(ctx, id).Return(nil)
+		mockStorage.EXPECT().DeleteCoinDirectory(id).Return(nil)
+	}
+
+	n, err := service.EmptyTrash(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestPurgeExpiredTrash(t *testing.T) {
+	service, mockRepo, _, _, _, mockStorage, _, _, _ := setupTest(t)
+	ctx := context.Background()
+	id := uuid.New()
+	collectionID := uuid.New()
+	retention := 30 * 24 * time.Hour
+	mockRepo.EXPECT().ListTrashedBefore(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, cutoff time.Time) (map[uuid.UUID][]uuid.UUID, error) {
+		assert.WithinDuration(t, time.Now().Add(-retention), cutoff, time.Minute)
+		return map[uuid.UUID][]uuid.UUID{collectionID: {id}}, nil
+	})
+	// Each coin is purged within its own collection
+	mockRepo.EXPECT().Delete(gomock.Any(), id).DoAndReturn(func(ctx context.Context, _ uuid.UUID) error {
+		got, ok := domain.CollectionFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, collectionID, got)
+		return nil
+	})
+	mockStorage.EXPECT().DeleteCoinDirectory(id).Return(nil)
+
+	n, err := service.PurgeExpiredTrash(ctx, retention)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+var (
+	ErrWantNotFound = errors.New("wishlist entry not found")
+	// ErrWantExists is returned when adding a Numista type that already has
+	// an open wishlist entry.
+	ErrWantExists = errors.New("wishlist entry already exists")
+)
+
+// WantParams are the wishlist entry fields set by the user. NumistaID is only
+// read when creating an entry.
+type WantParams struct {
+	NumistaID int     `json:"numista_id"`
+	Grade     string  `json:"grade"`
+	MaxPrice  float64 `json:"max_price"`
+	Priority  int     `json:"priority"`
+	Notes     string  `json:"notes"`
+}
+
+// ListWishlist returns the wishlist entries with the status, most wanted
+// first.
+func (s *CoinService) ListWishlist(ctx context.Context, status domain.WishlistStatus) ([]*domain.WishlistEntry, error) {
+	switch status {
+	case domain.WishlistOpen, domain.WishlistFulfilled, domain.WishlistAll:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrInvalidWant, status)
+	}
+	return s.wishlistRepo.List(ctx, status)
+}
+
+func (s *CoinService) GetWant(ctx context.Context, id int) (*domain.WishlistEntry, error) {
+	return s.findWant(ctx, id)
+}
+
+// CreateWant adds a Numista type to the wishlist, copying its title, issuer
+// and thumbnails from Numista. The priority defaults to
+// domain.DefaultWantPriority.
+func (s *CoinService) CreateWant(ctx context.Context, params WantParams) (*domain.WishlistEntry, error) {
+	if params.Priority == 0 {
+		params.Priority = domain.DefaultWantPriority
+	}
+	entry := &domain.WishlistEntry{NumistaID: params.NumistaID}
+	applyWantParams(entry, params)
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.wishlistRepo.GetOpen(ctx, params.NumistaID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: numista type %d", ErrWantExists, params.NumistaID)
+	}
+
+	details, err := s.numistaClient.GetType(ctx, params.NumistaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get numista details: %w", err)
+	}
+	applyNumistaType(entry, details)
+
+	if err := s.wishlistRepo.Create(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateWant changes the grade, maximum price, priority and notes of an
+// entry. A zero priority keeps the current one.
+func (s *CoinService) UpdateWant(ctx context.Context, id int, params WantParams) (*domain.WishlistEntry, error) {
+	entry, err := s.findWant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if params.Priority == 0 {
+		params.Priority = entry.Priority
+	}
+	applyWantParams(entry, params)
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.wishlistRepo.Update(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *CoinService) DeleteWant(ctx context.Context, id int) error {
+	if _, err := s.findWant(ctx, id); err != nil {
+		return err
+	}
+	return s.wishlistRepo.Delete(ctx, id)
+}
+
+func (s *CoinService) findWant(ctx context.Context, id int) (*domain.WishlistEntry, error) {
+	entry, err := s.wishlistRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %d", ErrWantNotFound, id)
+	}
+	return entry, nil
+}
+
+func applyWantParams(entry *domain.WishlistEntry, params WantParams) {
+	entry.Grade, _ = domain.NewGrade(normalizeGrade(params.Grade))
+	entry.MaxPrice = params.MaxPrice
+	entry.Priority = params.Priority
+	entry.Notes = params.Notes
+}
+
+// applyNumistaType copies the title, issuer and thumbnails of a Numista type,
+// as returned by NumistaService.GetType.
+func applyNumistaType(entry *domain.WishlistEntry, details map[string]any) {
+	if v, ok := details["title"].(string); ok {
+		entry.Title = v
+	}
+	if issuer, ok := details["issuer"].(map[string]any); ok {
+		if name, ok := issuer["name"].(string); ok {
+			entry.Issuer = name
+		}
+	}
+	if side, ok := details["obverse"].(map[string]any); ok {
+		if v, ok := side["thumbnail"].(string); ok {
+			entry.ObverseThumbnail = v
+		}
+	}
+	if side, ok := details["reverse"].(map[string]any); ok {
+		if v, ok := side["thumbnail"].(string); ok {
+			entry.ReverseThumbnail = v
+		}
+	}
+}
+
+// fulfilWants marks the open wishlist entries for the coin's Numista type as
+// fulfilled by it. The coin is already saved, so errors are only logged.
+func (s *CoinService) fulfilWants(ctx context.Context, coin *domain.Coin) {
+	if s.wishlistRepo == nil || coin.NumistaNumber == 0 {
+		return
+	}
+	entries, err := s.wishlistRepo.Fulfil(ctx, coin.NumistaNumber, coin.ID)
+	if err != nil {
+		slog.Warn("Failed to fulfil wishlist entries", "coin_id", coin.ID, "numista_id", coin.NumistaNumber, "error", err)
+		return
+	}
+	for _, e := range entries {
+		slog.Info("Wishlist entry fulfilled", "wishlist_id", e.ID, "coin_id", coin.ID, "numista_id", e.NumistaID)
+	}
+}
@@ -0,0 +1,186 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/application"
+	"github.com/antonioparicio/numismaticapp/internal/application/mocks"
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type wishlistTestMocks struct {
+	repo         *mocks.MockCoinRepository
+	wishlistRepo *mocks.MockWishlistRepository
+	numista      *mocks.MockNumistaService
+}
+
+// setupWishlistTest builds a service whose wishlist repository can be mocked,
+// which setupTest does not return.
+func setupWishlistTest(t *testing.T) (*application.CoinService, wishlistTestMocks) {
+	ctrl := gomock.NewController(t)
+	m := wishlistTestMocks{
+		repo:         mocks.NewMockCoinRepository(ctrl),
+		wishlistRepo: mocks.NewMockWishlistRepository(ctrl),
+		numista:      mocks.NewMockNumistaService(ctrl),
+	}
+	service := application.NewCoinService(m.repo, nil, nil, m.wishlistRepo, nil, nil, nil, nil, nil, m.numista, nil, nil)
+	return service, m
+}
+
+func TestCreateWant(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetOpen(ctx, 95420).Return(nil, nil)
+		m.numista.EXPECT().GetType(ctx, 95420).Return(map[string]any{
+			"title":   "2 Euro (Cervantes)",
+			"issuer":  map[string]any{"name": "Spain"},
+			"obverse": map[string]any{"thumbnail": "https://en.numista.com/obverse.jpg"},
+			"reverse": map[string]any{"thumbnail": "https://en.numista.com/reverse.jpg"},
+		}, nil)
+		m.wishlistRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e *domain.WishlistEntry) error {
+			e.ID = 1
+			return nil
+		})
+
+		entry, err := service.CreateWant(ctx, application.WantParams{NumistaID: 95420, Grade: "sc", MaxPrice: 4.5})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, entry.ID)
+		assert.Equal(t, "2 Euro (Cervantes)", entry.Title)
+		assert.Equal(t, "Spain", entry.Issuer)
+		assert.Equal(t, "https://en.numista.com/obverse.jpg", entry.ObverseThumbnail)
+		assert.Equal(t, "https://en.numista.com/reverse.jpg", entry.ReverseThumbnail)
+		assert.Equal(t, "SC", entry.Grade.String())
+		assert.Equal(t, domain.DefaultWantPriority, entry.Priority)
+	})
+
+	t.Run("Already Wanted", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetOpen(ctx, 95420).Return(&domain.WishlistEntry{ID: 1, NumistaID: 95420}, nil)
+
+		_, err := service.CreateWant(ctx, application.WantParams{NumistaID: 95420})
+		assert.ErrorIs(t, err, application.ErrWantExists)
+	})
+
+	t.Run("Invalid Priority", func(t *testing.T) {
+		service, _ := setupWishlistTest(t)
+		_, err := service.CreateWant(ctx, application.WantParams{NumistaID: 95420, Priority: 6})
+		assert.ErrorIs(t, err, domain.ErrInvalidWant)
+	})
+
+	t.Run("Missing Type", func(t *testing.T) {
+		service, _ := setupWishlistTest(t)
+		_, err := service.CreateWant(ctx, application.WantParams{})
+		assert.ErrorIs(t, err, domain.ErrInvalidWant)
+	})
+
+	t.Run("Numista Error", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetOpen(ctx, 95420).Return(nil, nil)
+		m.numista.EXPECT().GetType(ctx, 95420).Return(nil, assert.AnError)
+
+		_, err := service.CreateWant(ctx, application.WantParams{NumistaID: 95420})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestUpdateWant(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Keeps The Priority", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.WishlistEntry{ID: 1, NumistaID: 95420, Priority: 1}, nil)
+		m.wishlistRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+
+		entry, err := service.UpdateWant(ctx, 1, application.WantParams{Notes: "only with box"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, entry.Priority)
+		assert.Equal(t, "only with box", entry.Notes)
+	})
+
+	t.Run("Negative Price", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.WishlistEntry{ID: 1, NumistaID: 95420, Priority: 3}, nil)
+
+		_, err := service.UpdateWant(ctx, 1, application.WantParams{MaxPrice: -1})
+		assert.ErrorIs(t, err, domain.ErrInvalidWant)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetByID(ctx, 9).Return(nil, nil)
+
+		_, err := service.UpdateWant(ctx, 9, application.WantParams{})
+		assert.ErrorIs(t, err, application.ErrWantNotFound)
+	})
+}
+
+func TestDeleteWant(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetByID(ctx, 1).Return(&domain.WishlistEntry{ID: 1}, nil)
+		m.wishlistRepo.EXPECT().Delete(ctx, 1).Return(nil)
+		assert.NoError(t, service.DeleteWant(ctx, 1))
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().GetByID(ctx, 1).Return(nil, nil)
+		assert.ErrorIs(t, service.DeleteWant(ctx, 1), application.ErrWantNotFound)
+	})
+}
+
+func TestListWishlist(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Open", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.wishlistRepo.EXPECT().List(ctx, domain.WishlistOpen).Return([]*domain.WishlistEntry{{ID: 1}}, nil)
+
+		entries, err := service.ListWishlist(ctx, domain.WishlistOpen)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Unknown Status", func(t *testing.T) {
+		service, _ := setupWishlistTest(t)
+		_, err := service.ListWishlist(ctx, "wanted")
+		assert.ErrorIs(t, err, domain.ErrInvalidWant)
+	})
+}
+
+func TestApplyNumistaCandidate_FulfilsWants(t *testing.T) {
+	ctx := context.Background()
+	coinID := uuid.New()
+
+	t.Run("Fulfils The Type", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.repo.EXPECT().GetByID(ctx, coinID).Return(&domain.Coin{ID: coinID}, nil)
+		m.numista.EXPECT().GetType(ctx, 95420).Return(map[string]any{"title": "2 Euro (Cervantes)"}, nil)
+		m.repo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		m.repo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
+		m.wishlistRepo.EXPECT().Fulfil(ctx, 95420, coinID).Return([]*domain.WishlistEntry{{ID: 1, NumistaID: 95420}}, nil)
+
+		_, err := service.ApplyNumistaCandidate(ctx, coinID, 95420)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fulfil Error Is Not Fatal", func(t *testing.T) {
+		service, m := setupWishlistTest(t)
+		m.repo.EXPECT().GetByID(ctx, coinID).Return(&domain.Coin{ID: coinID}, nil)
+		m.numista.EXPECT().GetType(ctx, 95420).Return(map[string]any{}, nil)
+		m.repo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		m.repo.EXPECT().AppendAuditEntry(ctx, gomock.Any()).Return(nil)
+		m.wishlistRepo.EXPECT().Fulfil(ctx, 95420, coinID).Return(nil, assert.AnError)
+
+		_, err := service.ApplyNumistaCandidate(ctx, coinID, 95420)
+		assert.NoError(t, err)
+	})
+}
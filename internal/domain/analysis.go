@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KnownGrades are the conservation grades the app understands: the Spanish
+// scale used across the UI and its English equivalents. A trailing "+" or
+// "-" (MBC+, EBC--) is accepted on any of them.
+var KnownGrades = []string{
+	"PROOF", "FDC", "SC", "EBC", "MBC", "BC", "RC", "MC",
+	"UNC", "AU", "XF", "VF", "F", "VG", "G", "AG",
+}
+
+// IsKnownGrade reports whether g is one of KnownGrades, ignoring case and
+// +/- modifiers.
+func IsKnownGrade(g string) bool {
+	base := strings.ToUpper(strings.TrimRight(strings.TrimSpace(g), "+-"))
+	for _, known := range KnownGrades {
+		if base == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Plausibility bounds for physical measurements read by the AI. The heaviest
+// and widest coins in most collections stay well inside them.
+const (
+	MaxPlausibleWeightG     = 1000.0
+	MinPlausibleDiameterMM  = 4.0
+	MaxPlausibleDiameterMM  = 150.0
+	MaxPlausibleThicknessMM = 30.0
+)
+
+// AnalysisFieldError is a field of an AI analysis that failed validation.
+type AnalysisFieldError struct {
+	Field   string `json:"field"` // JSON name, as the model sees it
+	Message string `json:"message"`
+}
+
+func (e AnalysisFieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// AnalysisFailedError is returned when none of the answers of the model could
+// be used. Details holds what is still worth keeping in GeminiDetails, such as
+// the rejected attempts.
+type AnalysisFailedError struct {
+	Err     error
+	Details map[string]any
+}
+
+func (e *AnalysisFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AnalysisFailedError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the fields of an analysis that the rest of the app relies
+// on. Zero values mean "unknown" and are accepted, except for the name.
+func (r *CoinAnalysisResult) Validate() []AnalysisFieldError {
+	var errs []AnalysisFieldError
+	add := func(field, format string, args ...any) {
+		errs = append(errs, AnalysisFieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(r.Name) == "" {
+		add("name", "is required")
+	}
+	if _, err := NewYear(r.Year); err != nil || r.Year > time.Now().Year()+1 {
+		add("year", "%d is not a plausible year; use 0 if unknown", r.Year)
+	}
+	if r.Mintage < 0 {
+		add("mintage", "cannot be negative; use 0 if unknown")
+	}
+	if r.Grade != "" && !IsKnownGrade(r.Grade) {
+		add("grade", "%q is not a known grade; use one of %s", r.Grade, strings.Join(KnownGrades, ", "))
+	}
+	if r.WeightG < 0 || r.WeightG > MaxPlausibleWeightG {
+		add("weight_g", "%g g is not a plausible weight; use 0 if unknown", r.WeightG)
+	}
+	if r.DiameterMM != 0 && (r.DiameterMM < MinPlausibleDiameterMM || r.DiameterMM > MaxPlausibleDiameterMM) {
+		add("diameter_mm", "%g mm is not a plausible diameter; use 0 if unknown", r.DiameterMM)
+	}
+	if r.ThicknessMM < 0 || r.ThicknessMM > MaxPlausibleThicknessMM {
+		add("thickness_mm", "%g mm is not a plausible thickness; use 0 if unknown", r.ThicknessMM)
+	}
+	if r.MinValue < 0 {
+		add("min_value", "cannot be negative")
+	}
+	if r.MaxValue < 0 {
+		add("max_value", "cannot be negative")
+	}
+	if r.MinValue > 0 && r.MaxValue > 0 && r.MinValue > r.MaxValue {
+		add("max_value", "must not be lower than min_value")
+	}
+	for field, c := range r.Confidence {
+		if c < 0 || c > 1 {
+			add("confidence", "%s is %g, confidences go from 0 to 1", field, c)
+			break
+		}
+	}
+	return errs
+}
+
+// ClearInvalid resets the fields named in errs to "unknown", so a result
+// that never passed validation can still be stored.
+func (r *CoinAnalysisResult) ClearInvalid(errs []AnalysisFieldError) {
+	for _, e := range errs {
+		switch e.Field {
+		case "year":
+			r.Year = 0
+		case "mintage":
+			r.Mintage = 0
+		case "grade":
+			r.Grade = ""
+		case "weight_g":
+			r.WeightG = 0
+		case "diameter_mm":
+			r.DiameterMM = 0
+		case "thickness_mm":
+			r.ThicknessMM = 0
+		case "min_value", "max_value":
+			r.MinValue, r.MaxValue = 0, 0
+		case "confidence":
+			r.Confidence = nil
+		}
+	}
+}
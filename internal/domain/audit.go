@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Audited entities.
+const (
+	AuditEntityCoin  = "coin"
+	AuditEntityGroup = "group"
+)
+
+// Audited operations.
+const (
+	AuditOpUpdate       = "update"
+	AuditOpReanalyze    = "reanalyze"
+	AuditOpApplyNumista = "apply_numista"
+	AuditOpMarkSold     = "mark_sold"
+	AuditOpRotateImage  = "rotate_image"
+	AuditOpRevert       = "revert"
+	AuditOpTrash        = "trash"
+	AuditOpRestore      = "restore"
+	AuditOpMove         = "move"
+)
+
+// DefaultActor is recorded when the context carries no actor.
+const DefaultActor = "user"
+
+// FieldChange is the value of one field before and after an operation. Values
+// are kept as JSON so any field can be stored and restored as is.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditEntry is one revision in the history of a coin or group. Revisions
+// are numbered from 1 per entity; revision 0 is the state before the first
+// recorded change.
+type AuditEntry struct {
+	ID         int64         `json:"id"`
+	EntityType string        `json:"entity_type"`
+	EntityID   string        `json:"entity_id"`
+	Revision   int           `json:"revision"`
+	Actor      string        `json:"actor"`
+	Operation  string        `json:"operation"`
+	Changes    []FieldChange `json:"changes"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose changes are recorded as made by actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, or DefaultActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
+
+// Coin fields left out of the history: identity, bookkeeping and images,
+// whose files are tracked through rotations instead.
+var coinAuditSkip = map[string]bool{
+	"id":             true,
+	"images":         true,
+	"gallery_images": true,
+	"status":         true,
+	"created_at":     true,
+	"updated_at":     true,
+	"deleted_at":     true,
+}
+
+// RotationField is the pseudo-field recording the rotation of a coin image
+// side; its After value is the angle in degrees.
+func RotationField(side string) string {
+	return side + "_image_rotation"
+}
+
+// DiffCoins lists the fields that differ between two versions of a coin.
+func DiffCoins(before, after *Coin) ([]FieldChange, error) {
+	return diffJSON(before, after, coinAuditSkip)
+}
+
+// DiffGroups lists the editable fields that differ between two versions of
+// a group.
+func DiffGroups(before, after *Group) ([]FieldChange, error) {
+	changes, err := diffJSON(before, after, nil)
+	if err != nil {
+		return nil, err
+	}
+	var editable []FieldChange
+	for _, c := range changes {
+		if c.Field == "name" || c.Field == "description" || c.Field == "filter" || c.Field == "parent_id" {
+			editable = append(editable, c)
+		}
+	}
+	return editable, nil
+}
+
+// RestoreCoinFields returns a copy of the coin with the given fields set to
+// the JSON values. Unknown fields are ignored.
+func RestoreCoinFields(coin *Coin, values map[string]json.RawMessage) (*Coin, error) {
+	fields, err := toJSONFields(coin)
+	if err != nil {
+		return nil, err
+	}
+	for field, v := range values {
+		if _, ok := fields[field]; ok && !coinAuditSkip[field] {
+			fields[field] = v
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var restored Coin
+	if err := json.Unmarshal(data, &restored); err != nil {
+		return nil, fmt.Errorf("invalid value in history: %w", err)
+	}
+	return &restored, nil
+}
+
+func diffJSON(before, after any, skip map[string]bool) ([]FieldChange, error) {
+	old, err := toJSONFields(before)
+	if err != nil {
+		return nil, err
+	}
+	now, err := toJSONFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fields left out with omitempty on one side changed to or from null
+	names := make([]string, 0, len(now))
+	for name := range now {
+		names = append(names, name)
+	}
+	for name := range old {
+		if _, ok := now[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		if skip[name] || bytes.Equal(old[name], now[name]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Before: orNull(old[name]), After: orNull(now[name])})
+	}
+	return changes, nil
+}
+
+func orNull(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+	return v
+}
+
+func toJSONFields(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Lengths of checklist names and slot mint marks, in characters.
+const (
+	MaxChecklistNameLength = 100
+	MaxMintMarkLength      = 20
+)
+
+// ErrInvalidChecklist is returned for checklists without a name and for
+// slots without a Numista type.
+var ErrInvalidChecklist = errors.New("invalid checklist")
+
+// Checklist is a set of coins to complete, such as every year of a type.
+// Owned coins fill the slots they match; see MatchChecklist.
+type Checklist struct {
+	ID          int                `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Slots       []ChecklistSlot    `json:"slots"`
+	Progress    *ChecklistProgress `json:"progress,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// ChecklistSlot is one expected coin: a Numista type, a year (0 for any
+// year) and a mint mark (empty for any mint), matched against the coin's
+// MintMark rather than its mint name.
+type ChecklistSlot struct {
+	ID        int    `json:"id"`
+	NumistaID int    `json:"numista_id"`
+	Year      int    `json:"year"`
+	MintMark  string `json:"mint_mark"`
+	Label     string `json:"label"`
+	// CoinIDs are the owned coins filling the slot, set by MatchChecklist
+	CoinIDs []uuid.UUID `json:"coin_ids,omitempty"`
+}
+
+// ChecklistProgress sums up how far a checklist is from complete.
+type ChecklistProgress struct {
+	Slots      int             `json:"slots"`
+	Filled     int             `json:"filled"`
+	Completion float64         `json:"completion"` // Percentage of slots filled, one decimal
+	Missing    []ChecklistSlot `json:"missing"`
+	Duplicates []ChecklistSlot `json:"duplicates"` // Slots filled by more than one coin
+}
+
+// Validate checks the name and the slots, trimming them.
+func (c *Checklist) Validate() error {
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidChecklist)
+	}
+	if utf8.RuneCountInString(c.Name) > MaxChecklistNameLength {
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidChecklist, MaxChecklistNameLength)
+	}
+	return ValidateChecklistSlots(c.Slots)
+}
+
+// ValidateChecklistSlots checks and trims the slots.
+func ValidateChecklistSlots(slots []ChecklistSlot) error {
+	for i := range slots {
+		s := &slots[i]
+		s.MintMark = strings.TrimSpace(s.MintMark)
+		if s.NumistaID <= 0 {
+			return fmt.Errorf("%w: slot %d has no numista_id", ErrInvalidChecklist, i+1)
+		}
+		if s.Year < 0 {
+			return fmt.Errorf("%w: slot %d has a negative year", ErrInvalidChecklist, i+1)
+		}
+		if utf8.RuneCountInString(s.MintMark) > MaxMintMarkLength {
+			return fmt.Errorf("%w: mint mark %q is longer than %d characters", ErrInvalidChecklist, s.MintMark, MaxMintMarkLength)
+		}
+	}
+	return nil
+}
+
+// matches reports how well the coin fits the slot: -1 if it does not, else
+// the number of the slot's year and mint mark it had to match.
+func (s ChecklistSlot) matches(coin *Coin) int {
+	if coin.NumistaNumber != s.NumistaID {
+		return -1
+	}
+	score := 0
+	if s.Year != 0 {
+		if coin.Year.Int() != s.Year {
+			return -1
+		}
+		score++
+	}
+	if s.MintMark != "" {
+		if !strings.EqualFold(strings.TrimSpace(coin.MintMark), s.MintMark) {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// MatchChecklist fills the slots of the checklist with the coins, by Numista
+// type, year and mint mark (ignoring case), and sets its Progress. Sold coins
+// are not owned and fill nothing. A coin fills only its most specific
+// matching slot, the first one on a tie, so it is never counted twice.
+func MatchChecklist(c *Checklist, coins []*Coin) {
+	for i := range c.Slots {
+		c.Slots[i].CoinIDs = nil
+	}
+	for _, coin := range coins {
+		if coin.SoldAt != nil {
+			continue
+		}
+		best, bestScore := -1, -1
+		for i, slot := range c.Slots {
+			if score := slot.matches(coin); score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best >= 0 {
+			c.Slots[best].CoinIDs = append(c.Slots[best].CoinIDs, coin.ID)
+		}
+	}
+
+	p := &ChecklistProgress{
+		Slots:      len(c.Slots),
+		Missing:    []ChecklistSlot{},
+		Duplicates: []ChecklistSlot{},
+	}
+	for _, slot := range c.Slots {
+		switch {
+		case len(slot.CoinIDs) == 0:
+			p.Missing = append(p.Missing, slot)
+		case len(slot.CoinIDs) > 1:
+			p.Duplicates = append(p.Duplicates, slot)
+		}
+	}
+	p.Filled = p.Slots - len(p.Missing)
+	if p.Slots > 0 {
+		p.Completion = math.Round(float64(p.Filled)*1000/float64(p.Slots)) / 10
+	}
+	c.Progress = p
+}
+
+// NumistaTypes returns the Numista types of the slots, each once.
+func (c *Checklist) NumistaTypes() []int {
+	var types []int
+	seen := make(map[int]bool)
+	for _, s := range c.Slots {
+		if !seen[s.NumistaID] {
+			seen[s.NumistaID] = true
+			types = append(types, s.NumistaID)
+		}
+	}
+	return types
+}
+
+// ChecklistRepository persists checklists and their slots, within the
+// collection of the context like CoinRepository. Checklists are read along
+// with their slots.
+type ChecklistRepository interface {
+	// Create stores the checklist with its slots and sets their IDs and
+	// the timestamps.
+	Create(ctx context.Context, c *Checklist) error
+	// GetByID returns nil when the checklist is not in the collection.
+	GetByID(ctx context.Context, id int) (*Checklist, error)
+	// List returns the checklists sorted by name.
+	List(ctx context.Context) ([]*Checklist, error)
+	// Update saves the name and description.
+	Update(ctx context.Context, c *Checklist) error
+	Delete(ctx context.Context, id int) error
+	// AddSlots adds the slots the checklist does not have yet, comparing
+	// mint marks ignoring case, and returns the ones added.
+	AddSlots(ctx context.Context, checklistID int, slots []ChecklistSlot) ([]ChecklistSlot, error)
+	DeleteSlot(ctx context.Context, checklistID, slotID int) error
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchChecklist(t *testing.T) {
+	coin := func(numista, year int, mintMark string) *Coin {
+		y, _ := NewYear(year)
+		return &Coin{ID: uuid.New(), NumistaNumber: numista, Year: y, Mint: "Madrid", MintMark: mintMark}
+	}
+
+	t.Run("Year And Mint Mark", func(t *testing.T) {
+		c := &Checklist{Slots: []ChecklistSlot{
+			{ID: 1, NumistaID: 100, Year: 1975, MintMark: "M"},
+			{ID: 2, NumistaID: 100, Year: 1976, MintMark: "M"},
+			{ID: 3, NumistaID: 100, Year: 1976, MintMark: "B"},
+		}}
+		a, b := coin(100, 1975, " m "), coin(100, 1976, "M")
+		MatchChecklist(c, []*Coin{a, b, coin(100, 1977, "M"), coin(200, 1975, "M")})
+
+		assert.Equal(t, []uuid.UUID{a.ID}, c.Slots[0].CoinIDs)
+		assert.Equal(t, []uuid.UUID{b.ID}, c.Slots[1].CoinIDs)
+		assert.Equal(t, 3, c.Progress.Slots)
+		assert.Equal(t, 2, c.Progress.Filled)
+		assert.Equal(t, 66.7, c.Progress.Completion)
+		assert.Len(t, c.Progress.Missing, 1)
+		assert.Equal(t, 3, c.Progress.Missing[0].ID)
+		assert.Empty(t, c.Progress.Duplicates)
+	})
+
+	t.Run("Mint Name Is Not A Mint Mark", func(t *testing.T) {
+		c := &Checklist{Slots: []ChecklistSlot{{ID: 1, NumistaID: 100, Year: 1975, MintMark: "M"}}}
+		a := coin(100, 1975, "")
+		MatchChecklist(c, []*Coin{a})
+		assert.Zero(t, c.Progress.Filled)
+
+		a.MintMark = "M"
+		MatchChecklist(c, []*Coin{a})
+		assert.Equal(t, []uuid.UUID{a.ID}, c.Slots[0].CoinIDs)
+	})
+
+	t.Run("Most Specific Slot", func(t *testing.T) {
+		c := &Checklist{Slots: []ChecklistSlot{
+			{ID: 1, NumistaID: 100},
+			{ID: 2, NumistaID: 100, Year: 1975},
+		}}
+		a, b := coin(100, 1975, ""), coin(100, 1980, "")
+		MatchChecklist(c, []*Coin{a, b})
+
+		assert.Equal(t, []uuid.UUID{b.ID}, c.Slots[0].CoinIDs)
+		assert.Equal(t, []uuid.UUID{a.ID}, c.Slots[1].CoinIDs)
+		assert.Equal(t, 100.0, c.Progress.Completion)
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		c := &Checklist{Slots: []ChecklistSlot{{ID: 1, NumistaID: 100, Year: 1975}}}
+		MatchChecklist(c, []*Coin{coin(100, 1975, ""), coin(100, 1975, "M")})
+
+		assert.Len(t, c.Progress.Duplicates, 1)
+		assert.Len(t, c.Progress.Duplicates[0].CoinIDs, 2)
+		assert.Equal(t, 1, c.Progress.Filled)
+	})
+
+	t.Run("Sold Coins Are Not Owned", func(t *testing.T) {
+		c := &Checklist{Slots: []ChecklistSlot{{ID: 1, NumistaID: 100}}}
+		sold := coin(100, 1975, "")
+		now := time.Now()
+		sold.SoldAt = &now
+		MatchChecklist(c, []*Coin{sold})
+
+		assert.Zero(t, c.Progress.Filled)
+		assert.Len(t, c.Progress.Missing, 1)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		c := &Checklist{}
+		MatchChecklist(c, nil)
+		assert.Zero(t, c.Progress.Completion)
+		assert.NotNil(t, c.Progress.Missing)
+	})
+}
+
+func TestChecklistValidate(t *testing.T) {
+	t.Run("Trims", func(t *testing.T) {
+		c := &Checklist{Name: " Pesetas ", Slots: []ChecklistSlot{{NumistaID: 100, MintMark: " M "}}}
+		assert.NoError(t, c.Validate())
+		assert.Equal(t, "Pesetas", c.Name)
+		assert.Equal(t, "M", c.Slots[0].MintMark)
+	})
+
+	t.Run("No Name", func(t *testing.T) {
+		assert.ErrorIs(t, (&Checklist{Name: "  "}).Validate(), ErrInvalidChecklist)
+	})
+
+	t.Run("Slot Without Type", func(t *testing.T) {
+		c := &Checklist{Name: "Pesetas", Slots: []ChecklistSlot{{Year: 1975}}}
+		assert.ErrorIs(t, c.Validate(), ErrInvalidChecklist)
+	})
+}
@@ -11,7 +11,8 @@ import (
 type Coin struct {
 	ID             uuid.UUID      `json:"id"`
 	Name           string         `json:"name"`
-	Mint           string         `json:"mint"`
+	Mint           string         `json:"mint"`      // Mint name, e.g. Madrid
+	MintMark       string         `json:"mint_mark"` // Letter or symbol of the mint struck on the coin, e.g. M
 	Mintage        Mintage        `json:"mintage"`
 	Country        string         `json:"country"`
 	Year           Year           `json:"year"`
@@ -38,6 +39,7 @@ type Coin struct {
 	Images            []CoinImage        `json:"images"`
 	GalleryImages     []CoinGalleryImage `json:"gallery_images"`
 	GroupID           *int               `json:"group_id"`
+	Tags              []string           `json:"tags"` // Names, sorted
 	PersonalNotes     string             `json:"personal_notes"`
 	WeightG           float64            `json:"weight_g"`
 	DiameterMM        float64            `json:"diameter_mm"`
@@ -49,8 +51,19 @@ type Coin struct {
 	PricePaid         float64            `json:"price_paid"`
 	SoldPrice         float64            `json:"sold_price"`
 	SaleChannel       string             `json:"sale_channel"`
+	Status            string             `json:"status"` // pending, ready, failed
+	Provenance        Provenance         `json:"provenance"`
 	CreatedAt         time.Time          `json:"created_at"`
 	UpdatedAt         time.Time          `json:"updated_at"`
+	DeletedAt         *time.Time         `json:"deleted_at"`       // Set while the coin is in the trash
+	Search            *SearchMatch       `json:"search,omitempty"` // Set by List when the filter has a Query
+}
+
+// SearchMatch tells how well a coin matched a text search. Snippet is HTML:
+// excerpts of the coin's text, escaped, with the matched words in <mark>.
+type SearchMatch struct {
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
 }
 
 type Group struct {
@@ -65,6 +78,46 @@ type Group struct {
 	MinValue    float64      `json:"min_value"` // Total Value Range
 	MaxValue    float64      `json:"max_value"` // Total Value Range
 	Images      []GroupImage `json:"images"`
+	// ParentID is the group this one sits under, nil at the top level. The
+	// counts and values above include the coins of its subgroups.
+	ParentID *int `json:"parent_id"`
+	// Filter is set for smart groups, whose coins are the ones matching it
+	// rather than the ones assigned to the group
+	Filter *SmartFilter `json:"filter,omitempty"`
+}
+
+// IsSmart reports whether the group is defined by a saved filter. Coins
+// cannot be assigned to smart groups.
+func (g *Group) IsSmart() bool {
+	return g.Filter != nil
+}
+
+// SmartFilter is the saved filter of a smart group. Membership is evaluated
+// whenever the group is used, so it always reflects the collection as it is.
+// Empty fields do not filter.
+type SmartFilter struct {
+	Country  *string  `json:"country,omitempty"`
+	MinYear  *int     `json:"min_year,omitempty"`
+	MaxYear  *int     `json:"max_year,omitempty"`
+	Material *string  `json:"material,omitempty"`
+	Grade    *string  `json:"grade,omitempty"`
+	MinPrice *float64 `json:"min_price,omitempty"`
+	MaxPrice *float64 `json:"max_price,omitempty"`
+	Query    *string  `json:"query,omitempty"`
+}
+
+// CoinFilter returns the coin list filter that selects the group's coins.
+func (f SmartFilter) CoinFilter() CoinFilter {
+	return CoinFilter{
+		Country:  f.Country,
+		MinYear:  f.MinYear,
+		MaxYear:  f.MaxYear,
+		Material: f.Material,
+		Grade:    f.Grade,
+		MinPrice: f.MinPrice,
+		MaxPrice: f.MaxPrice,
+		Query:    f.Query,
+	}
 }
 
 type GroupImage struct {
@@ -108,11 +161,17 @@ type CoinStats struct {
 
 // CoinFilter defines available filters for listing coins.
 type CoinFilter struct {
-	Limit     int
-	Offset    int
-	GroupID   *int
-	Year      *int
-	Country   *string
+	Limit   int
+	Offset  int
+	GroupID *int
+	// IncludeSubgroups makes GroupID match the coins of its subgroups too,
+	// at any depth.
+	IncludeSubgroups bool
+	Year             *int
+	Country          *string
+	// Query is a full-text search over the coin's text, accents ignored and
+	// each word matched as a prefix, or a part of the KM code. Results are
+	// sorted by relevance unless SortBy is set.
 	Query     *string
 	MinPrice  *float64
 	MaxPrice  *float64
@@ -122,13 +181,43 @@ type CoinFilter struct {
 	MaxYear   *int
 	SortBy    *string
 	SortOrder *string
+	// Cursor continues a listing from the NextCursor or PrevCursor of a
+	// CoinPage, in place of Offset. It only fits the sort it was made with.
+	Cursor string
+	// Smart narrows the list to a smart group, on top of the other fields.
+	// The service sets it in place of a GroupID naming a smart group.
+	Smart *SmartFilter
+	// Tag names, compared ignoring case: coins with any of TagsAny, all of
+	// TagsAll and none of TagsNone.
+	TagsAny  []string
+	TagsAll  []string
+	TagsNone []string
+	// NumistaNumbers keeps the coins of any of these Numista types.
+	NumistaNumbers []int
+	// PublicSearch makes Query search only the text a share link shows,
+	// leaving the notes out.
+	PublicSearch bool
+	// IncludeTrashed counts the coins in the trash as well. It is only set
+	// by the service, never from a request.
+	IncludeTrashed bool
 }
 
-// CoinRepository defines the interface for persisting coins.
+// CoinRepository defines the interface for persisting coins. Every method
+// works within the collection of the context (see WithCollection).
 type CoinRepository interface {
 	Save(ctx context.Context, coin *Coin) error
 	GetByID(ctx context.Context, id uuid.UUID) (*Coin, error)
+	// Exists reports whether the coin is in the collection, trashed or not.
+	Exists(ctx context.Context, id uuid.UUID) (bool, error)
 	List(ctx context.Context, filter CoinFilter) ([]*Coin, error)
+	// ListPage is List with the cursors of the pages around it. It returns
+	// ErrInvalidCursor for cursors it cannot read or of another sort.
+	ListPage(ctx context.Context, filter CoinFilter) (*CoinPage, error)
+	// CountMatching and Facets ignore the limit and offset of the filter
+	CountMatching(ctx context.Context, filter CoinFilter) (int64, error)
+	// Matches reports whether the coin is one List selects with the filter.
+	Matches(ctx context.Context, filter CoinFilter, id uuid.UUID) (bool, error)
+	Facets(ctx context.Context, filter CoinFilter) (*CoinFacets, error)
 	Count(ctx context.Context) (int64, error)
 	GetTotalValue(ctx context.Context) (float64, error)
 	GetAverageValue(ctx context.Context) (float64, error)
@@ -138,12 +227,17 @@ type CoinRepository interface {
 	GetGradeDistribution(ctx context.Context) (map[string]int, error)
 	GetAllValues(ctx context.Context) ([]float64, error)
 	Update(ctx context.Context, coin *Coin) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetCountryDistribution(ctx context.Context) (map[string]int, error)
 	GetOldestCoin(ctx context.Context) (*Coin, error)
 	GetRarestCoins(ctx context.Context, limit int) ([]*Coin, error)
 	GetGroupDistribution(ctx context.Context) (map[string]int, error)
+	GetTagDistribution(ctx context.Context) (map[string]int, error)
 	GetGroupStats(ctx context.Context) ([]GroupStat, error)
+	// GetFilterStats computes the stats of GetGroupStats for the coins
+	// matching a filter, as for smart groups
+	GetFilterStats(ctx context.Context, filter CoinFilter) (*GroupStat, error)
 	GetTotalWeightByMaterial(ctx context.Context, materialLike string) (float64, error)
 	GetHeaviestCoin(ctx context.Context) (*Coin, error)
 	GetSmallestCoin(ctx context.Context) (*Coin, error)
@@ -168,6 +262,21 @@ type CoinRepository interface {
 	ListGalleryImages(ctx context.Context, coinID uuid.UUID) ([]CoinGalleryImage, error)
 	// Stats
 	GetCoinStats(ctx context.Context, id uuid.UUID) (*CoinStats, error)
+	// Numista enrichment tracking
+	SaveNumistaEnrichment(ctx context.Context, e *NumistaEnrichment) error
+	GetNumistaEnrichment(ctx context.Context, coinID uuid.UUID) (*NumistaEnrichment, error)
+	ListNumistaEnrichments(ctx context.Context, status string) ([]*NumistaEnrichment, error)
+	// Trash
+	// Trash reports whether a live coin was moved to the trash
+	Trash(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	RestoreFromTrash(ctx context.Context, id uuid.UUID) error
+	ListTrash(ctx context.Context) ([]*Coin, error)
+	// ListTrashedBefore looks at every collection and returns the IDs of the
+	// coins trashed before cutoff, grouped by collection.
+	ListTrashedBefore(ctx context.Context, cutoff time.Time) (map[uuid.UUID][]uuid.UUID, error)
+	// Audit log
+	AppendAuditEntry(ctx context.Context, e *AuditEntry) error
+	ListAuditEntries(ctx context.Context, entityType, entityID string) ([]*AuditEntry, error)
 }
 
 // CoinLink represents an external link associated with a coin.
@@ -182,12 +291,24 @@ type CoinLink struct {
 	CreatedAt     time.Time `json:"created_at"`
 }
 
-// GroupRepository defines the interface for persisting groups.
+// GroupRepository defines the interface for persisting groups, within the
+// collection of the context like CoinRepository.
 type GroupRepository interface {
 	Create(ctx context.Context, name, description string) (*Group, error)
+	CreateSmart(ctx context.Context, name, description string, filter SmartFilter) (*Group, error)
+	// GetByID returns nil when the group is not in the collection.
+	GetByID(ctx context.Context, id int) (*Group, error)
 	GetByName(ctx context.Context, name string) (*Group, error)
+	// Exists reports whether the group is in the collection.
+	Exists(ctx context.Context, id int) (bool, error)
 	List(ctx context.Context) ([]*Group, error)
+	// Update saves the name, description and filter; Move changes the
+	// parent.
 	Update(ctx context.Context, group *Group) error
+	// Move puts a group under parentID, or at the top level when nil.
+	// Callers check for cycles.
+	Move(ctx context.Context, id int, parentID *int) error
+	// Delete moves the subgroups of the group up to its parent.
 	Delete(ctx context.Context, id int) error
 	// Images
 	AddImage(ctx context.Context, img GroupImage) error
@@ -213,42 +334,45 @@ type ImageService interface {
 type GeminiModelInfo struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Provider    string `json:"provider,omitempty"` // AI backend serving the model
 }
 
 // AIService defines the interface for AI analysis.
 type AIService interface {
 	// AnalyzeCoin analyzes the front and back images of a coin and returns metadata.
 	AnalyzeCoin(ctx context.Context, frontImagePath, backImagePath, modelName string, temperature float32, lang string) (*CoinAnalysisResult, error)
-	// ListModels returns a list of available Gemini models.
+	// ListModels returns a list of available models.
 	ListModels(ctx context.Context) ([]GeminiModelInfo, error)
 }
 
 // CoinAnalysisResult contains the data extracted by the AI.
 type CoinAnalysisResult struct {
-	Country                      string         `json:"country"`
-	Year                         int            `json:"year"`
-	FaceValue                    string         `json:"face_value"`
-	Currency                     string         `json:"currency"`
-	Material                     string         `json:"material"`
-	Description                  string         `json:"description"`
-	KMCode                       string         `json:"km_code"`
-	NumistaNumber                int            `json:"numista_number"`
-	MinValue                     float64        `json:"min_value"`
-	MaxValue                     float64        `json:"max_value"`
-	Grade                        string         `json:"grade"`
-	Name                         string         `json:"name"`
-	Notes                        string         `json:"notes"`
-	VerticalCorrectionAngleFront float64        `json:"vertical_correction_angle_front"`
-	VerticalCorrectionAngleBack  float64        `json:"vertical_correction_angle_back"`
-	WeightG                      float64        `json:"weight_g"`
-	DiameterMM                   float64        `json:"diameter_mm"`
-	ThicknessMM                  float64        `json:"thickness_mm"`
-	Edge                         string         `json:"edge"`
-	Shape                        string         `json:"shape"`
-	Mint                         string         `json:"mint"`
-	Mintage                      int64          `json:"mintage"`
-	ReferenceSourceName          string         `json:"reference_source_name"`
-	RawDetails                   map[string]any `json:"raw_details"`
+	Country                      string             `json:"country"`
+	Year                         int                `json:"year"`
+	FaceValue                    string             `json:"face_value"`
+	Currency                     string             `json:"currency"`
+	Material                     string             `json:"material"`
+	Description                  string             `json:"description"`
+	KMCode                       string             `json:"km_code"`
+	NumistaNumber                int                `json:"numista_number"`
+	MinValue                     float64            `json:"min_value"`
+	MaxValue                     float64            `json:"max_value"`
+	Grade                        string             `json:"grade"`
+	Name                         string             `json:"name"`
+	Notes                        string             `json:"notes"`
+	VerticalCorrectionAngleFront float64            `json:"vertical_correction_angle_front"`
+	VerticalCorrectionAngleBack  float64            `json:"vertical_correction_angle_back"`
+	WeightG                      float64            `json:"weight_g"`
+	DiameterMM                   float64            `json:"diameter_mm"`
+	ThicknessMM                  float64            `json:"thickness_mm"`
+	Edge                         string             `json:"edge"`
+	Shape                        string             `json:"shape"`
+	Mint                         string             `json:"mint"`
+	MintMark                     string             `json:"mint_mark"`
+	Mintage                      int64              `json:"mintage"`
+	ReferenceSourceName          string             `json:"reference_source_name"`
+	Confidence                   map[string]float64 `json:"confidence"` // 0-1 per field, as judged by the model
+	RawDetails                   map[string]any     `json:"raw_details"`
 }
 
 type PriceClient interface {
@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+// ErrInvalidCursor is returned for page cursors that cannot be read or were
+// made for another sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CoinPage is a page of a coin listing. The cursors are opaque; pass one as
+// CoinFilter.Cursor to get the page after or before this one. They are empty
+// at either end of the listing.
+//
+// Pages are cut on the sort value and the coin ID rather than counted, so
+// coins added or removed while scrolling neither repeat nor go missing.
+type CoinPage struct {
+	Coins      []*Coin
+	NextCursor string
+	PrevCursor string
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Collection holds the coins, groups, images and links of one person or
+// household. Every user belongs to one collection and only sees its data.
+type Collection struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DefaultCollectionID is the collection created by the migration that
+// introduced collections. Data and accounts from before belong to it, and the
+// configured admin joins it.
+var DefaultCollectionID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// ErrNoCollection is returned by repositories asked to touch collection data
+// with a context that does not name a collection.
+var ErrNoCollection = errors.New("no collection in context")
+
+type collectionKey struct{}
+
+// WithCollection returns a context whose reads and writes are limited to the
+// collection.
+func WithCollection(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, collectionKey{}, id)
+}
+
+// CollectionFromContext returns the collection set by WithCollection.
+func CollectionFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(collectionKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
@@ -17,6 +17,7 @@ type DashboardStats struct {
 	OldestHighGradeCoin  *Coin          `json:"oldest_high_grade_coin"`
 	RarestCoins          []Coin         `json:"rarest_coins"`
 	GroupDistribution    map[string]int `json:"group_distribution"`
+	TagDistribution      map[string]int `json:"tag_distribution"`
 	TotalSilverWeight    float64        `json:"total_silver_weight"`
 	TotalGoldWeight      float64        `json:"total_gold_weight"`
 	TotalSilverValue     float64        `json:"total_silver_value"`
@@ -31,10 +32,12 @@ type DashboardStats struct {
 type GroupStat struct {
 	GroupID   int     `json:"group_id"`
 	GroupName string  `json:"group_name"`
-	Count     int64   `json:"count"`
+	ParentID  *int    `json:"parent_id,omitempty"`
+	Count     int64   `json:"count"` // Coins of the group and its subgroups
 	MinVal    float64 `json:"min_value"`
 	MaxVal    float64 `json:"max_value"`
 	AvgVal    float64 `json:"avg_value"`
 	MinYear   int     `json:"min_year"`
 	MaxYear   int     `json:"max_year"`
+	Smart     bool    `json:"smart,omitempty"` // Coins matching a smart group's filter
 }
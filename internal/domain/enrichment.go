@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Numista enrichment states.
+const (
+	EnrichmentStatusQueued    = "queued"
+	EnrichmentStatusRunning   = "running"
+	EnrichmentStatusRetrying  = "retrying"
+	EnrichmentStatusSucceeded = "succeeded"
+	EnrichmentStatusFailed    = "failed"
+)
+
+// NumistaEnrichment tracks the background Numista lookup of a coin.
+// Failed lookups are retried with exponential backoff until they run out of
+// attempts, then stay "failed" until retried by hand.
+type NumistaEnrichment struct {
+	CoinID      uuid.UUID  `json:"coin_id"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error"`
+	NextRetryAt *time.Time `json:"next_retry_at"`
+	JobID       *uuid.UUID `json:"job_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Filled only when listing, to identify the coin
+	CoinName string `json:"coin_name,omitempty"`
+	Country  string `json:"country,omitempty"`
+	Year     int    `json:"year,omitempty"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// CoinFacets counts the coins matching a filter by attribute, for the
+// filter controls of the list. Every facet is counted with its own filter
+// left out, so it shows what choosing another value would give.
+type CoinFacets struct {
+	Countries []FacetBucket     `json:"countries"`
+	Materials []FacetBucket     `json:"materials"`
+	Grades    []FacetBucket     `json:"grades"`
+	Groups    []FacetBucket     `json:"groups"` // Value is the group ID, Label its name
+	Years     []HistogramBucket `json:"years"`
+	Values    []HistogramBucket `json:"values"` // By max_value
+}
+
+// FacetBucket is one value of a facet, most frequent first. Coins without a
+// value are not counted.
+type FacetBucket struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// HistogramBucket counts the values in [From, To). The last bucket of a
+// histogram of decimals includes To, its largest value.
+type HistogramBucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int64   `json:"count"`
+}
+
+// HistogramBuckets is how many buckets NewHistogram makes at most.
+const HistogramBuckets = 20
+
+// NewHistogram spreads counts of values over at most HistogramBuckets
+// buckets of equal width between the smallest and largest value. With
+// integer set, bucket bounds are whole numbers, as for years. Empty buckets
+// are kept so a range slider can draw them.
+func NewHistogram(counts map[float64]int64, integer bool) []HistogramBucket {
+	if len(counts) == 0 {
+		return []HistogramBucket{}
+	}
+	values := make([]float64, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Float64s(values)
+	lo, hi := values[0], values[len(values)-1]
+
+	n := HistogramBuckets
+	width := (hi - lo) / float64(n)
+	if integer {
+		width = math.Ceil((hi - lo + 1) / float64(n))
+		n = int(math.Ceil((hi - lo + 1) / width))
+	} else if width == 0 {
+		n, width = 1, 0
+	}
+
+	buckets := make([]HistogramBucket, n)
+	for i := range buckets {
+		buckets[i].From = lo + float64(i)*width
+		buckets[i].To = lo + float64(i+1)*width
+	}
+	if !integer {
+		buckets[n-1].To = hi
+	}
+	for _, v := range values {
+		i := n - 1
+		if width > 0 {
+			i = min(int((v-lo)/width), n-1)
+		}
+		buckets[i].Count += counts[v]
+	}
+	return buckets
+}
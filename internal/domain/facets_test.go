@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHistogram(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, NewHistogram(nil, true))
+	})
+
+	t.Run("Years", func(t *testing.T) {
+		h := NewHistogram(map[float64]int64{1870: 2, 1875: 1, 1999: 4}, true)
+		// 130 years in buckets of 7
+		assert.Len(t, h, 19)
+		assert.Equal(t, HistogramBucket{From: 1870, To: 1877, Count: 3}, h[0])
+		assert.Equal(t, HistogramBucket{From: 1996, To: 2003, Count: 4}, h[18])
+		assert.Zero(t, h[9].Count)
+	})
+
+	t.Run("Few Years", func(t *testing.T) {
+		h := NewHistogram(map[float64]int64{1949: 1, 1951: 2}, true)
+		assert.Equal(t, []HistogramBucket{{1949, 1950, 1}, {1950, 1951, 0}, {1951, 1952, 2}}, h)
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		h := NewHistogram(map[float64]int64{0: 5, 10: 1, 100: 2}, false)
+		assert.Len(t, h, HistogramBuckets)
+		assert.Equal(t, HistogramBucket{From: 0, To: 5, Count: 5}, h[0])
+		assert.Equal(t, int64(1), h[2].Count)
+		assert.Equal(t, HistogramBucket{From: 95, To: 100, Count: 2}, h[19])
+	})
+
+	t.Run("Single Value", func(t *testing.T) {
+		h := NewHistogram(map[float64]int64{12.5: 3}, false)
+		assert.Equal(t, []HistogramBucket{{12.5, 12.5, 3}}, h)
+	})
+}
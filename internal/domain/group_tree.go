@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+)
+
+// ErrGroupCycle is returned when moving a group under itself or one of its
+// subgroups.
+var ErrGroupCycle = errors.New("a group cannot be moved under itself or one of its subgroups")
+
+// InSubtree reports whether the group id is rootID or sits below it, at any
+// depth, following the ParentID of groups.
+func InSubtree(groups []*Group, id, rootID int) bool {
+	parents := make(map[int]*int, len(groups))
+	for _, g := range groups {
+		parents[g.ID] = g.ParentID
+	}
+	seen := make(map[int]bool)
+	for cur := &id; cur != nil && !seen[*cur]; cur = parents[*cur] {
+		if *cur == rootID {
+			return true
+		}
+		seen[*cur] = true
+	}
+	return false
+}
+
+// ParentsFirst returns the groups ordered so that each comes after its
+// parent, keeping the order of groups at the same depth.
+func ParentsFirst(groups []*Group) []*Group {
+	parents := make(map[int]*int, len(groups))
+	for _, g := range groups {
+		parents[g.ID] = g.ParentID
+	}
+	depths := make(map[int]int, len(groups))
+	for _, g := range groups {
+		seen := map[int]bool{g.ID: true}
+		for p := g.ParentID; p != nil && !seen[*p]; p = parents[*p] {
+			seen[*p] = true
+			depths[g.ID]++
+		}
+	}
+	sorted := slices.Clone(groups)
+	slices.SortStableFunc(sorted, func(a, b *Group) int {
+		return cmp.Compare(depths[a.ID], depths[b.ID])
+	})
+	return sorted
+}
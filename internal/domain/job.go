@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Coin processing states. A coin is "pending" while its background job is
+// still running and becomes "ready" (or "failed") once the job finishes.
+const (
+	CoinStatusPending = "pending"
+	CoinStatusReady   = "ready"
+	CoinStatusFailed  = "failed"
+)
+
+// Job and step states.
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Job is a durable unit of background work stored in the database. It runs
+// in the collection it was enqueued from.
+type Job struct {
+	ID           uuid.UUID       `json:"id"`
+	Kind         string          `json:"kind"`
+	CoinID       *uuid.UUID      `json:"coin_id"`
+	CollectionID uuid.UUID       `json:"-"`
+	Status       string          `json:"status"`
+	Payload      json.RawMessage `json:"payload"`
+	Attempts     int             `json:"attempts"`
+	MaxAttempts  int             `json:"max_attempts"`
+	LastError    string          `json:"last_error"`
+	RunAt        time.Time       `json:"run_at"`
+	StartedAt    *time.Time      `json:"started_at"`
+	FinishedAt   *time.Time      `json:"finished_at"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	Steps        []JobStep       `json:"steps"`
+}
+
+// JobStep tracks the state of a single step inside a job. The output of a
+// succeeded step is persisted so a retried job can skip it.
+type JobStep struct {
+	Name       string          `json:"name"`
+	Status     string          `json:"status"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	Output     json.RawMessage `json:"output,omitempty"`
+	StartedAt  *time.Time      `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at"`
+}
+
+// JobRepository defines the interface for the persistent job queue.
+type JobRepository interface {
+	Enqueue(ctx context.Context, job *Job) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Job, error)
+	// ClaimNext locks and returns the next runnable job, or nil if there is none.
+	ClaimNext(ctx context.Context) (*Job, error)
+	Complete(ctx context.Context, id uuid.UUID) error
+	Retry(ctx context.Context, id uuid.UUID, lastError string, runAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, lastError string) error
+	// RequeueRunning puts jobs interrupted by a shutdown back in the queue.
+	RequeueRunning(ctx context.Context) (int64, error)
+	// Steps
+	StartStep(ctx context.Context, jobID uuid.UUID, name string) error
+	CompleteStep(ctx context.Context, jobID uuid.UUID, name string, output json.RawMessage) error
+	FailStep(ctx context.Context, jobID uuid.UUID, name, lastError string) error
+	ListSteps(ctx context.Context, jobID uuid.UUID) ([]JobStep, error)
+}
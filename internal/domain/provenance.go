@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"reflect"
+	"time"
+)
+
+// Sources a coin field can be filled from.
+const (
+	ProvenanceAI      = "ai"
+	ProvenanceNumista = "numista"
+	ProvenanceUser    = "user"
+)
+
+// FieldProvenance records who last set a field and when.
+type FieldProvenance struct {
+	Source     string    `json:"source"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Confidence *float64  `json:"confidence,omitempty"` // 0-1, only for AI values
+}
+
+// Provenance maps a coin field, by its JSON name, to where its value came
+// from. Fields without a value ("unknown") have no entry.
+type Provenance map[string]FieldProvenance
+
+// ProvenanceFields are the descriptive fields whose origin is tracked.
+// Personal data (prices, notes, dates) is always the user's and is left out.
+var ProvenanceFields = []string{
+	"name", "country", "year", "face_value", "currency", "material",
+	"description", "km_code", "numista_number", "mint", "mint_mark", "mintage",
+	"ruler", "orientation", "series", "commemorated_topic",
+	"min_value", "max_value", "grade", "technical_notes",
+	"weight_g", "diameter_mm", "thickness_mm", "edge", "shape",
+}
+
+// fieldValues returns the tracked fields of the coin keyed by JSON name.
+func (c *Coin) fieldValues() map[string]any {
+	return map[string]any{
+		"name":               c.Name,
+		"country":            c.Country,
+		"year":               c.Year.Int(),
+		"face_value":         c.FaceValue,
+		"currency":           c.Currency,
+		"material":           c.Material,
+		"description":        c.Description,
+		"km_code":            c.KMCode.String(),
+		"numista_number":     c.NumistaNumber,
+		"mint":               c.Mint,
+		"mint_mark":          c.MintMark,
+		"mintage":            c.Mintage.Int64(),
+		"ruler":              c.Ruler,
+		"orientation":        c.Orientation,
+		"series":             c.Series,
+		"commemorated_topic": c.CommemoratedTopic,
+		"min_value":          c.MinValue,
+		"max_value":          c.MaxValue,
+		"grade":              c.Grade.String(),
+		"technical_notes":    c.TechnicalNotes,
+		"weight_g":           c.WeightG,
+		"diameter_mm":        c.DiameterMM,
+		"thickness_mm":       c.ThicknessMM,
+		"edge":               c.Edge,
+		"shape":              c.Shape,
+	}
+}
+
+// SetProvenance records source as the origin of the given fields. Fields that
+// are empty after the change lose their entry. confidence is optional and
+// only read for AI values.
+func (c *Coin) SetProvenance(source string, at time.Time, confidence map[string]float64, fields ...string) {
+	if len(fields) == 0 {
+		return
+	}
+	if c.Provenance == nil {
+		c.Provenance = Provenance{}
+	}
+	values := c.fieldValues()
+	for _, f := range fields {
+		v, tracked := values[f]
+		if !tracked {
+			continue
+		}
+		if reflect.ValueOf(v).IsZero() {
+			delete(c.Provenance, f)
+			continue
+		}
+		p := FieldProvenance{Source: source, UpdatedAt: at}
+		if conf, ok := confidence[f]; ok {
+			p.Confidence = &conf
+		}
+		c.Provenance[f] = p
+	}
+}
+
+// ChangedFields lists the tracked fields whose value differs from before.
+func (c *Coin) ChangedFields(before *Coin) []string {
+	now, old := c.fieldValues(), before.fieldValues()
+	var changed []string
+	for _, f := range ProvenanceFields {
+		if now[f] != old[f] {
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
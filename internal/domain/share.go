@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareFilter narrows a share link to part of the collection. Empty fields
+// do not filter.
+type ShareFilter struct {
+	Country  *string `json:"country,omitempty"`
+	Year     *int    `json:"year,omitempty"`
+	MinYear  *int    `json:"min_year,omitempty"`
+	MaxYear  *int    `json:"max_year,omitempty"`
+	Grade    *string `json:"grade,omitempty"`
+	Material *string `json:"material,omitempty"`
+	Query    *string `json:"query,omitempty"`
+}
+
+// ShareLink gives people without an account a read-only view of a group, or
+// of the coins of a collection that match its filter. Like API tokens, only a
+// hash of its token is stored.
+type ShareLink struct {
+	ID           uuid.UUID   `json:"id"`
+	Name         string      `json:"name"`
+	GroupID      *int        `json:"group_id"`
+	Filter       ShareFilter `json:"filter"`
+	ViewCount    int64       `json:"view_count"`
+	CreatedAt    time.Time   `json:"created_at"`
+	LastViewedAt *time.Time  `json:"last_viewed_at"`
+	ExpiresAt    *time.Time  `json:"expires_at"`
+	CollectionID uuid.UUID   `json:"-"`
+}
+
+// CoinFilter lists the coins the link shows.
+func (l *ShareLink) CoinFilter(limit, offset int, sortBy, sortOrder *string) CoinFilter {
+	return CoinFilter{
+		Limit:     limit,
+		Offset:    offset,
+		GroupID:   l.GroupID,
+		Year:      l.Filter.Year,
+		Country:   l.Filter.Country,
+		Query:     l.Filter.Query,
+		Grade:     l.Filter.Grade,
+		Material:  l.Filter.Material,
+		MinYear:   l.Filter.MinYear,
+		MaxYear:   l.Filter.MaxYear,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+		// Visitors never see the notes, so they cannot search them
+		PublicSearch: true,
+	}
+}
+
+// PublicCoin is what a share link shows of a coin: its description and
+// images, without purchase, sale or personal details.
+type PublicCoin struct {
+	ID                uuid.UUID          `json:"id"`
+	Name              string             `json:"name"`
+	Mint              string             `json:"mint"`
+	MintMark          string             `json:"mint_mark"`
+	Mintage           Mintage            `json:"mintage"`
+	Country           string             `json:"country"`
+	Year              Year               `json:"year"`
+	FaceValue         string             `json:"face_value"`
+	Currency          string             `json:"currency"`
+	Material          string             `json:"material"`
+	Description       string             `json:"description"`
+	KMCode            KMCode             `json:"km_code"`
+	NumistaNumber     int                `json:"numista_number"`
+	Ruler             string             `json:"ruler"`
+	Orientation       string             `json:"orientation"`
+	Series            string             `json:"series"`
+	CommemoratedTopic string             `json:"commemorated_topic"`
+	MinValue          float64            `json:"min_value"`
+	MaxValue          float64            `json:"max_value"`
+	Grade             Grade              `json:"grade"`
+	WeightG           float64            `json:"weight_g"`
+	DiameterMM        float64            `json:"diameter_mm"`
+	ThicknessMM       float64            `json:"thickness_mm"`
+	Edge              string             `json:"edge"`
+	Shape             string             `json:"shape"`
+	GroupID           *int               `json:"group_id"`
+	Images            []CoinImage        `json:"images"`
+	GalleryImages     []CoinGalleryImage `json:"gallery_images"`
+}
+
+// NewPublicCoin copies the fields of c that may be shown publicly.
+func NewPublicCoin(c *Coin) *PublicCoin {
+	return &PublicCoin{
+		ID:                c.ID,
+		Name:              c.Name,
+		Mint:              c.Mint,
+		MintMark:          c.MintMark,
+		Mintage:           c.Mintage,
+		Country:           c.Country,
+		Year:              c.Year,
+		FaceValue:         c.FaceValue,
+		Currency:          c.Currency,
+		Material:          c.Material,
+		Description:       c.Description,
+		KMCode:            c.KMCode,
+		NumistaNumber:     c.NumistaNumber,
+		Ruler:             c.Ruler,
+		Orientation:       c.Orientation,
+		Series:            c.Series,
+		CommemoratedTopic: c.CommemoratedTopic,
+		MinValue:          c.MinValue,
+		MaxValue:          c.MaxValue,
+		Grade:             c.Grade,
+		WeightG:           c.WeightG,
+		DiameterMM:        c.DiameterMM,
+		ThicknessMM:       c.ThicknessMM,
+		Edge:              c.Edge,
+		Shape:             c.Shape,
+		GroupID:           c.GroupID,
+		Images:            c.Images,
+		GalleryImages:     c.GalleryImages,
+	}
+}
+
+// ShareRepository stores share links. Create, List and Delete work within
+// the collection of the context.
+type ShareRepository interface {
+	Create(ctx context.Context, link *ShareLink, tokenHash string) error
+	// GetByTokenHash looks at every collection and returns nil if the link
+	// does not exist or has expired.
+	GetByTokenHash(ctx context.Context, tokenHash string) (*ShareLink, error)
+	List(ctx context.Context) ([]*ShareLink, error)
+	// Delete reports whether the collection had a link with that ID.
+	Delete(ctx context.Context, id uuid.UUID) (bool, error)
+	RecordView(ctx context.Context, id uuid.UUID) error
+}
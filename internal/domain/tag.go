@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxTagLength is the longest tag name, in characters.
+const MaxTagLength = 50
+
+// ErrInvalidTag is returned for tag names that are empty or too long.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// Tag classifies coins along any axis: "euro", "commemorative", "to sell".
+// Unlike its one group, a coin can have any number of tags. Names are unique
+// within a collection, ignoring case.
+type Tag struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CoinCount int64     `json:"coin_count"` // Coins with the tag, not counting the trash
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeTagName trims the name and checks its length.
+func NormalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name cannot be empty", ErrInvalidTag)
+	}
+	if utf8.RuneCountInString(name) > MaxTagLength {
+		return "", fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, name, MaxTagLength)
+	}
+	return name, nil
+}
+
+// NormalizeTagNames normalizes each name and drops the blank ones and the
+// repeated ones, ignoring case. The first spelling of a name is kept.
+func NormalizeTagNames(names []string) ([]string, error) {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, n := range names {
+		if strings.TrimSpace(n) == "" {
+			continue
+		}
+		name, err := NormalizeTagName(n)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result, nil
+}
+
+// TagRepository persists tags and which coins have them, within the
+// collection of the context like CoinRepository. The tags of a coin are
+// read along with it, in Coin.Tags.
+type TagRepository interface {
+	Create(ctx context.Context, name string) (*Tag, error)
+	// GetByID and GetByName return nil when the tag is not in the
+	// collection. GetByName ignores case.
+	GetByID(ctx context.Context, id int) (*Tag, error)
+	GetByName(ctx context.Context, name string) (*Tag, error)
+	// List returns the tags sorted by name, with their coin counts.
+	List(ctx context.Context) ([]*Tag, error)
+	Rename(ctx context.Context, id int, name string) (*Tag, error)
+	Delete(ctx context.Context, id int) error
+	// Merge gives the coins of source the target tag and deletes source.
+	Merge(ctx context.Context, sourceID, targetID int) error
+	// SetCoinTags replaces the tags of a coin, creating the missing ones,
+	// and returns their names as stored, sorted.
+	SetCoinTags(ctx context.Context, coinID uuid.UUID, names []string) ([]string, error)
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User is a local account allowed to use the API. It sees the data of its
+// collection only.
+type User struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	IsAdmin      bool      `json:"is_admin"`
+	CollectionID uuid.UUID `json:"collection_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// API token scopes. Write includes read, and admin includes everything.
+const (
+	ScopeRead   = "read"
+	ScopeWrite  = "write"
+	ScopeExport = "export"
+	ScopeAdmin  = "admin"
+)
+
+// Scopes lists every known scope.
+var Scopes = []string{ScopeRead, ScopeWrite, ScopeExport, ScopeAdmin}
+
+// HasScope reports whether the granted scopes allow the wanted one. Write
+// and export both include read: the /export routes sit under the API that
+// asks for read on every GET, and an export already holds all the data.
+func HasScope(granted []string, want string) bool {
+	for _, s := range granted {
+		if s == want || s == ScopeAdmin || ((s == ScopeWrite || s == ScopeExport) && want == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIToken is a personal credential for scripts. Only a hash of the token
+// is stored; the token itself is shown once, when it is created.
+type APIToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+// UserRepository stores accounts with their collections, login sessions and
+// API tokens. Sessions and tokens are looked up by the hash of the token,
+// never by the token itself.
+type UserRepository interface {
+	Create(ctx context.Context, user *User) error
+	// GetByID and GetByUsername return nil if there is no such user.
+	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	List(ctx context.Context) ([]*User, error)
+	Count(ctx context.Context) (int64, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Sessions
+	CreateSession(ctx context.Context, tokenHash string, userID uuid.UUID, expiresAt time.Time) error
+	// GetSessionUser returns nil if the session does not exist or has expired.
+	GetSessionUser(ctx context.Context, tokenHash string) (*User, error)
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteUserSessions(ctx context.Context, userID uuid.UUID) error
+	DeleteExpiredSessions(ctx context.Context) error
+	// API tokens
+	CreateAPIToken(ctx context.Context, token *APIToken, tokenHash string) error
+	// GetAPIToken returns nil if the token does not exist or has expired.
+	GetAPIToken(ctx context.Context, tokenHash string) (*APIToken, error)
+	ListAPITokens(ctx context.Context, userID uuid.UUID) ([]*APIToken, error)
+	// DeleteAPIToken reports whether the user had a token with that ID.
+	DeleteAPIToken(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	TouchAPIToken(ctx context.Context, id uuid.UUID) error
+	// Collections
+	CreateCollection(ctx context.Context, name string) (*Collection, error)
+	// GetCollection returns nil if there is no such collection.
+	GetCollection(ctx context.Context, id uuid.UUID) (*Collection, error)
+	ListCollections(ctx context.Context) ([]*Collection, error)
+}
@@ -1,10 +1,14 @@
 package domain_test
 
 import (
+	"context"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -144,3 +148,214 @@ func TestGrade(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestIsKnownGrade(t *testing.T) {
+	for _, g := range []string{"EBC", "mbc+", "SC--", "PROOF", "XF", " unc "} {
+		assert.True(t, domain.IsKnownGrade(g), g)
+	}
+	for _, g := range []string{"", "Bueno", "MS-65", "+"} {
+		assert.False(t, domain.IsKnownGrade(g), g)
+	}
+}
+
+func TestCoinAnalysisResultValidate(t *testing.T) {
+	t.Run("Valid With Unknowns", func(t *testing.T) {
+		r := &domain.CoinAnalysisResult{Name: "5 Pesetas"}
+		assert.Empty(t, r.Validate())
+	})
+
+	t.Run("Reports Each Field", func(t *testing.T) {
+		r := &domain.CoinAnalysisResult{
+			Year:        2999,
+			Mintage:     -1,
+			Grade:       "Bueno",
+			WeightG:     2500,
+			DiameterMM:  2,
+			ThicknessMM: -1,
+			MinValue:    10,
+			MaxValue:    5,
+		}
+		fields := make(map[string]bool)
+		for _, e := range r.Validate() {
+			fields[e.Field] = true
+		}
+		for _, f := range []string{"name", "year", "mintage", "grade", "weight_g", "diameter_mm", "thickness_mm", "max_value"} {
+			assert.True(t, fields[f], f)
+		}
+
+		r.ClearInvalid(r.Validate())
+		assert.Equal(t, 0, r.Year)
+		assert.Equal(t, int64(0), r.Mintage)
+		assert.Equal(t, "", r.Grade)
+		assert.Equal(t, 0.0, r.WeightG)
+		assert.Equal(t, 0.0, r.DiameterMM)
+		assert.Equal(t, 0.0, r.MaxValue)
+	})
+}
+
+func TestCoinProvenance(t *testing.T) {
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	coin := &domain.Coin{Name: "1 Peseta", Country: "Spain", Mint: "Madrid"}
+
+	coin.SetProvenance(domain.ProvenanceAI, at, map[string]float64{"name": 0.9}, "name", "country", "mint", "weight_g", "personal_notes")
+	assert.Equal(t, domain.ProvenanceAI, coin.Provenance["name"].Source)
+	assert.InDelta(t, 0.9, *coin.Provenance["name"].Confidence, 0.001)
+	assert.Nil(t, coin.Provenance["country"].Confidence)
+	assert.NotContains(t, coin.Provenance, "weight_g", "empty fields have no provenance")
+	assert.NotContains(t, coin.Provenance, "personal_notes", "untracked fields are ignored")
+
+	before := *coin
+	coin.Country = "España"
+	coin.Mint = ""
+	coin.PersonalNotes = "bought in Madrid"
+	changed := coin.ChangedFields(&before)
+	assert.ElementsMatch(t, []string{"country", "mint"}, changed)
+
+	coin.SetProvenance(domain.ProvenanceUser, at.Add(time.Hour), nil, changed...)
+	assert.Equal(t, domain.ProvenanceUser, coin.Provenance["country"].Source)
+	assert.Equal(t, domain.ProvenanceAI, coin.Provenance["name"].Source)
+	assert.NotContains(t, coin.Provenance, "mint")
+
+	data, err := json.Marshal(coin.Provenance)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"country":{"source":"user","updated_at":"2024-05-01T01:00:00Z"}`)
+}
+
+func TestDiffAndRestoreCoin(t *testing.T) {
+	year, _ := domain.NewYear(1870)
+	before := &domain.Coin{Name: "Duro", Year: year, NumistaDetails: map[string]any{"a": 1.0, "b": 2.0}}
+	after := *before
+	after.Name = "5 Pesetas"
+	after.NumistaDetails = map[string]any{"a": 1.0}
+	after.Images = []domain.CoinImage{{Side: "front"}}
+
+	changes, err := domain.DiffCoins(before, &after)
+	assert.NoError(t, err)
+	fields := make([]string, len(changes))
+	for i, c := range changes {
+		fields[i] = c.Field
+	}
+	assert.Equal(t, []string{"name", "numista_details"}, fields, "images are not part of the history")
+
+	values := make(map[string]json.RawMessage)
+	for _, c := range changes {
+		values[c.Field] = c.Before
+	}
+	restored, err := domain.RestoreCoinFields(&after, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "Duro", restored.Name)
+	assert.Equal(t, 1870, restored.Year.Int())
+	assert.Equal(t, before.NumistaDetails, restored.NumistaDetails)
+	assert.Len(t, restored.Images, 1)
+}
+
+func TestHasScope(t *testing.T) {
+	assert.True(t, domain.HasScope([]string{domain.ScopeRead}, domain.ScopeRead))
+	assert.False(t, domain.HasScope([]string{domain.ScopeRead}, domain.ScopeWrite))
+	assert.True(t, domain.HasScope([]string{domain.ScopeWrite}, domain.ScopeRead))
+	assert.False(t, domain.HasScope([]string{domain.ScopeWrite}, domain.ScopeExport))
+	assert.True(t, domain.HasScope([]string{domain.ScopeExport}, domain.ScopeRead))
+	assert.False(t, domain.HasScope([]string{domain.ScopeExport}, domain.ScopeWrite))
+	assert.True(t, domain.HasScope([]string{domain.ScopeAdmin}, domain.ScopeExport))
+	assert.False(t, domain.HasScope(nil, domain.ScopeRead))
+}
+
+func TestCollectionContext(t *testing.T) {
+	_, ok := domain.CollectionFromContext(context.Background())
+	assert.False(t, ok)
+
+	_, ok = domain.CollectionFromContext(domain.WithCollection(context.Background(), uuid.Nil))
+	assert.False(t, ok)
+
+	id := uuid.New()
+	got, ok := domain.CollectionFromContext(domain.WithCollection(context.Background(), id))
+	assert.True(t, ok)
+	assert.Equal(t, id, got)
+}
+
+func TestShareLinkCoinFilter(t *testing.T) {
+	groupID, year := 2, 1870
+	query := "pesetas"
+	link := &domain.ShareLink{GroupID: &groupID, Filter: domain.ShareFilter{Query: &query, MinYear: &year}}
+
+	f := link.CoinFilter(10, 20, nil, nil)
+	assert.Equal(t, &groupID, f.GroupID)
+	assert.Equal(t, &query, f.Query)
+	assert.Equal(t, &year, f.MinYear)
+	assert.Equal(t, 10, f.Limit)
+	assert.Equal(t, 20, f.Offset)
+	assert.True(t, f.PublicSearch, "notes are never searched")
+}
+
+func TestNewPublicCoin(t *testing.T) {
+	coin := &domain.Coin{
+		ID:            uuid.New(),
+		Name:          "5 Pesetas",
+		PersonalNotes: "bought from uncle",
+		PricePaid:     40,
+		SoldPrice:     55,
+		SaleChannel:   "eBay",
+	}
+
+	data, err := json.Marshal(domain.NewPublicCoin(coin))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "5 Pesetas")
+	for _, field := range []string{"personal_notes", "price_paid", "sold_price", "sale_channel", "bought from uncle", "eBay"} {
+		assert.NotContains(t, string(data), field)
+	}
+}
+
+func TestNormalizeTagNames(t *testing.T) {
+	t.Run("Trims And Drops Repeats", func(t *testing.T) {
+		names, err := domain.NormalizeTagNames([]string{" Euro", "", "euro", "to sell ", "  "})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Euro", "to sell"}, names)
+	})
+
+	t.Run("Too Long", func(t *testing.T) {
+		long := strings.Repeat("ñ", domain.MaxTagLength+1)
+		_, err := domain.NormalizeTagNames([]string{"euro", long})
+		assert.ErrorIs(t, err, domain.ErrInvalidTag)
+	})
+
+	t.Run("Multibyte Within Limit", func(t *testing.T) {
+		_, err := domain.NormalizeTagName(strings.Repeat("ñ", domain.MaxTagLength))
+		assert.NoError(t, err)
+	})
+}
+
+func TestInSubtree(t *testing.T) {
+	parent := func(id int) *int { return &id }
+	groups := []*domain.Group{
+		{ID: 1, Name: "Europe"},
+		{ID: 2, Name: "Spain", ParentID: parent(1)},
+		{ID: 3, Name: "Franco", ParentID: parent(2)},
+		{ID: 4, Name: "Asia"},
+	}
+
+	assert.True(t, domain.InSubtree(groups, 3, 1))
+	assert.True(t, domain.InSubtree(groups, 2, 2))
+	assert.False(t, domain.InSubtree(groups, 1, 3))
+	assert.False(t, domain.InSubtree(groups, 3, 4))
+
+	// A cycle already in the data ends the walk
+	loop := []*domain.Group{{ID: 1, ParentID: parent(2)}, {ID: 2, ParentID: parent(1)}}
+	assert.False(t, domain.InSubtree(loop, 1, 3))
+}
+
+func TestParentsFirst(t *testing.T) {
+	parent := func(id int) *int { return &id }
+	groups := []*domain.Group{
+		{ID: 3, Name: "Franco", ParentID: parent(2)},
+		{ID: 2, Name: "Spain", ParentID: parent(1)},
+		{ID: 4, Name: "Asia"},
+		{ID: 1, Name: "Europe"},
+	}
+
+	var ids []int
+	for _, g := range domain.ParentsFirst(groups) {
+		ids = append(ids, g.ID)
+	}
+	assert.Equal(t, []int{4, 1, 2, 3}, ids)
+	assert.Equal(t, 3, groups[0].ID, "the input is left as it was")
+}
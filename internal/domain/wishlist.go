@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Wishlist priorities: 1 is the most wanted.
+const (
+	MinWantPriority     = 1
+	MaxWantPriority     = 5
+	DefaultWantPriority = 3
+)
+
+// ErrInvalidWant is returned for wishlist entries with an invalid type,
+// priority or price.
+var ErrInvalidWant = errors.New("invalid wishlist entry")
+
+// WishlistEntry is a coin type wanted for the collection, identified by its
+// Numista type. Title, Issuer and the thumbnails are copied from Numista when
+// the entry is created. The entry is fulfilled when a coin of the type is
+// added to the collection.
+type WishlistEntry struct {
+	ID               int        `json:"id"`
+	NumistaID        int        `json:"numista_id"`
+	Title            string     `json:"title"`
+	Issuer           string     `json:"issuer"`
+	ObverseThumbnail string     `json:"obverse_thumbnail"`
+	ReverseThumbnail string     `json:"reverse_thumbnail"`
+	Grade            Grade      `json:"grade"`     // Desired grade, if any
+	MaxPrice         float64    `json:"max_price"` // 0 for no limit
+	Priority         int        `json:"priority"`
+	Notes            string     `json:"notes"`
+	FulfilledAt      *time.Time `json:"fulfilled_at"`
+	FulfilledCoinID  *uuid.UUID `json:"fulfilled_coin_id"` // Nil once that coin is purged
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Open reports whether the entry is still wanted.
+func (w *WishlistEntry) Open() bool {
+	return w.FulfilledAt == nil
+}
+
+// Validate checks the fields set by the user.
+func (w *WishlistEntry) Validate() error {
+	if w.NumistaID <= 0 {
+		return fmt.Errorf("%w: numista_id is required", ErrInvalidWant)
+	}
+	if w.Priority < MinWantPriority || w.Priority > MaxWantPriority {
+		return fmt.Errorf("%w: priority must be between %d and %d", ErrInvalidWant, MinWantPriority, MaxWantPriority)
+	}
+	if w.MaxPrice < 0 {
+		return fmt.Errorf("%w: max_price cannot be negative", ErrInvalidWant)
+	}
+	return nil
+}
+
+// WishlistStatus selects entries by whether they are fulfilled.
+type WishlistStatus string
+
+const (
+	WishlistOpen      WishlistStatus = "open"
+	WishlistFulfilled WishlistStatus = "fulfilled"
+	WishlistAll       WishlistStatus = "all"
+)
+
+// WishlistRepository persists the wishlist, within the collection of the
+// context like CoinRepository.
+type WishlistRepository interface {
+	// Create stores the entry and sets its ID and timestamps.
+	Create(ctx context.Context, entry *WishlistEntry) error
+	// GetByID and GetOpen return nil when there is no such entry. GetOpen
+	// looks up the open entry for a Numista type.
+	GetByID(ctx context.Context, id int) (*WishlistEntry, error)
+	GetOpen(ctx context.Context, numistaID int) (*WishlistEntry, error)
+	// List returns the entries most wanted first, then oldest first.
+	List(ctx context.Context, status WishlistStatus) ([]*WishlistEntry, error)
+	// Update saves the grade, maximum price, priority and notes.
+	Update(ctx context.Context, entry *WishlistEntry) error
+	Delete(ctx context.Context, id int) error
+	// Fulfil marks the open entries for the Numista type as fulfilled by
+	// the coin, and returns them.
+	Fulfil(ctx context.Context, numistaID int, coinID uuid.UUID) ([]*WishlistEntry, error)
+}
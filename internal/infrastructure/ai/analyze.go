@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+// MaxAnalysisAttempts bounds the repair loop: the first answer plus the
+// retries that send the validation errors back to the model.
+const MaxAnalysisAttempts = 3
+
+// GenerateFunc sends a prompt, together with both photos of the coin, to a
+// model and returns the text of its answer.
+type GenerateFunc func(ctx context.Context, prompt string) (string, error)
+
+// AnalysisAttempt is stored under "attempts" in the raw details of every
+// result, so GeminiDetails shows how the answer was obtained.
+type AnalysisAttempt struct {
+	Attempt  int      `json:"attempt"`
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors,omitempty"`
+	Response string   `json:"response,omitempty"` // kept only when rejected
+}
+
+// RunAnalysis asks for an analysis and validates it. An answer that does not
+// parse or fails domain validation is sent back with the list of problems, up
+// to MaxAnalysisAttempts times. If the model never gets every field right, the
+// last parsed answer is returned with the invalid fields cleared; if none
+// parsed at all, a *domain.AnalysisFailedError carries every attempt. Errors
+// from generate itself (network, quota) are returned as they are.
+func RunAnalysis(ctx context.Context, generate GenerateFunc, lang string) (*domain.CoinAnalysisResult, error) {
+	prompts := NewPromptGenerator()
+	prompt := prompts.GetPrompt(lang)
+
+	var (
+		attempts []AnalysisAttempt
+		best     *domain.CoinAnalysisResult
+		bestErrs []domain.AnalysisFieldError
+		parseErr error
+	)
+	for n := 1; n <= MaxAnalysisAttempts; n++ {
+		text, err := generate(ctx, prompt)
+		if err != nil {
+			return nil, err
+		}
+
+		attempt := AnalysisAttempt{Attempt: n}
+		var problems []string
+		result, err := ParseAnalysis(text)
+		if err != nil {
+			parseErr = err
+			problems = []string{"the answer is not a JSON object matching the schema"}
+		} else {
+			fieldErrs := result.Validate()
+			if len(fieldErrs) == 0 {
+				attempt.Valid = true
+				result.RawDetails["attempts"] = append(attempts, attempt)
+				return result, nil
+			}
+			best, bestErrs = result, fieldErrs
+			for _, e := range fieldErrs {
+				problems = append(problems, e.Error())
+			}
+		}
+
+		attempt.Errors = problems
+		attempt.Response = text
+		attempts = append(attempts, attempt)
+		slog.Warn("AI analysis rejected", "attempt", n, "problems", problems)
+		prompt = prompts.GetRepairPrompt(lang, text, problems)
+	}
+
+	if best == nil {
+		return nil, &domain.AnalysisFailedError{
+			Err:     fmt.Errorf("no usable analysis after %d attempts: %w", MaxAnalysisAttempts, parseErr),
+			Details: map[string]any{"attempts": attempts},
+		}
+	}
+	best.ClearInvalid(bestErrs)
+	best.RawDetails["attempts"] = attempts
+	return best, nil
+}
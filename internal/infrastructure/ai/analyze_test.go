@@ -0,0 +1,127 @@
+package ai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+// scripted returns the answers in order and records the prompts it got.
+func scripted(answers ...string) (ai.GenerateFunc, *[]string) {
+	var prompts []string
+	return func(ctx context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		answer := answers[0]
+		if len(answers) > 1 {
+			answers = answers[1:]
+		}
+		return answer, nil
+	}, &prompts
+}
+
+func TestRunAnalysis(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid First Time", func(t *testing.T) {
+		gen, prompts := scripted(`{"name": "50 Céntimos", "country": "Spain", "year": 1949, "grade": "MBC+"}`)
+		result, err := ai.RunAnalysis(ctx, gen, "es")
+		assert.NoError(t, err)
+		assert.Equal(t, 1949, result.Year)
+		assert.Len(t, *prompts, 1)
+		assert.Contains(t, (*prompts)[0], `"weight_g"`)
+
+		attempts := result.RawDetails["attempts"].([]ai.AnalysisAttempt)
+		assert.Equal(t, []ai.AnalysisAttempt{{Attempt: 1, Valid: true}}, attempts)
+	})
+
+	t.Run("Repairs With Validation Errors", func(t *testing.T) {
+		gen, prompts := scripted(
+			"not json at all",
+			`{"name": "1 Peseta", "country": "Spain", "year": 19444, "grade": "Bueno"}`,
+			`{"name": "1 Peseta", "country": "Spain", "year": 1944, "grade": "BC"}`,
+		)
+		result, err := ai.RunAnalysis(ctx, gen, "en")
+		assert.NoError(t, err)
+		assert.Equal(t, 1944, result.Year)
+		assert.Equal(t, "BC", result.Grade)
+
+		if assert.Len(t, *prompts, 3) {
+			assert.Contains(t, (*prompts)[1], "not json at all")
+			assert.Contains(t, (*prompts)[1], "not a JSON object")
+			assert.Contains(t, (*prompts)[2], "year: 19444 is not a plausible year")
+			assert.Contains(t, (*prompts)[2], `grade: "Bueno" is not a known grade`)
+		}
+
+		attempts := result.RawDetails["attempts"].([]ai.AnalysisAttempt)
+		if assert.Len(t, attempts, 3) {
+			assert.False(t, attempts[0].Valid)
+			assert.Equal(t, "not json at all", attempts[0].Response)
+			assert.Len(t, attempts[1].Errors, 2)
+			assert.True(t, attempts[2].Valid)
+			assert.Empty(t, attempts[2].Response)
+		}
+	})
+
+	t.Run("Gives Up Clearing Invalid Fields", func(t *testing.T) {
+		gen, prompts := scripted(`{"name": "Duro", "country": "Spain", "year": 1870, "mintage": -3, "diameter_mm": 3800}`)
+		result, err := ai.RunAnalysis(ctx, gen, "es")
+		assert.NoError(t, err)
+		assert.Len(t, *prompts, ai.MaxAnalysisAttempts)
+		assert.Equal(t, 1870, result.Year)
+		assert.Equal(t, int64(0), result.Mintage)
+		assert.Equal(t, 0.0, result.DiameterMM)
+		assert.Len(t, result.RawDetails["attempts"], ai.MaxAnalysisAttempts)
+	})
+
+	t.Run("Never Parses", func(t *testing.T) {
+		gen, _ := scripted("```json\n{\"name\": ```")
+		_, err := ai.RunAnalysis(ctx, gen, "es")
+		assert.ErrorContains(t, err, "no usable analysis after 3 attempts")
+
+		var failed *domain.AnalysisFailedError
+		if assert.ErrorAs(t, err, &failed) {
+			attempts := failed.Details["attempts"].([]ai.AnalysisAttempt)
+			assert.Len(t, attempts, ai.MaxAnalysisAttempts)
+			assert.Equal(t, "```json\n{\"name\": ```", attempts[0].Response)
+		}
+	})
+
+	t.Run("Null Answer Is Rejected", func(t *testing.T) {
+		gen, prompts := scripted(
+			"null",
+			`{"name": "2 Reales", "country": "Spain", "year": 1850}`,
+		)
+		result, err := ai.RunAnalysis(ctx, gen, "es")
+		assert.NoError(t, err)
+		assert.Len(t, *prompts, 2)
+		assert.Equal(t, 1850, result.Year)
+		assert.Len(t, result.RawDetails["attempts"], 2)
+	})
+
+	t.Run("Generate Error Is Not Repaired", func(t *testing.T) {
+		calls := 0
+		_, err := ai.RunAnalysis(ctx, func(ctx context.Context, prompt string) (string, error) {
+			calls++
+			return "", assert.AnError
+		}, "es")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestAnalysisSchema(t *testing.T) {
+	schema := ai.AnalysisSchema()
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "integer", schema.Properties["year"].Type)
+	assert.Equal(t, "integer", schema.Properties["mintage"].Type)
+	assert.Equal(t, "number", schema.Properties["weight_g"].Type)
+	assert.Equal(t, "string", schema.Properties["grade"].Type)
+	assert.Contains(t, schema.Properties["grade"].Description, "EBC")
+	assert.NotContains(t, schema.Properties, "raw_details")
+	for _, name := range schema.Required {
+		assert.Contains(t, schema.Properties, name)
+	}
+}
@@ -0,0 +1,209 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+// OpenAIService talks to any OpenAI-compatible chat completions API that
+// accepts image input: OpenAI itself, Ollama (/v1), llama.cpp server, vLLM...
+type OpenAIService struct {
+	BaseURL      string // e.g. https://api.openai.com/v1 or http://ollama:11434/v1
+	APIKey       string // optional for local servers
+	DefaultModel string
+	HTTP         *http.Client
+
+	mu           sync.RWMutex
+	cachedModels []domain.GeminiModelInfo
+	lastCache    time.Time
+}
+
+func NewOpenAIService(baseURL, apiKey, defaultModel string) *OpenAIService {
+	return &OpenAIService{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		APIKey:       apiKey,
+		DefaultModel: defaultModel,
+		// Local vision models can take minutes per request
+		HTTP: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+type chatRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	Temperature    float32        `json:"temperature"`
+	ResponseFormat map[string]any `json:"response_format,omitempty"`
+}
+
+type chatMessage struct {
+	Role    string     `json:"role"`
+	Content []chatPart `json:"content"`
+}
+
+type chatPart struct {
+	Type     string        `json:"type"`
+	Text     string        `json:"text,omitempty"`
+	ImageURL *chatImageURL `json:"image_url,omitempty"`
+}
+
+type chatImageURL struct {
+	URL string `json:"url"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+type modelsResponse struct {
+	Data []struct {
+		ID      string `json:"id"`
+		OwnedBy string `json:"owned_by"`
+	} `json:"data"`
+}
+
+func (s *OpenAIService) AnalyzeCoin(ctx context.Context, frontImagePath, backImagePath, modelName string, temperature float32, lang string) (*domain.CoinAnalysisResult, error) {
+	front, err := imageDataURL(frontImagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read front image: %w", err)
+	}
+	back, err := imageDataURL(backImagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read back image: %w", err)
+	}
+
+	if modelName == "" {
+		modelName = s.DefaultModel
+	}
+	if modelName == "" {
+		return nil, fmt.Errorf("no model configured for %s", s.BaseURL)
+	}
+
+	return RunAnalysis(ctx, func(ctx context.Context, prompt string) (string, error) {
+		reqBody := chatRequest{
+			Model:       modelName,
+			Temperature: temperature,
+			Messages: []chatMessage{{
+				Role: "user",
+				Content: []chatPart{
+					{Type: "text", Text: prompt},
+					{Type: "image_url", ImageURL: &chatImageURL{URL: front}},
+					{Type: "image_url", ImageURL: &chatImageURL{URL: back}},
+				},
+			}},
+			ResponseFormat: map[string]any{
+				"type":        "json_schema",
+				"json_schema": map[string]any{"name": "coin_analysis", "schema": AnalysisSchema()},
+			},
+		}
+
+		var resp chatResponse
+		if err := s.do(ctx, http.MethodPost, "/chat/completions", reqBody, &resp); err != nil {
+			return "", fmt.Errorf("failed to generate content: %w", err)
+		}
+		if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == "" {
+			return "", fmt.Errorf("no content returned from %s", s.BaseURL)
+		}
+		return resp.Choices[0].Message.Content, nil
+	}, lang)
+}
+
+func (s *OpenAIService) ListModels(ctx context.Context) ([]domain.GeminiModelInfo, error) {
+	s.mu.RLock()
+	// Cache for 1 hour
+	if len(s.cachedModels) > 0 && time.Since(s.lastCache) < 1*time.Hour {
+		defer s.mu.RUnlock()
+		return s.cachedModels, nil
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Double check
+	if len(s.cachedModels) > 0 && time.Since(s.lastCache) < 1*time.Hour {
+		return s.cachedModels, nil
+	}
+
+	var resp modelsResponse
+	if err := s.do(ctx, http.MethodGet, "/models", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	models := make([]domain.GeminiModelInfo, 0, len(resp.Data))
+	for _, m := range resp.Data {
+		models = append(models, domain.GeminiModelInfo{Name: m.ID, Description: m.OwnedBy})
+	}
+
+	s.cachedModels = models
+	s.lastCache = time.Now()
+
+	return models, nil
+}
+
+func (s *OpenAIService) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+
+	resp, err := s.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("api error: %s - %s", resp.Status, string(msg))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func imageDataURL(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return "data:" + http.DetectContentType(data) + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+var _ domain.AIService = (*OpenAIService)(nil)
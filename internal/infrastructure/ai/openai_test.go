@@ -0,0 +1,109 @@
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOpenAI answers like an OpenAI-compatible server and records the last
+// chat request.
+func fakeOpenAI(t *testing.T, content string) (*httptest.Server, *map[string]any) {
+	var last map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"llava:13b","owned_by":"library"},{"id":"moondream","owned_by":"library"}]}`))
+		case "/v1/chat/completions":
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&last))
+			resp := map[string]any{"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": content}}}}
+			_ = json.NewEncoder(w).Encode(resp)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &last
+}
+
+func writeImages(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	front := filepath.Join(dir, "front.png")
+	back := filepath.Join(dir, "back.jpg")
+	assert.NoError(t, os.WriteFile(front, []byte("\x89PNG\r\n\x1a\nfront"), 0644))
+	assert.NoError(t, os.WriteFile(back, []byte("\xff\xd8\xffback"), 0644))
+	return front, back
+}
+
+func TestOpenAIServiceAnalyzeCoin(t *testing.T) {
+	srv, last := fakeOpenAI(t, "```json\n{\"name\": \"1 Peseta\", \"country\": \"Spain\", \"year\": 1944, \"grade\": \"MBC\"}\n```")
+	front, back := writeImages(t)
+
+	svc := ai.NewOpenAIService(srv.URL+"/v1/", "secret", "llava")
+	result, err := svc.AnalyzeCoin(context.Background(), front, back, "", 0.2, "es")
+	assert.NoError(t, err)
+	assert.Equal(t, "1 Peseta", result.Name)
+	assert.Equal(t, 1944, result.Year)
+	assert.Equal(t, "Spain", result.RawDetails["country"])
+
+	req := *last
+	assert.Equal(t, "llava", req["model"])
+	assert.InDelta(t, 0.2, req["temperature"], 0.001)
+	format := req["response_format"].(map[string]any)
+	assert.Equal(t, "json_schema", format["type"])
+	assert.Contains(t, format["json_schema"].(map[string]any)["schema"].(map[string]any)["properties"], "year")
+	parts := req["messages"].([]any)[0].(map[string]any)["content"].([]any)
+	if assert.Len(t, parts, 3) {
+		assert.Contains(t, parts[0].(map[string]any)["text"], "numismático")
+		assert.True(t, strings.HasPrefix(parts[1].(map[string]any)["image_url"].(map[string]any)["url"].(string), "data:image/png;base64,"))
+		assert.True(t, strings.HasPrefix(parts[2].(map[string]any)["image_url"].(map[string]any)["url"].(string), "data:image/jpeg;base64,"))
+	}
+}
+
+func TestOpenAIServiceErrors(t *testing.T) {
+	front, back := writeImages(t)
+
+	t.Run("Not JSON", func(t *testing.T) {
+		srv, _ := fakeOpenAI(t, "I think it is a peseta")
+		svc := ai.NewOpenAIService(srv.URL+"/v1", "secret", "llava")
+		_, err := svc.AnalyzeCoin(context.Background(), front, back, "", 0.1, "en")
+		assert.ErrorContains(t, err, "failed to parse AI response")
+	})
+
+	t.Run("Server Error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "model not found", http.StatusNotFound)
+		}))
+		defer srv.Close()
+		svc := ai.NewOpenAIService(srv.URL, "", "missing")
+		_, err := svc.AnalyzeCoin(context.Background(), front, back, "", 0.1, "en")
+		assert.ErrorContains(t, err, "model not found")
+	})
+
+	t.Run("Missing Image", func(t *testing.T) {
+		svc := ai.NewOpenAIService("http://127.0.0.1:0", "", "llava")
+		_, err := svc.AnalyzeCoin(context.Background(), "/does/not/exist.jpg", back, "", 0.1, "en")
+		assert.ErrorContains(t, err, "front image")
+	})
+}
+
+func TestOpenAIServiceListModels(t *testing.T) {
+	srv, _ := fakeOpenAI(t, "")
+	svc := ai.NewOpenAIService(srv.URL+"/v1", "", "")
+	models, err := svc.ListModels(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.GeminiModelInfo{
+		{Name: "llava:13b", Description: "library"},
+		{Name: "moondream", Description: "library"},
+	}, models)
+}
@@ -1,6 +1,9 @@
-package gemini
+package ai
 
-import "strings"
+import (
+	"encoding/json"
+	"strings"
+)
 
 type PromptGenerator struct{}
 
@@ -9,12 +12,43 @@ func NewPromptGenerator() *PromptGenerator {
 }
 
 func (p *PromptGenerator) GetPrompt(lang string) string {
-	lang = strings.ToLower(lang)
-	if strings.HasPrefix(lang, "en") {
-		return p.getEnglishPrompt()
+	schema, _ := json.MarshalIndent(AnalysisSchema(), "\t", "  ")
+	if isEnglish(lang) {
+		return p.getEnglishPrompt() + "\n\tThe answer must follow this JSON Schema:\n\t" + string(schema) + "\n"
 	}
 	// Default to Spanish
-	return p.getSpanishPrompt()
+	return p.getSpanishPrompt() + "\n\tLa respuesta debe seguir este JSON Schema:\n\t" + string(schema) + "\n"
+}
+
+// GetRepairPrompt asks the model to fix its previous answer. The photos are
+// sent again with it, so it is the full prompt plus the rejected answer and
+// what was wrong with it.
+func (p *PromptGenerator) GetRepairPrompt(lang, previous string, problems []string) string {
+	list := "\t- " + strings.Join(problems, "\n\t- ")
+	if isEnglish(lang) {
+		return p.GetPrompt(lang) + `
+	YOUR PREVIOUS ANSWER WAS REJECTED:
+	` + previous + `
+
+	PROBLEMS FOUND:
+` + list + `
+
+	Answer again with the complete corrected JSON object only.
+	`
+	}
+	return p.GetPrompt(lang) + `
+	TU RESPUESTA ANTERIOR FUE RECHAZADA:
+	` + previous + `
+
+	PROBLEMAS ENCONTRADOS:
+` + list + `
+
+	Responde de nuevo UNICAMENTE con el objeto JSON completo y corregido.
+	`
+}
+
+func isEnglish(lang string) bool {
+	return strings.HasPrefix(strings.ToLower(lang), "en")
 }
 
 func (p *PromptGenerator) getSpanishPrompt() string {
@@ -39,7 +73,8 @@ func (p *PromptGenerator) getSpanishPrompt() string {
 		"description": "Descripción visual detallada del anverso y reverso",
 		"km_code": "Código KM (ej: KM# 819)",
 
-		"mint": "Ceca (ej: Madrid, M coronada)",
+		"mint": "Ceca (ej: Madrid)",
+		"mint_mark": "Marca de ceca grabada en la moneda (ej: M coronada); vacío si no tiene",
 		"mintage": 0,
 		"min_value": 0.0,
 		"max_value": 0.0,
@@ -72,6 +107,7 @@ func (p *PromptGenerator) getEnglishPrompt() string {
 		"km_code": "KM Code (e.g. KM# 819)",
 
 		"mint": "Mint (e.g. Madrid)",
+		"mint_mark": "Mint mark struck on the coin (e.g. M); empty if there is none",
 		"mintage": 0,
 		"min_value": 0.0,
 		"max_value": 0.0,
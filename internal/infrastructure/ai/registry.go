@@ -0,0 +1,106 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+// Registry is an AIService that routes each request to one of several
+// configured backends. Model names may carry the provider as a prefix,
+// "ollama:llava:13b" or "openai:gpt-4o"; names without a known prefix go to
+// the default provider, so plain Gemini model names keep working.
+type Registry struct {
+	defaultName string
+	names       []string
+	providers   map[string]domain.AIService
+}
+
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]domain.AIService)}
+}
+
+// Register adds a backend. The first one registered is the default until
+// SetDefault says otherwise.
+func (r *Registry) Register(name string, svc domain.AIService) {
+	if _, ok := r.providers[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.providers[name] = svc
+	if r.defaultName == "" {
+		r.defaultName = name
+	}
+}
+
+func (r *Registry) SetDefault(name string) error {
+	if _, ok := r.providers[name]; !ok {
+		return fmt.Errorf("unknown AI provider %q (configured: %s)", name, strings.Join(r.names, ", "))
+	}
+	r.defaultName = name
+	return nil
+}
+
+func (r *Registry) Default() string {
+	return r.defaultName
+}
+
+func (r *Registry) Providers() []string {
+	return r.names
+}
+
+func (r *Registry) resolve(modelName string) (string, domain.AIService, string, error) {
+	if name, model, ok := strings.Cut(modelName, ":"); ok {
+		if svc, ok := r.providers[name]; ok {
+			return name, svc, model, nil
+		}
+	}
+	svc, ok := r.providers[r.defaultName]
+	if !ok {
+		return "", nil, "", fmt.Errorf("no AI provider configured")
+	}
+	return r.defaultName, svc, modelName, nil
+}
+
+func (r *Registry) AnalyzeCoin(ctx context.Context, frontImagePath, backImagePath, modelName string, temperature float32, lang string) (*domain.CoinAnalysisResult, error) {
+	name, svc, model, err := r.resolve(modelName)
+	if err != nil {
+		return nil, err
+	}
+	result, err := svc.AnalyzeCoin(ctx, frontImagePath, backImagePath, model, temperature, lang)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return result, nil
+}
+
+// ListModels lists the models of every provider, each name prefixed with its
+// provider so it can be passed back to AnalyzeCoin. A provider that cannot be
+// reached is skipped; an error is returned only when all of them fail.
+func (r *Registry) ListModels(ctx context.Context) ([]domain.GeminiModelInfo, error) {
+	var all []domain.GeminiModelInfo
+	var lastErr error
+	failed := 0
+	for _, name := range r.names {
+		models, err := r.providers[name].ListModels(ctx)
+		if err != nil {
+			slog.Warn("Failed to list AI models", "provider", name, "error", err)
+			lastErr = err
+			failed++
+			continue
+		}
+		for _, m := range models {
+			m.Name = name + ":" + m.Name
+			m.Provider = name
+			all = append(all, m)
+		}
+	}
+	if failed > 0 && failed == len(r.names) {
+		return nil, fmt.Errorf("failed to list models: %w", lastErr)
+	}
+	return all, nil
+}
+
+var _ domain.AIService = (*Registry)(nil)
@@ -0,0 +1,86 @@
+package ai_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAI records the model it was asked for.
+type stubAI struct {
+	models    []domain.GeminiModelInfo
+	listErr   error
+	lastModel string
+}
+
+func (s *stubAI) AnalyzeCoin(ctx context.Context, front, back, modelName string, temperature float32, lang string) (*domain.CoinAnalysisResult, error) {
+	s.lastModel = modelName
+	return &domain.CoinAnalysisResult{Name: modelName}, nil
+}
+
+func (s *stubAI) ListModels(ctx context.Context) ([]domain.GeminiModelInfo, error) {
+	return s.models, s.listErr
+}
+
+func TestRegistryRouting(t *testing.T) {
+	ctx := context.Background()
+	gemini := &stubAI{}
+	ollama := &stubAI{}
+	r := ai.NewRegistry()
+	r.Register("gemini", gemini)
+	r.Register("ollama", ollama)
+	assert.Equal(t, "gemini", r.Default())
+
+	// Prefixed names go to their provider; the rest of the name is untouched
+	_, err := r.AnalyzeCoin(ctx, "f", "b", "ollama:llava:13b", 0.1, "es")
+	assert.NoError(t, err)
+	assert.Equal(t, "llava:13b", ollama.lastModel)
+
+	// Plain and unknown-prefix names go to the default
+	_, err = r.AnalyzeCoin(ctx, "f", "b", "gemini-1.5-flash", 0.1, "es")
+	assert.NoError(t, err)
+	assert.Equal(t, "gemini-1.5-flash", gemini.lastModel)
+	_, err = r.AnalyzeCoin(ctx, "f", "b", "other:model", 0.1, "es")
+	assert.NoError(t, err)
+	assert.Equal(t, "other:model", gemini.lastModel)
+
+	assert.NoError(t, r.SetDefault("ollama"))
+	_, err = r.AnalyzeCoin(ctx, "f", "b", "", 0.1, "es")
+	assert.NoError(t, err)
+	assert.Equal(t, "", ollama.lastModel)
+
+	assert.Error(t, r.SetDefault("openai"))
+}
+
+func TestRegistryListModels(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Aggregates And Skips Failures", func(t *testing.T) {
+		r := ai.NewRegistry()
+		r.Register("gemini", &stubAI{models: []domain.GeminiModelInfo{{Name: "gemini-1.5-flash"}}})
+		r.Register("openai", &stubAI{listErr: assert.AnError})
+		r.Register("ollama", &stubAI{models: []domain.GeminiModelInfo{{Name: "llava"}}})
+
+		models, err := r.ListModels(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.GeminiModelInfo{
+			{Name: "gemini:gemini-1.5-flash", Provider: "gemini"},
+			{Name: "ollama:llava", Provider: "ollama"},
+		}, models)
+	})
+
+	t.Run("All Fail", func(t *testing.T) {
+		r := ai.NewRegistry()
+		r.Register("openai", &stubAI{listErr: assert.AnError})
+		_, err := r.ListModels(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := ai.NewRegistry().AnalyzeCoin(ctx, "f", "b", "", 0.1, "es")
+		assert.Error(t, err)
+	})
+}
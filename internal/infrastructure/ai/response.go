@@ -0,0 +1,39 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+// ParseAnalysis turns the JSON text returned by a model into a
+// CoinAnalysisResult. Markdown code fences around the JSON are tolerated.
+func ParseAnalysis(responseText string) (*domain.CoinAnalysisResult, error) {
+	responseText = strings.TrimSpace(responseText)
+	responseText = strings.TrimPrefix(responseText, "```json")
+	responseText = strings.TrimPrefix(responseText, "```")
+	responseText = strings.TrimSuffix(responseText, "```")
+	responseText = strings.TrimSpace(responseText)
+
+	// Only an object is an answer: null, arrays or bare values would leave
+	// the result empty
+	var rawDetails map[string]any
+	if err := json.Unmarshal([]byte(responseText), &rawDetails); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w. Response: %s", err, responseText)
+	}
+	if rawDetails == nil {
+		return nil, fmt.Errorf("failed to parse AI response: not a JSON object. Response: %s", responseText)
+	}
+
+	var result domain.CoinAnalysisResult
+	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w. Response: %s", err, responseText)
+	}
+
+	// Store raw details for debugging/extra info
+	result.RawDetails = rawDetails
+
+	return &result, nil
+}
@@ -0,0 +1,86 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+// Schema is the subset of JSON Schema understood by both Gemini response
+// schemas and OpenAI structured outputs.
+type Schema struct {
+	Type        string             `json:"type"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// Fields left out of the schema: filled in by us, not by the model.
+var schemaSkip = map[string]bool{
+	"raw_details": true,
+}
+
+var schemaRequired = []string{"name", "country", "year"}
+
+var schemaDescriptions = map[string]string{
+	"name":                            "Descriptive title, e.g. 25 Pesetas - World Cup 82",
+	"year":                            "Year of issue; 0 if unknown",
+	"km_code":                         "Krause-Mishler code, e.g. KM# 819",
+	"numista_number":                  "Numista type number; 0 if unknown",
+	"grade":                           "Estimated grade, one of: " + strings.Join(domain.KnownGrades, ", "),
+	"mint":                            "Name of the mint, e.g. Madrid",
+	"mint_mark":                       "Mint mark struck on the coin, e.g. M; empty if there is none",
+	"mintage":                         "Number of coins minted; 0 if unknown",
+	"weight_g":                        "Weight in grams; 0 if unknown",
+	"diameter_mm":                     "Diameter in millimetres; 0 if unknown",
+	"thickness_mm":                    "Thickness in millimetres; 0 if unknown",
+	"min_value":                       "Lowest estimated market value in EUR",
+	"max_value":                       "Highest estimated market value in EUR",
+	"vertical_correction_angle_front": "Degrees to rotate the obverse upright",
+	"vertical_correction_angle_back":  "Degrees to rotate the reverse upright",
+	"confidence":                      "How sure you are of each field you filled, from 0 (a guess) to 1 (read on the coin or certain)",
+}
+
+// AnalysisSchema describes domain.CoinAnalysisResult, built from its JSON
+// tags so the schema cannot drift from the struct it is decoded into.
+func AnalysisSchema() *Schema {
+	t := reflect.TypeOf(domain.CoinAnalysisResult{})
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema), Required: schemaRequired}
+	var perField []string // map[string]float64 fields: one number per scalar field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" || schemaSkip[name] {
+			continue
+		}
+		var typ string
+		switch f.Type.Kind() {
+		case reflect.String:
+			typ = "string"
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			typ = "integer"
+		case reflect.Float32, reflect.Float64:
+			typ = "number"
+		case reflect.Bool:
+			typ = "boolean"
+		case reflect.Map:
+			if f.Type.Key().Kind() == reflect.String && f.Type.Elem().Kind() == reflect.Float64 {
+				perField = append(perField, name)
+			}
+			continue
+		default:
+			continue
+		}
+		schema.Properties[name] = &Schema{Type: typ, Description: schemaDescriptions[name]}
+	}
+
+	for _, name := range perField {
+		obj := &Schema{Type: "object", Description: schemaDescriptions[name], Properties: make(map[string]*Schema)}
+		for field := range schema.Properties {
+			obj.Properties[field] = &Schema{Type: "number"}
+		}
+		schema.Properties[name] = obj
+	}
+	return schema
+}
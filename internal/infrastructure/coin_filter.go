@@ -0,0 +1,141 @@
+package infrastructure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Filters of the coin list that a facet can leave out of its own counts.
+const (
+	filterGroup    = "group"
+	filterCountry  = "country"
+	filterGrade    = "grade"
+	filterMaterial = "material"
+	filterYear     = "year"  // year, min_year and max_year
+	filterValue    = "value" // min_price and max_price
+)
+
+// sqlArgs collects the arguments of a query built in Go.
+type sqlArgs []any
+
+// add appends an argument and returns its placeholder.
+func (a *sqlArgs) add(v any) string {
+	*a = append(*a, v)
+	return fmt.Sprintf("$%d", len(*a))
+}
+
+// coinWhere builds the conditions of the coin list, which sqlc cannot
+// express with its sorts and cursors, and of the facets, which skip their
+// own filter. Columns are not qualified, so the coins table must be the only
+// one in scope. A smart group counts as the group filter; its own fields are
+// never skipped, since they define the group.
+func coinWhere(cid pgtype.UUID, f domain.CoinFilter, args *sqlArgs, skip ...string) string {
+	skipped := func(name string) bool {
+		for _, s := range skip {
+			if s == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	conds := []string{"collection_id = " + args.add(cid)}
+	if !f.IncludeTrashed {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	conds = append(conds, filterConds(f, args, skipped)...)
+	if f.Smart != nil && !skipped(filterGroup) {
+		conds = append(conds, filterConds(f.Smart.CoinFilter(), args, func(string) bool { return false })...)
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func filterConds(f domain.CoinFilter, args *sqlArgs, skipped func(string) bool) []string {
+	var conds []string
+	if f.GroupID != nil && !skipped(filterGroup) {
+		if f.IncludeSubgroups {
+			conds = append(conds, "group_id IN ("+subgroupIDs(args.add(*f.GroupID))+")")
+		} else {
+			conds = append(conds, "group_id = "+args.add(*f.GroupID))
+		}
+	}
+	if f.Country != nil && !skipped(filterCountry) {
+		conds = append(conds, "country ILIKE "+args.add(*f.Country))
+	}
+	if f.Query != nil {
+		search := prefixQuery(f.Query)
+		conds = append(conds, fmt.Sprintf("(%s @@ to_tsquery('coin_search', %s) OR km_code ILIKE '%%' || %s::text || '%%')",
+			searchVector(f), args.add(search), args.add(*f.Query)))
+	}
+	if !skipped(filterValue) {
+		if f.MinPrice != nil {
+			conds = append(conds, "min_value >= "+args.add(*f.MinPrice))
+		}
+		if f.MaxPrice != nil {
+			conds = append(conds, "max_value <= "+args.add(*f.MaxPrice))
+		}
+	}
+	if f.Grade != nil && !skipped(filterGrade) {
+		conds = append(conds, "grade = "+args.add(*f.Grade))
+	}
+	if f.Material != nil && !skipped(filterMaterial) {
+		conds = append(conds, "material = "+args.add(*f.Material))
+	}
+	if len(f.TagsAny) > 0 {
+		conds = append(conds, tagCond(f.TagsAny, args))
+	}
+	for _, name := range f.TagsAll {
+		conds = append(conds, tagCond([]string{name}, args))
+	}
+	if len(f.TagsNone) > 0 {
+		conds = append(conds, "NOT "+tagCond(f.TagsNone, args))
+	}
+	if len(f.NumistaNumbers) > 0 {
+		conds = append(conds, "numista_number = ANY("+args.add(f.NumistaNumbers)+"::int[])")
+	}
+	if !skipped(filterYear) {
+		if f.Year != nil {
+			conds = append(conds, "year = "+args.add(*f.Year))
+		}
+		if f.MinYear != nil {
+			conds = append(conds, "year >= "+args.add(*f.MinYear))
+		}
+		if f.MaxYear != nil {
+			conds = append(conds, "year <= "+args.add(*f.MaxYear))
+		}
+	}
+	return conds
+}
+
+// publicSearchVector is the search vector without the notes, as indexed by
+// idx_coins_public_search_vector.
+const publicSearchVector = "coin_public_search_vector(name, series, commemorated_topic, ruler, description, numista_details)"
+
+// searchVector is the text search column the filter's query looks in.
+func searchVector(f domain.CoinFilter) string {
+	if f.PublicSearch {
+		return publicSearchVector
+	}
+	return "search_vector"
+}
+
+// tagCond matches the coins with any of the tags, ignoring case. The coins
+// table is qualified because tags has an id column too.
+func tagCond(names []string, args *sqlArgs) string {
+	lower := make([]string, len(names))
+	for i, n := range names {
+		lower[i] = strings.ToLower(n)
+	}
+	return "EXISTS (SELECT 1 FROM coin_tags ct JOIN tags t ON t.id = ct.tag_id " +
+		"WHERE ct.coin_id = coins.id AND lower(t.name) = ANY(" + args.add(lower) + "::text[]))"
+}
+
+// subgroupIDs selects the ID of a group and of every group below it. UNION
+// drops groups already seen, so a cycle in parent_id cannot make it loop.
+func subgroupIDs(groupID string) string {
+	return "WITH RECURSIVE subgroups AS (SELECT id FROM groups WHERE id = " + groupID +
+		" UNION SELECT g.id FROM groups g JOIN subgroups s ON g.parent_id = s.id) SELECT id FROM subgroups"
+}
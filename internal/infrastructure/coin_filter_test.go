@@ -0,0 +1,91 @@
+package infrastructure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoinWhereTags(t *testing.T) {
+	cid := pgtype.UUID{Valid: true}
+	exists := "EXISTS (SELECT 1 FROM coin_tags ct JOIN tags t ON t.id = ct.tag_id WHERE ct.coin_id = coins.id AND lower(t.name) = ANY("
+
+	t.Run("Any", func(t *testing.T) {
+		var args sqlArgs
+		where := coinWhere(cid, domain.CoinFilter{TagsAny: []string{"Euro", "Gift"}}, &args)
+		assert.Contains(t, where, exists+"$2::text[]))")
+		assert.Equal(t, sqlArgs{cid, []string{"euro", "gift"}}, args)
+	})
+
+	t.Run("All", func(t *testing.T) {
+		var args sqlArgs
+		where := coinWhere(cid, domain.CoinFilter{TagsAll: []string{"euro", "commemorative"}}, &args)
+		assert.Equal(t, 2, strings.Count(where, exists), "one condition per tag")
+		assert.Equal(t, sqlArgs{cid, []string{"euro"}, []string{"commemorative"}}, args)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		var args sqlArgs
+		where := coinWhere(cid, domain.CoinFilter{TagsNone: []string{"to sell"}}, &args)
+		assert.Contains(t, where, "NOT "+exists+"$2::text[]))")
+	})
+
+	t.Run("Kept By Facets", func(t *testing.T) {
+		var args sqlArgs
+		where := coinWhere(cid, domain.CoinFilter{TagsAny: []string{"euro"}}, &args, filterCountry, filterGroup)
+		assert.Contains(t, where, exists)
+	})
+}
+
+func TestCoinWhereSubgroups(t *testing.T) {
+	cid := pgtype.UUID{Valid: true}
+	groupID := 3
+
+	var args sqlArgs
+	where := coinWhere(cid, domain.CoinFilter{GroupID: &groupID}, &args)
+	assert.Contains(t, where, "group_id = $2")
+
+	args = nil
+	where = coinWhere(cid, domain.CoinFilter{GroupID: &groupID, IncludeSubgroups: true}, &args)
+	assert.Contains(t, where, "group_id IN (WITH RECURSIVE subgroups AS (SELECT id FROM groups WHERE id = $2 ")
+	assert.NotContains(t, where, "UNION ALL", "a cycle in parent_id must not make it loop")
+	assert.Equal(t, sqlArgs{cid, groupID}, args)
+}
+
+func TestCoinWhereNumistaNumbers(t *testing.T) {
+	cid := pgtype.UUID{Valid: true}
+
+	var args sqlArgs
+	where := coinWhere(cid, domain.CoinFilter{NumistaNumbers: []int{95420, 1234}}, &args)
+	assert.Contains(t, where, "numista_number = ANY($2::int[])")
+	assert.Equal(t, sqlArgs{cid, []int{95420, 1234}}, args)
+}
+
+func TestCoinWhereIncludeTrashed(t *testing.T) {
+	cid := pgtype.UUID{Valid: true}
+	groupID := 3
+
+	var args sqlArgs
+	assert.Contains(t, coinWhere(cid, domain.CoinFilter{GroupID: &groupID}, &args), "deleted_at IS NULL")
+
+	args = nil
+	where := coinWhere(cid, domain.CoinFilter{GroupID: &groupID, IncludeTrashed: true}, &args)
+	assert.Equal(t, "collection_id = $1 AND group_id = $2", where)
+}
+
+func TestCoinWherePublicSearch(t *testing.T) {
+	cid := pgtype.UUID{Valid: true}
+	query := "peseta"
+
+	var args sqlArgs
+	where := coinWhere(cid, domain.CoinFilter{Query: &query}, &args)
+	assert.Contains(t, where, "(search_vector @@ to_tsquery('coin_search', $2)")
+
+	args = nil
+	where = coinWhere(cid, domain.CoinFilter{Query: &query, PublicSearch: true}, &args)
+	assert.Contains(t, where, "(coin_public_search_vector(name, series, commemorated_topic, ruler, description, numista_details) @@ to_tsquery('coin_search', $2)")
+	assert.NotContains(t, where, "search_vector @@")
+}
@@ -11,12 +11,30 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const coinExists = `-- name: CoinExists :one
+SELECT EXISTS (
+    SELECT 1 FROM coins WHERE id = $1 AND collection_id = $2
+)
+`
+
+type CoinExistsParams struct {
+	ID           pgtype.UUID `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) CoinExists(ctx context.Context, arg CoinExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, coinExists, arg.ID, arg.CollectionID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const countCoins = `-- name: CountCoins :one
-SELECT count(*) FROM coins
+SELECT count(*) FROM coins WHERE collection_id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) CountCoins(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countCoins)
+func (q *Queries) CountCoins(ctx context.Context, collectionID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countCoins, collectionID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -29,15 +47,17 @@ INSERT INTO coins (
     weight_g, diameter_mm, thickness_mm, edge, shape,
     acquired_at, sold_at, price_paid, sold_price, numista_number, numista_details,
     gemini_model, gemini_temperature, numista_search,
-    ruler, orientation, series, commemorated_topic
+    ruler, orientation, series, commemorated_topic,
+    status, sale_channel, field_provenance, collection_id, mint_mark
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
     $12, $13, $14, $15, $16, $17, $18,
     $19, $20, $21, $22, $23,
     $24, $25, $26, $27, $28, $29,
     $30, $31, $32,
-    $33, $34, $35, $36
-) RETURNING id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at
+    $33, $34, $35, $36,
+    $37, $38, $39, $40, $41
+) RETURNING id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark
 `
 
 type CreateCoinParams struct {
@@ -77,6 +97,11 @@ type CreateCoinParams struct {
 	Orientation       string         `json:"orientation"`
 	Series            string         `json:"series"`
 	CommemoratedTopic string         `json:"commemorated_topic"`
+	Status            string         `json:"status"`
+	SaleChannel       pgtype.Text    `json:"sale_channel"`
+	FieldProvenance   []byte         `json:"field_provenance"`
+	CollectionID      pgtype.UUID    `json:"collection_id"`
+	MintMark          string         `json:"mint_mark"`
 }
 
 func (q *Queries) CreateCoin(ctx context.Context, arg CreateCoinParams) (Coin, error) {
@@ -117,6 +142,11 @@ func (q *Queries) CreateCoin(ctx context.Context, arg CreateCoinParams) (Coin, e
 		arg.Orientation,
 		arg.Series,
 		arg.CommemoratedTopic,
+		arg.Status,
+		arg.SaleChannel,
+		arg.FieldProvenance,
+		arg.CollectionID,
+		arg.MintMark,
 	)
 	var i Coin
 	err := row.Scan(
@@ -159,26 +189,38 @@ func (q *Queries) CreateCoin(ctx context.Context, arg CreateCoinParams) (Coin, e
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
 
 const deleteCoin = `-- name: DeleteCoin :exec
 DELETE FROM coins
-WHERE id = $1
+WHERE id = $1 AND collection_id = $2
 `
 
-func (q *Queries) DeleteCoin(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteCoin, id)
+type DeleteCoinParams struct {
+	ID           pgtype.UUID `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) DeleteCoin(ctx context.Context, arg DeleteCoinParams) error {
+	_, err := q.db.Exec(ctx, deleteCoin, arg.ID, arg.CollectionID)
 	return err
 }
 
 const getAllCoins = `-- name: GetAllCoins :many
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins
+WHERE collection_id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetAllCoins(ctx context.Context) ([]Coin, error) {
-	rows, err := q.db.Query(ctx, getAllCoins)
+func (q *Queries) GetAllCoins(ctx context.Context, collectionID pgtype.UUID) ([]Coin, error) {
+	rows, err := q.db.Query(ctx, getAllCoins, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -226,6 +268,12 @@ func (q *Queries) GetAllCoins(ctx context.Context) ([]Coin, error) {
 			&i.CommemoratedTopic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.FieldProvenance,
+			&i.DeletedAt,
+			&i.CollectionID,
+			&i.SearchVector,
+			&i.MintMark,
 		); err != nil {
 			return nil, err
 		}
@@ -238,11 +286,11 @@ func (q *Queries) GetAllCoins(ctx context.Context) ([]Coin, error) {
 }
 
 const getAllValues = `-- name: GetAllValues :many
-SELECT max_value FROM coins WHERE max_value IS NOT NULL
+SELECT max_value FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND max_value IS NOT NULL
 `
 
-func (q *Queries) GetAllValues(ctx context.Context) ([]pgtype.Numeric, error) {
-	rows, err := q.db.Query(ctx, getAllValues)
+func (q *Queries) GetAllValues(ctx context.Context, collectionID pgtype.UUID) ([]pgtype.Numeric, error) {
+	rows, err := q.db.Query(ctx, getAllValues, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -262,23 +310,28 @@ func (q *Queries) GetAllValues(ctx context.Context) ([]pgtype.Numeric, error) {
 }
 
 const getAverageValue = `-- name: GetAverageValue :one
-SELECT COALESCE(AVG(max_value), 0)::float8 FROM coins
+SELECT COALESCE(AVG(max_value), 0)::float8 FROM coins WHERE collection_id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetAverageValue(ctx context.Context) (float64, error) {
-	row := q.db.QueryRow(ctx, getAverageValue)
+func (q *Queries) GetAverageValue(ctx context.Context, collectionID pgtype.UUID) (float64, error) {
+	row := q.db.QueryRow(ctx, getAverageValue, collectionID)
 	var column_1 float64
 	err := row.Scan(&column_1)
 	return column_1, err
 }
 
 const getCoin = `-- name: GetCoin :one
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins
-WHERE id = $1 LIMIT 1
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins
+WHERE id = $1 AND collection_id = $2 LIMIT 1
 `
 
-func (q *Queries) GetCoin(ctx context.Context, id pgtype.UUID) (Coin, error) {
-	row := q.db.QueryRow(ctx, getCoin, id)
+type GetCoinParams struct {
+	ID           pgtype.UUID `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) GetCoin(ctx context.Context, arg GetCoinParams) (Coin, error) {
+	row := q.db.QueryRow(ctx, getCoin, arg.ID, arg.CollectionID)
 	var i Coin
 	err := row.Scan(
 		&i.ID,
@@ -320,12 +373,18 @@ func (q *Queries) GetCoin(ctx context.Context, id pgtype.UUID) (Coin, error) {
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
 
 const getCountryDistribution = `-- name: GetCountryDistribution :many
-SELECT country, COUNT(*) as count FROM coins GROUP BY country
+SELECT country, COUNT(*) as count FROM coins WHERE collection_id = $1 AND deleted_at IS NULL GROUP BY country
 `
 
 type GetCountryDistributionRow struct {
@@ -333,8 +392,8 @@ type GetCountryDistributionRow struct {
 	Count   int64       `json:"count"`
 }
 
-func (q *Queries) GetCountryDistribution(ctx context.Context) ([]GetCountryDistributionRow, error) {
-	rows, err := q.db.Query(ctx, getCountryDistribution)
+func (q *Queries) GetCountryDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetCountryDistributionRow, error) {
+	rows, err := q.db.Query(ctx, getCountryDistribution, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -356,7 +415,7 @@ func (q *Queries) GetCountryDistribution(ctx context.Context) ([]GetCountryDistr
 const getGradeDistribution = `-- name: GetGradeDistribution :many
 SELECT grade, COUNT(*) as count
 FROM coins
-WHERE grade IS NOT NULL
+WHERE collection_id = $1 AND deleted_at IS NULL AND grade IS NOT NULL
 GROUP BY grade
 ORDER BY count DESC
 `
@@ -366,8 +425,8 @@ type GetGradeDistributionRow struct {
 	Count int64       `json:"count"`
 }
 
-func (q *Queries) GetGradeDistribution(ctx context.Context) ([]GetGradeDistributionRow, error) {
-	rows, err := q.db.Query(ctx, getGradeDistribution)
+func (q *Queries) GetGradeDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetGradeDistributionRow, error) {
+	rows, err := q.db.Query(ctx, getGradeDistribution, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -390,6 +449,7 @@ const getGroupDistribution = `-- name: GetGroupDistribution :many
 SELECT COALESCE(g.name, 'Uncategorized') as group_name, COUNT(c.id) as count 
 FROM coins c 
 LEFT JOIN groups g ON c.group_id = g.id 
+WHERE c.collection_id = $1 AND c.deleted_at IS NULL
 GROUP BY g.name
 `
 
@@ -398,8 +458,8 @@ type GetGroupDistributionRow struct {
 	Count     int64  `json:"count"`
 }
 
-func (q *Queries) GetGroupDistribution(ctx context.Context) ([]GetGroupDistributionRow, error) {
-	rows, err := q.db.Query(ctx, getGroupDistribution)
+func (q *Queries) GetGroupDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetGroupDistributionRow, error) {
+	rows, err := q.db.Query(ctx, getGroupDistribution, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -419,36 +479,47 @@ func (q *Queries) GetGroupDistribution(ctx context.Context) ([]GetGroupDistribut
 }
 
 const getGroupStats = `-- name: GetGroupStats :many
+WITH RECURSIVE subgroups AS (
+    SELECT id AS root_id, id AS group_id FROM groups WHERE groups.collection_id = $1
+    UNION
+    SELECT s.root_id, g.id FROM subgroups s
+    JOIN groups g ON g.parent_id = s.group_id
+)
 SELECT 
     g.id as group_id, 
     COALESCE(g.name, 'Uncategorized') as group_name, 
+    g.parent_id,
     COUNT(c.id) as count,
     COALESCE(MIN(c.min_value), 0)::float8 as min_val,
-    COALESCE(MIN(c.min_value), 0)::float8 as min_val,
     COALESCE(MAX(c.max_value), 0)::float8 as max_val,
     COALESCE(AVG(c.max_value), 0)::float8 as avg_val,
     COALESCE(MIN(NULLIF(c.year, 0)), 0)::int as min_year,
     COALESCE(MAX(NULLIF(c.year, 0)), 0)::int as max_year
 FROM coins c 
-LEFT JOIN groups g ON c.group_id = g.id 
-GROUP BY g.id, g.name
+LEFT JOIN subgroups s ON s.group_id = c.group_id
+LEFT JOIN groups g ON g.id = s.root_id
+WHERE c.collection_id = $1 AND c.deleted_at IS NULL
+GROUP BY g.id, g.name, g.parent_id
 ORDER BY count DESC
 `
 
 type GetGroupStatsRow struct {
 	GroupID   pgtype.Int4 `json:"group_id"`
 	GroupName string      `json:"group_name"`
+	ParentID  pgtype.Int4 `json:"parent_id"`
 	Count     int64       `json:"count"`
 	MinVal    float64     `json:"min_val"`
-	MinVal_2  float64     `json:"min_val_2"`
 	MaxVal    float64     `json:"max_val"`
 	AvgVal    float64     `json:"avg_val"`
 	MinYear   int32       `json:"min_year"`
 	MaxYear   int32       `json:"max_year"`
 }
 
-func (q *Queries) GetGroupStats(ctx context.Context) ([]GetGroupStatsRow, error) {
-	rows, err := q.db.Query(ctx, getGroupStats)
+// Each group counts the coins of its subgroups too, at any depth. Coins
+// without a group make the Uncategorized row. UNION stops at pairs already
+// seen, so a cycle in parent_id cannot make it loop
+func (q *Queries) GetGroupStats(ctx context.Context, collectionID pgtype.UUID) ([]GetGroupStatsRow, error) {
+	rows, err := q.db.Query(ctx, getGroupStats, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -459,9 +530,9 @@ func (q *Queries) GetGroupStats(ctx context.Context) ([]GetGroupStatsRow, error)
 		if err := rows.Scan(
 			&i.GroupID,
 			&i.GroupName,
+			&i.ParentID,
 			&i.Count,
 			&i.MinVal,
-			&i.MinVal_2,
 			&i.MaxVal,
 			&i.AvgVal,
 			&i.MinYear,
@@ -478,11 +549,11 @@ func (q *Queries) GetGroupStats(ctx context.Context) ([]GetGroupStatsRow, error)
 }
 
 const getHeaviestCoin = `-- name: GetHeaviestCoin :one
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins WHERE weight_g > 0 ORDER BY weight_g DESC LIMIT 1
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND weight_g > 0 ORDER BY weight_g DESC LIMIT 1
 `
 
-func (q *Queries) GetHeaviestCoin(ctx context.Context) (Coin, error) {
-	row := q.db.QueryRow(ctx, getHeaviestCoin)
+func (q *Queries) GetHeaviestCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error) {
+	row := q.db.QueryRow(ctx, getHeaviestCoin, collectionID)
 	var i Coin
 	err := row.Scan(
 		&i.ID,
@@ -524,6 +595,12 @@ func (q *Queries) GetHeaviestCoin(ctx context.Context) (Coin, error) {
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
@@ -531,7 +608,7 @@ func (q *Queries) GetHeaviestCoin(ctx context.Context) (Coin, error) {
 const getMaterialDistribution = `-- name: GetMaterialDistribution :many
 SELECT material, COUNT(*) as count
 FROM coins
-WHERE material IS NOT NULL AND material != ''
+WHERE collection_id = $1 AND deleted_at IS NULL AND material IS NOT NULL AND material != ''
 GROUP BY material
 ORDER BY count DESC
 `
@@ -541,8 +618,8 @@ type GetMaterialDistributionRow struct {
 	Count    int64       `json:"count"`
 }
 
-func (q *Queries) GetMaterialDistribution(ctx context.Context) ([]GetMaterialDistributionRow, error) {
-	rows, err := q.db.Query(ctx, getMaterialDistribution)
+func (q *Queries) GetMaterialDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetMaterialDistributionRow, error) {
+	rows, err := q.db.Query(ctx, getMaterialDistribution, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -562,11 +639,11 @@ func (q *Queries) GetMaterialDistribution(ctx context.Context) ([]GetMaterialDis
 }
 
 const getOldestCoin = `-- name: GetOldestCoin :one
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins WHERE year > 0 ORDER BY year ASC LIMIT 1
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND year > 0 ORDER BY year ASC LIMIT 1
 `
 
-func (q *Queries) GetOldestCoin(ctx context.Context) (Coin, error) {
-	row := q.db.QueryRow(ctx, getOldestCoin)
+func (q *Queries) GetOldestCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error) {
+	row := q.db.QueryRow(ctx, getOldestCoin, collectionID)
 	var i Coin
 	err := row.Scan(
 		&i.ID,
@@ -608,16 +685,22 @@ func (q *Queries) GetOldestCoin(ctx context.Context) (Coin, error) {
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
 
 const getRandomCoin = `-- name: GetRandomCoin :one
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins ORDER BY RANDOM() LIMIT 1
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins WHERE collection_id = $1 AND deleted_at IS NULL ORDER BY RANDOM() LIMIT 1
 `
 
-func (q *Queries) GetRandomCoin(ctx context.Context) (Coin, error) {
-	row := q.db.QueryRow(ctx, getRandomCoin)
+func (q *Queries) GetRandomCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error) {
+	row := q.db.QueryRow(ctx, getRandomCoin, collectionID)
 	var i Coin
 	err := row.Scan(
 		&i.ID,
@@ -659,16 +742,27 @@ func (q *Queries) GetRandomCoin(ctx context.Context) (Coin, error) {
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
 
 const getRarestCoins = `-- name: GetRarestCoins :many
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins WHERE mintage > 0 ORDER BY mintage ASC LIMIT $1
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND mintage > 0 ORDER BY mintage ASC LIMIT $2
 `
 
-func (q *Queries) GetRarestCoins(ctx context.Context, limit int32) ([]Coin, error) {
-	rows, err := q.db.Query(ctx, getRarestCoins, limit)
+type GetRarestCoinsParams struct {
+	CollectionID pgtype.UUID `json:"collection_id"`
+	Limit        int32       `json:"limit"`
+}
+
+func (q *Queries) GetRarestCoins(ctx context.Context, arg GetRarestCoinsParams) ([]Coin, error) {
+	rows, err := q.db.Query(ctx, getRarestCoins, arg.CollectionID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -716,6 +810,12 @@ func (q *Queries) GetRarestCoins(ctx context.Context, limit int32) ([]Coin, erro
 			&i.CommemoratedTopic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.FieldProvenance,
+			&i.DeletedAt,
+			&i.CollectionID,
+			&i.SearchVector,
+			&i.MintMark,
 		); err != nil {
 			return nil, err
 		}
@@ -728,11 +828,11 @@ func (q *Queries) GetRarestCoins(ctx context.Context, limit int32) ([]Coin, erro
 }
 
 const getSmallestCoin = `-- name: GetSmallestCoin :one
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins WHERE diameter_mm > 0 ORDER BY diameter_mm ASC LIMIT 1
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND diameter_mm > 0 ORDER BY diameter_mm ASC LIMIT 1
 `
 
-func (q *Queries) GetSmallestCoin(ctx context.Context) (Coin, error) {
-	row := q.db.QueryRow(ctx, getSmallestCoin)
+func (q *Queries) GetSmallestCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error) {
+	row := q.db.QueryRow(ctx, getSmallestCoin, collectionID)
 	var i Coin
 	err := row.Scan(
 		&i.ID,
@@ -774,172 +874,52 @@ func (q *Queries) GetSmallestCoin(ctx context.Context) (Coin, error) {
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
 
 const getTotalValue = `-- name: GetTotalValue :one
-SELECT COALESCE(SUM(max_value), 0)::float8 FROM coins
+SELECT COALESCE(SUM(max_value), 0)::float8 FROM coins WHERE collection_id = $1 AND deleted_at IS NULL
 `
 
-func (q *Queries) GetTotalValue(ctx context.Context) (float64, error) {
-	row := q.db.QueryRow(ctx, getTotalValue)
+func (q *Queries) GetTotalValue(ctx context.Context, collectionID pgtype.UUID) (float64, error) {
+	row := q.db.QueryRow(ctx, getTotalValue, collectionID)
 	var column_1 float64
 	err := row.Scan(&column_1)
 	return column_1, err
 }
 
 const getTotalWeightByMaterial = `-- name: GetTotalWeightByMaterial :one
-SELECT COALESCE(SUM(weight_g), 0)::float8 FROM coins WHERE material ILIKE $1
+SELECT COALESCE(SUM(weight_g), 0)::float8 FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND material ILIKE $2
 `
 
-func (q *Queries) GetTotalWeightByMaterial(ctx context.Context, material pgtype.Text) (float64, error) {
-	row := q.db.QueryRow(ctx, getTotalWeightByMaterial, material)
+type GetTotalWeightByMaterialParams struct {
+	CollectionID pgtype.UUID `json:"collection_id"`
+	Material     pgtype.Text `json:"material"`
+}
+
+func (q *Queries) GetTotalWeightByMaterial(ctx context.Context, arg GetTotalWeightByMaterialParams) (float64, error) {
+	row := q.db.QueryRow(ctx, getTotalWeightByMaterial, arg.CollectionID, arg.Material)
 	var column_1 float64
 	err := row.Scan(&column_1)
 	return column_1, err
 }
 
-const listCoins = `-- name: ListCoins :many
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins
-WHERE 
-    ($3::int IS NULL OR group_id = $3)
-    AND ($4::int IS NULL OR year = $4)
-    AND ($5::text IS NULL OR country ILIKE $5)
-    AND ($6::text IS NULL OR 
-        name ILIKE '%' || $6 || '%' OR 
-        description ILIKE '%' || $6 || '%' OR
-        km_code ILIKE '%' || $6 || '%'
-    )
-    AND ($7::float8 IS NULL OR min_value >= $7::float8)
-    AND ($8::float8 IS NULL OR max_value <= $8::float8)
-    AND ($9::text IS NULL OR grade = $9)
-    AND ($10::text IS NULL OR material = $10)
-    AND ($11::int IS NULL OR year >= $11)
-    AND ($12::int IS NULL OR year <= $12)
-ORDER BY
-    CASE WHEN $13::text = 'year' AND $14::text = 'asc' THEN year END ASC,
-    CASE WHEN $13::text = 'year' AND $14::text = 'desc' THEN year END DESC,
-    
-    CASE WHEN $13::text = 'min_value' AND $14::text = 'asc' THEN min_value END ASC,
-    CASE WHEN $13::text = 'min_value' AND $14::text = 'desc' THEN min_value END DESC,
-    
-    CASE WHEN $13::text = 'max_value' AND $14::text = 'asc' THEN max_value END ASC,
-    CASE WHEN $13::text = 'max_value' AND $14::text = 'desc' THEN max_value END DESC,
-    
-    CASE WHEN $13::text = 'created_at' AND $14::text = 'asc' THEN created_at END ASC,
-    CASE WHEN $13::text = 'created_at' AND $14::text = 'desc' THEN created_at END DESC,
-    
-    CASE WHEN $13::text = 'country' AND $14::text = 'asc' THEN country END ASC,
-    CASE WHEN $13::text = 'country' AND $14::text = 'desc' THEN country END DESC,
-    
-    CASE WHEN $13::text = 'name' AND $14::text = 'asc' THEN name END ASC,
-    CASE WHEN $13::text = 'name' AND $14::text = 'desc' THEN name END DESC,
-    
-    created_at DESC
-LIMIT $1 OFFSET $2
-`
-
-type ListCoinsParams struct {
-	Limit     int32         `json:"limit"`
-	Offset    int32         `json:"offset"`
-	GroupID   pgtype.Int4   `json:"group_id"`
-	Year      pgtype.Int4   `json:"year"`
-	Country   pgtype.Text   `json:"country"`
-	Query     pgtype.Text   `json:"query"`
-	MinPrice  pgtype.Float8 `json:"min_price"`
-	MaxPrice  pgtype.Float8 `json:"max_price"`
-	Grade     pgtype.Text   `json:"grade"`
-	Material  pgtype.Text   `json:"material"`
-	MinYear   pgtype.Int4   `json:"min_year"`
-	MaxYear   pgtype.Int4   `json:"max_year"`
-	SortBy    pgtype.Text   `json:"sort_by"`
-	SortOrder pgtype.Text   `json:"sort_order"`
-}
-
-func (q *Queries) ListCoins(ctx context.Context, arg ListCoinsParams) ([]Coin, error) {
-	rows, err := q.db.Query(ctx, listCoins,
-		arg.Limit,
-		arg.Offset,
-		arg.GroupID,
-		arg.Year,
-		arg.Country,
-		arg.Query,
-		arg.MinPrice,
-		arg.MaxPrice,
-		arg.Grade,
-		arg.Material,
-		arg.MinYear,
-		arg.MaxYear,
-		arg.SortBy,
-		arg.SortOrder,
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Coin
-	for rows.Next() {
-		var i Coin
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Mint,
-			&i.Mintage,
-			&i.Country,
-			&i.Year,
-			&i.FaceValue,
-			&i.Currency,
-			&i.Material,
-			&i.Description,
-			&i.KmCode,
-			&i.MinValue,
-			&i.MaxValue,
-			&i.Grade,
-			&i.TechnicalNotes,
-			&i.GeminiDetails,
-			&i.NumistaDetails,
-			&i.GroupID,
-			&i.PersonalNotes,
-			&i.WeightG,
-			&i.DiameterMm,
-			&i.ThicknessMm,
-			&i.Edge,
-			&i.Shape,
-			&i.NumistaNumber,
-			&i.AcquiredAt,
-			&i.SoldAt,
-			&i.PricePaid,
-			&i.SoldPrice,
-			&i.SaleChannel,
-			&i.GeminiModel,
-			&i.GeminiTemperature,
-			&i.NumistaSearch,
-			&i.Ruler,
-			&i.Orientation,
-			&i.Series,
-			&i.CommemoratedTopic,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const listRecentCoins = `-- name: ListRecentCoins :many
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins
+WHERE collection_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT 5
 `
 
-func (q *Queries) ListRecentCoins(ctx context.Context) ([]Coin, error) {
-	rows, err := q.db.Query(ctx, listRecentCoins)
+func (q *Queries) ListRecentCoins(ctx context.Context, collectionID pgtype.UUID) ([]Coin, error) {
+	rows, err := q.db.Query(ctx, listRecentCoins, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -987,6 +967,12 @@ func (q *Queries) ListRecentCoins(ctx context.Context) ([]Coin, error) {
 			&i.CommemoratedTopic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.FieldProvenance,
+			&i.DeletedAt,
+			&i.CollectionID,
+			&i.SearchVector,
+			&i.MintMark,
 		); err != nil {
 			return nil, err
 		}
@@ -999,13 +985,14 @@ func (q *Queries) ListRecentCoins(ctx context.Context) ([]Coin, error) {
 }
 
 const listTopValuableCoins = `-- name: ListTopValuableCoins :many
-SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at FROM coins
+SELECT id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark FROM coins
+WHERE collection_id = $1 AND deleted_at IS NULL
 ORDER BY max_value DESC
 LIMIT 5
 `
 
-func (q *Queries) ListTopValuableCoins(ctx context.Context) ([]Coin, error) {
-	rows, err := q.db.Query(ctx, listTopValuableCoins)
+func (q *Queries) ListTopValuableCoins(ctx context.Context, collectionID pgtype.UUID) ([]Coin, error) {
+	rows, err := q.db.Query(ctx, listTopValuableCoins, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -1053,6 +1040,12 @@ func (q *Queries) ListTopValuableCoins(ctx context.Context) ([]Coin, error) {
 			&i.CommemoratedTopic,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.FieldProvenance,
+			&i.DeletedAt,
+			&i.CollectionID,
+			&i.SearchVector,
+			&i.MintMark,
 		); err != nil {
 			return nil, err
 		}
@@ -1102,9 +1095,13 @@ SET
     orientation = $34,
     series = $35,
     commemorated_topic = $36,
+    status = $37,
+    sale_channel = $38,
+    field_provenance = $39,
+    mint_mark = $41,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at
+WHERE id = $1 AND collection_id = $40
+RETURNING id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark
 `
 
 type UpdateCoinParams struct {
@@ -1144,6 +1141,11 @@ type UpdateCoinParams struct {
 	Orientation       string         `json:"orientation"`
 	Series            string         `json:"series"`
 	CommemoratedTopic string         `json:"commemorated_topic"`
+	Status            string         `json:"status"`
+	SaleChannel       pgtype.Text    `json:"sale_channel"`
+	FieldProvenance   []byte         `json:"field_provenance"`
+	CollectionID      pgtype.UUID    `json:"collection_id"`
+	MintMark          string         `json:"mint_mark"`
 }
 
 func (q *Queries) UpdateCoin(ctx context.Context, arg UpdateCoinParams) (Coin, error) {
@@ -1184,6 +1186,11 @@ func (q *Queries) UpdateCoin(ctx context.Context, arg UpdateCoinParams) (Coin, e
 		arg.Orientation,
 		arg.Series,
 		arg.CommemoratedTopic,
+		arg.Status,
+		arg.SaleChannel,
+		arg.FieldProvenance,
+		arg.CollectionID,
+		arg.MintMark,
 	)
 	var i Coin
 	err := row.Scan(
@@ -1226,6 +1233,29 @@ func (q *Queries) UpdateCoin(ctx context.Context, arg UpdateCoinParams) (Coin, e
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
+
+const updateCoinStatus = `-- name: UpdateCoinStatus :exec
+UPDATE coins
+SET status = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND collection_id = $3
+`
+
+type UpdateCoinStatusParams struct {
+	ID           pgtype.UUID `json:"id"`
+	Status       string      `json:"status"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) UpdateCoinStatus(ctx context.Context, arg UpdateCoinStatusParams) error {
+	_, err := q.db.Exec(ctx, updateCoinStatus, arg.ID, arg.Status, arg.CollectionID)
+	return err
+}
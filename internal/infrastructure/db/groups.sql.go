@@ -12,62 +12,146 @@ import (
 )
 
 const createGroup = `-- name: CreateGroup :one
-INSERT INTO groups (name, description)
-VALUES ($1, $2)
-RETURNING id, name, description, created_at
+INSERT INTO groups (name, description, collection_id, smart_filter)
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, description, created_at, collection_id, smart_filter, parent_id
 `
 
 type CreateGroupParams struct {
-	Name        string      `json:"name"`
-	Description pgtype.Text `json:"description"`
+	Name         string      `json:"name"`
+	Description  pgtype.Text `json:"description"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+	SmartFilter  []byte      `json:"smart_filter"`
 }
 
 func (q *Queries) CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error) {
-	row := q.db.QueryRow(ctx, createGroup, arg.Name, arg.Description)
+	row := q.db.QueryRow(ctx, createGroup,
+		arg.Name,
+		arg.Description,
+		arg.CollectionID,
+		arg.SmartFilter,
+	)
 	var i Group
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Description,
 		&i.CreatedAt,
+		&i.CollectionID,
+		&i.SmartFilter,
+		&i.ParentID,
 	)
 	return i, err
 }
 
 const deleteGroup = `-- name: DeleteGroup :exec
 DELETE FROM groups
-WHERE id = $1
+WHERE id = $1 AND collection_id = $2
 `
 
-func (q *Queries) DeleteGroup(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteGroup, id)
+type DeleteGroupParams struct {
+	ID           int32       `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) DeleteGroup(ctx context.Context, arg DeleteGroupParams) error {
+	_, err := q.db.Exec(ctx, deleteGroup, arg.ID, arg.CollectionID)
 	return err
 }
 
+const getGroup = `-- name: GetGroup :one
+SELECT id, name, description, created_at, collection_id, smart_filter, parent_id FROM groups
+WHERE id = $1 AND collection_id = $2
+`
+
+type GetGroupParams struct {
+	ID           int32       `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) GetGroup(ctx context.Context, arg GetGroupParams) (Group, error) {
+	row := q.db.QueryRow(ctx, getGroup, arg.ID, arg.CollectionID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.CollectionID,
+		&i.SmartFilter,
+		&i.ParentID,
+	)
+	return i, err
+}
+
 const getGroupByName = `-- name: GetGroupByName :one
-SELECT id, name, description, created_at FROM groups
-WHERE name = $1
+SELECT id, name, description, created_at, collection_id, smart_filter, parent_id FROM groups
+WHERE name = $1 AND collection_id = $2
 `
 
-func (q *Queries) GetGroupByName(ctx context.Context, name string) (Group, error) {
-	row := q.db.QueryRow(ctx, getGroupByName, name)
+type GetGroupByNameParams struct {
+	Name         string      `json:"name"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) GetGroupByName(ctx context.Context, arg GetGroupByNameParams) (Group, error) {
+	row := q.db.QueryRow(ctx, getGroupByName, arg.Name, arg.CollectionID)
 	var i Group
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Description,
 		&i.CreatedAt,
+		&i.CollectionID,
+		&i.SmartFilter,
+		&i.ParentID,
 	)
 	return i, err
 }
 
+const groupExists = `-- name: GroupExists :one
+SELECT EXISTS (
+    SELECT 1 FROM groups WHERE id = $1 AND collection_id = $2
+)
+`
+
+type GroupExistsParams struct {
+	ID           int32       `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) GroupExists(ctx context.Context, arg GroupExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, groupExists, arg.ID, arg.CollectionID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const liftSubgroups = `-- name: LiftSubgroups :exec
+UPDATE groups
+SET parent_id = (SELECT p.parent_id FROM groups p WHERE p.id = $1 AND p.collection_id = $2)
+WHERE parent_id = $1 AND collection_id = $2
+`
+
+type LiftSubgroupsParams struct {
+	ID           int32       `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+// Moves the subgroups of a group up to its own parent
+func (q *Queries) LiftSubgroups(ctx context.Context, arg LiftSubgroupsParams) error {
+	_, err := q.db.Exec(ctx, liftSubgroups, arg.ID, arg.CollectionID)
+	return err
+}
+
 const listGroups = `-- name: ListGroups :many
-SELECT id, name, description, created_at FROM groups
+SELECT id, name, description, created_at, collection_id, smart_filter, parent_id FROM groups
+WHERE collection_id = $1
 ORDER BY name
 `
 
-func (q *Queries) ListGroups(ctx context.Context) ([]Group, error) {
-	rows, err := q.db.Query(ctx, listGroups)
+func (q *Queries) ListGroups(ctx context.Context, collectionID pgtype.UUID) ([]Group, error) {
+	rows, err := q.db.Query(ctx, listGroups, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +164,9 @@ func (q *Queries) ListGroups(ctx context.Context) ([]Group, error) {
 			&i.Name,
 			&i.Description,
 			&i.CreatedAt,
+			&i.CollectionID,
+			&i.SmartFilter,
+			&i.ParentID,
 		); err != nil {
 			return nil, err
 		}
@@ -91,27 +178,89 @@ func (q *Queries) ListGroups(ctx context.Context) ([]Group, error) {
 	return items, nil
 }
 
+const lockGroupParents = `-- name: LockGroupParents :many
+SELECT id, parent_id FROM groups
+WHERE collection_id = $1
+ORDER BY id
+FOR UPDATE
+`
+
+type LockGroupParentsRow struct {
+	ID       int32       `json:"id"`
+	ParentID pgtype.Int4 `json:"parent_id"`
+}
+
+// Locks the groups of a collection, so that moves check for cycles one at a
+// time
+func (q *Queries) LockGroupParents(ctx context.Context, collectionID pgtype.UUID) ([]LockGroupParentsRow, error) {
+	rows, err := q.db.Query(ctx, lockGroupParents, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockGroupParentsRow
+	for rows.Next() {
+		var i LockGroupParentsRow
+		if err := rows.Scan(&i.ID, &i.ParentID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveGroup = `-- name: MoveGroup :exec
+UPDATE groups
+SET parent_id = $2
+WHERE id = $1 AND collection_id = $3
+`
+
+type MoveGroupParams struct {
+	ID           int32       `json:"id"`
+	ParentID     pgtype.Int4 `json:"parent_id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) MoveGroup(ctx context.Context, arg MoveGroupParams) error {
+	_, err := q.db.Exec(ctx, moveGroup, arg.ID, arg.ParentID, arg.CollectionID)
+	return err
+}
+
 const updateGroup = `-- name: UpdateGroup :one
 UPDATE groups
-SET name = $2, description = $3
-WHERE id = $1
-RETURNING id, name, description, created_at
+SET name = $2, description = $3, smart_filter = $5
+WHERE id = $1 AND collection_id = $4
+RETURNING id, name, description, created_at, collection_id, smart_filter, parent_id
 `
 
 type UpdateGroupParams struct {
-	ID          int32       `json:"id"`
-	Name        string      `json:"name"`
-	Description pgtype.Text `json:"description"`
+	ID           int32       `json:"id"`
+	Name         string      `json:"name"`
+	Description  pgtype.Text `json:"description"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+	SmartFilter  []byte      `json:"smart_filter"`
 }
 
 func (q *Queries) UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error) {
-	row := q.db.QueryRow(ctx, updateGroup, arg.ID, arg.Name, arg.Description)
+	row := q.db.QueryRow(ctx, updateGroup,
+		arg.ID,
+		arg.Name,
+		arg.Description,
+		arg.CollectionID,
+		arg.SmartFilter,
+	)
 	var i Group
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Description,
 		&i.CreatedAt,
+		&i.CollectionID,
+		&i.SmartFilter,
+		&i.ParentID,
 	)
 	return i, err
 }
@@ -12,24 +12,26 @@ import (
 )
 
 const createCoinGalleryImage = `-- name: CreateCoinGalleryImage :one
-INSERT INTO coin_gallery_images (coin_id, path)
-VALUES ($1, $2)
-RETURNING id, coin_id, path, created_at
+INSERT INTO coin_gallery_images (coin_id, path, collection_id)
+VALUES ($1, $2, $3)
+RETURNING id, coin_id, path, created_at, collection_id
 `
 
 type CreateCoinGalleryImageParams struct {
-	CoinID pgtype.UUID `json:"coin_id"`
-	Path   string      `json:"path"`
+	CoinID       pgtype.UUID `json:"coin_id"`
+	Path         string      `json:"path"`
+	CollectionID pgtype.UUID `json:"collection_id"`
 }
 
 func (q *Queries) CreateCoinGalleryImage(ctx context.Context, arg CreateCoinGalleryImageParams) (CoinGalleryImage, error) {
-	row := q.db.QueryRow(ctx, createCoinGalleryImage, arg.CoinID, arg.Path)
+	row := q.db.QueryRow(ctx, createCoinGalleryImage, arg.CoinID, arg.Path, arg.CollectionID)
 	var i CoinGalleryImage
 	err := row.Scan(
 		&i.ID,
 		&i.CoinID,
 		&i.Path,
 		&i.CreatedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
@@ -45,10 +47,11 @@ INSERT INTO coin_images (
     width,
     height,
     mime_type,
-    original_filename
+    original_filename,
+    collection_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
-) RETURNING id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at, collection_id
 `
 
 type CreateCoinImageParams struct {
@@ -62,6 +65,7 @@ type CreateCoinImageParams struct {
 	Height           int32       `json:"height"`
 	MimeType         string      `json:"mime_type"`
 	OriginalFilename pgtype.Text `json:"original_filename"`
+	CollectionID     pgtype.UUID `json:"collection_id"`
 }
 
 func (q *Queries) CreateCoinImage(ctx context.Context, arg CreateCoinImageParams) (CoinImage, error) {
@@ -76,6 +80,7 @@ func (q *Queries) CreateCoinImage(ctx context.Context, arg CreateCoinImageParams
 		arg.Height,
 		arg.MimeType,
 		arg.OriginalFilename,
+		arg.CollectionID,
 	)
 	var i CoinImage
 	err := row.Scan(
@@ -92,61 +97,79 @@ func (q *Queries) CreateCoinImage(ctx context.Context, arg CreateCoinImageParams
 		&i.OriginalFilename,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
 const createGroupImage = `-- name: CreateGroupImage :one
-INSERT INTO group_images (group_id, path)
-VALUES ($1, $2)
-RETURNING id, group_id, path, created_at
+INSERT INTO group_images (group_id, path, collection_id)
+VALUES ($1, $2, $3)
+RETURNING id, group_id, path, created_at, collection_id
 `
 
 type CreateGroupImageParams struct {
-	GroupID int32  `json:"group_id"`
-	Path    string `json:"path"`
+	GroupID      int32       `json:"group_id"`
+	Path         string      `json:"path"`
+	CollectionID pgtype.UUID `json:"collection_id"`
 }
 
 func (q *Queries) CreateGroupImage(ctx context.Context, arg CreateGroupImageParams) (GroupImage, error) {
-	row := q.db.QueryRow(ctx, createGroupImage, arg.GroupID, arg.Path)
+	row := q.db.QueryRow(ctx, createGroupImage, arg.GroupID, arg.Path, arg.CollectionID)
 	var i GroupImage
 	err := row.Scan(
 		&i.ID,
 		&i.GroupID,
 		&i.Path,
 		&i.CreatedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
 const deleteCoinGalleryImage = `-- name: DeleteCoinGalleryImage :exec
 DELETE FROM coin_gallery_images
-WHERE id = $1
+WHERE id = $1 AND collection_id = $2
 `
 
-func (q *Queries) DeleteCoinGalleryImage(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteCoinGalleryImage, id)
+type DeleteCoinGalleryImageParams struct {
+	ID           pgtype.UUID `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) DeleteCoinGalleryImage(ctx context.Context, arg DeleteCoinGalleryImageParams) error {
+	_, err := q.db.Exec(ctx, deleteCoinGalleryImage, arg.ID, arg.CollectionID)
 	return err
 }
 
 const deleteGroupImage = `-- name: DeleteGroupImage :exec
 DELETE FROM group_images
-WHERE id = $1
+WHERE id = $1 AND collection_id = $2
 `
 
-func (q *Queries) DeleteGroupImage(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteGroupImage, id)
+type DeleteGroupImageParams struct {
+	ID           pgtype.UUID `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) DeleteGroupImage(ctx context.Context, arg DeleteGroupImageParams) error {
+	_, err := q.db.Exec(ctx, deleteGroupImage, arg.ID, arg.CollectionID)
 	return err
 }
 
 const listCoinGalleryImages = `-- name: ListCoinGalleryImages :many
-SELECT id, coin_id, path, created_at FROM coin_gallery_images
-WHERE coin_id = $1
+SELECT id, coin_id, path, created_at, collection_id FROM coin_gallery_images
+WHERE coin_id = $1 AND collection_id = $2
 ORDER BY created_at ASC
 `
 
-func (q *Queries) ListCoinGalleryImages(ctx context.Context, coinID pgtype.UUID) ([]CoinGalleryImage, error) {
-	rows, err := q.db.Query(ctx, listCoinGalleryImages, coinID)
+type ListCoinGalleryImagesParams struct {
+	CoinID       pgtype.UUID `json:"coin_id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) ListCoinGalleryImages(ctx context.Context, arg ListCoinGalleryImagesParams) ([]CoinGalleryImage, error) {
+	rows, err := q.db.Query(ctx, listCoinGalleryImages, arg.CoinID, arg.CollectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -159,6 +182,7 @@ func (q *Queries) ListCoinGalleryImages(ctx context.Context, coinID pgtype.UUID)
 			&i.CoinID,
 			&i.Path,
 			&i.CreatedAt,
+			&i.CollectionID,
 		); err != nil {
 			return nil, err
 		}
@@ -171,13 +195,18 @@ func (q *Queries) ListCoinGalleryImages(ctx context.Context, coinID pgtype.UUID)
 }
 
 const listCoinImagesByCoinID = `-- name: ListCoinImagesByCoinID :many
-SELECT id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at FROM coin_images
-WHERE coin_id = $1
+SELECT id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at, collection_id FROM coin_images
+WHERE coin_id = $1 AND collection_id = $2
 ORDER BY created_at ASC
 `
 
-func (q *Queries) ListCoinImagesByCoinID(ctx context.Context, coinID pgtype.UUID) ([]CoinImage, error) {
-	rows, err := q.db.Query(ctx, listCoinImagesByCoinID, coinID)
+type ListCoinImagesByCoinIDParams struct {
+	CoinID       pgtype.UUID `json:"coin_id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) ListCoinImagesByCoinID(ctx context.Context, arg ListCoinImagesByCoinIDParams) ([]CoinImage, error) {
+	rows, err := q.db.Query(ctx, listCoinImagesByCoinID, arg.CoinID, arg.CollectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +228,7 @@ func (q *Queries) ListCoinImagesByCoinID(ctx context.Context, coinID pgtype.UUID
 			&i.OriginalFilename,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CollectionID,
 		); err != nil {
 			return nil, err
 		}
@@ -211,13 +241,18 @@ func (q *Queries) ListCoinImagesByCoinID(ctx context.Context, coinID pgtype.UUID
 }
 
 const listCoinImagesByCoinIDs = `-- name: ListCoinImagesByCoinIDs :many
-SELECT id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at FROM coin_images
-WHERE coin_id = ANY($1::uuid[])
+SELECT id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at, collection_id FROM coin_images
+WHERE coin_id = ANY($1::uuid[]) AND collection_id = $2
 ORDER BY coin_id, created_at ASC
 `
 
-func (q *Queries) ListCoinImagesByCoinIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]CoinImage, error) {
-	rows, err := q.db.Query(ctx, listCoinImagesByCoinIDs, dollar_1)
+type ListCoinImagesByCoinIDsParams struct {
+	Column1      []pgtype.UUID `json:"column_1"`
+	CollectionID pgtype.UUID   `json:"collection_id"`
+}
+
+func (q *Queries) ListCoinImagesByCoinIDs(ctx context.Context, arg ListCoinImagesByCoinIDsParams) ([]CoinImage, error) {
+	rows, err := q.db.Query(ctx, listCoinImagesByCoinIDs, arg.Column1, arg.CollectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +274,7 @@ func (q *Queries) ListCoinImagesByCoinIDs(ctx context.Context, dollar_1 []pgtype
 			&i.OriginalFilename,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CollectionID,
 		); err != nil {
 			return nil, err
 		}
@@ -251,13 +287,18 @@ func (q *Queries) ListCoinImagesByCoinIDs(ctx context.Context, dollar_1 []pgtype
 }
 
 const listGroupImages = `-- name: ListGroupImages :many
-SELECT id, group_id, path, created_at FROM group_images
-WHERE group_id = $1
+SELECT id, group_id, path, created_at, collection_id FROM group_images
+WHERE group_id = $1 AND collection_id = $2
 ORDER BY created_at ASC
 `
 
-func (q *Queries) ListGroupImages(ctx context.Context, groupID int32) ([]GroupImage, error) {
-	rows, err := q.db.Query(ctx, listGroupImages, groupID)
+type ListGroupImagesParams struct {
+	GroupID      int32       `json:"group_id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) ListGroupImages(ctx context.Context, arg ListGroupImagesParams) ([]GroupImage, error) {
+	rows, err := q.db.Query(ctx, listGroupImages, arg.GroupID, arg.CollectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +311,7 @@ func (q *Queries) ListGroupImages(ctx context.Context, groupID int32) ([]GroupIm
 			&i.GroupID,
 			&i.Path,
 			&i.CreatedAt,
+			&i.CollectionID,
 		); err != nil {
 			return nil, err
 		}
@@ -13,10 +13,10 @@ import (
 
 const addCoinLink = `-- name: AddCoinLink :one
 INSERT INTO coin_links (
-    coin_id, url, name, og_title, og_description, og_image
+    coin_id, url, name, og_title, og_description, og_image, collection_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, coin_id, url, name, og_title, og_description, og_image, created_at
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, coin_id, url, name, og_title, og_description, og_image, created_at, collection_id
 `
 
 type AddCoinLinkParams struct {
@@ -26,6 +26,7 @@ type AddCoinLinkParams struct {
 	OgTitle       pgtype.Text `json:"og_title"`
 	OgDescription pgtype.Text `json:"og_description"`
 	OgImage       pgtype.Text `json:"og_image"`
+	CollectionID  pgtype.UUID `json:"collection_id"`
 }
 
 func (q *Queries) AddCoinLink(ctx context.Context, arg AddCoinLinkParams) (CoinLink, error) {
@@ -36,6 +37,7 @@ func (q *Queries) AddCoinLink(ctx context.Context, arg AddCoinLinkParams) (CoinL
 		arg.OgTitle,
 		arg.OgDescription,
 		arg.OgImage,
+		arg.CollectionID,
 	)
 	var i CoinLink
 	err := row.Scan(
@@ -47,28 +49,39 @@ func (q *Queries) AddCoinLink(ctx context.Context, arg AddCoinLinkParams) (CoinL
 		&i.OgDescription,
 		&i.OgImage,
 		&i.CreatedAt,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
 const deleteCoinLink = `-- name: DeleteCoinLink :exec
 DELETE FROM coin_links
-WHERE id = $1
+WHERE id = $1 AND collection_id = $2
 `
 
-func (q *Queries) DeleteCoinLink(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteCoinLink, id)
+type DeleteCoinLinkParams struct {
+	ID           pgtype.UUID `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) DeleteCoinLink(ctx context.Context, arg DeleteCoinLinkParams) error {
+	_, err := q.db.Exec(ctx, deleteCoinLink, arg.ID, arg.CollectionID)
 	return err
 }
 
 const listCoinLinks = `-- name: ListCoinLinks :many
-SELECT id, coin_id, url, name, og_title, og_description, og_image, created_at FROM coin_links
-WHERE coin_id = $1
+SELECT id, coin_id, url, name, og_title, og_description, og_image, created_at, collection_id FROM coin_links
+WHERE coin_id = $1 AND collection_id = $2
 ORDER BY created_at DESC
 `
 
-func (q *Queries) ListCoinLinks(ctx context.Context, coinID pgtype.UUID) ([]CoinLink, error) {
-	rows, err := q.db.Query(ctx, listCoinLinks, coinID)
+type ListCoinLinksParams struct {
+	CoinID       pgtype.UUID `json:"coin_id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) ListCoinLinks(ctx context.Context, arg ListCoinLinksParams) ([]CoinLink, error) {
+	rows, err := q.db.Query(ctx, listCoinLinks, arg.CoinID, arg.CollectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +98,7 @@ func (q *Queries) ListCoinLinks(ctx context.Context, coinID pgtype.UUID) ([]Coin
 			&i.OgDescription,
 			&i.OgImage,
 			&i.CreatedAt,
+			&i.CollectionID,
 		); err != nil {
 			return nil, err
 		}
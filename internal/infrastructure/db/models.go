@@ -97,6 +97,48 @@ func (ns NullImageType) Value() (driver.Value, error) {
 	return string(ns.ImageType), nil
 }
 
+type ApiToken struct {
+	ID         pgtype.UUID        `json:"id"`
+	UserID     pgtype.UUID        `json:"user_id"`
+	Name       string             `json:"name"`
+	TokenHash  string             `json:"token_hash"`
+	Scopes     []string           `json:"scopes"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	LastUsedAt pgtype.Timestamptz `json:"last_used_at"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+}
+
+type AuditLog struct {
+	ID           int64              `json:"id"`
+	EntityType   string             `json:"entity_type"`
+	EntityID     string             `json:"entity_id"`
+	Revision     int32              `json:"revision"`
+	Actor        string             `json:"actor"`
+	Operation    string             `json:"operation"`
+	Changes      []byte             `json:"changes"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+}
+
+type Checklist struct {
+	ID           int32              `json:"id"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ChecklistSlot struct {
+	ID           int32       `json:"id"`
+	ChecklistID  int32       `json:"checklist_id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+	NumistaID    int32       `json:"numista_id"`
+	Year         int32       `json:"year"`
+	MintMark     string      `json:"mint_mark"`
+	Label        string      `json:"label"`
+}
+
 type Coin struct {
 	ID                pgtype.UUID        `json:"id"`
 	Name              pgtype.Text        `json:"name"`
@@ -137,13 +179,20 @@ type Coin struct {
 	CommemoratedTopic string             `json:"commemorated_topic"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt         pgtype.Timestamptz `json:"updated_at"`
+	Status            string             `json:"status"`
+	FieldProvenance   []byte             `json:"field_provenance"`
+	DeletedAt         pgtype.Timestamptz `json:"deleted_at"`
+	CollectionID      pgtype.UUID        `json:"collection_id"`
+	SearchVector      interface{}        `json:"search_vector"`
+	MintMark          string             `json:"mint_mark"`
 }
 
 type CoinGalleryImage struct {
-	ID        pgtype.UUID        `json:"id"`
-	CoinID    pgtype.UUID        `json:"coin_id"`
-	Path      string             `json:"path"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID           pgtype.UUID        `json:"id"`
+	CoinID       pgtype.UUID        `json:"coin_id"`
+	Path         string             `json:"path"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
 }
 
 type CoinImage struct {
@@ -160,6 +209,7 @@ type CoinImage struct {
 	OriginalFilename pgtype.Text        `json:"original_filename"`
 	CreatedAt        pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	CollectionID     pgtype.UUID        `json:"collection_id"`
 }
 
 type CoinLink struct {
@@ -171,18 +221,129 @@ type CoinLink struct {
 	OgDescription pgtype.Text        `json:"og_description"`
 	OgImage       pgtype.Text        `json:"og_image"`
 	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	CollectionID  pgtype.UUID        `json:"collection_id"`
+}
+
+type CoinTag struct {
+	CoinID       pgtype.UUID `json:"coin_id"`
+	TagID        int32       `json:"tag_id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+type Collection struct {
+	ID        pgtype.UUID        `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Group struct {
-	ID          int32              `json:"id"`
-	Name        string             `json:"name"`
-	Description pgtype.Text        `json:"description"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ID           int32              `json:"id"`
+	Name         string             `json:"name"`
+	Description  pgtype.Text        `json:"description"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+	SmartFilter  []byte             `json:"smart_filter"`
+	ParentID     pgtype.Int4        `json:"parent_id"`
 }
 
 type GroupImage struct {
-	ID        pgtype.UUID        `json:"id"`
-	GroupID   int32              `json:"group_id"`
-	Path      string             `json:"path"`
+	ID           pgtype.UUID        `json:"id"`
+	GroupID      int32              `json:"group_id"`
+	Path         string             `json:"path"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+}
+
+type Job struct {
+	ID           pgtype.UUID        `json:"id"`
+	Kind         string             `json:"kind"`
+	CoinID       pgtype.UUID        `json:"coin_id"`
+	Status       string             `json:"status"`
+	Payload      []byte             `json:"payload"`
+	Attempts     int32              `json:"attempts"`
+	MaxAttempts  int32              `json:"max_attempts"`
+	LastError    pgtype.Text        `json:"last_error"`
+	RunAt        pgtype.Timestamptz `json:"run_at"`
+	StartedAt    pgtype.Timestamptz `json:"started_at"`
+	FinishedAt   pgtype.Timestamptz `json:"finished_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+}
+
+type JobStep struct {
+	JobID      pgtype.UUID        `json:"job_id"`
+	Name       string             `json:"name"`
+	Status     string             `json:"status"`
+	Attempts   int32              `json:"attempts"`
+	LastError  pgtype.Text        `json:"last_error"`
+	Output     []byte             `json:"output"`
+	StartedAt  pgtype.Timestamptz `json:"started_at"`
+	FinishedAt pgtype.Timestamptz `json:"finished_at"`
+}
+
+type NumistaEnrichment struct {
+	CoinID      pgtype.UUID        `json:"coin_id"`
+	Status      string             `json:"status"`
+	Attempts    int32              `json:"attempts"`
+	LastError   pgtype.Text        `json:"last_error"`
+	NextRetryAt pgtype.Timestamptz `json:"next_retry_at"`
+	JobID       pgtype.UUID        `json:"job_id"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Session struct {
+	TokenHash string             `json:"token_hash"`
+	UserID    pgtype.UUID        `json:"user_id"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+type ShareLink struct {
+	ID           pgtype.UUID        `json:"id"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+	Name         string             `json:"name"`
+	TokenHash    string             `json:"token_hash"`
+	GroupID      pgtype.Int4        `json:"group_id"`
+	Filter       []byte             `json:"filter"`
+	ViewCount    int64              `json:"view_count"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	LastViewedAt pgtype.Timestamptz `json:"last_viewed_at"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+type Tag struct {
+	ID           int32              `json:"id"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+	Name         string             `json:"name"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type User struct {
+	ID           pgtype.UUID        `json:"id"`
+	Username     string             `json:"username"`
+	PasswordHash string             `json:"password_hash"`
+	IsAdmin      bool               `json:"is_admin"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	CollectionID pgtype.UUID        `json:"collection_id"`
+}
+
+type Wishlist struct {
+	ID               int32              `json:"id"`
+	CollectionID     pgtype.UUID        `json:"collection_id"`
+	NumistaID        int32              `json:"numista_id"`
+	Title            string             `json:"title"`
+	Issuer           string             `json:"issuer"`
+	ObverseThumbnail string             `json:"obverse_thumbnail"`
+	ReverseThumbnail string             `json:"reverse_thumbnail"`
+	Grade            string             `json:"grade"`
+	MaxPrice         pgtype.Numeric     `json:"max_price"`
+	Priority         int32              `json:"priority"`
+	Notes            string             `json:"notes"`
+	FulfilledAt      pgtype.Timestamptz `json:"fulfilled_at"`
+	FulfilledCoinID  pgtype.UUID        `json:"fulfilled_coin_id"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
 }
@@ -11,51 +11,146 @@ import (
 )
 
 type Querier interface {
+	// Returns no row when the checklist already has the slot
+	AddChecklistSlot(ctx context.Context, arg AddChecklistSlotParams) (ChecklistSlot, error)
 	AddCoinLink(ctx context.Context, arg AddCoinLinkParams) (CoinLink, error)
-	CountCoins(ctx context.Context) (int64, error)
+	AddCoinTag(ctx context.Context, arg AddCoinTagParams) error
+	AppendAuditEntry(ctx context.Context, arg AppendAuditEntryParams) (AuditLog, error)
+	ClaimNextJob(ctx context.Context) (Job, error)
+	CoinExists(ctx context.Context, arg CoinExistsParams) (bool, error)
+	CompleteJob(ctx context.Context, id pgtype.UUID) error
+	CompleteJobStep(ctx context.Context, arg CompleteJobStepParams) error
+	CountCoins(ctx context.Context, collectionID pgtype.UUID) (int64, error)
+	CountUsers(ctx context.Context) (int64, error)
+	CreateAPIToken(ctx context.Context, arg CreateAPITokenParams) (ApiToken, error)
+	CreateChecklist(ctx context.Context, arg CreateChecklistParams) (Checklist, error)
 	CreateCoin(ctx context.Context, arg CreateCoinParams) (Coin, error)
 	CreateCoinGalleryImage(ctx context.Context, arg CreateCoinGalleryImageParams) (CoinGalleryImage, error)
 	CreateCoinImage(ctx context.Context, arg CreateCoinImageParams) (CoinImage, error)
+	CreateCollection(ctx context.Context, name string) (Collection, error)
 	CreateGroup(ctx context.Context, arg CreateGroupParams) (Group, error)
 	CreateGroupImage(ctx context.Context, arg CreateGroupImageParams) (GroupImage, error)
-	DeleteCoin(ctx context.Context, id pgtype.UUID) error
-	DeleteCoinGalleryImage(ctx context.Context, id pgtype.UUID) error
-	DeleteCoinLink(ctx context.Context, id pgtype.UUID) error
-	DeleteGroup(ctx context.Context, id int32) error
-	DeleteGroupImage(ctx context.Context, id pgtype.UUID) error
-	GetAllCoins(ctx context.Context) ([]Coin, error)
-	GetAllValues(ctx context.Context) ([]pgtype.Numeric, error)
-	GetAverageValue(ctx context.Context) (float64, error)
-	GetCoin(ctx context.Context, id pgtype.UUID) (Coin, error)
-	GetCoinPercentiles(ctx context.Context, id pgtype.UUID) (GetCoinPercentilesRow, error)
-	GetCollectionGradeDistribution(ctx context.Context) ([]GetCollectionGradeDistributionRow, error)
-	GetCollectionYearDistribution(ctx context.Context) ([]GetCollectionYearDistributionRow, error)
-	GetCountryDistribution(ctx context.Context) ([]GetCountryDistributionRow, error)
-	GetDistinctSaleChannels(ctx context.Context) ([]pgtype.Text, error)
-	GetGradeDistribution(ctx context.Context) ([]GetGradeDistributionRow, error)
-	GetGroupByName(ctx context.Context, name string) (Group, error)
-	GetGroupDistribution(ctx context.Context) ([]GetGroupDistributionRow, error)
-	GetGroupStats(ctx context.Context) ([]GetGroupStatsRow, error)
-	GetHeaviestCoin(ctx context.Context) (Coin, error)
-	GetMaterialDistribution(ctx context.Context) ([]GetMaterialDistributionRow, error)
-	GetOldestCoin(ctx context.Context) (Coin, error)
-	GetRandomCoin(ctx context.Context) (Coin, error)
-	GetRarestCoins(ctx context.Context, limit int32) ([]Coin, error)
-	GetSmallestCoin(ctx context.Context) (Coin, error)
-	GetTotalValue(ctx context.Context) (float64, error)
-	GetTotalWeightByMaterial(ctx context.Context, material pgtype.Text) (float64, error)
-	ListCoinGalleryImages(ctx context.Context, coinID pgtype.UUID) ([]CoinGalleryImage, error)
-	ListCoinImagesByCoinID(ctx context.Context, coinID pgtype.UUID) ([]CoinImage, error)
-	ListCoinImagesByCoinIDs(ctx context.Context, dollar_1 []pgtype.UUID) ([]CoinImage, error)
-	ListCoinLinks(ctx context.Context, coinID pgtype.UUID) ([]CoinLink, error)
-	ListCoins(ctx context.Context, arg ListCoinsParams) ([]Coin, error)
-	ListGroupImages(ctx context.Context, groupID int32) ([]GroupImage, error)
-	ListGroups(ctx context.Context) ([]Group, error)
-	ListRecentCoins(ctx context.Context) ([]Coin, error)
-	ListTopValuableCoins(ctx context.Context) ([]Coin, error)
+	CreateJob(ctx context.Context, arg CreateJobParams) (Job, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) error
+	CreateShareLink(ctx context.Context, arg CreateShareLinkParams) (ShareLink, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWishlistEntry(ctx context.Context, arg CreateWishlistEntryParams) (Wishlist, error)
+	DeleteAPIToken(ctx context.Context, arg DeleteAPITokenParams) (int64, error)
+	DeleteChecklist(ctx context.Context, arg DeleteChecklistParams) error
+	DeleteChecklistSlot(ctx context.Context, arg DeleteChecklistSlotParams) error
+	DeleteCoin(ctx context.Context, arg DeleteCoinParams) error
+	DeleteCoinGalleryImage(ctx context.Context, arg DeleteCoinGalleryImageParams) error
+	DeleteCoinLink(ctx context.Context, arg DeleteCoinLinkParams) error
+	DeleteCoinTags(ctx context.Context, arg DeleteCoinTagsParams) error
+	DeleteExpiredSessions(ctx context.Context) error
+	DeleteGroup(ctx context.Context, arg DeleteGroupParams) error
+	DeleteGroupImage(ctx context.Context, arg DeleteGroupImageParams) error
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteShareLink(ctx context.Context, arg DeleteShareLinkParams) (int64, error)
+	DeleteTag(ctx context.Context, arg DeleteTagParams) error
+	DeleteUser(ctx context.Context, id pgtype.UUID) error
+	DeleteUserSessions(ctx context.Context, userID pgtype.UUID) error
+	DeleteWishlistEntry(ctx context.Context, arg DeleteWishlistEntryParams) error
+	FailJob(ctx context.Context, arg FailJobParams) error
+	FailJobStep(ctx context.Context, arg FailJobStepParams) error
+	// Marks the open entries for the Numista type as fulfilled by the coin
+	FulfilWishlistEntries(ctx context.Context, arg FulfilWishlistEntriesParams) ([]Wishlist, error)
+	GetAPITokenByHash(ctx context.Context, tokenHash string) (ApiToken, error)
+	GetAllCoins(ctx context.Context, collectionID pgtype.UUID) ([]Coin, error)
+	GetAllValues(ctx context.Context, collectionID pgtype.UUID) ([]pgtype.Numeric, error)
+	GetAverageValue(ctx context.Context, collectionID pgtype.UUID) (float64, error)
+	GetChecklist(ctx context.Context, arg GetChecklistParams) (Checklist, error)
+	GetCoin(ctx context.Context, arg GetCoinParams) (Coin, error)
+	GetCoinPercentiles(ctx context.Context, arg GetCoinPercentilesParams) (GetCoinPercentilesRow, error)
+	GetCollection(ctx context.Context, id pgtype.UUID) (Collection, error)
+	GetCollectionGradeDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetCollectionGradeDistributionRow, error)
+	GetCollectionYearDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetCollectionYearDistributionRow, error)
+	GetCountryDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetCountryDistributionRow, error)
+	GetDistinctSaleChannels(ctx context.Context, collectionID pgtype.UUID) ([]pgtype.Text, error)
+	GetGradeDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetGradeDistributionRow, error)
+	GetGroup(ctx context.Context, arg GetGroupParams) (Group, error)
+	GetGroupByName(ctx context.Context, arg GetGroupByNameParams) (Group, error)
+	GetGroupDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetGroupDistributionRow, error)
+	// Each group counts the coins of its subgroups too, at any depth. Coins
+	// without a group make the Uncategorized row. UNION stops at pairs already
+	// seen, so a cycle in parent_id cannot make it loop
+	GetGroupStats(ctx context.Context, collectionID pgtype.UUID) ([]GetGroupStatsRow, error)
+	GetHeaviestCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error)
+	GetJob(ctx context.Context, arg GetJobParams) (Job, error)
+	GetMaterialDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetMaterialDistributionRow, error)
+	GetNumistaEnrichment(ctx context.Context, arg GetNumistaEnrichmentParams) (NumistaEnrichment, error)
+	GetOldestCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error)
+	// Returns the entry for the Numista type that is not fulfilled yet
+	GetOpenWishlistEntry(ctx context.Context, arg GetOpenWishlistEntryParams) (Wishlist, error)
+	GetRandomCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error)
+	GetRarestCoins(ctx context.Context, arg GetRarestCoinsParams) ([]Coin, error)
+	GetSessionUser(ctx context.Context, tokenHash string) (User, error)
+	// Spans every collection: the link itself names the collection it shows
+	GetShareLinkByHash(ctx context.Context, tokenHash string) (ShareLink, error)
+	GetSmallestCoin(ctx context.Context, collectionID pgtype.UUID) (Coin, error)
+	GetTag(ctx context.Context, arg GetTagParams) (Tag, error)
+	GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error)
+	GetTagDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetTagDistributionRow, error)
+	GetTotalValue(ctx context.Context, collectionID pgtype.UUID) (float64, error)
+	GetTotalWeightByMaterial(ctx context.Context, arg GetTotalWeightByMaterialParams) (float64, error)
+	GetUser(ctx context.Context, id pgtype.UUID) (User, error)
+	GetUserByUsername(ctx context.Context, username string) (User, error)
+	GetWishlistEntry(ctx context.Context, arg GetWishlistEntryParams) (Wishlist, error)
+	GroupExists(ctx context.Context, arg GroupExistsParams) (bool, error)
+	// Moves the subgroups of a group up to its own parent
+	LiftSubgroups(ctx context.Context, arg LiftSubgroupsParams) error
+	ListAPITokens(ctx context.Context, userID pgtype.UUID) ([]ApiToken, error)
+	ListAllChecklistSlots(ctx context.Context, collectionID pgtype.UUID) ([]ChecklistSlot, error)
+	ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditLog, error)
+	ListChecklistSlots(ctx context.Context, arg ListChecklistSlotsParams) ([]ChecklistSlot, error)
+	ListChecklists(ctx context.Context, collectionID pgtype.UUID) ([]Checklist, error)
+	ListCoinGalleryImages(ctx context.Context, arg ListCoinGalleryImagesParams) ([]CoinGalleryImage, error)
+	ListCoinImagesByCoinID(ctx context.Context, arg ListCoinImagesByCoinIDParams) ([]CoinImage, error)
+	ListCoinImagesByCoinIDs(ctx context.Context, arg ListCoinImagesByCoinIDsParams) ([]CoinImage, error)
+	ListCoinLinks(ctx context.Context, arg ListCoinLinksParams) ([]CoinLink, error)
+	ListCoinTagsByCoinIDs(ctx context.Context, arg ListCoinTagsByCoinIDsParams) ([]ListCoinTagsByCoinIDsRow, error)
+	// Spans every collection: the trash purger runs outside any request
+	ListCoinsTrashedBefore(ctx context.Context, deletedAt pgtype.Timestamptz) ([]ListCoinsTrashedBeforeRow, error)
+	ListCollections(ctx context.Context) ([]Collection, error)
+	ListGroupImages(ctx context.Context, arg ListGroupImagesParams) ([]GroupImage, error)
+	ListGroups(ctx context.Context, collectionID pgtype.UUID) ([]Group, error)
+	ListJobSteps(ctx context.Context, jobID pgtype.UUID) ([]JobStep, error)
+	ListNumistaEnrichmentsByStatus(ctx context.Context, arg ListNumistaEnrichmentsByStatusParams) ([]ListNumistaEnrichmentsByStatusRow, error)
+	ListRecentCoins(ctx context.Context, collectionID pgtype.UUID) ([]Coin, error)
+	ListShareLinks(ctx context.Context, collectionID pgtype.UUID) ([]ShareLink, error)
+	ListTags(ctx context.Context, collectionID pgtype.UUID) ([]ListTagsRow, error)
+	ListTopValuableCoins(ctx context.Context, collectionID pgtype.UUID) ([]Coin, error)
+	ListTrashedCoins(ctx context.Context, collectionID pgtype.UUID) ([]Coin, error)
+	ListUsers(ctx context.Context) ([]User, error)
+	// Most wanted first. A NULL fulfilled lists both open and fulfilled entries
+	ListWishlist(ctx context.Context, arg ListWishlistParams) ([]Wishlist, error)
+	LockAuditEntity(ctx context.Context, arg LockAuditEntityParams) error
+	// Locks the groups of a collection, so that moves check for cycles one at a
+	// time
+	LockGroupParents(ctx context.Context, collectionID pgtype.UUID) ([]LockGroupParentsRow, error)
 	MarkCoinAsSold(ctx context.Context, arg MarkCoinAsSoldParams) (Coin, error)
+	// Gives the coins of one tag another, skipping coins that already have it
+	MoveCoinTags(ctx context.Context, arg MoveCoinTagsParams) error
+	MoveGroup(ctx context.Context, arg MoveGroupParams) error
+	RecordShareLinkView(ctx context.Context, id pgtype.UUID) error
+	RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error)
+	RequeueRunningJobs(ctx context.Context) (int64, error)
+	RestoreCoin(ctx context.Context, arg RestoreCoinParams) error
+	RetryJob(ctx context.Context, arg RetryJobParams) error
+	StartJobStep(ctx context.Context, arg StartJobStepParams) error
+	// Written at most once a minute per token to keep requests cheap
+	TouchAPIToken(ctx context.Context, id pgtype.UUID) error
+	TrashCoin(ctx context.Context, arg TrashCoinParams) (int64, error)
+	UpdateChecklist(ctx context.Context, arg UpdateChecklistParams) (Checklist, error)
 	UpdateCoin(ctx context.Context, arg UpdateCoinParams) (Coin, error)
+	UpdateCoinStatus(ctx context.Context, arg UpdateCoinStatusParams) error
 	UpdateGroup(ctx context.Context, arg UpdateGroupParams) (Group, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
+	UpdateWishlistEntry(ctx context.Context, arg UpdateWishlistEntryParams) (Wishlist, error)
+	UpsertNumistaEnrichment(ctx context.Context, arg UpsertNumistaEnrichmentParams) error
+	// Returns the tag with the name, ignoring case, creating it if needed
+	UpsertTag(ctx context.Context, arg UpsertTagParams) (Tag, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,20 @@
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens (user_id, name, token_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: DeleteAPIToken :execrows
+DELETE FROM api_tokens WHERE id = $1 AND user_id = $2;
+
+-- name: GetAPITokenByHash :one
+SELECT * FROM api_tokens
+WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP);
+
+-- name: ListAPITokens :many
+SELECT * FROM api_tokens WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: TouchAPIToken :exec
+-- Written at most once a minute per token to keep requests cheap
+UPDATE api_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute');
@@ -0,0 +1,16 @@
+-- name: AppendAuditEntry :one
+INSERT INTO audit_log (entity_type, entity_id, revision, actor, operation, changes, collection_id)
+VALUES (
+    $1, $2,
+    (SELECT COALESCE(MAX(revision), 0) + 1 FROM audit_log WHERE entity_type = $1 AND entity_id = $2),
+    $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: LockAuditEntity :exec
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg('entity_type')::text || ':' || sqlc.arg('entity_id')::text, 0));
+
+-- name: ListAuditEntries :many
+SELECT * FROM audit_log
+WHERE entity_type = $1 AND entity_id = $2 AND collection_id = $3
+ORDER BY revision ASC;
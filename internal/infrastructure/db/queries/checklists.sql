@@ -0,0 +1,46 @@
+-- name: CreateChecklist :one
+INSERT INTO checklists (collection_id, name, description)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetChecklist :one
+SELECT * FROM checklists
+WHERE id = $1 AND collection_id = $2;
+
+-- name: ListChecklists :many
+SELECT * FROM checklists
+WHERE collection_id = $1
+ORDER BY lower(name), id;
+
+-- name: UpdateChecklist :one
+UPDATE checklists
+SET name = $2,
+    description = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND collection_id = $4
+RETURNING *;
+
+-- name: DeleteChecklist :exec
+DELETE FROM checklists
+WHERE id = $1 AND collection_id = $2;
+
+-- name: AddChecklistSlot :one
+-- Returns no row when the checklist already has the slot
+INSERT INTO checklist_slots (checklist_id, collection_id, numista_id, year, mint_mark, label)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (checklist_id, numista_id, year, lower(mint_mark)) DO NOTHING
+RETURNING *;
+
+-- name: ListChecklistSlots :many
+SELECT * FROM checklist_slots
+WHERE checklist_id = $1 AND collection_id = $2
+ORDER BY numista_id, year, lower(mint_mark), id;
+
+-- name: ListAllChecklistSlots :many
+SELECT * FROM checklist_slots
+WHERE collection_id = $1
+ORDER BY checklist_id, numista_id, year, lower(mint_mark), id;
+
+-- name: DeleteChecklistSlot :exec
+DELETE FROM checklist_slots
+WHERE id = $1 AND checklist_id = $2 AND collection_id = $3;
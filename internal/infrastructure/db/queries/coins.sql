@@ -5,61 +5,24 @@ INSERT INTO coins (
     weight_g, diameter_mm, thickness_mm, edge, shape,
     acquired_at, sold_at, price_paid, sold_price, numista_number, numista_details,
     gemini_model, gemini_temperature, numista_search,
-    ruler, orientation, series, commemorated_topic
+    ruler, orientation, series, commemorated_topic,
+    status, sale_channel, field_provenance, collection_id, mint_mark
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
     $12, $13, $14, $15, $16, $17, $18,
     $19, $20, $21, $22, $23,
     $24, $25, $26, $27, $28, $29,
     $30, $31, $32,
-    $33, $34, $35, $36
+    $33, $34, $35, $36,
+    $37, $38, $39, $40, $41
 ) RETURNING *;
 
 -- name: GetCoin :one
 SELECT * FROM coins
-WHERE id = $1 LIMIT 1;
-
--- name: ListCoins :many
-SELECT * FROM coins
-WHERE 
-    (sqlc.narg('group_id')::int IS NULL OR group_id = sqlc.narg('group_id'))
-    AND (sqlc.narg('year')::int IS NULL OR year = sqlc.narg('year'))
-    AND (sqlc.narg('country')::text IS NULL OR country ILIKE sqlc.narg('country'))
-    AND (sqlc.narg('query')::text IS NULL OR 
-        name ILIKE '%' || sqlc.narg('query') || '%' OR 
-        description ILIKE '%' || sqlc.narg('query') || '%' OR
-        km_code ILIKE '%' || sqlc.narg('query') || '%'
-    )
-    AND (sqlc.narg('min_price')::float8 IS NULL OR min_value >= sqlc.narg('min_price')::float8)
-    AND (sqlc.narg('max_price')::float8 IS NULL OR max_value <= sqlc.narg('max_price')::float8)
-    AND (sqlc.narg('grade')::text IS NULL OR grade = sqlc.narg('grade'))
-    AND (sqlc.narg('material')::text IS NULL OR material = sqlc.narg('material'))
-    AND (sqlc.narg('min_year')::int IS NULL OR year >= sqlc.narg('min_year'))
-    AND (sqlc.narg('max_year')::int IS NULL OR year <= sqlc.narg('max_year'))
-ORDER BY
-    CASE WHEN sqlc.narg('sort_by')::text = 'year' AND sqlc.narg('sort_order')::text = 'asc' THEN year END ASC,
-    CASE WHEN sqlc.narg('sort_by')::text = 'year' AND sqlc.narg('sort_order')::text = 'desc' THEN year END DESC,
-    
-    CASE WHEN sqlc.narg('sort_by')::text = 'min_value' AND sqlc.narg('sort_order')::text = 'asc' THEN min_value END ASC,
-    CASE WHEN sqlc.narg('sort_by')::text = 'min_value' AND sqlc.narg('sort_order')::text = 'desc' THEN min_value END DESC,
-    
-    CASE WHEN sqlc.narg('sort_by')::text = 'max_value' AND sqlc.narg('sort_order')::text = 'asc' THEN max_value END ASC,
-    CASE WHEN sqlc.narg('sort_by')::text = 'max_value' AND sqlc.narg('sort_order')::text = 'desc' THEN max_value END DESC,
-    
-    CASE WHEN sqlc.narg('sort_by')::text = 'created_at' AND sqlc.narg('sort_order')::text = 'asc' THEN created_at END ASC,
-    CASE WHEN sqlc.narg('sort_by')::text = 'created_at' AND sqlc.narg('sort_order')::text = 'desc' THEN created_at END DESC,
-    
-    CASE WHEN sqlc.narg('sort_by')::text = 'country' AND sqlc.narg('sort_order')::text = 'asc' THEN country END ASC,
-    CASE WHEN sqlc.narg('sort_by')::text = 'country' AND sqlc.narg('sort_order')::text = 'desc' THEN country END DESC,
-    
-    CASE WHEN sqlc.narg('sort_by')::text = 'name' AND sqlc.narg('sort_order')::text = 'asc' THEN name END ASC,
-    CASE WHEN sqlc.narg('sort_by')::text = 'name' AND sqlc.narg('sort_order')::text = 'desc' THEN name END DESC,
-    
-    created_at DESC
-LIMIT $1 OFFSET $2;
+WHERE id = $1 AND collection_id = $2 LIMIT 1;
 
 -- name: CountCoins :one
-SELECT count(*) FROM coins;
+SELECT count(*) FROM coins WHERE collection_id = $1 AND deleted_at IS NULL;
 
 -- name: UpdateCoin :one
 UPDATE coins
@@ -99,89 +62,118 @@ SET
     orientation = $34,
     series = $35,
     commemorated_topic = $36,
+    status = $37,
+    sale_channel = $38,
+    field_provenance = $39,
+    mint_mark = $41,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+WHERE id = $1 AND collection_id = $40
 RETURNING *;
 
 -- name: DeleteCoin :exec
 DELETE FROM coins
-WHERE id = $1;
+WHERE id = $1 AND collection_id = $2;
 
 -- name: GetTotalValue :one
-SELECT COALESCE(SUM(max_value), 0)::float8 FROM coins;
+SELECT COALESCE(SUM(max_value), 0)::float8 FROM coins WHERE collection_id = $1 AND deleted_at IS NULL;
 
 -- name: GetAverageValue :one
-SELECT COALESCE(AVG(max_value), 0)::float8 FROM coins;
+SELECT COALESCE(AVG(max_value), 0)::float8 FROM coins WHERE collection_id = $1 AND deleted_at IS NULL;
 
 -- name: ListTopValuableCoins :many
 SELECT * FROM coins
+WHERE collection_id = $1 AND deleted_at IS NULL
 ORDER BY max_value DESC
 LIMIT 5;
 
 -- name: ListRecentCoins :many
 SELECT * FROM coins
+WHERE collection_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 LIMIT 5;
 
 -- name: GetMaterialDistribution :many
 SELECT material, COUNT(*) as count
 FROM coins
-WHERE material IS NOT NULL AND material != ''
+WHERE collection_id = $1 AND deleted_at IS NULL AND material IS NOT NULL AND material != ''
 GROUP BY material
 ORDER BY count DESC;
 
 -- name: GetGradeDistribution :many
 SELECT grade, COUNT(*) as count
 FROM coins
-WHERE grade IS NOT NULL
+WHERE collection_id = $1 AND deleted_at IS NULL AND grade IS NOT NULL
 GROUP BY grade
 ORDER BY count DESC;
 
 -- name: GetAllValues :many
-SELECT max_value FROM coins WHERE max_value IS NOT NULL;
+SELECT max_value FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND max_value IS NOT NULL;
 
 -- name: GetCountryDistribution :many
-SELECT country, COUNT(*) as count FROM coins GROUP BY country;
+SELECT country, COUNT(*) as count FROM coins WHERE collection_id = $1 AND deleted_at IS NULL GROUP BY country;
 
 -- name: GetOldestCoin :one
-SELECT * FROM coins WHERE year > 0 ORDER BY year ASC LIMIT 1;
+SELECT * FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND year > 0 ORDER BY year ASC LIMIT 1;
 
 -- name: GetRarestCoins :many
-SELECT * FROM coins WHERE mintage > 0 ORDER BY mintage ASC LIMIT $1;
+SELECT * FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND mintage > 0 ORDER BY mintage ASC LIMIT $2;
 
 -- name: GetGroupDistribution :many
 SELECT COALESCE(g.name, 'Uncategorized') as group_name, COUNT(c.id) as count 
 FROM coins c 
 LEFT JOIN groups g ON c.group_id = g.id 
+WHERE c.collection_id = $1 AND c.deleted_at IS NULL
 GROUP BY g.name;
 
 -- name: GetTotalWeightByMaterial :one
-SELECT COALESCE(SUM(weight_g), 0)::float8 FROM coins WHERE material ILIKE $1;
+SELECT COALESCE(SUM(weight_g), 0)::float8 FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND material ILIKE $2;
 
 -- name: GetHeaviestCoin :one
-SELECT * FROM coins WHERE weight_g > 0 ORDER BY weight_g DESC LIMIT 1;
+SELECT * FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND weight_g > 0 ORDER BY weight_g DESC LIMIT 1;
 
 -- name: GetSmallestCoin :one
-SELECT * FROM coins WHERE diameter_mm > 0 ORDER BY diameter_mm ASC LIMIT 1;
+SELECT * FROM coins WHERE collection_id = $1 AND deleted_at IS NULL AND diameter_mm > 0 ORDER BY diameter_mm ASC LIMIT 1;
 
 -- name: GetRandomCoin :one
-SELECT * FROM coins ORDER BY RANDOM() LIMIT 1;
+SELECT * FROM coins WHERE collection_id = $1 AND deleted_at IS NULL ORDER BY RANDOM() LIMIT 1;
 
 -- name: GetAllCoins :many
-SELECT * FROM coins;
+SELECT * FROM coins
+WHERE collection_id = $1 AND deleted_at IS NULL;
 
 -- name: GetGroupStats :many
+-- Each group counts the coins of its subgroups too, at any depth. Coins
+-- without a group make the Uncategorized row. UNION stops at pairs already
+-- seen, so a cycle in parent_id cannot make it loop
+WITH RECURSIVE subgroups AS (
+    SELECT id AS root_id, id AS group_id FROM groups WHERE groups.collection_id = $1
+    UNION
+    SELECT s.root_id, g.id FROM subgroups s
+    JOIN groups g ON g.parent_id = s.group_id
+)
 SELECT 
     g.id as group_id, 
     COALESCE(g.name, 'Uncategorized') as group_name, 
+    g.parent_id,
     COUNT(c.id) as count,
     COALESCE(MIN(c.min_value), 0)::float8 as min_val,
-    COALESCE(MIN(c.min_value), 0)::float8 as min_val,
     COALESCE(MAX(c.max_value), 0)::float8 as max_val,
     COALESCE(AVG(c.max_value), 0)::float8 as avg_val,
     COALESCE(MIN(NULLIF(c.year, 0)), 0)::int as min_year,
     COALESCE(MAX(NULLIF(c.year, 0)), 0)::int as max_year
 FROM coins c 
-LEFT JOIN groups g ON c.group_id = g.id 
-GROUP BY g.id, g.name
+LEFT JOIN subgroups s ON s.group_id = c.group_id
+LEFT JOIN groups g ON g.id = s.root_id
+WHERE c.collection_id = $1 AND c.deleted_at IS NULL
+GROUP BY g.id, g.name, g.parent_id
 ORDER BY count DESC;
+
+-- name: CoinExists :one
+SELECT EXISTS (
+    SELECT 1 FROM coins WHERE id = $1 AND collection_id = $2
+);
+
+-- name: UpdateCoinStatus :exec
+UPDATE coins
+SET status = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND collection_id = $3;
@@ -0,0 +1,10 @@
+-- name: CreateCollection :one
+INSERT INTO collections (name)
+VALUES ($1)
+RETURNING *;
+
+-- name: GetCollection :one
+SELECT * FROM collections WHERE id = $1;
+
+-- name: ListCollections :many
+SELECT * FROM collections ORDER BY name;
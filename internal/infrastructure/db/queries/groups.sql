@@ -1,22 +1,51 @@
 -- name: CreateGroup :one
-INSERT INTO groups (name, description)
-VALUES ($1, $2)
+INSERT INTO groups (name, description, collection_id, smart_filter)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
+-- name: GetGroup :one
+SELECT * FROM groups
+WHERE id = $1 AND collection_id = $2;
+
 -- name: GetGroupByName :one
 SELECT * FROM groups
-WHERE name = $1;
+WHERE name = $1 AND collection_id = $2;
+
+-- name: GroupExists :one
+SELECT EXISTS (
+    SELECT 1 FROM groups WHERE id = $1 AND collection_id = $2
+);
+
+-- name: LiftSubgroups :exec
+-- Moves the subgroups of a group up to its own parent
+UPDATE groups
+SET parent_id = (SELECT p.parent_id FROM groups p WHERE p.id = $1 AND p.collection_id = $2)
+WHERE parent_id = $1 AND collection_id = $2;
 
 -- name: ListGroups :many
 SELECT * FROM groups
+WHERE collection_id = $1
 ORDER BY name;
 
 -- name: UpdateGroup :one
 UPDATE groups
-SET name = $2, description = $3
-WHERE id = $1
+SET name = $2, description = $3, smart_filter = $5
+WHERE id = $1 AND collection_id = $4
 RETURNING *;
 
+-- name: LockGroupParents :many
+-- Locks the groups of a collection, so that moves check for cycles one at a
+-- time
+SELECT id, parent_id FROM groups
+WHERE collection_id = $1
+ORDER BY id
+FOR UPDATE;
+
+-- name: MoveGroup :exec
+UPDATE groups
+SET parent_id = $2
+WHERE id = $1 AND collection_id = $3;
+
 -- name: DeleteGroup :exec
 DELETE FROM groups
-WHERE id = $1;
+WHERE id = $1 AND collection_id = $2;
@@ -0,0 +1,61 @@
+-- name: CreateJob :one
+INSERT INTO jobs (id, kind, coin_id, payload, max_attempts, collection_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetJob :one
+SELECT * FROM jobs
+WHERE id = $1 AND collection_id = $2 LIMIT 1;
+
+-- name: ClaimNextJob :one
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, started_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = (
+    SELECT id FROM jobs
+    WHERE status = 'queued' AND run_at <= CURRENT_TIMESTAMP
+    ORDER BY run_at, created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteJob :exec
+UPDATE jobs
+SET status = 'succeeded', last_error = NULL, finished_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RetryJob :exec
+UPDATE jobs
+SET status = 'queued', last_error = $2, run_at = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: FailJob :exec
+UPDATE jobs
+SET status = 'failed', last_error = $2, finished_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RequeueRunningJobs :execrows
+UPDATE jobs
+SET status = 'queued', run_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE status = 'running';
+
+-- name: StartJobStep :exec
+INSERT INTO job_steps (job_id, name, status, attempts, started_at)
+VALUES ($1, $2, 'running', 1, CURRENT_TIMESTAMP)
+ON CONFLICT (job_id, name) DO UPDATE
+SET status = 'running', attempts = job_steps.attempts + 1, started_at = CURRENT_TIMESTAMP, finished_at = NULL;
+
+-- name: CompleteJobStep :exec
+UPDATE job_steps
+SET status = 'succeeded', output = $3, last_error = NULL, finished_at = CURRENT_TIMESTAMP
+WHERE job_id = $1 AND name = $2;
+
+-- name: FailJobStep :exec
+UPDATE job_steps
+SET status = 'failed', last_error = $3, finished_at = CURRENT_TIMESTAMP
+WHERE job_id = $1 AND name = $2;
+
+-- name: ListJobSteps :many
+SELECT * FROM job_steps
+WHERE job_id = $1
+ORDER BY started_at ASC;
@@ -1,15 +1,15 @@
 -- name: AddCoinLink :one
 INSERT INTO coin_links (
-    coin_id, url, name, og_title, og_description, og_image
+    coin_id, url, name, og_title, og_description, og_image, collection_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7
 ) RETURNING *;
 
 -- name: ListCoinLinks :many
 SELECT * FROM coin_links
-WHERE coin_id = $1
+WHERE coin_id = $1 AND collection_id = $2
 ORDER BY created_at DESC;
 
 -- name: DeleteCoinLink :exec
 DELETE FROM coin_links
-WHERE id = $1;
+WHERE id = $1 AND collection_id = $2;
@@ -0,0 +1,25 @@
+-- name: GetNumistaEnrichment :one
+SELECT e.* FROM numista_enrichments e
+JOIN coins c ON c.id = e.coin_id
+WHERE e.coin_id = $1 AND c.collection_id = $2 LIMIT 1;
+
+-- name: UpsertNumistaEnrichment :exec
+INSERT INTO numista_enrichments (coin_id, status, attempts, last_error, next_retry_at, job_id)
+SELECT c.id, $2, $3, $4, $5, $6
+FROM coins c
+WHERE c.id = $1 AND c.collection_id = $7
+ON CONFLICT (coin_id) DO UPDATE
+SET status = EXCLUDED.status,
+    attempts = EXCLUDED.attempts,
+    last_error = EXCLUDED.last_error,
+    next_retry_at = EXCLUDED.next_retry_at,
+    job_id = EXCLUDED.job_id,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListNumistaEnrichmentsByStatus :many
+SELECT e.coin_id, e.status, e.attempts, e.last_error, e.next_retry_at, e.job_id, e.created_at, e.updated_at,
+       c.name AS coin_name, c.country, c.year
+FROM numista_enrichments e
+JOIN coins c ON c.id = e.coin_id
+WHERE e.status = $1 AND c.collection_id = $2
+ORDER BY e.updated_at DESC;
@@ -5,11 +5,11 @@ SET
     sold_price = $3,
     sale_channel = $4,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
+WHERE id = $1 AND collection_id = $5
 RETURNING *;
 
 -- name: GetDistinctSaleChannels :many
 SELECT DISTINCT sale_channel
 FROM coins
-WHERE sale_channel IS NOT NULL AND sale_channel != ''
+WHERE collection_id = $1 AND deleted_at IS NULL AND sale_channel IS NOT NULL AND sale_channel != ''
 ORDER BY sale_channel;
@@ -0,0 +1,20 @@
+-- name: CreateShareLink :one
+INSERT INTO share_links (collection_id, name, token_hash, group_id, filter, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: DeleteShareLink :execrows
+DELETE FROM share_links WHERE id = $1 AND collection_id = $2;
+
+-- name: GetShareLinkByHash :one
+-- Spans every collection: the link itself names the collection it shows
+SELECT * FROM share_links
+WHERE token_hash = $1 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP);
+
+-- name: ListShareLinks :many
+SELECT * FROM share_links WHERE collection_id = $1 ORDER BY created_at DESC;
+
+-- name: RecordShareLinkView :exec
+UPDATE share_links
+SET view_count = view_count + 1, last_viewed_at = CURRENT_TIMESTAMP
+WHERE id = $1;
@@ -11,7 +11,7 @@ WITH stats AS (
         PERCENT_RANK() OVER (ORDER BY c.weight_g) as weight_percentile,
         PERCENT_RANK() OVER (ORDER BY c.diameter_mm) as size_percentile
     FROM coins c
-    WHERE c.sold_at IS NULL -- Compare only with collection
+    WHERE c.collection_id = $2 AND c.sold_at IS NULL AND c.deleted_at IS NULL -- Compare only with collection
 )
 SELECT 
     value_percentile,
@@ -27,8 +27,10 @@ SELECT
     COUNT(*) as count
 FROM coins
 WHERE 
-    year > 0 
+    collection_id = $1
+    AND year > 0
     AND sold_at IS NULL
+    AND deleted_at IS NULL
 GROUP BY year
 ORDER BY year;
 
@@ -38,7 +40,9 @@ SELECT
     COUNT(*) as count
 FROM coins
 WHERE 
-    grade != '' 
+    collection_id = $1
+    AND grade != ''
     AND sold_at IS NULL
+    AND deleted_at IS NULL
 GROUP BY grade
 ORDER BY grade;
@@ -0,0 +1,68 @@
+-- name: CreateTag :one
+INSERT INTO tags (collection_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: UpsertTag :one
+-- Returns the tag with the name, ignoring case, creating it if needed
+INSERT INTO tags (collection_id, name)
+VALUES ($1, $2)
+ON CONFLICT (collection_id, lower(name)) DO UPDATE SET name = tags.name
+RETURNING *;
+
+-- name: GetTag :one
+SELECT * FROM tags
+WHERE id = $1 AND collection_id = $2;
+
+-- name: GetTagByName :one
+SELECT * FROM tags
+WHERE lower(name) = lower($1) AND collection_id = $2;
+
+-- name: ListTags :many
+SELECT t.id, t.collection_id, t.name, t.created_at, COUNT(c.id) AS coin_count
+FROM tags t
+LEFT JOIN coin_tags ct ON ct.tag_id = t.id
+LEFT JOIN coins c ON c.id = ct.coin_id AND c.deleted_at IS NULL
+WHERE t.collection_id = $1
+GROUP BY t.id
+ORDER BY lower(t.name);
+
+-- name: RenameTag :one
+UPDATE tags
+SET name = $2
+WHERE id = $1 AND collection_id = $3
+RETURNING *;
+
+-- name: DeleteTag :exec
+DELETE FROM tags
+WHERE id = $1 AND collection_id = $2;
+
+-- name: MoveCoinTags :exec
+-- Gives the coins of one tag another, skipping coins that already have it
+INSERT INTO coin_tags (coin_id, tag_id, collection_id)
+SELECT coin_id, sqlc.arg('target_id')::int, collection_id FROM coin_tags
+WHERE tag_id = sqlc.arg('source_id')::int AND collection_id = sqlc.arg('collection_id')
+ON CONFLICT DO NOTHING;
+
+-- name: ListCoinTagsByCoinIDs :many
+SELECT ct.coin_id, t.name FROM coin_tags ct
+JOIN tags t ON t.id = ct.tag_id
+WHERE ct.coin_id = ANY($1::uuid[]) AND ct.collection_id = $2
+ORDER BY lower(t.name);
+
+-- name: AddCoinTag :exec
+INSERT INTO coin_tags (coin_id, tag_id, collection_id)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteCoinTags :exec
+DELETE FROM coin_tags
+WHERE coin_id = $1 AND collection_id = $2;
+
+-- name: GetTagDistribution :many
+SELECT t.name AS tag_name, COUNT(c.id) AS count
+FROM coin_tags ct
+JOIN tags t ON t.id = ct.tag_id
+JOIN coins c ON c.id = ct.coin_id
+WHERE ct.collection_id = $1 AND c.deleted_at IS NULL
+GROUP BY t.name;
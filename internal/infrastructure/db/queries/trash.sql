@@ -0,0 +1,19 @@
+-- name: TrashCoin :execrows
+UPDATE coins
+SET deleted_at = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND collection_id = $3 AND deleted_at IS NULL;
+
+-- name: RestoreCoin :exec
+UPDATE coins
+SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND collection_id = $2;
+
+-- name: ListTrashedCoins :many
+SELECT * FROM coins
+WHERE collection_id = $1 AND deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: ListCoinsTrashedBefore :many
+-- Spans every collection: the trash purger runs outside any request
+SELECT id, collection_id FROM coins
+WHERE deleted_at IS NOT NULL AND deleted_at < $1;
@@ -0,0 +1,47 @@
+-- name: CreateWishlistEntry :one
+INSERT INTO wishlist (
+    collection_id, numista_id, title, issuer, obverse_thumbnail, reverse_thumbnail,
+    grade, max_price, priority, notes
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetWishlistEntry :one
+SELECT * FROM wishlist
+WHERE id = $1 AND collection_id = $2;
+
+-- name: GetOpenWishlistEntry :one
+-- Returns the entry for the Numista type that is not fulfilled yet
+SELECT * FROM wishlist
+WHERE numista_id = $1 AND collection_id = $2 AND fulfilled_at IS NULL;
+
+-- name: ListWishlist :many
+-- Most wanted first. A NULL fulfilled lists both open and fulfilled entries
+SELECT * FROM wishlist
+WHERE collection_id = sqlc.arg('collection_id')
+  AND (sqlc.narg('fulfilled')::bool IS NULL OR (fulfilled_at IS NOT NULL) = sqlc.narg('fulfilled')::bool)
+ORDER BY priority, created_at, id;
+
+-- name: UpdateWishlistEntry :one
+UPDATE wishlist
+SET grade = $2,
+    max_price = $3,
+    priority = $4,
+    notes = $5,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND collection_id = $6
+RETURNING *;
+
+-- name: DeleteWishlistEntry :exec
+DELETE FROM wishlist
+WHERE id = $1 AND collection_id = $2;
+
+-- name: FulfilWishlistEntries :many
+-- Marks the open entries for the Numista type as fulfilled by the coin
+UPDATE wishlist
+SET fulfilled_at = CURRENT_TIMESTAMP,
+    fulfilled_coin_id = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE numista_id = $1 AND collection_id = $3 AND fulfilled_at IS NULL
+RETURNING *;
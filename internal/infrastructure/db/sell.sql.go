@@ -14,12 +14,12 @@ import (
 const getDistinctSaleChannels = `-- name: GetDistinctSaleChannels :many
 SELECT DISTINCT sale_channel
 FROM coins
-WHERE sale_channel IS NOT NULL AND sale_channel != ''
+WHERE collection_id = $1 AND deleted_at IS NULL AND sale_channel IS NOT NULL AND sale_channel != ''
 ORDER BY sale_channel
 `
 
-func (q *Queries) GetDistinctSaleChannels(ctx context.Context) ([]pgtype.Text, error) {
-	rows, err := q.db.Query(ctx, getDistinctSaleChannels)
+func (q *Queries) GetDistinctSaleChannels(ctx context.Context, collectionID pgtype.UUID) ([]pgtype.Text, error) {
+	rows, err := q.db.Query(ctx, getDistinctSaleChannels, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -45,15 +45,16 @@ SET
     sold_price = $3,
     sale_channel = $4,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1
-RETURNING id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at
+WHERE id = $1 AND collection_id = $5
+RETURNING id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark
 `
 
 type MarkCoinAsSoldParams struct {
-	ID          pgtype.UUID    `json:"id"`
-	SoldAt      pgtype.Date    `json:"sold_at"`
-	SoldPrice   pgtype.Numeric `json:"sold_price"`
-	SaleChannel pgtype.Text    `json:"sale_channel"`
+	ID           pgtype.UUID    `json:"id"`
+	SoldAt       pgtype.Date    `json:"sold_at"`
+	SoldPrice    pgtype.Numeric `json:"sold_price"`
+	SaleChannel  pgtype.Text    `json:"sale_channel"`
+	CollectionID pgtype.UUID    `json:"collection_id"`
 }
 
 func (q *Queries) MarkCoinAsSold(ctx context.Context, arg MarkCoinAsSoldParams) (Coin, error) {
@@ -62,6 +63,7 @@ func (q *Queries) MarkCoinAsSold(ctx context.Context, arg MarkCoinAsSoldParams)
 		arg.SoldAt,
 		arg.SoldPrice,
 		arg.SaleChannel,
+		arg.CollectionID,
 	)
 	var i Coin
 	err := row.Scan(
@@ -104,6 +106,12 @@ func (q *Queries) MarkCoinAsSold(ctx context.Context, arg MarkCoinAsSoldParams)
 		&i.CommemoratedTopic,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.FieldProvenance,
+		&i.DeletedAt,
+		&i.CollectionID,
+		&i.SearchVector,
+		&i.MintMark,
 	)
 	return i, err
 }
@@ -24,7 +24,7 @@ WITH stats AS (
         PERCENT_RANK() OVER (ORDER BY c.weight_g) as weight_percentile,
         PERCENT_RANK() OVER (ORDER BY c.diameter_mm) as size_percentile
     FROM coins c
-    WHERE c.sold_at IS NULL -- Compare only with collection
+    WHERE c.collection_id = $2 AND c.sold_at IS NULL AND c.deleted_at IS NULL -- Compare only with collection
 )
 SELECT 
     value_percentile,
@@ -42,8 +42,13 @@ type GetCoinPercentilesRow struct {
 	SizePercentile   float64 `json:"size_percentile"`
 }
 
-func (q *Queries) GetCoinPercentiles(ctx context.Context, id pgtype.UUID) (GetCoinPercentilesRow, error) {
-	row := q.db.QueryRow(ctx, getCoinPercentiles, id)
+type GetCoinPercentilesParams struct {
+	ID           pgtype.UUID `json:"id"`
+	CollectionID pgtype.UUID `json:"collection_id"`
+}
+
+func (q *Queries) GetCoinPercentiles(ctx context.Context, arg GetCoinPercentilesParams) (GetCoinPercentilesRow, error) {
+	row := q.db.QueryRow(ctx, getCoinPercentiles, arg.ID, arg.CollectionID)
 	var i GetCoinPercentilesRow
 	err := row.Scan(
 		&i.ValuePercentile,
@@ -60,8 +65,10 @@ SELECT
     COUNT(*) as count
 FROM coins
 WHERE 
-    grade != '' 
+    collection_id = $1
+    AND grade != ''
     AND sold_at IS NULL
+    AND deleted_at IS NULL
 GROUP BY grade
 ORDER BY grade
 `
@@ -71,8 +78,8 @@ type GetCollectionGradeDistributionRow struct {
 	Count int64       `json:"count"`
 }
 
-func (q *Queries) GetCollectionGradeDistribution(ctx context.Context) ([]GetCollectionGradeDistributionRow, error) {
-	rows, err := q.db.Query(ctx, getCollectionGradeDistribution)
+func (q *Queries) GetCollectionGradeDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetCollectionGradeDistributionRow, error) {
+	rows, err := q.db.Query(ctx, getCollectionGradeDistribution, collectionID)
 	if err != nil {
 		return nil, err
 	}
@@ -97,8 +104,10 @@ SELECT
     COUNT(*) as count
 FROM coins
 WHERE 
-    year > 0 
+    collection_id = $1
+    AND year > 0
     AND sold_at IS NULL
+    AND deleted_at IS NULL
 GROUP BY year
 ORDER BY year
 `
@@ -108,8 +117,8 @@ type GetCollectionYearDistributionRow struct {
 	Count int64       `json:"count"`
 }
 
-func (q *Queries) GetCollectionYearDistribution(ctx context.Context) ([]GetCollectionYearDistributionRow, error) {
-	rows, err := q.db.Query(ctx, getCollectionYearDistribution)
+func (q *Queries) GetCollectionYearDistribution(ctx context.Context, collectionID pgtype.UUID) ([]GetCollectionYearDistributionRow, error) {
+	rows, err := q.db.Query(ctx, getCollectionYearDistribution, collectionID)
 	if err != nil {
 		return nil, err
 	}
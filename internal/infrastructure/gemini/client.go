@@ -2,7 +2,6 @@ package gemini
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -10,6 +9,7 @@ import (
 	"time"
 
 	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/ai"
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
@@ -108,46 +108,53 @@ func (s *GeminiService) AnalyzeCoin(ctx context.Context, frontImagePath, backIma
 	// Ensure model has temperature set
 	model := s.client.GenerativeModel(modelName)
 	model.SetTemperature(temperature)
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = toGenaiSchema(ai.AnalysisSchema())
+
+	return ai.RunAnalysis(ctx, func(ctx context.Context, prompt string) (string, error) {
+		resp, err := model.GenerateContent(ctx,
+			genai.Text(prompt),
+			genai.ImageData("jpeg", frontData),
+			genai.ImageData("jpeg", backData),
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate content: %w", err)
+		}
 
-	promptGen := NewPromptGenerator()
-	prompt := promptGen.GetPrompt(lang)
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+			return "", fmt.Errorf("no content returned from gemini")
+		}
 
-	resp, err := model.GenerateContent(ctx,
-		genai.Text(prompt),
-		genai.ImageData("jpeg", frontData),
-		genai.ImageData("jpeg", backData),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
-	}
+		// Extract text from response
+		var responseText string
+		for _, part := range resp.Candidates[0].Content.Parts {
+			if txt, ok := part.(genai.Text); ok {
+				responseText += string(txt)
+			}
+		}
+		return responseText, nil
+	}, lang)
+}
 
-	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
-		return nil, fmt.Errorf("no content returned from gemini")
+func toGenaiSchema(s *ai.Schema) *genai.Schema {
+	gs := &genai.Schema{Description: s.Description, Required: s.Required}
+	switch s.Type {
+	case "object":
+		gs.Type = genai.TypeObject
+	case "integer":
+		gs.Type = genai.TypeInteger
+	case "number":
+		gs.Type = genai.TypeNumber
+	case "boolean":
+		gs.Type = genai.TypeBoolean
+	default:
+		gs.Type = genai.TypeString
 	}
-
-	// Extract text from response
-	var responseText string
-	for _, part := range resp.Candidates[0].Content.Parts {
-		if txt, ok := part.(genai.Text); ok {
-			responseText += string(txt)
+	if len(s.Properties) > 0 {
+		gs.Properties = make(map[string]*genai.Schema, len(s.Properties))
+		for name, p := range s.Properties {
+			gs.Properties[name] = toGenaiSchema(p)
 		}
 	}
-
-	// Clean up markdown code blocks if present
-	responseText = strings.TrimPrefix(responseText, "```json")
-	responseText = strings.TrimPrefix(responseText, "```")
-	responseText = strings.TrimSuffix(responseText, "```")
-	responseText = strings.TrimSpace(responseText)
-
-	var result domain.CoinAnalysisResult
-	if err := json.Unmarshal([]byte(responseText), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse gemini response: %w. Response: %s", err, responseText)
-	}
-
-	// Store raw details for debugging/extra info
-	var rawDetails map[string]any
-	_ = json.Unmarshal([]byte(responseText), &rawDetails)
-	result.RawDetails = rawDetails
-
-	return &result, nil
+	return gs
 }
@@ -45,6 +45,17 @@ type Issuer struct {
 	Name string `json:"name"`
 }
 
+// Issue is one year and mint of a type, as listed by Numista.
+type Issue struct {
+	ID            int    `json:"id"`
+	IsDated       bool   `json:"is_dated"`
+	Year          int    `json:"year"`
+	GregorianYear int    `json:"gregorian_year"`
+	MintLetter    string `json:"mint_letter"`
+	Mintage       int64  `json:"mintage"`
+	Comment       string `json:"comment"`
+}
+
 func (c *Client) SearchTypes(ctx context.Context, query, category, year, issuer string, count int) (*TypeSearchResponse, error) {
 	if c.APIKey == "" {
 		return nil, fmt.Errorf("numista API key is not set")
@@ -145,3 +156,47 @@ func (c *Client) GetType(ctx context.Context, id int) (map[string]any, error) {
 
 	return typeDetails, nil
 }
+
+// GetIssues returns the issues of a type: its years and mint marks.
+func (c *Client) GetIssues(ctx context.Context, typeID int) ([]Issue, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("numista API key is not set")
+	}
+
+	u, err := url.Parse(fmt.Sprintf("%s/types/%d/issues", c.BaseURL, typeID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("lang", "es")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Numista-API-Key", c.APIKey)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Error("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("numista api error: %s - %s", resp.Status, string(body))
+	}
+
+	var issues []Issue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,257 @@
+// Package pdf renders printable documents with a pure Go PDF writer, so it
+// needs no system libraries in the container.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Field is one labelled value.
+type Field struct {
+	Label string
+	Value string
+}
+
+// CatalogueItem is one coin of the catalogue. Images returns the PNG or
+// JPEG data of the front and back, either of which may be empty. It is only
+// called when the page of the coin is drawn, so the images of one coin at a
+// time are held besides what the document has embedded.
+type CatalogueItem struct {
+	Title  string
+	Images func() (front, back []byte)
+	Fields []Field
+}
+
+type Catalogue struct {
+	Title     string
+	Subtitle  string
+	Generated time.Time
+	Summary   []Field
+	// Breakdown is printed as a table below the summary, e.g. coins per
+	// country. Rows that do not fit on the page are left out.
+	BreakdownTitle string
+	Breakdown      []Field
+	Items          []CatalogueItem
+}
+
+// A4 portrait, in millimetres
+const (
+	pageW        = 210.0
+	pageH        = 297.0
+	margin       = 15.0
+	contentW     = pageW - 2*margin
+	headerH      = 12.0
+	lineH        = 6.0
+	itemsPerPage = 4
+	itemH        = (pageH - 2*margin - headerH - 10) / itemsPerPage
+	imageSize    = 44.0
+	tocPerPage   = 38
+	summaryRows  = 30
+)
+
+// RenderCatalogue writes the catalogue: a cover, a table of contents, the
+// summary, and the coins four to a page, with page numbers on every page
+// but the cover. The layout is fixed, so the table of contents can give page
+// numbers before the pages are drawn.
+func RenderCatalogue(w io.Writer, c Catalogue) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.AliasNbPages("")
+	pdf.SetTitle(c.Title, true)
+	pdf.SetCreationDate(c.Generated)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(pageH - margin + 3)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(contentW/2, 5, tr(c.Title), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentW/2, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	tocPages := (len(c.Items) + tocPerPage - 1) / tocPerPage
+	if tocPages == 0 {
+		tocPages = 1
+	}
+	summaryPage := 2 + tocPages
+	itemPage := func(i int) int { return summaryPage + 1 + i/itemsPerPage }
+
+	links := make([]int, len(c.Items))
+	for i := range links {
+		links[i] = pdf.AddLink()
+	}
+	summaryLink := pdf.AddLink()
+
+	// Cover
+	pdf.AddPage()
+	pdf.SetY(pageH / 3)
+	pdf.SetFont("Helvetica", "B", 28)
+	pdf.MultiCell(contentW, 12, tr(c.Title), "", "C", false)
+	if c.Subtitle != "" {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "", 14)
+		pdf.MultiCell(contentW, 8, tr(c.Subtitle), "", "C", false)
+	}
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.SetTextColor(90, 90, 90)
+	pdf.CellFormat(contentW, 6, fmt.Sprintf("%d coins", len(c.Items)), "", 1, "C", false, 0, "")
+	pdf.CellFormat(contentW, 6, c.Generated.Format("2 January 2006"), "", 1, "C", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+
+	// Table of contents
+	for p := 0; p < tocPages; p++ {
+		pdf.AddPage()
+		heading(pdf, tr, "Contents")
+		pdf.SetFont("Helvetica", "", 10)
+		if p == 0 {
+			tocLine(pdf, tr, "Summary", summaryPage, summaryLink)
+		}
+		for i := p * tocPerPage; i < len(c.Items) && i < (p+1)*tocPerPage; i++ {
+			tocLine(pdf, tr, c.Items[i].Title, itemPage(i), links[i])
+		}
+	}
+
+	// Summary
+	pdf.AddPage()
+	pdf.SetLink(summaryLink, 0, -1)
+	heading(pdf, tr, "Summary")
+	fieldTable(pdf, tr, c.Summary, len(c.Summary))
+	if len(c.Breakdown) > 0 {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(contentW, 8, tr(c.BreakdownTitle), "", 1, "L", false, 0, "")
+		fieldTable(pdf, tr, c.Breakdown, summaryRows-len(c.Summary))
+	}
+
+	// Coins
+	for i, item := range c.Items {
+		slot := i % itemsPerPage
+		if slot == 0 {
+			pdf.AddPage()
+			pdf.SetFont("Helvetica", "", 9)
+			pdf.SetTextColor(120, 120, 120)
+			pdf.CellFormat(contentW, headerH-4, tr(c.Title), "B", 1, "L", false, 0, "")
+			pdf.SetTextColor(0, 0, 0)
+		}
+		top := margin + headerH + float64(slot)*itemH
+		pdf.SetLink(links[i], top, -1)
+		drawItem(pdf, tr, item, i, top)
+	}
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to render catalogue: %w", err)
+	}
+	return nil
+}
+
+func heading(pdf *fpdf.Fpdf, tr func(string) string, text string) {
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(contentW, 12, tr(text), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+}
+
+func tocLine(pdf *fpdf.Fpdf, tr func(string) string, title string, page, link int) {
+	numW := 15.0
+	pdf.CellFormat(contentW-numW, lineH, fit(pdf, tr(title), contentW-numW-2), "", 0, "L", false, link, "")
+	pdf.CellFormat(numW, lineH, fmt.Sprintf("%d", page), "", 1, "R", false, link, "")
+}
+
+func fieldTable(pdf *fpdf.Fpdf, tr func(string) string, fields []Field, limit int) {
+	for i, f := range fields {
+		if i >= limit {
+			break
+		}
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(70, lineH+1, tr(f.Label), "B", 0, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(contentW-70, lineH+1, fit(pdf, tr(f.Value), contentW-72), "B", 1, "L", false, 0, "")
+	}
+}
+
+func drawItem(pdf *fpdf.Fpdf, tr func(string) string, item CatalogueItem, n int, top float64) {
+	var front, back []byte
+	if item.Images != nil {
+		front, back = item.Images()
+	}
+	x := margin
+	for side, data := range [][]byte{front, back} {
+		drawImage(pdf, fmt.Sprintf("coin-%d-%d", n, side), data, x, top+4)
+		x += imageSize + 4
+	}
+
+	textX := margin + 2*(imageSize+4) + 2
+	textW := pageW - margin - textX
+	pdf.SetXY(textX, top+3)
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(textW, 6, fit(pdf, tr(item.Title), textW), "", 2, "L", false, 0, "")
+	pdf.Ln(1)
+	for _, f := range item.Fields {
+		if pdf.GetY()+4.5 > top+itemH-2 {
+			break
+		}
+		pdf.SetX(textX)
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(26, 4.5, tr(f.Label), "", 0, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.CellFormat(textW-26, 4.5, fit(pdf, tr(f.Value), textW-27), "", 1, "L", false, 0, "")
+	}
+
+	pdf.SetDrawColor(210, 210, 210)
+	pdf.Line(margin, top+itemH, pageW-margin, top+itemH)
+	pdf.SetDrawColor(0, 0, 0)
+}
+
+// drawImage fits an image into a square box. An image the PDF writer cannot
+// read leaves an empty frame instead of failing the document.
+func drawImage(pdf *fpdf.Fpdf, name string, data []byte, x, y float64) {
+	var imageType string
+	switch http.DetectContentType(data) {
+	case "image/png":
+		imageType = "PNG"
+	case "image/jpeg":
+		imageType = "JPG"
+	}
+	if imageType != "" {
+		info := pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(data))
+		if pdf.Err() {
+			pdf.ClearError()
+		} else if info != nil {
+			w, h := info.Extent()
+			scale := imageSize / max(w, h)
+			w, h = w*scale, h*scale
+			pdf.ImageOptions(name, x+(imageSize-w)/2, y+(imageSize-h)/2, w, h, false, fpdf.ImageOptions{ImageType: imageType}, 0, "")
+			return
+		}
+	}
+	pdf.SetDrawColor(210, 210, 210)
+	pdf.Rect(x, y, imageSize, imageSize, "D")
+	pdf.SetDrawColor(0, 0, 0)
+}
+
+// fit shortens text with an ellipsis so it fits in width w at the current
+// font.
+func fit(pdf *fpdf.Fpdf, text string, w float64) string {
+	if pdf.GetStringWidth(text) <= w {
+		return text
+	}
+	ellipsis := "\x85" // … in cp1252
+	b := []byte(text)
+	for len(b) > 0 && pdf.GetStringWidth(string(b)+ellipsis) > w {
+		b = b[:len(b)-1]
+	}
+	return string(b) + ellipsis
+}
@@ -0,0 +1,193 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/qr"
+	"github.com/go-pdf/fpdf"
+)
+
+// ErrInvalidLayout is returned for label sheets that do not fit on the page.
+var ErrInvalidLayout = errors.New("invalid label layout")
+
+// LabelLayout places labels on a sheet, in millimetres. Labels are filled
+// row by row from the top left corner.
+type LabelLayout struct {
+	PageSize    string // A4 or Letter
+	LabelWidth  float64
+	LabelHeight float64
+	Columns     int
+	Rows        int // 0 fits as many as the page takes
+	MarginTop   float64
+	MarginLeft  float64
+	GapX        float64
+	GapY        float64
+	Outline     bool // frame each label, to cut along
+}
+
+// DefaultLabelLayout fits 2x2 holders: four 48 mm squares across A4,
+// five rows down.
+var DefaultLabelLayout = LabelLayout{
+	PageSize:    "A4",
+	LabelWidth:  48,
+	LabelHeight: 48,
+	Columns:     4,
+	MarginTop:   10,
+	MarginLeft:  9,
+	Outline:     true,
+}
+
+// Label is the content of one label: a few lines of text, the first in bold,
+// and a link printed as a QR code.
+type Label struct {
+	Lines []string
+	Link  string
+}
+
+const labelPadding = 2.0
+
+var pageSizes = map[string][2]float64{
+	"A4":     {210, 297},
+	"Letter": {215.9, 279.4},
+}
+
+// LabelsPerSheet checks the layout and returns how many labels fit on one
+// sheet.
+func (l LabelLayout) LabelsPerSheet() (int, error) {
+	page, ok := pageSizes[l.PageSize]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown page size %q", ErrInvalidLayout, l.PageSize)
+	}
+	if l.LabelWidth < 15 || l.LabelHeight < 15 {
+		return 0, fmt.Errorf("%w: labels must be at least 15 mm wide and high", ErrInvalidLayout)
+	}
+	if l.Columns < 1 || l.Rows < 0 || l.MarginTop < 0 || l.MarginLeft < 0 || l.GapX < 0 || l.GapY < 0 {
+		return 0, fmt.Errorf("%w: columns, rows, margins and gaps cannot be negative", ErrInvalidLayout)
+	}
+	if l.MarginLeft+float64(l.Columns)*l.LabelWidth+float64(l.Columns-1)*l.GapX > page[0] {
+		return 0, fmt.Errorf("%w: %d columns do not fit across the page", ErrInvalidLayout, l.Columns)
+	}
+	fit := int((page[1] - l.MarginTop + l.GapY) / (l.LabelHeight + l.GapY))
+	rows := l.Rows
+	if rows == 0 {
+		rows = fit
+	}
+	if rows < 1 || rows > fit {
+		return 0, fmt.Errorf("%w: %d rows do not fit down the page", ErrInvalidLayout, rows)
+	}
+	return l.Columns * rows, nil
+}
+
+// RenderLabels writes the labels as sheets to print. skip leaves that many
+// positions empty at the start of the first sheet, to finish a partly used
+// one.
+func RenderLabels(w io.Writer, layout LabelLayout, labels []Label, skip int) error {
+	perSheet, err := layout.LabelsPerSheet()
+	if err != nil {
+		return err
+	}
+	if skip < 0 || skip >= perSheet {
+		return fmt.Errorf("%w: offset must be between 0 and %d", ErrInvalidLayout, perSheet-1)
+	}
+
+	page := pageSizes[layout.PageSize]
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: page[0], Ht: page[1]},
+	})
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("Coin labels", true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+	for i, label := range labels {
+		pos := (i + skip) % perSheet
+		if i == 0 || pos == 0 {
+			pdf.AddPage()
+		}
+		x := layout.MarginLeft + float64(pos%layout.Columns)*(layout.LabelWidth+layout.GapX)
+		y := layout.MarginTop + float64(pos/layout.Columns)*(layout.LabelHeight+layout.GapY)
+		if err := drawLabel(pdf, tr, layout, label, x, y); err != nil {
+			return err
+		}
+	}
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to render labels: %w", err)
+	}
+	return nil
+}
+
+// drawLabel prints the text lines from the top and the QR code in the
+// bottom right corner; lines beside the code are narrowed to clear it.
+func drawLabel(pdf *fpdf.Fpdf, tr func(string) string, layout LabelLayout, label Label, x, y float64) error {
+	w, h := layout.LabelWidth, layout.LabelHeight
+	if layout.Outline {
+		pdf.SetDrawColor(200, 200, 200)
+		pdf.Rect(x, y, w, h, "D")
+		pdf.SetDrawColor(0, 0, 0)
+	}
+
+	qrSize := 0.0
+	if label.Link != "" {
+		code, err := qr.Encode(label.Link)
+		if err != nil {
+			return fmt.Errorf("failed to encode label link: %w", err)
+		}
+		qrSize = min(w, h) * 0.5
+		drawQR(pdf, code, x+w-labelPadding-qrSize, y+h-labelPadding-qrSize, qrSize)
+	}
+
+	fontSize := min(9, h/6*72/25.4)
+	lineH := fontSize * 25.4 / 72 * 1.25
+	qrTop := y + h - labelPadding - qrSize - 1
+	textY := y + labelPadding
+	for i, line := range label.Lines {
+		if textY+lineH > y+h-labelPadding {
+			break
+		}
+		textW := w - 2*labelPadding
+		if qrSize > 0 && textY+lineH > qrTop {
+			textW -= qrSize + 1
+		}
+		style := ""
+		if i == 0 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, fontSize)
+		pdf.SetXY(x+labelPadding, textY)
+		pdf.CellFormat(textW, lineH, fit(pdf, tr(line), textW), "", 0, "L", false, 0, "")
+		textY += lineH
+	}
+	return nil
+}
+
+// drawQR draws the code as filled squares in a size x size box, quiet zone
+// included.
+func drawQR(pdf *fpdf.Fpdf, code *qr.Code, x, y, size float64) {
+	const quiet = 4
+	module := size / float64(code.Size+2*quiet)
+	x += quiet * module
+	y += quiet * module
+	pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < code.Size; row++ {
+		// One rectangle per run of dark modules keeps the file small
+		for col := 0; col < code.Size; {
+			if !code.Black(col, row) {
+				col++
+				continue
+			}
+			start := col
+			for col < code.Size && code.Black(col, row) {
+				col++
+			}
+			pdf.Rect(x+float64(start)*module, y+float64(row)*module, float64(col-start)*module, module, "F")
+		}
+	}
+}
@@ -3,12 +3,14 @@ package infrastructure
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/antonioparicio/numismaticapp/internal/domain"
 	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -26,7 +28,11 @@ func NewPostgresCoinRepository(pool *pgxpool.Pool) *PostgresCoinRepository {
 }
 
 func (r *PostgresCoinRepository) Save(ctx context.Context, coin *domain.Coin) error {
-	params, err := toDBParams(coin)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	params, err := toDBParams(coin, cid)
 	if err != nil {
 		return err
 	}
@@ -45,6 +51,7 @@ func (r *PostgresCoinRepository) Save(ctx context.Context, coin *domain.Coin) er
 	for _, img := range coin.Images {
 		imgParams := db.CreateCoinImageParams{
 			CoinID:           pgtype.UUID{Bytes: coin.ID, Valid: true},
+			CollectionID:     cid,
 			ImageType:        db.ImageType(img.ImageType),
 			Side:             db.CoinSide(img.Side),
 			Path:             img.Path,
@@ -64,8 +71,13 @@ func (r *PostgresCoinRepository) Save(ctx context.Context, coin *domain.Coin) er
 }
 
 func (r *PostgresCoinRepository) AddImage(ctx context.Context, img domain.CoinImage) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
 	imgParams := db.CreateCoinImageParams{
 		CoinID:           pgtype.UUID{Bytes: img.CoinID, Valid: true},
+		CollectionID:     cid,
 		ImageType:        db.ImageType(img.ImageType),
 		Side:             db.CoinSide(img.Side),
 		Path:             img.Path,
@@ -83,7 +95,14 @@ func (r *PostgresCoinRepository) AddImage(ctx context.Context, img domain.CoinIm
 }
 
 func (r *PostgresCoinRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Coin, error) {
-	row, err := r.q.GetCoin(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetCoin(ctx, db.GetCoinParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get coin: %w", err)
 	}
@@ -103,47 +122,51 @@ func (r *PostgresCoinRepository) GetByID(ctx context.Context, id uuid.UUID) (*do
 	return coin, nil
 }
 
-func (r *PostgresCoinRepository) List(ctx context.Context, filter domain.CoinFilter) ([]*domain.Coin, error) {
-	params := db.ListCoinsParams{
-		Limit:     int32(filter.Limit),
-		Offset:    int32(filter.Offset),
-		GroupID:   toNullInt4Ptr(filter.GroupID),
-		Year:      toNullInt4Ptr(filter.Year),
-		Country:   toNullStringPtr(filter.Country),
-		Query:     toNullStringPtr(filter.Query),
-		MinPrice:  toNullFloat8Ptr(filter.MinPrice),
-		MaxPrice:  toNullFloat8Ptr(filter.MaxPrice),
-		Grade:     toNullStringPtr(filter.Grade),
-		Material:  toNullStringPtr(filter.Material),
-		MinYear:   toNullInt4Ptr(filter.MinYear),
-		MaxYear:   toNullInt4Ptr(filter.MaxYear),
-		SortBy:    toNullStringPtr(filter.SortBy),
-		SortOrder: toNullStringPtr(filter.SortOrder),
+func (r *PostgresCoinRepository) Exists(ctx context.Context, id uuid.UUID) (bool, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return false, err
 	}
-
-	rows, err := r.q.ListCoins(ctx, params)
+	exists, err := r.q.CoinExists(ctx, db.CoinExistsParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list coins: %w", err)
+		return false, fmt.Errorf("failed to check coin: %w", err)
 	}
-
-	// Use the shared helper which generates coins and batch-fetches images
-	return r.rowsToCoins(ctx, rows)
+	return exists, nil
 }
 
 func (r *PostgresCoinRepository) Count(ctx context.Context) (int64, error) {
-	return r.q.CountCoins(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return r.q.CountCoins(ctx, cid)
 }
 
 func (r *PostgresCoinRepository) GetTotalValue(ctx context.Context) (float64, error) {
-	return r.q.GetTotalValue(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return r.q.GetTotalValue(ctx, cid)
 }
 
 func (r *PostgresCoinRepository) GetAverageValue(ctx context.Context) (float64, error) {
-	return r.q.GetAverageValue(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return r.q.GetAverageValue(ctx, cid)
 }
 
 func (r *PostgresCoinRepository) ListTopValuable(ctx context.Context) ([]*domain.Coin, error) {
-	rows, err := r.q.ListTopValuableCoins(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListTopValuableCoins(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list top valuable coins: %w", err)
 	}
@@ -151,7 +174,11 @@ func (r *PostgresCoinRepository) ListTopValuable(ctx context.Context) ([]*domain
 }
 
 func (r *PostgresCoinRepository) ListRecent(ctx context.Context) ([]*domain.Coin, error) {
-	rows, err := r.q.ListRecentCoins(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListRecentCoins(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list recent coins: %w", err)
 	}
@@ -159,7 +186,11 @@ func (r *PostgresCoinRepository) ListRecent(ctx context.Context) ([]*domain.Coin
 }
 
 func (r *PostgresCoinRepository) GetMaterialDistribution(ctx context.Context) (map[string]int, error) {
-	rows, err := r.q.GetMaterialDistribution(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetMaterialDistribution(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get material distribution: %w", err)
 	}
@@ -171,7 +202,11 @@ func (r *PostgresCoinRepository) GetMaterialDistribution(ctx context.Context) (m
 }
 
 func (r *PostgresCoinRepository) GetGradeDistribution(ctx context.Context) (map[string]int, error) {
-	rows, err := r.q.GetGradeDistribution(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetGradeDistribution(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get grade distribution: %w", err)
 	}
@@ -183,7 +218,11 @@ func (r *PostgresCoinRepository) GetGradeDistribution(ctx context.Context) (map[
 }
 
 func (r *PostgresCoinRepository) GetAllValues(ctx context.Context) ([]float64, error) {
-	rows, err := r.q.GetAllValues(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetAllValues(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all values: %w", err)
 	}
@@ -196,7 +235,11 @@ func (r *PostgresCoinRepository) GetAllValues(ctx context.Context) ([]float64, e
 }
 
 func (r *PostgresCoinRepository) GetCountryDistribution(ctx context.Context) (map[string]int, error) {
-	rows, err := r.q.GetCountryDistribution(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetCountryDistribution(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get country distribution: %w", err)
 	}
@@ -208,7 +251,11 @@ func (r *PostgresCoinRepository) GetCountryDistribution(ctx context.Context) (ma
 }
 
 func (r *PostgresCoinRepository) GetOldestCoin(ctx context.Context) (*domain.Coin, error) {
-	row, err := r.q.GetOldestCoin(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetOldestCoin(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get oldest coin: %w", err)
 	}
@@ -216,7 +263,14 @@ func (r *PostgresCoinRepository) GetOldestCoin(ctx context.Context) (*domain.Coi
 }
 
 func (r *PostgresCoinRepository) GetRarestCoins(ctx context.Context, limit int) ([]*domain.Coin, error) {
-	rows, err := r.q.GetRarestCoins(ctx, int32(limit))
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetRarestCoins(ctx, db.GetRarestCoinsParams{
+		CollectionID: cid,
+		Limit:        int32(limit),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rarest coins: %w", err)
 	}
@@ -224,7 +278,11 @@ func (r *PostgresCoinRepository) GetRarestCoins(ctx context.Context, limit int)
 }
 
 func (r *PostgresCoinRepository) GetGroupDistribution(ctx context.Context) (map[string]int, error) {
-	rows, err := r.q.GetGroupDistribution(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetGroupDistribution(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group distribution: %w", err)
 	}
@@ -235,8 +293,28 @@ func (r *PostgresCoinRepository) GetGroupDistribution(ctx context.Context) (map[
 	return dist, nil
 }
 
+func (r *PostgresCoinRepository) GetTagDistribution(ctx context.Context) (map[string]int, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetTagDistribution(ctx, cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag distribution: %w", err)
+	}
+	dist := make(map[string]int)
+	for _, row := range rows {
+		dist[row.TagName] = int(row.Count)
+	}
+	return dist, nil
+}
+
 func (r *PostgresCoinRepository) GetGroupStats(ctx context.Context) ([]domain.GroupStat, error) {
-	rows, err := r.q.GetGroupStats(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetGroupStats(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group stats: %w", err)
 	}
@@ -252,6 +330,7 @@ func (r *PostgresCoinRepository) GetGroupStats(ctx context.Context) ([]domain.Gr
 		stats[i] = domain.GroupStat{
 			GroupID:   groupID,
 			GroupName: row.GroupName,
+			ParentID:  toIntPtr(row.ParentID),
 			Count:     row.Count,
 			MinVal:    row.MinVal,
 			MaxVal:    row.MaxVal,
@@ -264,7 +343,14 @@ func (r *PostgresCoinRepository) GetGroupStats(ctx context.Context) ([]domain.Gr
 }
 
 func (r *PostgresCoinRepository) GetTotalWeightByMaterial(ctx context.Context, materialLike string) (float64, error) {
-	weight, err := r.q.GetTotalWeightByMaterial(ctx, toNullString(materialLike))
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	weight, err := r.q.GetTotalWeightByMaterial(ctx, db.GetTotalWeightByMaterialParams{
+		CollectionID: cid,
+		Material:     toNullString(materialLike),
+	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to get total weight: %w", err)
 	}
@@ -272,7 +358,11 @@ func (r *PostgresCoinRepository) GetTotalWeightByMaterial(ctx context.Context, m
 }
 
 func (r *PostgresCoinRepository) GetHeaviestCoin(ctx context.Context) (*domain.Coin, error) {
-	row, err := r.q.GetHeaviestCoin(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetHeaviestCoin(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get heaviest coin: %w", err)
 	}
@@ -280,7 +370,11 @@ func (r *PostgresCoinRepository) GetHeaviestCoin(ctx context.Context) (*domain.C
 }
 
 func (r *PostgresCoinRepository) GetSmallestCoin(ctx context.Context) (*domain.Coin, error) {
-	row, err := r.q.GetSmallestCoin(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetSmallestCoin(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get smallest coin: %w", err)
 	}
@@ -288,7 +382,11 @@ func (r *PostgresCoinRepository) GetSmallestCoin(ctx context.Context) (*domain.C
 }
 
 func (r *PostgresCoinRepository) GetRandomCoin(ctx context.Context) (*domain.Coin, error) {
-	row, err := r.q.GetRandomCoin(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetRandomCoin(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get random coin: %w", err)
 	}
@@ -296,7 +394,11 @@ func (r *PostgresCoinRepository) GetRandomCoin(ctx context.Context) (*domain.Coi
 }
 
 func (r *PostgresCoinRepository) GetAllCoins(ctx context.Context) ([]*domain.Coin, error) {
-	rows, err := r.q.GetAllCoins(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetAllCoins(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all coins: %w", err)
 	}
@@ -330,13 +432,21 @@ func (r *PostgresCoinRepository) rowsToCoins(ctx context.Context, rows []db.Coin
 			return nil, err
 		}
 		c.Images = []domain.CoinImage{} // Initialize empty slice
+		c.Tags = []string{}
 		coins[i] = c
 		coinIDs[i] = pgtype.UUID{Bytes: c.ID, Valid: true}
 		coinMap[c.ID] = c
 	}
 
 	// Batch fetch images
-	images, err := r.q.ListCoinImagesByCoinIDs(ctx, coinIDs)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	images, err := r.q.ListCoinImagesByCoinIDs(ctx, db.ListCoinImagesByCoinIDsParams{
+		Column1:      coinIDs,
+		CollectionID: cid,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to batch list coin images: %w", err)
 	}
@@ -348,11 +458,29 @@ func (r *PostgresCoinRepository) rowsToCoins(ctx context.Context, rows []db.Coin
 		}
 	}
 
+	// Batch fetch tags
+	tags, err := r.q.ListCoinTagsByCoinIDs(ctx, db.ListCoinTagsByCoinIDsParams{
+		Column1:      coinIDs,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to batch list coin tags: %w", err)
+	}
+	for _, t := range tags {
+		if coin, exists := coinMap[uuid.UUID(t.CoinID.Bytes)]; exists {
+			coin.Tags = append(coin.Tags, t.Name)
+		}
+	}
+
 	return coins, nil
 }
 
 func (r *PostgresCoinRepository) Update(ctx context.Context, coin *domain.Coin) error {
-	createParams, err := toDBParams(coin)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	createParams, err := toDBParams(coin, cid)
 	if err != nil {
 		return err
 	}
@@ -368,16 +496,43 @@ func (r *PostgresCoinRepository) Update(ctx context.Context, coin *domain.Coin)
 	return nil
 }
 
+func (r *PostgresCoinRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.UpdateCoinStatus(ctx, db.UpdateCoinStatusParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		Status:       status,
+		CollectionID: cid,
+	}); err != nil {
+		return fmt.Errorf("failed to update coin status: %w", err)
+	}
+	return nil
+}
+
 func (r *PostgresCoinRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.q.DeleteCoin(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.DeleteCoin(ctx, db.DeleteCoinParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	})
 }
 
 // Group Repository Implementation
 
 func (r *PostgresCoinRepository) AddGalleryImage(ctx context.Context, img domain.CoinGalleryImage) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
 	params := db.CreateCoinGalleryImageParams{
-		CoinID: pgtype.UUID{Bytes: img.CoinID, Valid: true},
-		Path:   img.Path,
+		CoinID:       pgtype.UUID{Bytes: img.CoinID, Valid: true},
+		Path:         img.Path,
+		CollectionID: cid,
 	}
 	if _, err := r.q.CreateCoinGalleryImage(ctx, params); err != nil {
 		return fmt.Errorf("failed to add gallery image: %w", err)
@@ -386,14 +541,28 @@ func (r *PostgresCoinRepository) AddGalleryImage(ctx context.Context, img domain
 }
 
 func (r *PostgresCoinRepository) RemoveGalleryImage(ctx context.Context, id uuid.UUID) error {
-	if err := r.q.DeleteCoinGalleryImage(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteCoinGalleryImage(ctx, db.DeleteCoinGalleryImageParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	}); err != nil {
 		return fmt.Errorf("failed to remove gallery image: %w", err)
 	}
 	return nil
 }
 
 func (r *PostgresCoinRepository) ListGalleryImages(ctx context.Context, coinID uuid.UUID) ([]domain.CoinGalleryImage, error) {
-	rows, err := r.q.ListCoinGalleryImages(ctx, pgtype.UUID{Bytes: coinID, Valid: true})
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListCoinGalleryImages(ctx, db.ListCoinGalleryImagesParams{
+		CoinID:       pgtype.UUID{Bytes: coinID, Valid: true},
+		CollectionID: cid,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list gallery images: %w", err)
 	}
@@ -411,14 +580,22 @@ func (r *PostgresCoinRepository) ListGalleryImages(ctx context.Context, coinID u
 }
 
 func (r *PostgresCoinRepository) GetCoinStats(ctx context.Context, id uuid.UUID) (*domain.CoinStats, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1. Get Percentiles
-	percentiles, err := r.q.GetCoinPercentiles(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	percentiles, err := r.q.GetCoinPercentiles(ctx, db.GetCoinPercentilesParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get coin percentiles: %w", err)
 	}
 
 	// 2. Get Year Distribution
-	yearRows, err := r.q.GetCollectionYearDistribution(ctx)
+	yearRows, err := r.q.GetCollectionYearDistribution(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get year distribution: %w", err)
 	}
@@ -430,7 +607,7 @@ func (r *PostgresCoinRepository) GetCoinStats(ctx context.Context, id uuid.UUID)
 	}
 
 	// 3. Get Grade Distribution
-	gradeRows, err := r.q.GetCollectionGradeDistribution(ctx)
+	gradeRows, err := r.q.GetCollectionGradeDistribution(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get grade distribution: %w", err)
 	}
@@ -452,52 +629,113 @@ func (r *PostgresCoinRepository) GetCoinStats(ctx context.Context, id uuid.UUID)
 }
 
 type PostgresGroupRepository struct {
-	q *db.Queries
+	q  *db.Queries
+	db *pgxpool.Pool
 }
 
 func NewPostgresGroupRepository(pool *pgxpool.Pool) *PostgresGroupRepository {
 	return &PostgresGroupRepository{
-		q: db.New(pool),
+		q:  db.New(pool),
+		db: pool,
 	}
 }
 
 func (r *PostgresGroupRepository) Create(ctx context.Context, name, description string) (*domain.Group, error) {
+	return r.create(ctx, name, description, nil)
+}
+
+func (r *PostgresGroupRepository) CreateSmart(ctx context.Context, name, description string, filter domain.SmartFilter) (*domain.Group, error) {
+	return r.create(ctx, name, description, &filter)
+}
+
+func (r *PostgresGroupRepository) create(ctx context.Context, name, description string, filter *domain.SmartFilter) (*domain.Group, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	smart, err := smartFilterToDB(filter)
+	if err != nil {
+		return nil, err
+	}
 	row, err := r.q.CreateGroup(ctx, db.CreateGroupParams{
-		Name:        name,
-		Description: toNullString(description),
+		Name:         name,
+		Description:  toNullString(description),
+		CollectionID: cid,
+		SmartFilter:  smart,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create group: %w", err)
 	}
-	return toDomainGroup(row), nil
+	return toDomainGroup(row)
+}
+
+func (r *PostgresGroupRepository) GetByID(ctx context.Context, id int) (*domain.Group, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetGroup(ctx, db.GetGroupParams{
+		ID:           int32(id),
+		CollectionID: cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get group: %w", err)
+	}
+	return toDomainGroup(row)
 }
 
 func (r *PostgresGroupRepository) GetByName(ctx context.Context, name string) (*domain.Group, error) {
-	row, err := r.q.GetGroupByName(ctx, name)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetGroupByName(ctx, db.GetGroupByNameParams{
+		Name:         name,
+		CollectionID: cid,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get group by name: %w", err)
 	}
-	return toDomainGroup(row), nil
+	return toDomainGroup(row)
 }
 
 func (r *PostgresGroupRepository) List(ctx context.Context) ([]*domain.Group, error) {
-	rows, err := r.q.ListGroups(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListGroups(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list groups: %w", err)
 	}
 
 	groups := make([]*domain.Group, len(rows))
 	for i, row := range rows {
-		groups[i] = toDomainGroup(row)
+		if groups[i], err = toDomainGroup(row); err != nil {
+			return nil, err
+		}
 	}
 	return groups, nil
 }
 
 func (r *PostgresGroupRepository) Update(ctx context.Context, group *domain.Group) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	smart, err := smartFilterToDB(group.Filter)
+	if err != nil {
+		return err
+	}
 	row, err := r.q.UpdateGroup(ctx, db.UpdateGroupParams{
-		ID:          int32(group.ID),
-		Name:        group.Name,
-		Description: toNullString(group.Description),
+		ID:           int32(group.ID),
+		Name:         group.Name,
+		Description:  toNullString(group.Description),
+		CollectionID: cid,
+		SmartFilter:  smart,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update group: %w", err)
@@ -508,14 +746,96 @@ func (r *PostgresGroupRepository) Update(ctx context.Context, group *domain.Grou
 	return nil
 }
 
+// Move checks for cycles again with the groups locked, since the service
+// checks on a list that another move may have changed since.
+func (r *PostgresGroupRepository) Move(ctx context.Context, id int, parentID *int) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin group move: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := r.q.WithTx(tx)
+	if parentID != nil {
+		rows, err := q.LockGroupParents(ctx, cid)
+		if err != nil {
+			return fmt.Errorf("failed to lock groups: %w", err)
+		}
+		groups := make([]*domain.Group, len(rows))
+		for i, row := range rows {
+			groups[i] = &domain.Group{ID: int(row.ID), ParentID: toIntPtr(row.ParentID)}
+		}
+		if domain.InSubtree(groups, *parentID, id) {
+			return domain.ErrGroupCycle
+		}
+	}
+	if err := q.MoveGroup(ctx, db.MoveGroupParams{
+		ID:           int32(id),
+		ParentID:     toNullInt4Ptr(parentID),
+		CollectionID: cid,
+	}); err != nil {
+		return fmt.Errorf("failed to move group: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit group move: %w", err)
+	}
+	return nil
+}
+
 func (r *PostgresGroupRepository) Delete(ctx context.Context, id int) error {
-	return r.q.DeleteGroup(ctx, int32(id))
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin group delete: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := r.q.WithTx(tx)
+	if err := q.LiftSubgroups(ctx, db.LiftSubgroupsParams{ID: int32(id), CollectionID: cid}); err != nil {
+		return fmt.Errorf("failed to move subgroups: %w", err)
+	}
+	if err := q.DeleteGroup(ctx, db.DeleteGroupParams{
+		ID:           int32(id),
+		CollectionID: cid,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *PostgresGroupRepository) Exists(ctx context.Context, id int) (bool, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return false, err
+	}
+	exists, err := r.q.GroupExists(ctx, db.GroupExistsParams{
+		ID:           int32(id),
+		CollectionID: cid,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check group: %w", err)
+	}
+	return exists, nil
 }
 
 func (r *PostgresGroupRepository) AddImage(ctx context.Context, img domain.GroupImage) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
 	params := db.CreateGroupImageParams{
-		GroupID: int32(img.GroupID),
-		Path:    img.Path,
+		GroupID:      int32(img.GroupID),
+		Path:         img.Path,
+		CollectionID: cid,
 	}
 	if _, err := r.q.CreateGroupImage(ctx, params); err != nil {
 		return fmt.Errorf("failed to add group image: %w", err)
@@ -524,14 +844,28 @@ func (r *PostgresGroupRepository) AddImage(ctx context.Context, img domain.Group
 }
 
 func (r *PostgresGroupRepository) RemoveImage(ctx context.Context, id uuid.UUID) error {
-	if err := r.q.DeleteGroupImage(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteGroupImage(ctx, db.DeleteGroupImageParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	}); err != nil {
 		return fmt.Errorf("failed to remove group image: %w", err)
 	}
 	return nil
 }
 
 func (r *PostgresGroupRepository) ListImages(ctx context.Context, groupID int) ([]domain.GroupImage, error) {
-	rows, err := r.q.ListGroupImages(ctx, int32(groupID))
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListGroupImages(ctx, db.ListGroupImagesParams{
+		GroupID:      int32(groupID),
+		CollectionID: cid,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list group images: %w", err)
 	}
@@ -554,15 +888,30 @@ func (r *PostgresCoinRepository) coinFromDB(ctx context.Context, row db.Coin) (*
 		return nil, err
 	}
 
-	images, err := r.q.ListCoinImagesByCoinID(ctx, row.ID)
+	images, err := r.q.ListCoinImagesByCoinID(ctx, db.ListCoinImagesByCoinIDParams{
+		CoinID:       row.ID,
+		CollectionID: row.CollectionID,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list images: %w", err)
 	}
 	coin.Images = toDomainImages(images)
+
+	tags, err := r.q.ListCoinTagsByCoinIDs(ctx, db.ListCoinTagsByCoinIDsParams{
+		Column1:      []pgtype.UUID{row.ID},
+		CollectionID: row.CollectionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	coin.Tags = make([]string, len(tags))
+	for i, t := range tags {
+		coin.Tags[i] = t.Name
+	}
 	return coin, nil
 }
 
-func toDBParams(coin *domain.Coin) (db.CreateCoinParams, error) {
+func toDBParams(coin *domain.Coin, collectionID pgtype.UUID) (db.CreateCoinParams, error) {
 	geminiDetailsBytes, err := json.Marshal(coin.GeminiDetails)
 	if err != nil {
 		return db.CreateCoinParams{}, fmt.Errorf("failed to marshal gemini details: %w", err)
@@ -573,6 +922,21 @@ func toDBParams(coin *domain.Coin) (db.CreateCoinParams, error) {
 		return db.CreateCoinParams{}, fmt.Errorf("failed to marshal numista details: %w", err)
 	}
 
+	provenance := coin.Provenance
+	if provenance == nil {
+		provenance = domain.Provenance{}
+	}
+	provenanceBytes, err := json.Marshal(provenance)
+	if err != nil {
+		return db.CreateCoinParams{}, fmt.Errorf("failed to marshal field provenance: %w", err)
+	}
+
+	// Coins created outside the job pipeline are complete from the start
+	status := coin.Status
+	if status == "" {
+		status = domain.CoinStatusReady
+	}
+
 	return db.CreateCoinParams{
 		ID:                pgtype.UUID{Bytes: coin.ID, Valid: true},
 		Name:              toNullString(coin.Name),
@@ -606,6 +970,15 @@ func toDBParams(coin *domain.Coin) (db.CreateCoinParams, error) {
 		GeminiModel:       toNullString(coin.GeminiModel),
 		GeminiTemperature: toNumeric(coin.GeminiTemperature),
 		NumistaSearch:     toNullString(coin.NumistaSearch),
+		Ruler:             coin.Ruler,
+		Orientation:       coin.Orientation,
+		Series:            coin.Series,
+		CommemoratedTopic: coin.CommemoratedTopic,
+		Status:            status,
+		SaleChannel:       toNullString(coin.SaleChannel),
+		FieldProvenance:   provenanceBytes,
+		CollectionID:      collectionID,
+		MintMark:          coin.MintMark,
 	}, nil
 }
 
@@ -626,6 +999,13 @@ func toDomainCoin(row db.Coin) (*domain.Coin, error) {
 		}
 	}
 
+	var provenance domain.Provenance
+	if len(row.FieldProvenance) > 0 {
+		if err := json.Unmarshal(row.FieldProvenance, &provenance); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal field provenance: %w", err)
+		}
+	}
+
 	// Helper to convert numeric to float64 (simplified for this example)
 	// In production, handle pgtype.Numeric carefully
 	minVal, _ := row.MinValue.Float64Value()
@@ -676,6 +1056,7 @@ func toDomainCoin(row db.Coin) (*domain.Coin, error) {
 		ID:                uuid.UUID(row.ID.Bytes),
 		Name:              row.Name.String,
 		Mint:              row.Mint.String,
+		MintMark:          row.MintMark,
 		Mintage:           mintageVO,
 		Country:           row.Country.String,
 		Year:              yearVO,
@@ -686,10 +1067,10 @@ func toDomainCoin(row db.Coin) (*domain.Coin, error) {
 		KMCode:            kmVO,
 		NumistaNumber:     int(row.NumistaNumber.Int32),
 		NumistaDetails:    numistaDetails,
-		Ruler:             "", // populated if needed
-		Orientation:       "",
-		Series:            "",
-		CommemoratedTopic: "",
+		Ruler:             row.Ruler,
+		Orientation:       row.Orientation,
+		Series:            row.Series,
+		CommemoratedTopic: row.CommemoratedTopic,
 		MinValue:          minVal.Float64,
 		MaxValue:          maxVal.Float64,
 		Grade:             gradeVO,
@@ -706,11 +1087,15 @@ func toDomainCoin(row db.Coin) (*domain.Coin, error) {
 		SoldAt:            soldAt,
 		PricePaid:         pricePaid.Float64,
 		SoldPrice:         soldPrice.Float64,
+		SaleChannel:       row.SaleChannel.String,
 		GeminiModel:       row.GeminiModel.String,
 		GeminiTemperature: geminiTemp.Float64,
 		NumistaSearch:     row.NumistaSearch.String,
+		Status:            row.Status,
+		Provenance:        provenance,
 		CreatedAt:         row.CreatedAt.Time,
 		UpdatedAt:         row.UpdatedAt.Time,
+		DeletedAt:         toTimePtr(row.DeletedAt),
 	}, nil
 }
 
@@ -736,13 +1121,43 @@ func toDomainImages(rows []db.CoinImage) []domain.CoinImage {
 	return images
 }
 
-func toDomainGroup(row db.Group) *domain.Group {
-	return &domain.Group{
+func toDomainGroup(row db.Group) (*domain.Group, error) {
+	group := &domain.Group{
 		ID:          int(row.ID),
 		Name:        row.Name,
 		Description: row.Description.String,
 		CreatedAt:   row.CreatedAt.Time,
+		ParentID:    toIntPtr(row.ParentID),
+	}
+	if len(row.SmartFilter) > 0 {
+		group.Filter = &domain.SmartFilter{}
+		if err := json.Unmarshal(row.SmartFilter, group.Filter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal smart group filter: %w", err)
+		}
+	}
+	return group, nil
+}
+
+// smartFilterToDB stores nil for ordinary groups.
+func smartFilterToDB(f *domain.SmartFilter) ([]byte, error) {
+	if f == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal smart group filter: %w", err)
 	}
+	return data, nil
+}
+
+// collectionID returns the collection the context is limited to. Queries on
+// collection data must not run without one.
+func collectionID(ctx context.Context) (pgtype.UUID, error) {
+	id, ok := domain.CollectionFromContext(ctx)
+	if !ok {
+		return pgtype.UUID{}, domain.ErrNoCollection
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, nil
 }
 
 func toNullString(s string) pgtype.Text {
@@ -787,6 +1202,14 @@ func toNullInt4Ptr(i *int) pgtype.Int4 {
 	}
 }
 
+func toIntPtr(i pgtype.Int4) *int {
+	if !i.Valid {
+		return nil
+	}
+	v := int(i.Int32)
+	return &v
+}
+
 func toNullStringPtr(s *string) pgtype.Text {
 	if s == nil {
 		return pgtype.Text{Valid: false}
@@ -819,11 +1242,16 @@ func toNullFloat8Ptr(f *float64) pgtype.Float8 {
 
 // MarkAsSold marks a coin as sold with price and channel
 func (r *PostgresCoinRepository) MarkAsSold(ctx context.Context, id uuid.UUID, soldAt time.Time, soldPrice float64, saleChannel string) (*domain.Coin, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	row, err := r.q.MarkCoinAsSold(ctx, db.MarkCoinAsSoldParams{
-		ID:          pgtype.UUID{Bytes: id, Valid: true},
-		SoldAt:      pgtype.Date{Time: soldAt, Valid: true},
-		SoldPrice:   toNumeric(soldPrice),
-		SaleChannel: toNullString(saleChannel),
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		SoldAt:       pgtype.Date{Time: soldAt, Valid: true},
+		SoldPrice:    toNumeric(soldPrice),
+		SaleChannel:  toNullString(saleChannel),
+		CollectionID: cid,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to mark coin as sold: %w", err)
@@ -833,7 +1261,11 @@ func (r *PostgresCoinRepository) MarkAsSold(ctx context.Context, id uuid.UUID, s
 
 // GetSaleChannels returns list of distinct sale channels
 func (r *PostgresCoinRepository) GetSaleChannels(ctx context.Context) ([]string, error) {
-	rows, err := r.q.GetDistinctSaleChannels(ctx)
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.GetDistinctSaleChannels(ctx, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sale channels: %w", err)
 	}
@@ -848,8 +1280,13 @@ func (r *PostgresCoinRepository) GetSaleChannels(ctx context.Context) ([]string,
 
 // AddLink adds a new coin link
 func (r *PostgresCoinRepository) AddLink(ctx context.Context, link *domain.CoinLink) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
 	params := db.AddCoinLinkParams{
 		CoinID:        pgtype.UUID{Bytes: link.CoinID, Valid: true},
+		CollectionID:  cid,
 		Url:           link.URL,
 		Name:          toNullString(link.Name),
 		OgTitle:       toNullString(link.OGTitle),
@@ -869,15 +1306,20 @@ func (r *PostgresCoinRepository) AddLink(ctx context.Context, link *domain.CoinL
 
 // GetLink retrieves a single link by ID
 func (r *PostgresCoinRepository) GetLink(ctx context.Context, linkID uuid.UUID) (*domain.CoinLink, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var l domain.CoinLink
 	var idBytes, coinIDBytes [16]byte
 	var pName, pOgTitle, pOgDesc, pOgImage pgtype.Text
 
-	err := r.db.QueryRow(ctx, `
+	err = r.db.QueryRow(ctx, `
 		SELECT id, coin_id, url, name, og_title, og_description, og_image, created_at
 		FROM coin_links
-		WHERE id = $1
-	`, pgtype.UUID{Bytes: linkID, Valid: true}).Scan(
+		WHERE id = $1 AND collection_id = $2
+	`, pgtype.UUID{Bytes: linkID, Valid: true}, cid).Scan(
 		&idBytes,
 		&coinIDBytes,
 		&l.URL,
@@ -903,11 +1345,15 @@ func (r *PostgresCoinRepository) GetLink(ctx context.Context, linkID uuid.UUID)
 
 // UpdateLink updates a coin link (e.g. refreshed OG data)
 func (r *PostgresCoinRepository) UpdateLink(ctx context.Context, link *domain.CoinLink) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
 	// Manual SQL because sqlc is not available to regenerate
-	_, err := r.db.Exec(ctx, `
+	_, err = r.db.Exec(ctx, `
 		UPDATE coin_links 
 		SET url = $1, name = $2, og_title = $3, og_description = $4, og_image = $5
-		WHERE id = $6
+		WHERE id = $6 AND collection_id = $7
 	`,
 		link.URL,
 		toNullString(link.Name),
@@ -915,6 +1361,7 @@ func (r *PostgresCoinRepository) UpdateLink(ctx context.Context, link *domain.Co
 		toNullString(link.OGDescription),
 		toNullString(link.OGImage),
 		pgtype.UUID{Bytes: link.ID, Valid: true},
+		cid,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update link: %w", err)
@@ -924,12 +1371,26 @@ func (r *PostgresCoinRepository) UpdateLink(ctx context.Context, link *domain.Co
 
 // RemoveLink removes a coin link
 func (r *PostgresCoinRepository) RemoveLink(ctx context.Context, linkID uuid.UUID) error {
-	return r.q.DeleteCoinLink(ctx, pgtype.UUID{Bytes: linkID, Valid: true})
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	return r.q.DeleteCoinLink(ctx, db.DeleteCoinLinkParams{
+		ID:           pgtype.UUID{Bytes: linkID, Valid: true},
+		CollectionID: cid,
+	})
 }
 
 // ListLinks lists all links for a coin
 func (r *PostgresCoinRepository) ListLinks(ctx context.Context, coinID uuid.UUID) ([]*domain.CoinLink, error) {
-	rows, err := r.q.ListCoinLinks(ctx, pgtype.UUID{Bytes: coinID, Valid: true})
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListCoinLinks(ctx, db.ListCoinLinksParams{
+		CoinID:       pgtype.UUID{Bytes: coinID, Valid: true},
+		CollectionID: cid,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list links: %w", err)
 	}
@@ -952,11 +1413,16 @@ func (r *PostgresCoinRepository) ListLinks(ctx context.Context, coinID uuid.UUID
 
 // GetAllImages returns all images for export features
 func (r *PostgresCoinRepository) GetAllImages(ctx context.Context) ([]domain.CoinImage, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	rows, err := r.db.Query(ctx, `
 		SELECT id, coin_id, image_type, side, path, extension, size, width, height, mime_type, original_filename, created_at, updated_at
 		FROM coin_images
+		WHERE collection_id = $1
 		ORDER BY coin_id, created_at
-	`)
+	`, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list all images: %w", err)
 	}
@@ -1001,11 +1467,16 @@ func (r *PostgresCoinRepository) GetAllImages(ctx context.Context) ([]domain.Coi
 
 // GetAllLinks returns all links for export features
 func (r *PostgresCoinRepository) GetAllLinks(ctx context.Context) ([]*domain.CoinLink, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
 	rows, err := r.db.Query(ctx, `
 		SELECT id, coin_id, url, name, og_title, og_description, og_image, created_at
 		FROM coin_links
+		WHERE collection_id = $1
 		ORDER BY coin_id, created_at
-	`)
+	`, cid)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list all links: %w", err)
 	}
@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+)
+
+// AppendAuditEntry stores the entry as the next revision of its entity and
+// fills in its ID, revision and timestamp. Writers of the same entity are
+// serialized with an advisory lock, so revisions never collide or skip.
+func (r *PostgresCoinRepository) AppendAuditEntry(ctx context.Context, e *domain.AuditEntry) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit changes: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin audit transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := r.q.WithTx(tx)
+	if err := q.LockAuditEntity(ctx, db.LockAuditEntityParams{EntityType: e.EntityType, EntityID: e.EntityID}); err != nil {
+		return fmt.Errorf("failed to lock audit entity: %w", err)
+	}
+	row, err := q.AppendAuditEntry(ctx, db.AppendAuditEntryParams{
+		EntityType:   e.EntityType,
+		EntityID:     e.EntityID,
+		Actor:        e.Actor,
+		Operation:    e.Operation,
+		Changes:      changes,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit audit entry: %w", err)
+	}
+
+	e.ID = row.ID
+	e.Revision = int(row.Revision)
+	e.CreatedAt = row.CreatedAt.Time
+	return nil
+}
+
+func (r *PostgresCoinRepository) ListAuditEntries(ctx context.Context, entityType, entityID string) ([]*domain.AuditEntry, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListAuditEntries(ctx, db.ListAuditEntriesParams{
+		EntityType:   entityType,
+		EntityID:     entityID,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	result := make([]*domain.AuditEntry, len(rows))
+	for i, row := range rows {
+		var changes []domain.FieldChange
+		if err := json.Unmarshal(row.Changes, &changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit changes: %w", err)
+		}
+		result[i] = &domain.AuditEntry{
+			ID:         row.ID,
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+			Revision:   int(row.Revision),
+			Actor:      row.Actor,
+			Operation:  row.Operation,
+			Changes:    changes,
+			CreatedAt:  row.CreatedAt.Time,
+		}
+	}
+	return result, nil
+}
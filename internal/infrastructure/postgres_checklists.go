@@ -0,0 +1,228 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresChecklistRepository struct {
+	q  *db.Queries
+	db *pgxpool.Pool
+}
+
+func NewPostgresChecklistRepository(pool *pgxpool.Pool) *PostgresChecklistRepository {
+	return &PostgresChecklistRepository{
+		q:  db.New(pool),
+		db: pool,
+	}
+}
+
+func (r *PostgresChecklistRepository) Create(ctx context.Context, c *domain.Checklist) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin checklist creation: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := r.q.WithTx(tx)
+	row, err := q.CreateChecklist(ctx, db.CreateChecklistParams{
+		CollectionID: cid,
+		Name:         c.Name,
+		Description:  c.Description,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create checklist: %w", err)
+	}
+	slots, err := addSlots(ctx, q, cid, row.ID, c.Slots)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit checklist: %w", err)
+	}
+
+	*c = *toDomainChecklist(row)
+	c.Slots = slots
+	return nil
+}
+
+func (r *PostgresChecklistRepository) GetByID(ctx context.Context, id int) (*domain.Checklist, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetChecklist(ctx, db.GetChecklistParams{
+		ID:           int32(id),
+		CollectionID: cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get checklist: %w", err)
+	}
+	slots, err := r.q.ListChecklistSlots(ctx, db.ListChecklistSlotsParams{
+		ChecklistID:  row.ID,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checklist slots: %w", err)
+	}
+
+	c := toDomainChecklist(row)
+	for _, s := range slots {
+		c.Slots = append(c.Slots, toDomainChecklistSlot(s))
+	}
+	return c, nil
+}
+
+func (r *PostgresChecklistRepository) List(ctx context.Context) ([]*domain.Checklist, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListChecklists(ctx, cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checklists: %w", err)
+	}
+	slots, err := r.q.ListAllChecklistSlots(ctx, cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checklist slots: %w", err)
+	}
+
+	checklists := make([]*domain.Checklist, len(rows))
+	byID := make(map[int32]*domain.Checklist, len(rows))
+	for i, row := range rows {
+		checklists[i] = toDomainChecklist(row)
+		byID[row.ID] = checklists[i]
+	}
+	for _, s := range slots {
+		if c, ok := byID[s.ChecklistID]; ok {
+			c.Slots = append(c.Slots, toDomainChecklistSlot(s))
+		}
+	}
+	return checklists, nil
+}
+
+func (r *PostgresChecklistRepository) Update(ctx context.Context, c *domain.Checklist) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	row, err := r.q.UpdateChecklist(ctx, db.UpdateChecklistParams{
+		ID:           int32(c.ID),
+		Name:         c.Name,
+		Description:  c.Description,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update checklist: %w", err)
+	}
+	c.UpdatedAt = row.UpdatedAt.Time
+	return nil
+}
+
+func (r *PostgresChecklistRepository) Delete(ctx context.Context, id int) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteChecklist(ctx, db.DeleteChecklistParams{ID: int32(id), CollectionID: cid}); err != nil {
+		return fmt.Errorf("failed to delete checklist: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresChecklistRepository) AddSlots(ctx context.Context, checklistID int, slots []domain.ChecklistSlot) ([]domain.ChecklistSlot, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin checklist slots update: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	added, err := addSlots(ctx, r.q.WithTx(tx), cid, int32(checklistID), slots)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit checklist slots: %w", err)
+	}
+	return added, nil
+}
+
+func (r *PostgresChecklistRepository) DeleteSlot(ctx context.Context, checklistID, slotID int) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteChecklistSlot(ctx, db.DeleteChecklistSlotParams{
+		ID:           int32(slotID),
+		ChecklistID:  int32(checklistID),
+		CollectionID: cid,
+	}); err != nil {
+		return fmt.Errorf("failed to delete checklist slot: %w", err)
+	}
+	return nil
+}
+
+// addSlots inserts the slots the checklist does not have yet and returns
+// them.
+func addSlots(ctx context.Context, q *db.Queries, cid pgtype.UUID, checklistID int32, slots []domain.ChecklistSlot) ([]domain.ChecklistSlot, error) {
+	added := []domain.ChecklistSlot{}
+	for _, s := range slots {
+		row, err := q.AddChecklistSlot(ctx, db.AddChecklistSlotParams{
+			ChecklistID:  checklistID,
+			CollectionID: cid,
+			NumistaID:    int32(s.NumistaID),
+			Year:         int32(s.Year),
+			MintMark:     s.MintMark,
+			Label:        s.Label,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue // Already in the checklist
+			}
+			return nil, fmt.Errorf("failed to add checklist slot: %w", err)
+		}
+		added = append(added, toDomainChecklistSlot(row))
+	}
+	return added, nil
+}
+
+func toDomainChecklist(row db.Checklist) *domain.Checklist {
+	return &domain.Checklist{
+		ID:          int(row.ID),
+		Name:        row.Name,
+		Description: row.Description,
+		Slots:       []domain.ChecklistSlot{},
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
+
+func toDomainChecklistSlot(row db.ChecklistSlot) domain.ChecklistSlot {
+	return domain.ChecklistSlot{
+		ID:        int(row.ID),
+		NumistaID: int(row.NumistaID),
+		Year:      int(row.Year),
+		MintMark:  row.MintMark,
+		Label:     row.Label,
+	}
+}
@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SaveNumistaEnrichment only touches coins of the collection in ctx.
+func (r *PostgresCoinRepository) SaveNumistaEnrichment(ctx context.Context, e *domain.NumistaEnrichment) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	var nextRetry pgtype.Timestamptz
+	if e.NextRetryAt != nil {
+		nextRetry = pgtype.Timestamptz{Time: *e.NextRetryAt, Valid: true}
+	}
+	var jobID pgtype.UUID
+	if e.JobID != nil {
+		jobID = pgtype.UUID{Bytes: *e.JobID, Valid: true}
+	}
+
+	if err := r.q.UpsertNumistaEnrichment(ctx, db.UpsertNumistaEnrichmentParams{
+		CoinID:       pgtype.UUID{Bytes: e.CoinID, Valid: true},
+		Status:       e.Status,
+		Attempts:     int32(e.Attempts),
+		LastError:    toNullString(e.LastError),
+		NextRetryAt:  nextRetry,
+		JobID:        jobID,
+		CollectionID: cid,
+	}); err != nil {
+		return fmt.Errorf("failed to save numista enrichment: %w", err)
+	}
+	return nil
+}
+
+// GetNumistaEnrichment returns nil if the coin was never queued for enrichment.
+func (r *PostgresCoinRepository) GetNumistaEnrichment(ctx context.Context, coinID uuid.UUID) (*domain.NumistaEnrichment, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetNumistaEnrichment(ctx, db.GetNumistaEnrichmentParams{
+		CoinID:       pgtype.UUID{Bytes: coinID, Valid: true},
+		CollectionID: cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get numista enrichment: %w", err)
+	}
+	return toDomainEnrichment(row), nil
+}
+
+func (r *PostgresCoinRepository) ListNumistaEnrichments(ctx context.Context, status string) ([]*domain.NumistaEnrichment, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListNumistaEnrichmentsByStatus(ctx, db.ListNumistaEnrichmentsByStatusParams{
+		Status:       status,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list numista enrichments: %w", err)
+	}
+
+	result := make([]*domain.NumistaEnrichment, len(rows))
+	for i, row := range rows {
+		e := toDomainEnrichment(db.NumistaEnrichment{
+			CoinID:      row.CoinID,
+			Status:      row.Status,
+			Attempts:    row.Attempts,
+			LastError:   row.LastError,
+			NextRetryAt: row.NextRetryAt,
+			JobID:       row.JobID,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+		})
+		e.CoinName = row.CoinName.String
+		e.Country = row.Country.String
+		e.Year = int(row.Year.Int32)
+		result[i] = e
+	}
+	return result, nil
+}
+
+func toDomainEnrichment(row db.NumistaEnrichment) *domain.NumistaEnrichment {
+	var jobID *uuid.UUID
+	if row.JobID.Valid {
+		id := uuid.UUID(row.JobID.Bytes)
+		jobID = &id
+	}
+
+	return &domain.NumistaEnrichment{
+		CoinID:      uuid.UUID(row.CoinID.Bytes),
+		Status:      row.Status,
+		Attempts:    int(row.Attempts),
+		LastError:   row.LastError.String,
+		NextRetryAt: toTimePtr(row.NextRetryAt),
+		JobID:       jobID,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,183 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CountMatching returns how many coins match the filter, ignoring its limit
+// and offset.
+func (r *PostgresCoinRepository) CountMatching(ctx context.Context, filter domain.CoinFilter) (int64, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var args sqlArgs
+	where := coinWhere(cid, filter, &args)
+
+	var n int64
+	if err := r.db.QueryRow(ctx, "SELECT count(*) FROM coins WHERE "+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count coins: %w", err)
+	}
+	return n, nil
+}
+
+// Matches reports whether the coin is in the collection and matches the
+// filter, with the conditions List uses.
+func (r *PostgresCoinRepository) Matches(ctx context.Context, filter domain.CoinFilter, id uuid.UUID) (bool, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return false, err
+	}
+	var args sqlArgs
+	where := coinWhere(cid, filter, &args)
+	where += " AND id = " + args.add(pgtype.UUID{Bytes: id, Valid: true})
+
+	var ok bool
+	if err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM coins WHERE "+where+")", args...).Scan(&ok); err != nil {
+		return false, fmt.Errorf("failed to match coin: %w", err)
+	}
+	return ok, nil
+}
+
+// Facets counts the coins matching the filter by country, material, grade
+// and group, and builds the year and value histograms. Each one leaves its
+// own filter out.
+func (r *PostgresCoinRepository) Facets(ctx context.Context, filter domain.CoinFilter) (*domain.CoinFacets, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	facets := &domain.CoinFacets{}
+	for _, f := range []struct {
+		column string
+		skip   string
+		dst    *[]domain.FacetBucket
+	}{
+		{"country", filterCountry, &facets.Countries},
+		{"material", filterMaterial, &facets.Materials},
+		{"grade", filterGrade, &facets.Grades},
+	} {
+		var args sqlArgs
+		where := coinWhere(cid, filter, &args, f.skip)
+		query := fmt.Sprintf(`
+			SELECT %[1]s, count(*) FROM coins
+			WHERE %[2]s AND %[1]s IS NOT NULL AND %[1]s <> ''
+			GROUP BY %[1]s
+			ORDER BY count(*) DESC, %[1]s`, f.column, where)
+		if *f.dst, err = r.facetBuckets(ctx, query, args, false); err != nil {
+			return nil, fmt.Errorf("failed to count coins by %s: %w", f.column, err)
+		}
+	}
+
+	var args sqlArgs
+	where := coinWhere(cid, filter, &args, filterGroup)
+	query := `
+		SELECT g.id::text, g.name, f.n FROM (
+			SELECT group_id, count(*) AS n FROM coins
+			WHERE ` + where + ` AND group_id IS NOT NULL
+			GROUP BY group_id
+		) f
+		JOIN groups g ON g.id = f.group_id
+		ORDER BY f.n DESC, g.name`
+	if facets.Groups, err = r.facetBuckets(ctx, query, args, true); err != nil {
+		return nil, fmt.Errorf("failed to count coins by group: %w", err)
+	}
+
+	for _, h := range []struct {
+		column  string
+		skip    string
+		integer bool
+		dst     *[]domain.HistogramBucket
+	}{
+		{"year", filterYear, true, &facets.Years},
+		{"max_value", filterValue, false, &facets.Values},
+	} {
+		var args sqlArgs
+		where := coinWhere(cid, filter, &args, h.skip)
+		query := fmt.Sprintf(`
+			SELECT %[1]s::float8, count(*) FROM coins
+			WHERE %[2]s AND %[1]s IS NOT NULL
+			GROUP BY %[1]s`, h.column, where)
+		counts, err := r.valueCounts(ctx, query, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count coins by %s: %w", h.column, err)
+		}
+		*h.dst = domain.NewHistogram(counts, h.integer)
+	}
+
+	return facets, nil
+}
+
+// GetFilterStats computes the stats of GetGroupStats over the coins matching
+// the filter, ignoring its limit and offset.
+func (r *PostgresCoinRepository) GetFilterStats(ctx context.Context, filter domain.CoinFilter) (*domain.GroupStat, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var args sqlArgs
+	where := coinWhere(cid, filter, &args)
+
+	stat := &domain.GroupStat{}
+	err = r.db.QueryRow(ctx, `
+		SELECT count(*),
+			COALESCE(MIN(min_value), 0)::float8,
+			COALESCE(MAX(max_value), 0)::float8,
+			COALESCE(AVG(max_value), 0)::float8,
+			COALESCE(MIN(NULLIF(year, 0)), 0)::int,
+			COALESCE(MAX(NULLIF(year, 0)), 0)::int
+		FROM coins WHERE `+where, args...).Scan(
+		&stat.Count, &stat.MinVal, &stat.MaxVal, &stat.AvgVal, &stat.MinYear, &stat.MaxYear)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get filter stats: %w", err)
+	}
+	return stat, nil
+}
+
+func (r *PostgresCoinRepository) facetBuckets(ctx context.Context, query string, args sqlArgs, labelled bool) ([]domain.FacetBucket, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []domain.FacetBucket{}
+	for rows.Next() {
+		var b domain.FacetBucket
+		if labelled {
+			err = rows.Scan(&b.Value, &b.Label, &b.Count)
+		} else {
+			err = rows.Scan(&b.Value, &b.Count)
+		}
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+func (r *PostgresCoinRepository) valueCounts(ctx context.Context, query string, args sqlArgs) (map[float64]int64, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[float64]int64)
+	for rows.Next() {
+		var v float64
+		var n int64
+		if err := rows.Scan(&v, &n); err != nil {
+			return nil, err
+		}
+		counts[v] = n
+	}
+	return counts, rows.Err()
+}
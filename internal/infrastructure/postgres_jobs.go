@@ -0,0 +1,208 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresJobRepository struct {
+	q *db.Queries
+}
+
+func NewPostgresJobRepository(pool *pgxpool.Pool) *PostgresJobRepository {
+	return &PostgresJobRepository{
+		q: db.New(pool),
+	}
+}
+
+// Enqueue stores the job in the collection of the context, where the worker
+// will run it.
+func (r *PostgresJobRepository) Enqueue(ctx context.Context, job *domain.Job) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	payload := job.Payload
+	if len(payload) == 0 {
+		payload = json.RawMessage("{}")
+	}
+	var coinID pgtype.UUID
+	if job.CoinID != nil {
+		coinID = pgtype.UUID{Bytes: *job.CoinID, Valid: true}
+	}
+
+	row, err := r.q.CreateJob(ctx, db.CreateJobParams{
+		ID:           pgtype.UUID{Bytes: job.ID, Valid: true},
+		Kind:         job.Kind,
+		CoinID:       coinID,
+		Payload:      payload,
+		MaxAttempts:  int32(job.MaxAttempts),
+		CollectionID: cid,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	*job = *toDomainJob(row)
+	return nil
+}
+
+func (r *PostgresJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Job, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetJob(ctx, db.GetJobParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return toDomainJob(row), nil
+}
+
+func (r *PostgresJobRepository) ClaimNext(ctx context.Context) (*domain.Job, error) {
+	row, err := r.q.ClaimNextJob(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+	return toDomainJob(row), nil
+}
+
+func (r *PostgresJobRepository) Complete(ctx context.Context, id uuid.UUID) error {
+	if err := r.q.CompleteJob(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) Retry(ctx context.Context, id uuid.UUID, lastError string, runAt time.Time) error {
+	if err := r.q.RetryJob(ctx, db.RetryJobParams{
+		ID:        pgtype.UUID{Bytes: id, Valid: true},
+		LastError: toNullString(lastError),
+		RunAt:     pgtype.Timestamptz{Time: runAt, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("failed to reschedule job: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) Fail(ctx context.Context, id uuid.UUID, lastError string) error {
+	if err := r.q.FailJob(ctx, db.FailJobParams{
+		ID:        pgtype.UUID{Bytes: id, Valid: true},
+		LastError: toNullString(lastError),
+	}); err != nil {
+		return fmt.Errorf("failed to mark job as failed: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) RequeueRunning(ctx context.Context) (int64, error) {
+	n, err := r.q.RequeueRunningJobs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue running jobs: %w", err)
+	}
+	return n, nil
+}
+
+func (r *PostgresJobRepository) StartStep(ctx context.Context, jobID uuid.UUID, name string) error {
+	if err := r.q.StartJobStep(ctx, db.StartJobStepParams{
+		JobID: pgtype.UUID{Bytes: jobID, Valid: true},
+		Name:  name,
+	}); err != nil {
+		return fmt.Errorf("failed to start job step: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) CompleteStep(ctx context.Context, jobID uuid.UUID, name string, output json.RawMessage) error {
+	if err := r.q.CompleteJobStep(ctx, db.CompleteJobStepParams{
+		JobID:  pgtype.UUID{Bytes: jobID, Valid: true},
+		Name:   name,
+		Output: output,
+	}); err != nil {
+		return fmt.Errorf("failed to complete job step: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) FailStep(ctx context.Context, jobID uuid.UUID, name, lastError string) error {
+	if err := r.q.FailJobStep(ctx, db.FailJobStepParams{
+		JobID:     pgtype.UUID{Bytes: jobID, Valid: true},
+		Name:      name,
+		LastError: toNullString(lastError),
+	}); err != nil {
+		return fmt.Errorf("failed to fail job step: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresJobRepository) ListSteps(ctx context.Context, jobID uuid.UUID) ([]domain.JobStep, error) {
+	rows, err := r.q.ListJobSteps(ctx, pgtype.UUID{Bytes: jobID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job steps: %w", err)
+	}
+
+	steps := make([]domain.JobStep, len(rows))
+	for i, row := range rows {
+		steps[i] = domain.JobStep{
+			Name:       row.Name,
+			Status:     row.Status,
+			Attempts:   int(row.Attempts),
+			LastError:  row.LastError.String,
+			Output:     row.Output,
+			StartedAt:  toTimePtr(row.StartedAt),
+			FinishedAt: toTimePtr(row.FinishedAt),
+		}
+	}
+	return steps, nil
+}
+
+func toDomainJob(row db.Job) *domain.Job {
+	var coinID *uuid.UUID
+	if row.CoinID.Valid {
+		id := uuid.UUID(row.CoinID.Bytes)
+		coinID = &id
+	}
+
+	return &domain.Job{
+		ID:           uuid.UUID(row.ID.Bytes),
+		Kind:         row.Kind,
+		CoinID:       coinID,
+		CollectionID: uuid.UUID(row.CollectionID.Bytes),
+		Status:       row.Status,
+		Payload:      row.Payload,
+		Attempts:     int(row.Attempts),
+		MaxAttempts:  int(row.MaxAttempts),
+		LastError:    row.LastError.String,
+		RunAt:        row.RunAt.Time,
+		StartedAt:    toTimePtr(row.StartedAt),
+		FinishedAt:   toTimePtr(row.FinishedAt),
+		CreatedAt:    row.CreatedAt.Time,
+		UpdatedAt:    row.UpdatedAt.Time,
+	}
+}
+
+func toTimePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
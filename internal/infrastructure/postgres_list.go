@@ -0,0 +1,260 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// coinColumns are the columns of db.Coin, in the order of coinScanTargets.
+const coinColumns = `id, name, mint, mintage, country, year, face_value, currency, material, description, km_code, min_value, max_value, grade, technical_notes, gemini_details, numista_details, group_id, personal_notes, weight_g, diameter_mm, thickness_mm, edge, shape, numista_number, acquired_at, sold_at, price_paid, sold_price, sale_channel, gemini_model, gemini_temperature, numista_search, ruler, orientation, series, commemorated_topic, created_at, updated_at, status, field_provenance, deleted_at, collection_id, search_vector, mint_mark`
+
+func coinScanTargets(i *db.Coin) []any {
+	return []any{
+		&i.ID, &i.Name, &i.Mint, &i.Mintage, &i.Country, &i.Year, &i.FaceValue, &i.Currency, &i.Material, &i.Description,
+		&i.KmCode, &i.MinValue, &i.MaxValue, &i.Grade, &i.TechnicalNotes, &i.GeminiDetails, &i.NumistaDetails, &i.GroupID,
+		&i.PersonalNotes, &i.WeightG, &i.DiameterMm, &i.ThicknessMm, &i.Edge, &i.Shape, &i.NumistaNumber, &i.AcquiredAt,
+		&i.SoldAt, &i.PricePaid, &i.SoldPrice, &i.SaleChannel, &i.GeminiModel, &i.GeminiTemperature, &i.NumistaSearch,
+		&i.Ruler, &i.Orientation, &i.Series, &i.CommemoratedTopic, &i.CreatedAt, &i.UpdatedAt, &i.Status,
+		&i.FieldProvenance, &i.DeletedAt, &i.CollectionID, &i.SearchVector, &i.MintMark,
+	}
+}
+
+// coinSortColumns maps the sort_by values to their column and its type, for
+// reading sort values back from cursors. Migration 018 indexes each one the
+// way coinPageQuery walks it.
+var coinSortColumns = map[string]string{
+	"year":       "int",
+	"min_value":  "numeric",
+	"max_value":  "numeric",
+	"created_at": "timestamptz",
+	"country":    "text",
+	"name":       "text",
+}
+
+// coinSort is the resolved order of a listing. Ties are broken by the coin
+// ID, so every coin has its own place for a cursor to point at.
+type coinSort struct {
+	by   string
+	desc bool
+}
+
+// sortOf resolves the sort of a filter: by relevance for searches unless
+// SortBy says otherwise, newest first by default. Relevance always puts the
+// best match first.
+func sortOf(f domain.CoinFilter, searching bool) coinSort {
+	by := ""
+	if f.SortBy != nil {
+		by = *f.SortBy
+	}
+	if by == "" && searching {
+		by = "relevance"
+	}
+	if by == "relevance" {
+		if searching {
+			return coinSort{by: by, desc: true}
+		}
+		by = ""
+	}
+	if _, ok := coinSortColumns[by]; !ok {
+		return coinSort{by: "created_at", desc: true}
+	}
+	return coinSort{by: by, desc: f.SortOrder == nil || *f.SortOrder != "asc"}
+}
+
+func (s coinSort) String() string {
+	if s.desc {
+		return s.by + " desc"
+	}
+	return s.by + " asc"
+}
+
+// coinCursor is what the opaque cursors hold: the sort they were made for,
+// and the sort value (nil for NULL) and ID of the coin the page starts
+// after. Back cursors walk towards the start of the listing.
+type coinCursor struct {
+	Sort  string    `json:"s"`
+	Value *string   `json:"v"`
+	ID    uuid.UUID `json:"id"`
+	Back  bool      `json:"b,omitempty"`
+}
+
+func (c coinCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCoinCursor(s string, sort coinSort) (*coinCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	var c coinCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	if c.Sort != sort.String() {
+		return nil, fmt.Errorf("%w: it is for sort %q, not %q", domain.ErrInvalidCursor, c.Sort, sort)
+	}
+	return &c, nil
+}
+
+// coinPageQuery builds the query of a page. Rows are ordered by
+// (key IS NULL, key, id) all in one direction, which puts NULLs last going
+// up and first going down like Postgres does, and lets a cursor be a single
+// row comparison that the sort indexes can seek to. Back cursors walk the
+// order the other way; the caller reverses what they return.
+func coinPageQuery(cid pgtype.UUID, f domain.CoinFilter, cursor *coinCursor) (string, sqlArgs) {
+	var args sqlArgs
+	where := coinWhere(cid, f, &args)
+
+	search := prefixQuery(f.Query)
+	sort := sortOf(f, search.Valid)
+	key, typ := sort.by, coinSortColumns[sort.by]
+	rank, snippet := "0::float8", "''"
+	if search.Valid {
+		tsquery := fmt.Sprintf("to_tsquery('coin_search', %s)", args.add(search))
+		rank = fmt.Sprintf("ts_rank(%s, %s)", searchVector(f), tsquery)
+		snippet = fmt.Sprintf(`ts_headline('coin_search',
+			concat_ws(' … ', name, description, NULLIF(series, ''), NULLIF(commemorated_topic, ''), NULLIF(ruler, ''), technical_notes, personal_notes),
+			%s,
+			'StartSel=' || chr(2) || ', StopSel=' || chr(3) || ', MaxFragments=2, MaxWords=20, MinWords=8, FragmentDelimiter=" … "')`, tsquery)
+		if sort.by == "relevance" {
+			key, typ = rank, "real"
+		}
+		rank += "::float8"
+	}
+
+	desc := sort.desc
+	if cursor != nil && cursor.Back {
+		desc = !desc
+	}
+	dir, op := "ASC", ">"
+	if desc {
+		dir, op = "DESC", "<"
+	}
+
+	if cursor != nil {
+		if cursor.Value != nil {
+			where += fmt.Sprintf(" AND ((%s) IS NULL, %s, id) %s (false, %s::%s, %s)",
+				key, key, op, args.add(*cursor.Value), typ, args.add(pgtype.UUID{Bytes: cursor.ID, Valid: true}))
+		} else {
+			// Past a NULL only NULLs are compared, by ID
+			where += fmt.Sprintf(" AND ((%s) IS NULL, id) %s (true, %s)", key, op, args.add(pgtype.UUID{Bytes: cursor.ID, Valid: true}))
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s, (%s)::text, %s, %s
+		FROM coins
+		WHERE %s
+		ORDER BY (%s) IS NULL %s, %s %s, id %s`,
+		coinColumns, key, rank, snippet, where, key, dir, key, dir, dir)
+	if f.Limit > 0 {
+		// One more than asked tells whether there is another page
+		query += " LIMIT " + args.add(f.Limit+1)
+	}
+	if cursor == nil && f.Offset > 0 {
+		query += " OFFSET " + args.add(f.Offset)
+	}
+	return query, args
+}
+
+func (r *PostgresCoinRepository) List(ctx context.Context, filter domain.CoinFilter) ([]*domain.Coin, error) {
+	page, err := r.ListPage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return page.Coins, nil
+}
+
+// ListPage lists the coins matching the filter. Without a limit it lists
+// them all.
+func (r *PostgresCoinRepository) ListPage(ctx context.Context, filter domain.CoinFilter) (*domain.CoinPage, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	searching := prefixQuery(filter.Query).Valid
+	sort := sortOf(filter, searching)
+	var cursor *coinCursor
+	if filter.Cursor != "" {
+		if cursor, err = decodeCoinCursor(filter.Cursor, sort); err != nil {
+			return nil, err
+		}
+	}
+
+	query, args := coinPageQuery(cid, filter, cursor)
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coins: %w", err)
+	}
+	defer rows.Close()
+
+	type pageRow struct {
+		coin    db.Coin
+		key     *string
+		rank    float64
+		snippet string
+	}
+	var found []pageRow
+	for rows.Next() {
+		var row pageRow
+		if err := rows.Scan(append(coinScanTargets(&row.coin), &row.key, &row.rank, &row.snippet)...); err != nil {
+			return nil, fmt.Errorf("failed to list coins: %w", err)
+		}
+		found = append(found, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list coins: %w", err)
+	}
+
+	more := filter.Limit > 0 && len(found) > filter.Limit
+	if more {
+		found = found[:filter.Limit]
+	}
+	back := cursor != nil && cursor.Back
+	if back {
+		slices.Reverse(found)
+	}
+
+	coinRows := make([]db.Coin, len(found))
+	for i, row := range found {
+		coinRows[i] = row.coin
+	}
+	// Use the shared helper which generates coins and batch-fetches images
+	coins, err := r.rowsToCoins(ctx, coinRows)
+	if err != nil {
+		return nil, err
+	}
+	if searching {
+		for i, row := range found {
+			coins[i].Search = &domain.SearchMatch{
+				Rank:    row.rank,
+				Snippet: highlightSnippet(row.snippet),
+			}
+		}
+	}
+
+	page := &domain.CoinPage{Coins: coins}
+	if len(found) == 0 {
+		return page, nil
+	}
+	first, last := found[0], found[len(found)-1]
+	// Walking back, the page the cursor came from is always next; walking
+	// forward, there is a previous page once past the first one
+	if more || back {
+		page.NextCursor = coinCursor{Sort: sort.String(), Value: last.key, ID: uuid.UUID(last.coin.ID.Bytes)}.encode()
+	}
+	if back && more || !back && (cursor != nil || filter.Offset > 0) {
+		page.PrevCursor = coinCursor{Sort: sort.String(), Value: first.key, ID: uuid.UUID(first.coin.ID.Bytes), Back: true}.encode()
+	}
+	return page, nil
+}
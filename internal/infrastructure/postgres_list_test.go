@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortOf(t *testing.T) {
+	s := func(v string) *string { return &v }
+
+	assert.Equal(t, "created_at desc", sortOf(domain.CoinFilter{}, false).String())
+	assert.Equal(t, "relevance desc", sortOf(domain.CoinFilter{}, true).String())
+	assert.Equal(t, "created_at desc", sortOf(domain.CoinFilter{SortBy: s("relevance")}, false).String())
+	assert.Equal(t, "created_at desc", sortOf(domain.CoinFilter{SortBy: s("mintage")}, false).String())
+	assert.Equal(t, "year desc", sortOf(domain.CoinFilter{SortBy: s("year")}, true).String())
+	assert.Equal(t, "name asc", sortOf(domain.CoinFilter{SortBy: s("name"), SortOrder: s("asc")}, false).String())
+}
+
+func TestCoinCursor(t *testing.T) {
+	sort := coinSort{by: "year", desc: true}
+	year := "1975"
+	c := coinCursor{Sort: sort.String(), Value: &year, ID: uuid.New(), Back: true}
+
+	got, err := decodeCoinCursor(c.encode(), sort)
+	require.NoError(t, err)
+	assert.Equal(t, c, *got)
+
+	_, err = decodeCoinCursor(c.encode(), coinSort{by: "year"})
+	assert.True(t, errors.Is(err, domain.ErrInvalidCursor))
+	_, err = decodeCoinCursor("not a cursor", sort)
+	assert.True(t, errors.Is(err, domain.ErrInvalidCursor))
+}
+
+func TestCoinPageQuery(t *testing.T) {
+	cid := pgtype.UUID{Valid: true}
+	asc := "asc"
+	year := "year"
+	f := domain.CoinFilter{SortBy: &year, SortOrder: &asc, Limit: 10, Offset: 40}
+
+	t.Run("Offset", func(t *testing.T) {
+		query, args := coinPageQuery(cid, f, nil)
+		assert.Contains(t, query, "ORDER BY (year) IS NULL ASC, year ASC, id ASC")
+		assert.Contains(t, query, "LIMIT $2 OFFSET $3")
+		assert.Equal(t, sqlArgs{cid, 11, 40}, args)
+	})
+
+	t.Run("Next Cursor", func(t *testing.T) {
+		v := "1975"
+		id := uuid.New()
+		query, args := coinPageQuery(cid, f, &coinCursor{Value: &v, ID: id})
+		assert.Contains(t, query, "((year) IS NULL, year, id) > (false, $2::int, $3)")
+		assert.Contains(t, query, "ORDER BY (year) IS NULL ASC, year ASC, id ASC")
+		assert.False(t, strings.Contains(query, "OFFSET"), "cursors replace the offset")
+		assert.Equal(t, sqlArgs{cid, "1975", pgtype.UUID{Bytes: id, Valid: true}, 11}, args)
+	})
+
+	t.Run("Back From A Null", func(t *testing.T) {
+		query, _ := coinPageQuery(cid, f, &coinCursor{ID: uuid.New(), Back: true})
+		assert.Contains(t, query, "((year) IS NULL, id) < (true, $2)")
+		assert.Contains(t, query, "ORDER BY (year) IS NULL DESC, year DESC, id DESC")
+	})
+
+	t.Run("Relevance", func(t *testing.T) {
+		q := "peseta"
+		v := "0.06"
+		query, _ := coinPageQuery(cid, domain.CoinFilter{Query: &q, Limit: 10}, &coinCursor{Value: &v, ID: uuid.New()})
+		assert.Contains(t, query, "((ts_rank(search_vector, to_tsquery('coin_search', $4))) IS NULL, ts_rank(search_vector, to_tsquery('coin_search', $4)), id) < (false, $5::real, $6)")
+	})
+}
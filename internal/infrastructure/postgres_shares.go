@@ -0,0 +1,134 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresShareRepository struct {
+	q *db.Queries
+}
+
+func NewPostgresShareRepository(pool *pgxpool.Pool) *PostgresShareRepository {
+	return &PostgresShareRepository{
+		q: db.New(pool),
+	}
+}
+
+func (r *PostgresShareRepository) Create(ctx context.Context, link *domain.ShareLink, tokenHash string) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	filter, err := json.Marshal(link.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal share filter: %w", err)
+	}
+	var expiresAt pgtype.Timestamptz
+	if link.ExpiresAt != nil {
+		expiresAt = pgtype.Timestamptz{Time: *link.ExpiresAt, Valid: true}
+	}
+
+	row, err := r.q.CreateShareLink(ctx, db.CreateShareLinkParams{
+		CollectionID: cid,
+		Name:         link.Name,
+		TokenHash:    tokenHash,
+		GroupID:      toNullInt4Ptr(link.GroupID),
+		Filter:       filter,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create share link: %w", err)
+	}
+	created, err := toDomainShareLink(row)
+	if err != nil {
+		return err
+	}
+	*link = *created
+	return nil
+}
+
+func (r *PostgresShareRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.ShareLink, error) {
+	row, err := r.q.GetShareLinkByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	return toDomainShareLink(row)
+}
+
+func (r *PostgresShareRepository) List(ctx context.Context) ([]*domain.ShareLink, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListShareLinks(ctx, cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	links := make([]*domain.ShareLink, len(rows))
+	for i, row := range rows {
+		if links[i], err = toDomainShareLink(row); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+func (r *PostgresShareRepository) Delete(ctx context.Context, id uuid.UUID) (bool, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.DeleteShareLink(ctx, db.DeleteShareLinkParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete share link: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *PostgresShareRepository) RecordView(ctx context.Context, id uuid.UUID) error {
+	if err := r.q.RecordShareLinkView(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("failed to record share link view: %w", err)
+	}
+	return nil
+}
+
+func toDomainShareLink(row db.ShareLink) (*domain.ShareLink, error) {
+	var filter domain.ShareFilter
+	if len(row.Filter) > 0 {
+		if err := json.Unmarshal(row.Filter, &filter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal share filter: %w", err)
+		}
+	}
+	var groupID *int
+	if row.GroupID.Valid {
+		id := int(row.GroupID.Int32)
+		groupID = &id
+	}
+	return &domain.ShareLink{
+		ID:           row.ID.Bytes,
+		Name:         row.Name,
+		GroupID:      groupID,
+		Filter:       filter,
+		ViewCount:    row.ViewCount,
+		CreatedAt:    row.CreatedAt.Time,
+		LastViewedAt: toTimePtr(row.LastViewedAt),
+		ExpiresAt:    toTimePtr(row.ExpiresAt),
+		CollectionID: row.CollectionID.Bytes,
+	}, nil
+}
@@ -0,0 +1,203 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresTagRepository struct {
+	q  *db.Queries
+	db *pgxpool.Pool
+}
+
+func NewPostgresTagRepository(pool *pgxpool.Pool) *PostgresTagRepository {
+	return &PostgresTagRepository{
+		q:  db.New(pool),
+		db: pool,
+	}
+}
+
+func (r *PostgresTagRepository) Create(ctx context.Context, name string) (*domain.Tag, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.CreateTag(ctx, db.CreateTagParams{
+		CollectionID: cid,
+		Name:         name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return toDomainTag(row), nil
+}
+
+func (r *PostgresTagRepository) GetByID(ctx context.Context, id int) (*domain.Tag, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetTag(ctx, db.GetTagParams{
+		ID:           int32(id),
+		CollectionID: cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return toDomainTag(row), nil
+}
+
+func (r *PostgresTagRepository) GetByName(ctx context.Context, name string) (*domain.Tag, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetTagByName(ctx, db.GetTagByNameParams{
+		Lower:        name,
+		CollectionID: cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get tag by name: %w", err)
+	}
+	return toDomainTag(row), nil
+}
+
+func (r *PostgresTagRepository) List(ctx context.Context) ([]*domain.Tag, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListTags(ctx, cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	tags := make([]*domain.Tag, len(rows))
+	for i, row := range rows {
+		tags[i] = &domain.Tag{
+			ID:        int(row.ID),
+			Name:      row.Name,
+			CoinCount: row.CoinCount,
+			CreatedAt: row.CreatedAt.Time,
+		}
+	}
+	return tags, nil
+}
+
+func (r *PostgresTagRepository) Rename(ctx context.Context, id int, name string) (*domain.Tag, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.RenameTag(ctx, db.RenameTagParams{
+		ID:           int32(id),
+		Name:         name,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename tag: %w", err)
+	}
+	return toDomainTag(row), nil
+}
+
+func (r *PostgresTagRepository) Delete(ctx context.Context, id int) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteTag(ctx, db.DeleteTagParams{ID: int32(id), CollectionID: cid}); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) Merge(ctx context.Context, sourceID, targetID int) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin tag merge: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := r.q.WithTx(tx)
+	if err := q.MoveCoinTags(ctx, db.MoveCoinTagsParams{
+		TargetID:     int32(targetID),
+		SourceID:     int32(sourceID),
+		CollectionID: cid,
+	}); err != nil {
+		return fmt.Errorf("failed to move coin tags: %w", err)
+	}
+	if err := q.DeleteTag(ctx, db.DeleteTagParams{ID: int32(sourceID), CollectionID: cid}); err != nil {
+		return fmt.Errorf("failed to delete merged tag: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit tag merge: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) SetCoinTags(ctx context.Context, coinID uuid.UUID, names []string) ([]string, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	coin := pgtype.UUID{Bytes: coinID, Valid: true}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin coin tags update: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	q := r.q.WithTx(tx)
+	if err := q.DeleteCoinTags(ctx, db.DeleteCoinTagsParams{CoinID: coin, CollectionID: cid}); err != nil {
+		return nil, fmt.Errorf("failed to clear coin tags: %w", err)
+	}
+	stored := make([]string, 0, len(names))
+	for _, name := range names {
+		tag, err := q.UpsertTag(ctx, db.UpsertTagParams{CollectionID: cid, Name: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save tag %q: %w", name, err)
+		}
+		if err := q.AddCoinTag(ctx, db.AddCoinTagParams{CoinID: coin, TagID: tag.ID, CollectionID: cid}); err != nil {
+			return nil, fmt.Errorf("failed to tag coin: %w", err)
+		}
+		stored = append(stored, tag.Name)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit coin tags: %w", err)
+	}
+
+	// Same order as the coin queries
+	sort.Slice(stored, func(i, j int) bool {
+		return strings.ToLower(stored[i]) < strings.ToLower(stored[j])
+	})
+	return stored, nil
+}
+
+func toDomainTag(row db.Tag) *domain.Tag {
+	return &domain.Tag{
+		ID:        int(row.ID),
+		Name:      row.Name,
+		CreatedAt: row.CreatedAt.Time,
+	}
+}
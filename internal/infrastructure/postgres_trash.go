@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Trash hides a coin from listings and stats. Coins already in the trash keep
+// their original deletion time.
+// Trash reports false when no live coin of the collection has that ID.
+func (r *PostgresCoinRepository) Trash(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := r.q.TrashCoin(ctx, db.TrashCoinParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		DeletedAt:    pgtype.Timestamptz{Time: at, Valid: true},
+		CollectionID: cid,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to trash coin: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *PostgresCoinRepository) RestoreFromTrash(ctx context.Context, id uuid.UUID) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.RestoreCoin(ctx, db.RestoreCoinParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		CollectionID: cid,
+	}); err != nil {
+		return fmt.Errorf("failed to restore coin: %w", err)
+	}
+	return nil
+}
+
+// ListTrash returns the trashed coins, most recently deleted first.
+func (r *PostgresCoinRepository) ListTrash(ctx context.Context) ([]*domain.Coin, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.ListTrashedCoins(ctx, cid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trashed coins: %w", err)
+	}
+	return r.rowsToCoins(ctx, rows)
+}
+
+// ListTrashedBefore ignores the collection of the context: it serves the
+// purger, which cleans up every collection.
+func (r *PostgresCoinRepository) ListTrashedBefore(ctx context.Context, cutoff time.Time) (map[uuid.UUID][]uuid.UUID, error) {
+	rows, err := r.q.ListCoinsTrashedBefore(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired trash: %w", err)
+	}
+
+	ids := make(map[uuid.UUID][]uuid.UUID)
+	for _, row := range rows {
+		collection := uuid.UUID(row.CollectionID.Bytes)
+		ids[collection] = append(ids[collection], uuid.UUID(row.ID.Bytes))
+	}
+	return ids, nil
+}
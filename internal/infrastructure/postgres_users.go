@@ -0,0 +1,268 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresUserRepository struct {
+	q *db.Queries
+}
+
+func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{
+		q: db.New(pool),
+	}
+}
+
+func (r *PostgresUserRepository) Create(ctx context.Context, user *domain.User) error {
+	row, err := r.q.CreateUser(ctx, db.CreateUserParams{
+		Username:     user.Username,
+		PasswordHash: user.PasswordHash,
+		IsAdmin:      user.IsAdmin,
+		CollectionID: pgtype.UUID{Bytes: user.CollectionID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	*user = *toDomainUser(row)
+	return nil
+}
+
+func (r *PostgresUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	row, err := r.q.GetUser(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return toDomainUser(row), nil
+}
+
+func (r *PostgresUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	row, err := r.q.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return toDomainUser(row), nil
+}
+
+func (r *PostgresUserRepository) List(ctx context.Context) ([]*domain.User, error) {
+	rows, err := r.q.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	users := make([]*domain.User, len(rows))
+	for i, row := range rows {
+		users[i] = toDomainUser(row)
+	}
+	return users, nil
+}
+
+func (r *PostgresUserRepository) Count(ctx context.Context) (int64, error) {
+	n, err := r.q.CountUsers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return n, nil
+}
+
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	if err := r.q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+		ID:           pgtype.UUID{Bytes: id, Valid: true},
+		PasswordHash: passwordHash,
+	}); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.q.DeleteUser(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) CreateSession(ctx context.Context, tokenHash string, userID uuid.UUID, expiresAt time.Time) error {
+	if err := r.q.CreateSession(ctx, db.CreateSessionParams{
+		TokenHash: tokenHash,
+		UserID:    pgtype.UUID{Bytes: userID, Valid: true},
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) GetSessionUser(ctx context.Context, tokenHash string) (*domain.User, error) {
+	row, err := r.q.GetSessionUser(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return toDomainUser(row), nil
+}
+
+func (r *PostgresUserRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	if err := r.q.DeleteSession(ctx, tokenHash); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) DeleteUserSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := r.q.DeleteUserSessions(ctx, pgtype.UUID{Bytes: userID, Valid: true}); err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) DeleteExpiredSessions(ctx context.Context) error {
+	if err := r.q.DeleteExpiredSessions(ctx); err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) CreateAPIToken(ctx context.Context, token *domain.APIToken, tokenHash string) error {
+	var expiresAt pgtype.Timestamptz
+	if token.ExpiresAt != nil {
+		expiresAt = pgtype.Timestamptz{Time: *token.ExpiresAt, Valid: true}
+	}
+	row, err := r.q.CreateAPIToken(ctx, db.CreateAPITokenParams{
+		UserID:    pgtype.UUID{Bytes: token.UserID, Valid: true},
+		Name:      token.Name,
+		TokenHash: tokenHash,
+		Scopes:    token.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create api token: %w", err)
+	}
+	*token = *toDomainAPIToken(row)
+	return nil
+}
+
+func (r *PostgresUserRepository) GetAPIToken(ctx context.Context, tokenHash string) (*domain.APIToken, error) {
+	row, err := r.q.GetAPITokenByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+	return toDomainAPIToken(row), nil
+}
+
+func (r *PostgresUserRepository) ListAPITokens(ctx context.Context, userID uuid.UUID) ([]*domain.APIToken, error) {
+	rows, err := r.q.ListAPITokens(ctx, pgtype.UUID{Bytes: userID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	tokens := make([]*domain.APIToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = toDomainAPIToken(row)
+	}
+	return tokens, nil
+}
+
+func (r *PostgresUserRepository) DeleteAPIToken(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	n, err := r.q.DeleteAPIToken(ctx, db.DeleteAPITokenParams{
+		ID:     pgtype.UUID{Bytes: id, Valid: true},
+		UserID: pgtype.UUID{Bytes: userID, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete api token: %w", err)
+	}
+	return n > 0, nil
+}
+
+func (r *PostgresUserRepository) TouchAPIToken(ctx context.Context, id uuid.UUID) error {
+	if err := r.q.TouchAPIToken(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("failed to update api token: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresUserRepository) CreateCollection(ctx context.Context, name string) (*domain.Collection, error) {
+	row, err := r.q.CreateCollection(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collection: %w", err)
+	}
+	return toDomainCollection(row), nil
+}
+
+func (r *PostgresUserRepository) GetCollection(ctx context.Context, id uuid.UUID) (*domain.Collection, error) {
+	row, err := r.q.GetCollection(ctx, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+	return toDomainCollection(row), nil
+}
+
+func (r *PostgresUserRepository) ListCollections(ctx context.Context) ([]*domain.Collection, error) {
+	rows, err := r.q.ListCollections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	collections := make([]*domain.Collection, len(rows))
+	for i, row := range rows {
+		collections[i] = toDomainCollection(row)
+	}
+	return collections, nil
+}
+
+func toDomainCollection(row db.Collection) *domain.Collection {
+	return &domain.Collection{
+		ID:        row.ID.Bytes,
+		Name:      row.Name,
+		CreatedAt: row.CreatedAt.Time,
+	}
+}
+
+func toDomainAPIToken(row db.ApiToken) *domain.APIToken {
+	scopes := row.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return &domain.APIToken{
+		ID:         row.ID.Bytes,
+		UserID:     row.UserID.Bytes,
+		Name:       row.Name,
+		Scopes:     scopes,
+		CreatedAt:  row.CreatedAt.Time,
+		LastUsedAt: toTimePtr(row.LastUsedAt),
+		ExpiresAt:  toTimePtr(row.ExpiresAt),
+	}
+}
+
+func toDomainUser(row db.User) *domain.User {
+	return &domain.User{
+		ID:           row.ID.Bytes,
+		Username:     row.Username,
+		PasswordHash: row.PasswordHash,
+		IsAdmin:      row.IsAdmin,
+		CollectionID: row.CollectionID.Bytes,
+		CreatedAt:    row.CreatedAt.Time,
+		UpdatedAt:    row.UpdatedAt.Time,
+	}
+}
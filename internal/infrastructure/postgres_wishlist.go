@@ -0,0 +1,188 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/infrastructure/db"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresWishlistRepository struct {
+	q *db.Queries
+}
+
+func NewPostgresWishlistRepository(pool *pgxpool.Pool) *PostgresWishlistRepository {
+	return &PostgresWishlistRepository{
+		q: db.New(pool),
+	}
+}
+
+func (r *PostgresWishlistRepository) Create(ctx context.Context, entry *domain.WishlistEntry) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	row, err := r.q.CreateWishlistEntry(ctx, db.CreateWishlistEntryParams{
+		CollectionID:     cid,
+		NumistaID:        int32(entry.NumistaID),
+		Title:            entry.Title,
+		Issuer:           entry.Issuer,
+		ObverseThumbnail: entry.ObverseThumbnail,
+		ReverseThumbnail: entry.ReverseThumbnail,
+		Grade:            entry.Grade.String(),
+		MaxPrice:         toNumeric(entry.MaxPrice),
+		Priority:         int32(entry.Priority),
+		Notes:            entry.Notes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create wishlist entry: %w", err)
+	}
+	*entry = *toDomainWishlistEntry(row)
+	return nil
+}
+
+func (r *PostgresWishlistRepository) GetByID(ctx context.Context, id int) (*domain.WishlistEntry, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetWishlistEntry(ctx, db.GetWishlistEntryParams{
+		ID:           int32(id),
+		CollectionID: cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get wishlist entry: %w", err)
+	}
+	return toDomainWishlistEntry(row), nil
+}
+
+func (r *PostgresWishlistRepository) GetOpen(ctx context.Context, numistaID int) (*domain.WishlistEntry, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	row, err := r.q.GetOpenWishlistEntry(ctx, db.GetOpenWishlistEntryParams{
+		NumistaID:    int32(numistaID),
+		CollectionID: cid,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get open wishlist entry: %w", err)
+	}
+	return toDomainWishlistEntry(row), nil
+}
+
+func (r *PostgresWishlistRepository) List(ctx context.Context, status domain.WishlistStatus) ([]*domain.WishlistEntry, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var fulfilled pgtype.Bool
+	switch status {
+	case domain.WishlistOpen:
+		fulfilled = pgtype.Bool{Bool: false, Valid: true}
+	case domain.WishlistFulfilled:
+		fulfilled = pgtype.Bool{Bool: true, Valid: true}
+	}
+	rows, err := r.q.ListWishlist(ctx, db.ListWishlistParams{
+		CollectionID: cid,
+		Fulfilled:    fulfilled,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wishlist: %w", err)
+	}
+	return toDomainWishlist(rows), nil
+}
+
+func (r *PostgresWishlistRepository) Update(ctx context.Context, entry *domain.WishlistEntry) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	row, err := r.q.UpdateWishlistEntry(ctx, db.UpdateWishlistEntryParams{
+		ID:           int32(entry.ID),
+		Grade:        entry.Grade.String(),
+		MaxPrice:     toNumeric(entry.MaxPrice),
+		Priority:     int32(entry.Priority),
+		Notes:        entry.Notes,
+		CollectionID: cid,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update wishlist entry: %w", err)
+	}
+	*entry = *toDomainWishlistEntry(row)
+	return nil
+}
+
+func (r *PostgresWishlistRepository) Delete(ctx context.Context, id int) error {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.q.DeleteWishlistEntry(ctx, db.DeleteWishlistEntryParams{ID: int32(id), CollectionID: cid}); err != nil {
+		return fmt.Errorf("failed to delete wishlist entry: %w", err)
+	}
+	return nil
+}
+
+func (r *PostgresWishlistRepository) Fulfil(ctx context.Context, numistaID int, coinID uuid.UUID) ([]*domain.WishlistEntry, error) {
+	cid, err := collectionID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.FulfilWishlistEntries(ctx, db.FulfilWishlistEntriesParams{
+		NumistaID:       int32(numistaID),
+		FulfilledCoinID: pgtype.UUID{Bytes: coinID, Valid: true},
+		CollectionID:    cid,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fulfil wishlist entries: %w", err)
+	}
+	return toDomainWishlist(rows), nil
+}
+
+func toDomainWishlist(rows []db.Wishlist) []*domain.WishlistEntry {
+	entries := make([]*domain.WishlistEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = toDomainWishlistEntry(row)
+	}
+	return entries
+}
+
+func toDomainWishlistEntry(row db.Wishlist) *domain.WishlistEntry {
+	var coinID *uuid.UUID
+	if row.FulfilledCoinID.Valid {
+		id := uuid.UUID(row.FulfilledCoinID.Bytes)
+		coinID = &id
+	}
+	grade, _ := domain.NewGrade(row.Grade)
+	maxPrice, _ := row.MaxPrice.Float64Value()
+
+	return &domain.WishlistEntry{
+		ID:               int(row.ID),
+		NumistaID:        int(row.NumistaID),
+		Title:            row.Title,
+		Issuer:           row.Issuer,
+		ObverseThumbnail: row.ObverseThumbnail,
+		ReverseThumbnail: row.ReverseThumbnail,
+		Grade:            grade,
+		MaxPrice:         maxPrice.Float64,
+		Priority:         int(row.Priority),
+		Notes:            row.Notes,
+		FulfilledAt:      toTimePtr(row.FulfilledAt),
+		FulfilledCoinID:  coinID,
+		CreatedAt:        row.CreatedAt.Time,
+		UpdatedAt:        row.UpdatedAt.Time,
+	}
+}
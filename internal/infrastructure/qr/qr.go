@@ -0,0 +1,457 @@
+// Package qr encodes short texts, such as links, as QR codes (ISO/IEC 18004)
+// in byte mode with error correction level M. Versions 1 to 10 are
+// supported, which holds up to 213 bytes: plenty for a URL.
+package qr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTooLong is returned for texts that do not fit in version 10.
+var ErrTooLong = errors.New("text is too long for a QR code")
+
+// Code is an encoded QR symbol without its quiet zone, which the caller must
+// leave blank: four modules on every side.
+type Code struct {
+	Size    int
+	modules []bool
+}
+
+// Black reports whether the module at column x, row y is dark.
+func (c *Code) Black(x, y int) bool {
+	return c.modules[y*c.Size+x]
+}
+
+const maxVersion = 10
+
+// Error correction codewords per block and number of blocks for level M,
+// indexed by version.
+var (
+	eccPerBlock = [maxVersion + 1]int{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26}
+	eccBlocks   = [maxVersion + 1]int{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5}
+)
+
+// Encode returns the smallest QR code holding text.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	for version := 1; version <= maxVersion; version++ {
+		capacity := dataCodewords(version)*8 - 4 - countBits(version)
+		if len(data)*8 > capacity {
+			continue
+		}
+		return build(version, data), nil
+	}
+	return nil, fmt.Errorf("%w: %d bytes", ErrTooLong, len(data))
+}
+
+func countBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// rawModules is the number of modules left for data and error correction
+// once the function patterns are drawn.
+func rawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func dataCodewords(version int) int {
+	return rawModules(version)/8 - eccPerBlock[version]*eccBlocks[version]
+}
+
+func build(version int, data []byte) *Code {
+	size := version*4 + 17
+	m := &matrix{size: size, dark: make([]bool, size*size), function: make([]bool, size*size)}
+	m.drawFunctionPatterns(version)
+	m.drawCodewords(interleave(version, encodeData(version, data)))
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormat(mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask) // XOR undoes it
+	}
+	m.applyMask(best)
+	m.drawFormat(best)
+
+	return &Code{Size: size, modules: m.dark}
+}
+
+// encodeData lays out the byte mode segment and pads it to the data
+// capacity of the version.
+func encodeData(version int, data []byte) []byte {
+	var bits bitBuffer
+	bits.append(0x4, 4) // byte mode
+	bits.append(len(data), countBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := dataCodewords(version) * 8
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	out := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// interleave splits the data into blocks, adds the error correction of each
+// and interleaves them into the final codeword sequence.
+func interleave(version int, data []byte) []byte {
+	numBlocks, eccLen := eccBlocks[version], eccPerBlock[version]
+	raw := rawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, divisor)
+		if i < numShort {
+			block = append(block, 0) // placeholder so all blocks line up
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	out := make([]byte, 0, raw)
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				out = append(out, block[i])
+			}
+		}
+	}
+	return out
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, (value>>i)&1 == 1)
+	}
+}
+
+// Reed-Solomon over GF(2^8) with the polynomial x^8+x^4+x^3+x^2+1
+
+func gfMul(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		hi := z & 0x80
+		z <<= 1
+		if hi != 0 {
+			z ^= 0x1D
+		}
+		if (y>>i)&1 == 1 {
+			z ^= x
+		}
+	}
+	return z
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// matrix is the symbol being drawn. function marks the modules of the
+// finder, timing, alignment, format and version patterns, which data and
+// masks leave alone.
+type matrix struct {
+	size     int
+	dark     []bool
+	function []bool
+}
+
+func (m *matrix) set(x, y int, dark bool) {
+	m.dark[y*m.size+x] = dark
+	m.function[y*m.size+x] = true
+}
+
+func (m *matrix) drawFunctionPatterns(version int) {
+	for i := 0; i < m.size; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+
+	pos := alignmentPositions(version)
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the three that would overlap the finders
+			if (i == 0 && j == 0) || (i == 0 && j == len(pos)-1) || (i == len(pos)-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; drawFormat fills them in
+	m.drawFormat(0)
+
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>i)&1 == 1
+			a, b := m.size-11+i%3, i/3
+			m.set(a, b, dark)
+			m.set(b, a, dark)
+		}
+	}
+}
+
+// drawFinder draws a finder pattern centred on x, y along with its
+// separator.
+func (m *matrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= m.size || yy < 0 || yy >= m.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			m.set(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*4 + n*2 + 1) / (n*2 - 2) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, version*4+17-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// drawFormat writes both copies of the format information for level M and
+// the given mask, plus the dark module.
+func (m *matrix) drawFormat(mask int) {
+	data := mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.set(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, m.size-15+i, bit(i))
+	}
+	m.set(8, m.size-8, true)
+}
+
+// drawCodewords places the codewords in the zigzag order of the standard,
+// two columns at a time from the bottom right, skipping the vertical timing
+// pattern.
+func (m *matrix) drawCodewords(data []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = m.size - 1 - vert
+				}
+				if m.function[y*m.size+x] || i >= len(data)*8 {
+					continue
+				}
+				m.dark[y*m.size+x] = (data[i/8]>>(7-i%8))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !m.function[y*m.size+x] {
+				m.dark[y*m.size+x] = !m.dark[y*m.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four rules of the standard; the mask
+// with the lowest score is the easiest to scan.
+func (m *matrix) penalty() int {
+	at := func(x, y int) bool { return m.dark[y*m.size+x] }
+	score := 0
+
+	// Rules 1 and 3 along rows (transposed == false) and columns
+	for _, transposed := range []bool{false, true} {
+		get := at
+		if transposed {
+			get = func(x, y int) bool { return at(y, x) }
+		}
+		for y := 0; y < m.size; y++ {
+			run := 0
+			for x := 0; x < m.size; x++ {
+				if x > 0 && get(x, y) == get(x-1, y) {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					score += 3
+				} else if run > 5 {
+					score++
+				}
+				if x >= 10 && finderLike(func(i int) bool { return get(x-10+i, y) }) {
+					score += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of one colour
+	for y := 0; y < m.size-1; y++ {
+		for x := 0; x < m.size-1; x++ {
+			c := at(x, y)
+			if c == at(x+1, y) && c == at(x, y+1) && c == at(x+1, y+1) {
+				score += 3
+			}
+		}
+	}
+
+	// Rule 4: balance of dark and light
+	dark := 0
+	for _, d := range m.dark {
+		if d {
+			dark++
+		}
+	}
+	total := len(m.dark)
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	score += max(k, 0) * 10
+
+	return score
+}
+
+// finderLike reports whether the 11 modules match 1:1:3:1:1 with four light
+// modules on either side.
+func finderLike(get func(int) bool) bool {
+	const a, b = "10111010000", "00001011101"
+	matches := func(p string) bool {
+		for i := 0; i < 11; i++ {
+			if get(i) != (p[i] == '1') {
+				return false
+			}
+		}
+		return true
+	}
+	return matches(a) || matches(b)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
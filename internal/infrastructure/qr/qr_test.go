@@ -0,0 +1,216 @@
+package qr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// Version 1-M example of ISO/IEC 18004 Annex I ("01234567")
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	ecc := rsRemainder(data, rsDivisor(10))
+	assert.Equal(t, []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}, ecc)
+}
+
+func TestFormatAndVersionInfo(t *testing.T) {
+	// Level M format strings for masks 0 to 7, most significant bit first
+	want := []string{
+		"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+		"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+	}
+	for mask, bits := range want {
+		m := newMatrix(1)
+		m.drawFormat(mask)
+		assert.Equal(t, bits, readFormat(m), "mask %d", mask)
+		assert.Equal(t, bits, readFormatCopy(m), "mask %d", mask)
+	}
+
+	m := newMatrix(7)
+	m.drawFunctionPatterns(7)
+	var got strings.Builder
+	for i := 17; i >= 0; i-- {
+		if m.dark[(i/3)*m.size+m.size-11+i%3] {
+			got.WriteByte('1')
+		} else {
+			got.WriteByte('0')
+		}
+	}
+	assert.Equal(t, "000111110010010100", got.String())
+}
+
+func TestEncode(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"https://coins.example.com/coin/7f8c2b9e-2d4f-4c39-9a51-0b6f3b1d2e44",
+		strings.Repeat("numismatica ", 17) + "x",
+	} {
+		code, err := Encode(text)
+		require.NoError(t, err)
+		assert.Equal(t, text, decode(t, code))
+
+		// Finder patterns in three corners
+		for _, corner := range [][2]int{{0, 0}, {code.Size - 7, 0}, {0, code.Size - 7}} {
+			assert.True(t, code.Black(corner[0], corner[1]))
+			assert.False(t, code.Black(corner[0]+1, corner[1]+1))
+			assert.True(t, code.Black(corner[0]+3, corner[1]+3))
+		}
+	}
+
+	code, err := Encode("https://coins.example.com/coin/7f8c2b9e-2d4f-4c39-9a51-0b6f3b1d2e44")
+	require.NoError(t, err)
+	assert.Equal(t, 37, code.Size) // version 5
+
+	_, err = Encode(strings.Repeat("x", 214))
+	assert.True(t, errors.Is(err, ErrTooLong))
+}
+
+func newMatrix(version int) *matrix {
+	size := version*4 + 17
+	return &matrix{size: size, dark: make([]bool, size*size), function: make([]bool, size*size)}
+}
+
+func bits(m *matrix, coords [][2]int) string {
+	var b strings.Builder
+	for i := len(coords) - 1; i >= 0; i-- {
+		if m.dark[coords[i][1]*m.size+coords[i][0]] {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+// readFormat reads the copy around the top left finder, bit 0 first.
+func readFormat(m *matrix) string {
+	var coords [][2]int
+	for y := 0; y <= 8; y++ {
+		if y != 6 {
+			coords = append(coords, [2]int{8, y})
+		}
+	}
+	for x := 7; x >= 0; x-- {
+		if x != 6 {
+			coords = append(coords, [2]int{x, 8})
+		}
+	}
+	return bits(m, coords)
+}
+
+// readFormatCopy reads the copy split between the other two finders.
+func readFormatCopy(m *matrix) string {
+	var coords [][2]int
+	for i := 0; i < 8; i++ {
+		coords = append(coords, [2]int{m.size - 1 - i, 8})
+	}
+	for i := 8; i < 15; i++ {
+		coords = append(coords, [2]int{8, m.size - 15 + i})
+	}
+	return bits(m, coords)
+}
+
+// decode reads a symbol back: it finds the mask from the format bits,
+// removes it, collects the codewords, checks the error correction of every
+// block and returns the byte mode text.
+func decode(t *testing.T, code *Code) string {
+	t.Helper()
+	version := (code.Size - 17) / 4
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns(version)
+	copy(m.dark, code.modules)
+
+	format := readFormat(m)
+	require.Equal(t, format, readFormatCopy(m))
+	var mask int
+	for i := 0; i < 8; i++ {
+		probe := newMatrix(version)
+		probe.drawFormat(i)
+		if readFormat(probe) == format {
+			mask = i
+		}
+	}
+	m.applyMask(mask)
+
+	var codewords []byte
+	var cur byte
+	n := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = m.size - 1 - vert
+				}
+				if m.function[y*m.size+x] {
+					continue
+				}
+				cur <<= 1
+				if m.dark[y*m.size+x] {
+					cur |= 1
+				}
+				if n++; n%8 == 0 {
+					codewords = append(codewords, cur)
+				}
+			}
+		}
+	}
+
+	numBlocks, eccLen := eccBlocks[version], eccPerBlock[version]
+	raw := rawModules(version) / 8
+	require.Len(t, codewords, raw)
+	numShort := numBlocks - raw%numBlocks
+	shortData := raw/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortData; i++ {
+		for j := range blocks {
+			if i < shortData || j >= numShort {
+				blocks[j] = append(blocks[j], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for j, block := range blocks {
+		ecc := codewords[k+j : len(codewords) : len(codewords)]
+		var own []byte
+		for i := 0; i < eccLen; i++ {
+			own = append(own, ecc[i*numBlocks])
+		}
+		assert.Equal(t, rsRemainder(block, rsDivisor(eccLen)), own, "block %d", j)
+		data = append(data, block...)
+	}
+
+	var bitsRead []bool
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bitsRead = append(bitsRead, (b>>i)&1 == 1)
+		}
+	}
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if bitsRead[0] {
+				v |= 1
+			}
+			bitsRead = bitsRead[1:]
+		}
+		return v
+	}
+	require.Equal(t, 0x4, read(4))
+	length := read(countBits(version))
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = byte(read(8))
+	}
+	return string(out)
+}
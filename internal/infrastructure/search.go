@@ -0,0 +1,35 @@
+package infrastructure
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// prefixQuery turns free text into a tsquery that matches every word as a
+// prefix, e.g. "real de a 8" becomes "real:* & de:* & a:* & 8:*". Only
+// letters and digits are kept, so the result is always valid tsquery
+// syntax. Stop words are dropped by Postgres. Null when nothing is left.
+func prefixQuery(q *string) pgtype.Text {
+	if q == nil {
+		return pgtype.Text{}
+	}
+	words := strings.FieldsFunc(*q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return pgtype.Text{}
+	}
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return pgtype.Text{String: strings.Join(words, " & "), Valid: true}
+}
+
+// highlightSnippet escapes a search snippet for HTML and turns the markers
+// the coin list puts around hits into <mark> tags.
+func highlightSnippet(s string) string {
+	return strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>").Replace(html.EscapeString(s))
+}
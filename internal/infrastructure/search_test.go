@@ -0,0 +1,22 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixQuery(t *testing.T) {
+	q := func(s string) *string { return &s }
+
+	assert.False(t, prefixQuery(nil).Valid)
+	assert.False(t, prefixQuery(q(" ¿?! ")).Valid)
+	assert.Equal(t, "Real:* & de:* & a:* & 8:*", prefixQuery(q("Real de a 8")).String)
+	assert.Equal(t, "céntimos:* & O:* & Neill:*", prefixQuery(q("céntimos & O'Neill:*")).String)
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	assert.Equal(t,
+		"50 <mark>Céntimos</mark> &lt;b&gt;1949&lt;/b&gt;",
+		highlightSnippet("50 \x02Céntimos\x03 <b>1949</b>"))
+}
@@ -69,3 +69,12 @@ func (s *LocalStorage) DeleteCoinDirectory(coinID uuid.UUID) error {
 }
 
 var _ domain.ImageStorage = (*LocalStorage)(nil)
+
+// ReadFile returns the content of a file previously stored by SaveFile or SaveGroupFile.
+func (s *LocalFileStorage) ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
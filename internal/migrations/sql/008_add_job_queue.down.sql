@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS job_steps;
+DROP TABLE IF EXISTS jobs;
+ALTER TABLE coins DROP COLUMN IF EXISTS status;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS numista_enrichments;
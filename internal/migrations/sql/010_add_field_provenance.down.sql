@@ -0,0 +1 @@
+ALTER TABLE coins DROP COLUMN IF EXISTS field_provenance;
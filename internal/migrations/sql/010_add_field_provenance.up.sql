@@ -0,0 +1,2 @@
+-- Where each coin field came from: {"weight_g": {"source": "numista", "updated_at": "...", "confidence": 0.8}}
+ALTER TABLE coins ADD COLUMN IF NOT EXISTS field_provenance JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,22 @@
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(64) NOT NULL,
+    revision INTEGER NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    operation VARCHAR(50) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id, revision)
+);
+
+-- Entries are never changed or removed, not even when their coin is deleted
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
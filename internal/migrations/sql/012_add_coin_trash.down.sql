@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_coins_deleted_at;
+ALTER TABLE coins DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+-- Deleted coins stay in the trash until restored or purged
+ALTER TABLE coins ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_coins_deleted_at ON coins(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,27 @@
+DROP INDEX IF EXISTS idx_audit_log_collection_id;
+DROP INDEX IF EXISTS idx_users_collection_id;
+DROP INDEX IF EXISTS idx_coins_collection_id;
+
+ALTER TABLE group_images DROP CONSTRAINT IF EXISTS group_images_group_collection_fkey;
+ALTER TABLE coin_links DROP CONSTRAINT IF EXISTS coin_links_coin_collection_fkey;
+ALTER TABLE coin_gallery_images DROP CONSTRAINT IF EXISTS coin_gallery_images_coin_collection_fkey;
+ALTER TABLE coin_images DROP CONSTRAINT IF EXISTS coin_images_coin_collection_fkey;
+ALTER TABLE coins DROP CONSTRAINT IF EXISTS coins_group_collection_fkey;
+ALTER TABLE coins DROP CONSTRAINT IF EXISTS coins_id_collection_id_key;
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_id_collection_id_key;
+
+-- Fails if two collections have a group with the same name
+ALTER TABLE groups DROP CONSTRAINT IF EXISTS groups_collection_id_name_key;
+ALTER TABLE groups ADD CONSTRAINT groups_name_key UNIQUE (name);
+
+ALTER TABLE audit_log DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE group_images DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE coin_links DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE coin_gallery_images DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE coin_images DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE coins DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE groups DROP COLUMN IF EXISTS collection_id;
+ALTER TABLE users DROP COLUMN IF EXISTS collection_id;
+
+DROP TABLE IF EXISTS collections;
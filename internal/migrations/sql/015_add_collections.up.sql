@@ -0,0 +1,55 @@
+-- A collection is the coins, groups, images and links of one person or
+-- household. Every user belongs to one and only sees its data.
+CREATE TABLE collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing data and accounts move to the default collection. The defaults
+-- only backfill current rows; new rows must name their collection.
+INSERT INTO collections (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'Default');
+
+ALTER TABLE users ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+ALTER TABLE groups ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+ALTER TABLE coins ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+ALTER TABLE coin_images ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE coin_gallery_images ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE coin_links ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE group_images ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE jobs ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+-- Adding a column does not fire the append-only trigger
+ALTER TABLE audit_log ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+
+ALTER TABLE users ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE groups ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coins ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coin_images ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coin_gallery_images ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coin_links ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE group_images ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE jobs ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE audit_log ALTER COLUMN collection_id DROP DEFAULT;
+
+-- Group names only need to be unique within a collection
+ALTER TABLE groups DROP CONSTRAINT groups_name_key;
+ALTER TABLE groups ADD CONSTRAINT groups_collection_id_name_key UNIQUE (collection_id, name);
+
+-- Composite keys so a row can only point at a parent in its own collection
+ALTER TABLE groups ADD CONSTRAINT groups_id_collection_id_key UNIQUE (id, collection_id);
+ALTER TABLE coins ADD CONSTRAINT coins_id_collection_id_key UNIQUE (id, collection_id);
+
+ALTER TABLE coins ADD CONSTRAINT coins_group_collection_fkey
+    FOREIGN KEY (group_id, collection_id) REFERENCES groups(id, collection_id);
+ALTER TABLE coin_images ADD CONSTRAINT coin_images_coin_collection_fkey
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE;
+ALTER TABLE coin_gallery_images ADD CONSTRAINT coin_gallery_images_coin_collection_fkey
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE;
+ALTER TABLE coin_links ADD CONSTRAINT coin_links_coin_collection_fkey
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE;
+ALTER TABLE group_images ADD CONSTRAINT group_images_group_collection_fkey
+    FOREIGN KEY (group_id, collection_id) REFERENCES groups(id, collection_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_coins_collection_id ON coins(collection_id);
+CREATE INDEX idx_users_collection_id ON users(collection_id);
+CREATE INDEX idx_audit_log_collection_id ON audit_log(collection_id, entity_type, entity_id);
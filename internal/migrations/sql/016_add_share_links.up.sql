@@ -0,0 +1,17 @@
+-- Read-only public views of a group or a filtered part of a collection
+CREATE TABLE share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    group_id INT,
+    filter JSONB NOT NULL DEFAULT '{}',
+    view_count BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_viewed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    -- Deleting the group revokes its links
+    FOREIGN KEY (group_id, collection_id) REFERENCES groups(id, collection_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_share_links_collection_id ON share_links(collection_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_coins_search_vector;
+ALTER TABLE coins DROP COLUMN IF EXISTS search_vector;
+DROP TEXT SEARCH CONFIGURATION IF EXISTS coin_search;
@@ -0,0 +1,19 @@
+-- Full-text search: Spanish stemming with accents folded, so "pesetas"
+-- finds "Peseta" and "cent" finds "Céntimos"
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE TEXT SEARCH CONFIGURATION coin_search (COPY = spanish);
+ALTER TEXT SEARCH CONFIGURATION coin_search
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, spanish_stem;
+
+-- Weighted by where a word appears: name, then series and topic, then
+-- description, then notes and the Numista data
+ALTER TABLE coins ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('coin_search', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('coin_search', series || ' ' || commemorated_topic || ' ' || ruler), 'B') ||
+    setweight(to_tsvector('coin_search', coalesce(description, '')), 'C') ||
+    setweight(to_tsvector('coin_search', coalesce(technical_notes, '') || ' ' || coalesce(personal_notes, '')), 'D') ||
+    setweight(jsonb_to_tsvector('coin_search', coalesce(numista_details, '{}'), '["string"]'), 'D')
+) STORED;
+
+CREATE INDEX idx_coins_search_vector ON coins USING GIN (search_vector);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_coins_sort_name;
+DROP INDEX IF EXISTS idx_coins_sort_country;
+DROP INDEX IF EXISTS idx_coins_sort_max_value;
+DROP INDEX IF EXISTS idx_coins_sort_min_value;
+DROP INDEX IF EXISTS idx_coins_sort_year;
+DROP INDEX IF EXISTS idx_coins_sort_created_at;
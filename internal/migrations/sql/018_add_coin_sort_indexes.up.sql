@@ -0,0 +1,9 @@
+-- One index per sort of the coin list, in the order pages are walked:
+-- (key IS NULL, key, id) within a collection, so a cursor seeks straight to
+-- its page however deep it is
+CREATE INDEX idx_coins_sort_created_at ON coins (collection_id, (created_at IS NULL), created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_year ON coins (collection_id, (year IS NULL), year, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_min_value ON coins (collection_id, (min_value IS NULL), min_value, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_max_value ON coins (collection_id, (max_value IS NULL), max_value, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_country ON coins (collection_id, (country IS NULL), country, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_name ON coins (collection_id, (name IS NULL), name, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Smart groups: a saved coin filter instead of assigned coins. NULL for
+-- ordinary groups
+ALTER TABLE groups ADD COLUMN smart_filter JSONB;
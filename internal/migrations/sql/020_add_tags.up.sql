@@ -0,0 +1,22 @@
+-- Tags classify coins along any number of axes, next to their one group.
+-- Names are unique within a collection, ignoring case
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (id, collection_id)
+);
+
+CREATE UNIQUE INDEX idx_tags_collection_id_name ON tags (collection_id, lower(name));
+
+CREATE TABLE coin_tags (
+    coin_id UUID NOT NULL,
+    tag_id INT NOT NULL,
+    collection_id UUID NOT NULL,
+    PRIMARY KEY (coin_id, tag_id),
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id, collection_id) REFERENCES tags(id, collection_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_coin_tags_tag_id ON coin_tags(tag_id);
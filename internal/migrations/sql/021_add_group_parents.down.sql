@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_groups_parent_id;
+ALTER TABLE groups DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,8 @@
+-- Nested groups: a group can sit under another of its collection, e.g.
+-- region, then country, then series. NULL for top-level groups
+ALTER TABLE groups ADD COLUMN parent_id INT;
+ALTER TABLE groups ADD CONSTRAINT groups_parent_collection_fkey
+    FOREIGN KEY (parent_id, collection_id) REFERENCES groups(id, collection_id);
+ALTER TABLE groups ADD CONSTRAINT groups_parent_id_check CHECK (parent_id <> id);
+
+CREATE INDEX idx_groups_parent_id ON groups(parent_id);
@@ -0,0 +1,24 @@
+-- Wishlist: coin types wanted for the collection, by Numista type. Title,
+-- issuer and thumbnails are copied from Numista when the entry is created.
+-- An entry is fulfilled when a coin of its type is added
+CREATE TABLE wishlist (
+    id SERIAL PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    numista_id INT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    issuer TEXT NOT NULL DEFAULT '',
+    obverse_thumbnail TEXT NOT NULL DEFAULT '',
+    reverse_thumbnail TEXT NOT NULL DEFAULT '',
+    grade TEXT NOT NULL DEFAULT '',
+    max_price NUMERIC(10, 2),
+    priority INT NOT NULL DEFAULT 3 CHECK (priority BETWEEN 1 AND 5),
+    notes TEXT NOT NULL DEFAULT '',
+    fulfilled_at TIMESTAMP WITH TIME ZONE,
+    fulfilled_coin_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (fulfilled_coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE SET NULL (fulfilled_coin_id)
+);
+
+-- One open entry per type
+CREATE UNIQUE INDEX idx_wishlist_open_numista_id ON wishlist (collection_id, numista_id) WHERE fulfilled_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS checklist_slots;
+DROP TABLE IF EXISTS checklists;
@@ -0,0 +1,25 @@
+-- Checklists: the coins expected for a set, e.g. every year of a type. Each
+-- slot is a Numista type, a year (0 for any) and a mint mark ('' for any).
+-- Owned coins fill the slots they match
+CREATE TABLE checklists (
+    id SERIAL PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (id, collection_id)
+);
+
+CREATE TABLE checklist_slots (
+    id SERIAL PRIMARY KEY,
+    checklist_id INT NOT NULL,
+    collection_id UUID NOT NULL,
+    numista_id INT NOT NULL,
+    year INT NOT NULL DEFAULT 0,
+    mint_mark VARCHAR(20) NOT NULL DEFAULT '',
+    label TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (checklist_id, collection_id) REFERENCES checklists(id, collection_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_checklist_slots_unique ON checklist_slots (checklist_id, numista_id, year, lower(mint_mark));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_coins_public_search_vector;
+DROP FUNCTION IF EXISTS coin_public_search_vector(text, text, text, text, text, jsonb);
@@ -0,0 +1,13 @@
+-- Search for share links: only the text a shared coin shows, so anonymous
+-- visitors cannot find coins by their notes
+CREATE FUNCTION coin_public_search_vector(text, text, text, text, text, jsonb) RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT setweight(to_tsvector('coin_search', coalesce($1, '')), 'A') ||
+        setweight(to_tsvector('coin_search', $2 || ' ' || $3 || ' ' || $4), 'B') ||
+        setweight(to_tsvector('coin_search', coalesce($5, '')), 'C') ||
+        setweight(jsonb_to_tsvector('coin_search', coalesce($6, '{}'), '["string"]'), 'D')
+$$;
+
+CREATE INDEX idx_coins_public_search_vector ON coins USING GIN (
+    coin_public_search_vector(name, series, commemorated_topic, ruler, description, numista_details)
+);
@@ -0,0 +1,2 @@
+-- Mint mark struck on the coin (e.g. M for Madrid); mint holds the mint name
+ALTER TABLE coins ADD COLUMN IF NOT EXISTS mint_mark TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,600 @@
+// Package showcase renders a collection as a static HTML site that can be
+// published on any plain web host: one page per coin and group, index pages
+// by country, century and group, and a JSON index for the search box.
+package showcase
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+)
+
+//go:embed templates/*.html templates/style.css
+var templatesFS embed.FS
+
+var pageTemplates = template.Must(template.New("").ParseFS(templatesFS, "templates/*.html"))
+
+// Privacy chooses what the published site may reveal. The zero value leaves
+// out every price and personal note.
+type Privacy struct {
+	ShowPrices bool `json:"show_prices"` // price paid, acquisition date and sale details
+	ShowValues bool `json:"show_values"` // estimated market value range
+	ShowNotes  bool `json:"show_notes"`  // personal notes
+}
+
+type Config struct {
+	Title   string  `json:"title"`
+	Privacy Privacy `json:"privacy"`
+}
+
+// LoadConfig reads a JSON config file. Unknown keys are rejected so that a
+// misspelt privacy setting is noticed instead of silently ignored.
+func LoadConfig(file string) (Config, error) {
+	var cfg Config
+	f, err := os.Open(file)
+	if err != nil {
+		return cfg, err
+	}
+	defer func() { _ = f.Close() }()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("invalid showcase config %s: %w", file, err)
+	}
+	return cfg, nil
+}
+
+// Source is what the site is built from, typically a backup archive. Coin
+// images are given separately from the coins, as the backup stores them.
+type Source struct {
+	Coins       []*domain.Coin
+	Groups      []*domain.Group
+	GroupImages []domain.GroupImage
+	Images      []domain.CoinImage
+	// OpenFile opens an image by the storage path its row refers to.
+	OpenFile func(storagePath string) (io.ReadCloser, error)
+}
+
+type Report struct {
+	Coins    int      `json:"coins"`
+	Groups   int      `json:"groups"`
+	Pages    int      `json:"pages"`
+	Files    int      `json:"files"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SearchEntry is one coin in search.json. It holds public fields only.
+type SearchEntry struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Country   string `json:"country,omitempty"`
+	Year      int    `json:"year,omitempty"`
+	FaceValue string `json:"face_value,omitempty"`
+	Material  string `json:"material,omitempty"`
+	KMCode    string `json:"km_code,omitempty"`
+	Group     string `json:"group,omitempty"`
+	URL       string `json:"url"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+}
+
+type fact struct {
+	Label string
+	Value string
+}
+
+type side struct {
+	Description string
+	Lettering   string
+}
+
+type numistaView struct {
+	Number    int
+	URL       string
+	Obverse   side
+	Reverse   side
+	Edge      side
+	Technique string
+}
+
+type link struct {
+	Name  string
+	URL   string
+	Count int
+}
+
+type coinView struct {
+	ID          string
+	Name        string
+	Year        int
+	URL         string
+	Front       string
+	Back        string
+	Thumbnail   string
+	Description string
+	Notes       string
+	Facts       []fact
+	Numista     *numistaView
+	Country     link
+	Century     link
+	Group       *link
+}
+
+type groupView struct {
+	link
+	Description string
+	Images      []string
+}
+
+// index is one of the country, century or group listings.
+type index struct {
+	link
+	key   int // sort key for centuries
+	Coins []*coinView
+}
+
+type listData struct {
+	List  *index
+	Group *groupView
+}
+
+// page is passed to every template. Root is the way back to the top of the
+// site and goes into <base>, so templates use site paths throughout.
+type page struct {
+	Title     string
+	SiteTitle string
+	Root      string
+	Generated string
+}
+
+// Build writes the site into outDir, which is created if needed. Images that
+// cannot be read are reported as warnings and left out of the pages.
+func Build(src Source, cfg Config, outDir string) (*Report, error) {
+	if cfg.Title == "" {
+		cfg.Title = "Coin Collection"
+	}
+	b := &builder{src: src, cfg: cfg, out: outDir, report: &Report{}, copied: make(map[string]string)}
+	if err := b.build(); err != nil {
+		return nil, err
+	}
+	return b.report, nil
+}
+
+type builder struct {
+	src    Source
+	cfg    Config
+	out    string
+	report *Report
+	copied map[string]string // storage path -> site path
+}
+
+func (b *builder) build() error {
+	if err := os.MkdirAll(b.out, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	imagesByCoin := make(map[string][]domain.CoinImage)
+	for _, img := range b.src.Images {
+		imagesByCoin[img.CoinID.String()] = append(imagesByCoin[img.CoinID.String()], img)
+	}
+	groupImages := make(map[int][]domain.GroupImage)
+	for _, img := range b.src.GroupImages {
+		groupImages[img.GroupID] = append(groupImages[img.GroupID], img)
+	}
+
+	groups := make(map[int]*index)
+	groupInfo := make(map[int]*groupView)
+	for _, g := range b.src.Groups {
+		gv := &groupView{link: link{Name: g.Name, URL: fmt.Sprintf("groups/%d.html", g.ID)}, Description: g.Description}
+		for _, img := range groupImages[g.ID] {
+			if p := b.copyImage(img.Path, fmt.Sprintf("images/groups/%d", g.ID)); p != "" {
+				gv.Images = append(gv.Images, p)
+			}
+		}
+		groupInfo[g.ID] = gv
+		groups[g.ID] = &index{link: gv.link}
+	}
+
+	coins := make([]*domain.Coin, 0, len(b.src.Coins))
+	for _, c := range b.src.Coins {
+		if c.DeletedAt == nil {
+			coins = append(coins, c)
+		}
+	}
+	sort.SliceStable(coins, func(i, j int) bool {
+		a, c := coins[i], coins[j]
+		if a.Country != c.Country {
+			return a.Country < c.Country
+		}
+		if a.Year.Int() != c.Year.Int() {
+			return a.Year.Int() < c.Year.Int()
+		}
+		return a.Name < c.Name
+	})
+
+	countries := make(map[string]*index)
+	centuries := make(map[int]*index)
+	var all []*coinView
+	var search []SearchEntry
+	for _, c := range coins {
+		v := b.coinView(c, imagesByCoin[c.ID.String()])
+
+		country, ok := countries[v.Country.URL]
+		if !ok {
+			country = &index{link: v.Country}
+			countries[v.Country.URL] = country
+		}
+		country.Coins = append(country.Coins, v)
+
+		key := centuryKey(c.Year.Int())
+		century, ok := centuries[key]
+		if !ok {
+			century = &index{link: v.Century, key: key}
+			centuries[key] = century
+		}
+		century.Coins = append(century.Coins, v)
+
+		entry := SearchEntry{
+			ID:        v.ID,
+			Name:      c.Name,
+			Country:   c.Country,
+			Year:      c.Year.Int(),
+			FaceValue: strings.TrimSpace(c.FaceValue + " " + c.Currency),
+			Material:  c.Material,
+			KMCode:    c.KMCode.String(),
+			URL:       v.URL,
+			Thumbnail: v.Thumbnail,
+		}
+		if c.GroupID != nil {
+			if g, ok := groups[*c.GroupID]; ok {
+				v.Group = &g.link
+				g.Coins = append(g.Coins, v)
+				entry.Group = g.Name
+			}
+		}
+		all = append(all, v)
+		search = append(search, entry)
+	}
+
+	for _, c := range all {
+		if err := b.render("coin.html", c.URL, c.Name, c); err != nil {
+			return err
+		}
+	}
+	countryList := sortedIndexes(countries, func(a, c *index) bool { return a.Name < c.Name })
+	// Undated coins go last
+	centuryList := sortedIndexes(centuries, func(a, c *index) bool { return a.key != 0 && (c.key == 0 || a.key < c.key) })
+	groupList := sortedIndexes(groups, func(a, c *index) bool { return a.Name < c.Name })
+	for _, list := range [][]*index{countryList, centuryList} {
+		for _, ix := range list {
+			if err := b.render("list.html", ix.URL, ix.Name, listData{List: ix}); err != nil {
+				return err
+			}
+		}
+	}
+	for id, g := range groups {
+		if err := b.render("list.html", g.URL, g.Name, listData{List: g, Group: groupInfo[id]}); err != nil {
+			return err
+		}
+	}
+
+	home := struct {
+		Coins     []*coinView
+		Countries []*index
+		Centuries []*index
+		Groups    []*index
+	}{all, countryList, centuryList, groupList}
+	if err := b.render("index.html", "index.html", b.cfg.Title, home); err != nil {
+		return err
+	}
+
+	if search == nil {
+		search = []SearchEntry{}
+	}
+	data, err := json.Marshal(search)
+	if err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+	if err := b.writeFile("search.json", data); err != nil {
+		return err
+	}
+	css, err := templatesFS.ReadFile("templates/style.css")
+	if err != nil {
+		return err
+	}
+	if err := b.writeFile("style.css", css); err != nil {
+		return err
+	}
+
+	b.report.Coins = len(all)
+	b.report.Groups = len(groups)
+	return nil
+}
+
+// coinView collects what the coin page shows, leaving out whatever the
+// privacy settings do not allow.
+func (b *builder) coinView(c *domain.Coin, images []domain.CoinImage) *coinView {
+	id := c.ID.String()
+	v := &coinView{
+		ID:          id,
+		Name:        c.Name,
+		Year:        c.Year.Int(),
+		URL:         "coins/" + id + ".html",
+		Description: c.Description,
+	}
+	if v.Name == "" {
+		v.Name = "Untitled coin"
+	}
+
+	dir := "images/coins/" + id
+	v.Front = b.copyImage(pickImage(images, "front", "crop", "original"), dir)
+	v.Back = b.copyImage(pickImage(images, "back", "crop", "original"), dir)
+	v.Thumbnail = b.copyImage(pickImage(images, "front", "thumbnail", "crop", "original"), dir)
+
+	country := c.Country
+	if country == "" {
+		country = "Unknown country"
+	}
+	v.Country = link{Name: country, URL: "countries/" + slug(country) + ".html"}
+	key := centuryKey(c.Year.Int())
+	v.Century = link{Name: centuryName(key), URL: "centuries/" + centurySlug(key) + ".html"}
+
+	add := func(label, value string) {
+		if value = strings.TrimSpace(value); value != "" && value != "0" {
+			v.Facts = append(v.Facts, fact{label, value})
+		}
+	}
+	add("Country", c.Country)
+	if y := c.Year.Int(); y != 0 {
+		add("Year", strconv.Itoa(y))
+	}
+	add("Face value", c.FaceValue+" "+c.Currency)
+	add("Ruler", c.Ruler)
+	add("Mint", c.Mint)
+	add("Mintage", strconv.FormatInt(c.Mintage.Int64(), 10))
+	add("Material", c.Material)
+	add("Weight", unit(c.WeightG, "g"))
+	add("Diameter", unit(c.DiameterMM, "mm"))
+	add("Thickness", unit(c.ThicknessMM, "mm"))
+	add("Edge", c.Edge)
+	add("Shape", c.Shape)
+	add("Orientation", c.Orientation)
+	add("Series", c.Series)
+	add("Commemorates", c.CommemoratedTopic)
+	add("Grade", c.Grade.String())
+	add("KM", c.KMCode.String())
+
+	privacy := b.cfg.Privacy
+	if privacy.ShowValues && (c.MinValue > 0 || c.MaxValue > 0) {
+		add("Estimated value", fmt.Sprintf("%s – %s", money(c.MinValue), money(c.MaxValue)))
+	}
+	if privacy.ShowPrices {
+		if c.PricePaid > 0 {
+			add("Price paid", money(c.PricePaid))
+		}
+		if c.AcquiredAt != nil {
+			add("Acquired", c.AcquiredAt.Format("2006-01-02"))
+		}
+		if c.SoldAt != nil {
+			sold := c.SoldAt.Format("2006-01-02")
+			if c.SoldPrice > 0 {
+				sold += " for " + money(c.SoldPrice)
+			}
+			if c.SaleChannel != "" {
+				sold += " (" + c.SaleChannel + ")"
+			}
+			add("Sold", sold)
+		}
+	}
+	if privacy.ShowNotes {
+		v.Notes = c.PersonalNotes
+	}
+
+	if c.NumistaNumber > 0 {
+		d := c.NumistaDetails
+		v.Numista = &numistaView{
+			Number:    c.NumistaNumber,
+			URL:       fmt.Sprintf("https://en.numista.com/catalogue/pieces%d.html", c.NumistaNumber),
+			Obverse:   side{nested(d, "obverse", "description"), nested(d, "obverse", "lettering")},
+			Reverse:   side{nested(d, "reverse", "description"), nested(d, "reverse", "lettering")},
+			Edge:      side{nested(d, "edge", "description"), nested(d, "edge", "lettering")},
+			Technique: nested(d, "technique", "text"),
+		}
+	}
+	return v
+}
+
+// copyImage copies a stored image into dir of the site, once, and returns
+// its path within the site, or "" if it cannot be read.
+func (b *builder) copyImage(storagePath, dir string) string {
+	if storagePath == "" {
+		return ""
+	}
+	if p, ok := b.copied[storagePath]; ok {
+		return p
+	}
+	b.copied[storagePath] = ""
+
+	rc, err := b.src.OpenFile(storagePath)
+	if err != nil {
+		b.warn("image %s: %v", storagePath, err)
+		return ""
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		b.warn("image %s: %v", storagePath, err)
+		return ""
+	}
+
+	sitePath := path.Join(dir, filepath.Base(storagePath))
+	if err := b.writeFile(sitePath, data); err != nil {
+		b.warn("image %s: %v", storagePath, err)
+		return ""
+	}
+	b.report.Files++
+	b.copied[storagePath] = sitePath
+	return sitePath
+}
+
+func (b *builder) render(name, sitePath, title string, data any) error {
+	root := strings.Repeat("../", strings.Count(sitePath, "/"))
+	if root == "" {
+		root = "./"
+	}
+	var buf bytes.Buffer
+	err := pageTemplates.ExecuteTemplate(&buf, name, struct {
+		Page page
+		Data any
+	}{page{Title: title, SiteTitle: b.cfg.Title, Root: root, Generated: time.Now().Format("2006-01-02")}, data})
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", sitePath, err)
+	}
+	if err := b.writeFile(sitePath, buf.Bytes()); err != nil {
+		return err
+	}
+	b.report.Pages++
+	return nil
+}
+
+func (b *builder) writeFile(sitePath string, data []byte) error {
+	full := filepath.Join(b.out, filepath.FromSlash(sitePath))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(full, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", sitePath, err)
+	}
+	return nil
+}
+
+func (b *builder) warn(format string, args ...any) {
+	b.report.Warnings = append(b.report.Warnings, fmt.Sprintf(format, args...))
+}
+
+// pickImage returns the path of the first image of the side found among
+// types, in order of preference.
+func pickImage(images []domain.CoinImage, side string, types ...string) string {
+	for _, t := range types {
+		for _, img := range images {
+			if img.Side == side && img.ImageType == t {
+				return img.Path
+			}
+		}
+	}
+	return ""
+}
+
+func sortedIndexes[K comparable](m map[K]*index, less func(a, b *index) bool) []*index {
+	list := make([]*index, 0, len(m))
+	for _, ix := range m {
+		ix.Count = len(ix.Coins)
+		list = append(list, ix)
+	}
+	sort.Slice(list, func(i, j int) bool { return less(list[i], list[j]) })
+	return list
+}
+
+// centuryKey numbers centuries from 1 for 1-100 AD and from -1 for
+// 100-1 BC. Undated coins get 0.
+func centuryKey(year int) int {
+	switch {
+	case year > 0:
+		return (year-1)/100 + 1
+	case year < 0:
+		return -((-year-1)/100 + 1)
+	}
+	return 0
+}
+
+func centuryName(key int) string {
+	switch {
+	case key == 0:
+		return "Undated"
+	case key < 0:
+		return ordinal(-key) + " century BC"
+	}
+	return ordinal(key) + " century"
+}
+
+func centurySlug(key int) string {
+	switch {
+	case key == 0:
+		return "undated"
+	case key < 0:
+		return strconv.Itoa(-key) + "-bc"
+	}
+	return strconv.Itoa(key)
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// slug makes a file name out of a country name.
+func slug(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	out := strings.TrimSuffix(sb.String(), "-")
+	if out == "" {
+		return "unknown"
+	}
+	return out
+}
+
+func unit(v float64, u string) string {
+	if v <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + u
+}
+
+func money(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64) + " €"
+}
+
+// nested reads a string from a Numista JSON object, e.g. obverse.lettering.
+func nested(m map[string]any, keys ...string) string {
+	var cur any = m
+	for _, k := range keys {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return ""
+		}
+		cur = obj[k]
+	}
+	s, _ := cur.(string)
+	return s
+}
@@ -0,0 +1,148 @@
+package showcase_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/antonioparicio/numismaticapp/internal/domain"
+	"github.com/antonioparicio/numismaticapp/internal/showcase"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSource(t *testing.T) (showcase.Source, *domain.Coin) {
+	groupID := 3
+	year, err := domain.NewYear(1870)
+	assert.NoError(t, err)
+	duro := &domain.Coin{
+		ID:             uuid.New(),
+		Name:           "5 Pesetas",
+		Country:        "Spain",
+		Year:           year,
+		Material:       "Silver",
+		MinValue:       30,
+		MaxValue:       45,
+		PricePaid:      123.45,
+		SaleChannel:    "Flea market",
+		PersonalNotes:  "From grandfather's box",
+		NumistaNumber:  1234,
+		NumistaDetails: map[string]any{"obverse": map[string]any{"lettering": "AMADEO I REY DE ESPAÑA"}},
+		GroupID:        &groupID,
+	}
+	undated := &domain.Coin{ID: uuid.New(), Name: "Token", Country: "Côte d'Ivoire"}
+
+	files := map[string]string{
+		"storage/coins/a/processed_front.png": "front",
+		"storage/coins/a/thumb_front.png":     "thumb",
+	}
+	src := showcase.Source{
+		Coins:  []*domain.Coin{duro, undated},
+		Groups: []*domain.Group{{ID: groupID, Name: "Amadeo I"}},
+		Images: []domain.CoinImage{
+			{CoinID: duro.ID, Side: "front", ImageType: "crop", Path: "storage/coins/a/processed_front.png"},
+			{CoinID: duro.ID, Side: "front", ImageType: "thumbnail", Path: "storage/coins/a/thumb_front.png"},
+			{CoinID: duro.ID, Side: "back", ImageType: "crop", Path: "storage/coins/a/processed_back.png"},
+		},
+		OpenFile: func(p string) (io.ReadCloser, error) {
+			data, ok := files[p]
+			if !ok {
+				return nil, errors.New("not found")
+			}
+			return io.NopCloser(strings.NewReader(data)), nil
+		},
+	}
+	return src, duro
+}
+
+func readFile(t *testing.T, dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestBuild(t *testing.T) {
+	t.Run("Writes Pages And Indexes", func(t *testing.T) {
+		src, duro := testSource(t)
+		out := t.TempDir()
+
+		report, err := showcase.Build(src, showcase.Config{Title: "Test Collection"}, out)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Coins)
+		assert.Equal(t, 1, report.Groups)
+		assert.Equal(t, 2, report.Files)
+		assert.Len(t, report.Warnings, 1, "the back image is missing")
+
+		for _, name := range []string{
+			"index.html", "style.css", "search.json",
+			"coins/" + duro.ID.String() + ".html",
+			"countries/spain.html", "countries/côte-d-ivoire.html",
+			"centuries/19.html", "centuries/undated.html",
+			"groups/3.html",
+			"images/coins/" + duro.ID.String() + "/processed_front.png",
+		} {
+			assert.FileExists(t, filepath.Join(out, name))
+		}
+
+		page := readFile(t, out, "coins/"+duro.ID.String()+".html")
+		assert.Contains(t, page, `<base href="../">`)
+		assert.Contains(t, page, "AMADEO I REY DE ESPAÑA")
+		assert.Contains(t, page, "pieces1234.html")
+		assert.Contains(t, page, "groups/3.html")
+
+		var entries []showcase.SearchEntry
+		assert.NoError(t, json.Unmarshal([]byte(readFile(t, out, "search.json")), &entries))
+		assert.Len(t, entries, 2)
+		assert.Equal(t, "Amadeo I", entries[1].Group)
+		assert.Equal(t, "images/coins/"+duro.ID.String()+"/thumb_front.png", entries[1].Thumbnail)
+	})
+
+	t.Run("Private Fields Left Out By Default", func(t *testing.T) {
+		src, duro := testSource(t)
+		out := t.TempDir()
+
+		_, err := showcase.Build(src, showcase.Config{}, out)
+		assert.NoError(t, err)
+
+		for _, name := range []string{"index.html", "search.json", "coins/" + duro.ID.String() + ".html"} {
+			content := readFile(t, out, name)
+			for _, private := range []string{"123.45", "Flea market", "grandfather", "30.00"} {
+				assert.NotContains(t, content, private, name)
+			}
+		}
+	})
+
+	t.Run("Privacy Settings Allow Fields", func(t *testing.T) {
+		src, duro := testSource(t)
+		out := t.TempDir()
+
+		_, err := showcase.Build(src, showcase.Config{Privacy: showcase.Privacy{ShowValues: true, ShowNotes: true}}, out)
+		assert.NoError(t, err)
+
+		page := readFile(t, out, "coins/"+duro.ID.String()+".html")
+		assert.Contains(t, page, "30.00 €")
+		assert.Contains(t, page, "grandfather")
+		assert.NotContains(t, page, "123.45")
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.json")
+	assert.NoError(t, os.WriteFile(good, []byte(`{"title": "Mine", "privacy": {"show_values": true}}`), 0644))
+	cfg, err := showcase.LoadConfig(good)
+	assert.NoError(t, err)
+	assert.Equal(t, "Mine", cfg.Title)
+	assert.True(t, cfg.Privacy.ShowValues)
+	assert.False(t, cfg.Privacy.ShowPrices)
+
+	typo := filepath.Join(dir, "typo.json")
+	assert.NoError(t, os.WriteFile(typo, []byte(`{"privacy": {"show_price": true}}`), 0644))
+	_, err = showcase.LoadConfig(typo)
+	assert.Error(t, err)
+}
@@ -41,7 +41,8 @@ CREATE TABLE coins (
     series TEXT NOT NULL DEFAULT '',
     commemorated_topic TEXT NOT NULL DEFAULT '',
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'ready'
 );
 
 CREATE TABLE groups (
@@ -102,3 +103,321 @@ CREATE TABLE coin_gallery_images (
 );
 
 CREATE INDEX idx_coin_gallery_images_coin_id ON coin_gallery_images(coin_id);
+
+CREATE TABLE jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(50) NOT NULL,
+    coin_id UUID REFERENCES coins(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    payload JSONB NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    last_error TEXT,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_status_run_at ON jobs(status, run_at);
+CREATE INDEX idx_jobs_coin_id ON jobs(coin_id);
+
+CREATE TABLE job_steps (
+    job_id UUID NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    output JSONB,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (job_id, name)
+);
+
+CREATE TABLE numista_enrichments (
+    coin_id UUID PRIMARY KEY REFERENCES coins(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_retry_at TIMESTAMP WITH TIME ZONE,
+    job_id UUID REFERENCES jobs(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_numista_enrichments_status ON numista_enrichments(status);
+
+-- Where each coin field came from: {"weight_g": {"source": "numista", "updated_at": "...", "confidence": 0.8}}
+ALTER TABLE coins ADD COLUMN IF NOT EXISTS field_provenance JSONB NOT NULL DEFAULT '{}';
+
+-- Append-only change history of coins and groups
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id VARCHAR(64) NOT NULL,
+    revision INTEGER NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    operation VARCHAR(50) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (entity_type, entity_id, revision)
+);
+
+-- Entries are never changed or removed, not even when their coin is deleted
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+-- Deleted coins stay in the trash until restored or purged
+ALTER TABLE coins ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_coins_deleted_at ON coins(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Local accounts and their login sessions
+CREATE TABLE users (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    username VARCHAR(64) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL,
+    is_admin BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Only a SHA-256 of the session token is stored
+CREATE TABLE sessions (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+-- Personal API tokens for scripts, stored hashed like sessions
+CREATE TABLE api_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_tokens_user_id ON api_tokens(user_id);
+
+-- A collection is the coins, groups, images and links of one person or
+-- household. Every user belongs to one and only sees its data.
+CREATE TABLE collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing data and accounts move to the default collection. The defaults
+-- only backfill current rows; new rows must name their collection.
+INSERT INTO collections (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'Default');
+
+ALTER TABLE users ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+ALTER TABLE groups ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+ALTER TABLE coins ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+ALTER TABLE coin_images ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE coin_gallery_images ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE coin_links ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE group_images ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001';
+ALTER TABLE jobs ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+-- Adding a column does not fire the append-only trigger
+ALTER TABLE audit_log ADD COLUMN collection_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001' REFERENCES collections(id);
+
+ALTER TABLE users ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE groups ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coins ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coin_images ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coin_gallery_images ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE coin_links ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE group_images ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE jobs ALTER COLUMN collection_id DROP DEFAULT;
+ALTER TABLE audit_log ALTER COLUMN collection_id DROP DEFAULT;
+
+-- Group names only need to be unique within a collection
+ALTER TABLE groups DROP CONSTRAINT groups_name_key;
+ALTER TABLE groups ADD CONSTRAINT groups_collection_id_name_key UNIQUE (collection_id, name);
+
+-- Composite keys so a row can only point at a parent in its own collection
+ALTER TABLE groups ADD CONSTRAINT groups_id_collection_id_key UNIQUE (id, collection_id);
+ALTER TABLE coins ADD CONSTRAINT coins_id_collection_id_key UNIQUE (id, collection_id);
+
+ALTER TABLE coins ADD CONSTRAINT coins_group_collection_fkey
+    FOREIGN KEY (group_id, collection_id) REFERENCES groups(id, collection_id);
+ALTER TABLE coin_images ADD CONSTRAINT coin_images_coin_collection_fkey
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE;
+ALTER TABLE coin_gallery_images ADD CONSTRAINT coin_gallery_images_coin_collection_fkey
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE;
+ALTER TABLE coin_links ADD CONSTRAINT coin_links_coin_collection_fkey
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE;
+ALTER TABLE group_images ADD CONSTRAINT group_images_group_collection_fkey
+    FOREIGN KEY (group_id, collection_id) REFERENCES groups(id, collection_id) ON DELETE CASCADE;
+
+CREATE INDEX idx_coins_collection_id ON coins(collection_id);
+CREATE INDEX idx_users_collection_id ON users(collection_id);
+CREATE INDEX idx_audit_log_collection_id ON audit_log(collection_id, entity_type, entity_id);
+
+-- Read-only public views of a group or a filtered part of a collection
+CREATE TABLE share_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    group_id INT,
+    filter JSONB NOT NULL DEFAULT '{}',
+    view_count BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_viewed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    -- Deleting the group revokes its links
+    FOREIGN KEY (group_id, collection_id) REFERENCES groups(id, collection_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_share_links_collection_id ON share_links(collection_id);
+
+-- Full-text search: Spanish stemming with accents folded, so "pesetas"
+-- finds "Peseta" and "cent" finds "Céntimos"
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE TEXT SEARCH CONFIGURATION coin_search (COPY = spanish);
+ALTER TEXT SEARCH CONFIGURATION coin_search
+    ALTER MAPPING FOR hword, hword_part, word WITH unaccent, spanish_stem;
+
+-- Weighted by where a word appears: name, then series and topic, then
+-- description, then notes and the Numista data
+ALTER TABLE coins ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('coin_search', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('coin_search', series || ' ' || commemorated_topic || ' ' || ruler), 'B') ||
+    setweight(to_tsvector('coin_search', coalesce(description, '')), 'C') ||
+    setweight(to_tsvector('coin_search', coalesce(technical_notes, '') || ' ' || coalesce(personal_notes, '')), 'D') ||
+    setweight(jsonb_to_tsvector('coin_search', coalesce(numista_details, '{}'), '["string"]'), 'D')
+) STORED;
+
+CREATE INDEX idx_coins_search_vector ON coins USING GIN (search_vector);
+
+-- One index per sort of the coin list, in the order pages are walked:
+-- (key IS NULL, key, id) within a collection, so a cursor seeks straight to
+-- its page however deep it is
+CREATE INDEX idx_coins_sort_created_at ON coins (collection_id, (created_at IS NULL), created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_year ON coins (collection_id, (year IS NULL), year, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_min_value ON coins (collection_id, (min_value IS NULL), min_value, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_max_value ON coins (collection_id, (max_value IS NULL), max_value, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_country ON coins (collection_id, (country IS NULL), country, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_coins_sort_name ON coins (collection_id, (name IS NULL), name, id) WHERE deleted_at IS NULL;
+
+-- Smart groups: a saved coin filter instead of assigned coins. NULL for
+-- ordinary groups
+ALTER TABLE groups ADD COLUMN smart_filter JSONB;
+
+-- Tags classify coins along any number of axes, next to their one group.
+-- Names are unique within a collection, ignoring case
+CREATE TABLE tags (
+    id SERIAL PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (id, collection_id)
+);
+
+CREATE UNIQUE INDEX idx_tags_collection_id_name ON tags (collection_id, lower(name));
+
+CREATE TABLE coin_tags (
+    coin_id UUID NOT NULL,
+    tag_id INT NOT NULL,
+    collection_id UUID NOT NULL,
+    PRIMARY KEY (coin_id, tag_id),
+    FOREIGN KEY (coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id, collection_id) REFERENCES tags(id, collection_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_coin_tags_tag_id ON coin_tags(tag_id);
+
+-- Nested groups: a group can sit under another of its collection, e.g.
+-- region, then country, then series. NULL for top-level groups
+ALTER TABLE groups ADD COLUMN parent_id INT;
+ALTER TABLE groups ADD CONSTRAINT groups_parent_collection_fkey
+    FOREIGN KEY (parent_id, collection_id) REFERENCES groups(id, collection_id);
+ALTER TABLE groups ADD CONSTRAINT groups_parent_id_check CHECK (parent_id <> id);
+
+CREATE INDEX idx_groups_parent_id ON groups(parent_id);
+
+-- Wishlist: coin types wanted for the collection, by Numista type. Title,
+-- issuer and thumbnails are copied from Numista when the entry is created.
+-- An entry is fulfilled when a coin of its type is added
+CREATE TABLE wishlist (
+    id SERIAL PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    numista_id INT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    issuer TEXT NOT NULL DEFAULT '',
+    obverse_thumbnail TEXT NOT NULL DEFAULT '',
+    reverse_thumbnail TEXT NOT NULL DEFAULT '',
+    grade TEXT NOT NULL DEFAULT '',
+    max_price NUMERIC(10, 2),
+    priority INT NOT NULL DEFAULT 3 CHECK (priority BETWEEN 1 AND 5),
+    notes TEXT NOT NULL DEFAULT '',
+    fulfilled_at TIMESTAMP WITH TIME ZONE,
+    fulfilled_coin_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (fulfilled_coin_id, collection_id) REFERENCES coins(id, collection_id) ON DELETE SET NULL (fulfilled_coin_id)
+);
+
+-- One open entry per type
+CREATE UNIQUE INDEX idx_wishlist_open_numista_id ON wishlist (collection_id, numista_id) WHERE fulfilled_at IS NULL;
+
+-- Checklists: the coins expected for a set, e.g. every year of a type. Each
+-- slot is a Numista type, a year (0 for any) and a mint mark ('' for any).
+-- Owned coins fill the slots they match
+CREATE TABLE checklists (
+    id SERIAL PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES collections(id),
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (id, collection_id)
+);
+
+CREATE TABLE checklist_slots (
+    id SERIAL PRIMARY KEY,
+    checklist_id INT NOT NULL,
+    collection_id UUID NOT NULL,
+    numista_id INT NOT NULL,
+    year INT NOT NULL DEFAULT 0,
+    mint_mark VARCHAR(20) NOT NULL DEFAULT '',
+    label TEXT NOT NULL DEFAULT '',
+    FOREIGN KEY (checklist_id, collection_id) REFERENCES checklists(id, collection_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_checklist_slots_unique ON checklist_slots (checklist_id, numista_id, year, lower(mint_mark));
+
+-- Search for share links: only the text a shared coin shows, so anonymous
+-- visitors cannot find coins by their notes
+CREATE FUNCTION coin_public_search_vector(text, text, text, text, text, jsonb) RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+    SELECT setweight(to_tsvector('coin_search', coalesce($1, '')), 'A') ||
+        setweight(to_tsvector('coin_search', $2 || ' ' || $3 || ' ' || $4), 'B') ||
+        setweight(to_tsvector('coin_search', coalesce($5, '')), 'C') ||
+        setweight(jsonb_to_tsvector('coin_search', coalesce($6, '{}'), '["string"]'), 'D')
+$$;
+
+CREATE INDEX idx_coins_public_search_vector ON coins USING GIN (
+    coin_public_search_vector(name, series, commemorated_topic, ruler, description, numista_details)
+);
+
+-- Mint mark struck on the coin (e.g. M for Madrid); mint holds the mint name
+ALTER TABLE coins ADD COLUMN IF NOT EXISTS mint_mark TEXT NOT NULL DEFAULT '';
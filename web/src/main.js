@@ -6,7 +6,19 @@ import router from './router'
 import i18n from './i18n'
 
 import { createPinia } from 'pinia'
+import axios from 'axios'
 
 const pinia = createPinia()
 
-createApp(App).use(router).use(i18n).use(pinia).mount('#app')
+// Back to the login page when the session expires. Auth calls handle their
+// own 401s (the router guard checks the session through /auth/me).
+axios.interceptors.response.use(undefined, (error) => {
+    const current = router.currentRoute.value
+    const isAuthCall = error.config?.url?.includes('/auth/')
+    if (error.response?.status === 401 && !isAuthCall && !current.meta.public) {
+        router.push({ path: '/login', query: { redirect: current.fullPath } })
+    }
+    return Promise.reject(error)
+})
+
+createApp(App).use(pinia).use(router).use(i18n).mount('#app')
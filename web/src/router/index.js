@@ -6,6 +6,8 @@ import Detail from '../views/Detail.vue'
 import EditCoin from '../views/EditCoin.vue'
 
 import GroupsList from '../views/GroupsList.vue'
+import Login from '../views/Login.vue'
+import { useAuthStore } from '../stores/auth'
 
 const routes = [
     { path: '/', component: Home },
@@ -14,6 +16,7 @@ const routes = [
     { path: '/groups', component: GroupsList },
     { path: '/coin/:id', component: Detail },
     { path: '/edit/:id', component: EditCoin },
+    { path: '/login', component: Login, meta: { public: true } },
 ]
 
 const router = createRouter({
@@ -21,4 +24,12 @@ const router = createRouter({
     routes,
 })
 
+// Everything but the login page needs a session
+router.beforeEach(async (to) => {
+    if (to.meta.public) return true
+    const authStore = useAuthStore()
+    if (authStore.user || await authStore.fetchMe()) return true
+    return { path: '/login', query: { redirect: to.fullPath } }
+})
+
 export default router
@@ -0,0 +1,43 @@
+import { defineStore } from 'pinia'
+import { ref } from 'vue'
+import axios from 'axios'
+
+const API_URL = import.meta.env.VITE_API_URL || '/api/v1'
+
+export const useAuthStore = defineStore('auth', () => {
+    // State
+    const user = ref(null)
+
+    // Actions
+    // The session lives in an HttpOnly cookie, so asking the server is the
+    // only way to know whether we are logged in.
+    const fetchMe = async () => {
+        try {
+            const res = await axios.get(`${API_URL}/auth/me`)
+            user.value = res.data
+        } catch {
+            user.value = null
+        }
+        return user.value
+    }
+
+    const login = async (username, password) => {
+        const res = await axios.post(`${API_URL}/auth/login`, { username, password })
+        user.value = res.data.user
+    }
+
+    const logout = async () => {
+        try {
+            await axios.post(`${API_URL}/auth/logout`)
+        } finally {
+            user.value = null
+        }
+    }
+
+    return {
+        user,
+        fetchMe,
+        login,
+        logout
+    }
+})